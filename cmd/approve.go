@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/approval"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/openapi"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	approvalMode       bool
+	pendingFile        string
+	approveAll         bool
+	approvePrefix      string
+	approveInteractive bool
+)
+
+// stageDescriptions holds back changed descriptions until they are approved
+func stageDescriptions(previous map[string]interface{}, spec openapi.Spec) error {
+	pending, err := approval.Load(pendingFile)
+	if err != nil {
+		return err
+	}
+
+	previousPaths, _ := previous["paths"].(map[string]interface{})
+	staged := pending.Stage(previousPaths, spec.Paths)
+	if err := pending.Save(pendingFile); err != nil {
+		return fmt.Errorf("failed to write pending file: %w", err)
+	}
+
+	if staged > 0 {
+		fmt.Printf("📝 %d generated descriptions await review in %s (run `approve`)\n", staged, pendingFile)
+	}
+	return nil
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Promote reviewed descriptions from the pending file into the spec",
+	Long: `Reviews descriptions generated in --approval mode and promotes them into
+the spec. Use --interactive to decide on each change, or --all to approve everything.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		pending, err := approval.Load(pendingFile)
+		if err != nil {
+			fmt.Printf("❌ Error loading pending file: %v\n", err)
+			os.Exit(1)
+		}
+		if len(pending.Entries) == 0 {
+			fmt.Printf("✅ Nothing pending in %s\n", pendingFile)
+			return
+		}
+
+		spec, err := diff.LoadSpec(outputFile)
+		if err != nil {
+			fmt.Printf("❌ Error loading spec: %v\n", err)
+			os.Exit(1)
+		}
+		paths, _ := spec["paths"].(map[string]interface{})
+
+		reader := bufio.NewReader(os.Stdin)
+		var remaining []approval.Entry
+		approved := 0
+
+		for i, entry := range pending.Entries {
+			if approvePrefix != "" && !strings.HasPrefix(entry.Path, approvePrefix) {
+				remaining = append(remaining, entry)
+				continue
+			}
+
+			decision := "y"
+			if approveInteractive {
+				decision = ask(reader, entry)
+			} else if !approveAll {
+				remaining = append(remaining, entry)
+				continue
+			}
+
+			switch decision {
+			case "y":
+				if approval.Apply(paths, entry) {
+					approved++
+				} else {
+					fmt.Printf("⚠️ %s %s no longer exists, dropping proposal\n", entry.Method, entry.Path)
+				}
+			case "n":
+				// Rejected proposals are dropped; the published text stays
+			case "q":
+				remaining = append(remaining, pending.Entries[i:]...)
+			default:
+				remaining = append(remaining, entry)
+			}
+			if decision == "q" {
+				break
+			}
+		}
+
+		if !approveAll && !approveInteractive {
+			fmt.Printf("ℹ️ %d entries pending; pass --interactive or --all to approve\n", len(remaining))
+			return
+		}
+
+		if err := specfile.Write(outputFile, spec); err != nil {
+			fmt.Printf("❌ Error writing spec: %v\n", err)
+			os.Exit(1)
+		}
+
+		pending.Entries = remaining
+		if err := pending.Save(pendingFile); err != nil {
+			fmt.Printf("❌ Error writing pending file: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Approved %d descriptions into %s (%d still pending)\n", approved, outputFile, len(remaining))
+	},
+}
+
+// ask shows one proposal and reads a y/n/s/q decision
+func ask(reader *bufio.Reader, entry approval.Entry) string {
+	fmt.Printf("\n%s %s — %s\n", entry.Method, entry.Path, entry.Field)
+	if entry.Current != "" {
+		fmt.Printf("  current:  %s\n", entry.Current)
+	}
+	fmt.Printf("  proposed: %s\n", entry.Proposed)
+	fmt.Printf("Approve? [y]es / [n]o / [s]kip / [q]uit: ")
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "q"
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	if answer == "" {
+		return "s"
+	}
+	return answer[:1]
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&approvalMode, "approval", false, "Hold back new or changed descriptions in the pending file until approved")
+	rootCmd.PersistentFlags().StringVar(&pendingFile, "pending", approval.DefaultFile, "File holding descriptions awaiting approval")
+
+	approveCmd.Flags().StringVarP(&outputFile, "output", "o", "openapi.json", "Spec file to promote descriptions into")
+	approveCmd.Flags().BoolVarP(&approveInteractive, "interactive", "i", false, "Review each proposal interactively")
+	approveCmd.Flags().BoolVar(&approveAll, "all", false, "Approve every pending proposal")
+	approveCmd.Flags().StringVar(&approvePrefix, "path-prefix", "", "Only review proposals for paths with this prefix")
+	rootCmd.AddCommand(approveCmd)
+}
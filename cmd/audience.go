@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/split"
+)
+
+// audiences holds the --audience values; the first is written to --output
+var audiences []string
+
+// checkAudiences validates --audience before any route is documented
+func checkAudiences() error {
+	for _, audience := range audiences {
+		if _, err := split.Audience(nil, audience); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAudiences writes a variant of the spec for every audience after the
+// first, next to outputFile, and returns the variant for the first. The public
+// variant has the redaction rules applied.
+func writeAudiences(spec interface{}, redaction []models.RedactionRule) (interface{}, error) {
+	if len(audiences) == 0 || len(audiences) == 1 && audiences[0] == split.AudienceAll {
+		return spec, nil
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	var first interface{}
+	for i, audience := range audiences {
+		source := generic
+		if audience == split.AudiencePublic && len(redaction) > 0 {
+			var count int
+			if source, count, err = split.Redact(generic, redaction); err != nil {
+				return nil, err
+			}
+			fmt.Printf("🙈 Redacted %d fields from the public spec\n", count)
+		}
+		variant, err := split.Audience(source, audience)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			first = variant
+			continue
+		}
+
+		file := audienceFile(outputFile, audience)
+		if err := validateSpec(file, variant); err != nil {
+			return nil, err
+		}
+		if err := writeOpenAPIFile(file, variant); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Printf("👥 %s spec written to: %s\n", audience, file)
+	}
+	return first, nil
+}
+
+// audienceFile names a variant after the output, e.g. openapi.internal.json
+func audienceFile(output, audience string) string {
+	ext := filepath.Ext(output)
+	return strings.TrimSuffix(output, ext) + "." + audience + ext
+}
+
+func init() {
+	rootCmd.Flags().StringSliceVar(&audiences, "audience", []string{split.AudienceAll}, "Audiences to emit: public, internal or all; the first goes to --output, others to <output>.<audience>.json")
+}
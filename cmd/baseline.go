@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nextjs-to-openapi/internal/merge"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+	"nextjs-to-openapi/internal/specfile"
+	"nextjs-to-openapi/internal/swagger"
+)
+
+var baselineFile string
+
+// loadBaseline reads a hand-written spec, upgrading Swagger 2.0 to OpenAPI 3.
+// basePath is folded into the paths so they line up with generated ones.
+func loadBaseline(path string) (map[string]interface{}, error) {
+	spec, err := specfile.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	if swagger.IsV2(spec) {
+		fmt.Printf("⬆️ Upgrading baseline %s from Swagger 2.0\n", path)
+		return swagger.Convert(spec, swagger.Options{FoldBasePath: true})
+	}
+	return spec, nil
+}
+
+// undocumentedRoutes drops the routes the baseline already documents
+func undocumentedRoutes(routes []models.APIRoute, cfg *models.Config, baseline map[string]interface{}) []models.APIRoute {
+	apps := make(map[string]models.WorkspaceApp)
+	for _, app := range cfg.Workspace.Apps {
+		apps[app.Name] = app
+	}
+	paths, _ := baseline["paths"].(map[string]interface{})
+
+	var rest []models.APIRoute
+	for _, route := range routes {
+		documented := false
+		for path := range paths {
+			if samePath(path, routeURL(route, apps, cfg.Prefixes)) {
+				documented = true
+				break
+			}
+		}
+		if !documented {
+			rest = append(rest, route)
+		}
+	}
+	return rest
+}
+
+// coveredRoutes returns the routes undocumentedRoutes left out
+func coveredRoutes(routes, pending []models.APIRoute) []models.APIRoute {
+	left := make(map[string]bool, len(pending))
+	for _, route := range pending {
+		left[route.FilePath] = true
+	}
+	var covered []models.APIRoute
+	for _, route := range routes {
+		if !left[route.FilePath] {
+			covered = append(covered, route)
+		}
+	}
+	return covered
+}
+
+// applyBaseline layers generated operations under the baseline: hand-written
+// operations always win, and generated components that clash with baseline ones
+// are renamed with a Generated prefix
+func applyBaseline(baseline map[string]interface{}, generated openapi.Spec) (openapi.Spec, error) {
+	fresh, err := json.Marshal(generated)
+	if err != nil {
+		return generated, err
+	}
+	var freshMap map[string]interface{}
+	if err := json.Unmarshal(fresh, &freshMap); err != nil {
+		return generated, err
+	}
+
+	merged, conflicts := merge.Merge([]merge.Input{
+		{Name: baselineFile, Spec: baseline},
+		{Name: "generated", Spec: freshMap, SchemaPrefix: "Generated"},
+	}, merge.Options{OnConflict: merge.OnConflictFirst})
+	for _, c := range conflicts {
+		fmt.Printf("ℹ️ Keeping baseline %s %s\n", c.Kind, c.Name)
+	}
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return generated, err
+	}
+	var spec openapi.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return generated, fmt.Errorf("failed to apply baseline: %w", err)
+	}
+	return spec, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/prune"
+)
+
+var (
+	maxSize        string
+	omitExamples   bool
+	maxDescription int
+)
+
+// sizeOptions combines the config's size budget with the flags, which win
+func sizeOptions(cfg *models.Config) (prune.Options, error) {
+	budget := cfg.SizeBudget
+	if maxSize != "" {
+		budget.MaxSize = maxSize
+	}
+	if omitExamples {
+		budget.OmitExamples = true
+	}
+	if maxDescription != 0 {
+		budget.MaxDescription = maxDescription
+	}
+
+	opts := prune.Options{OmitExamples: budget.OmitExamples, MaxDescription: budget.MaxDescription}
+	if budget.MaxSize != "" {
+		size, err := prune.ParseSize(budget.MaxSize)
+		if err != nil {
+			return opts, err
+		}
+		opts.MaxBytes = size
+	}
+	return opts, nil
+}
+
+// fitSpec shrinks the spec to the size budget and returns what should be written
+func fitSpec(spec interface{}, opts prune.Options) (interface{}, error) {
+	if opts == (prune.Options{}) {
+		return spec, nil
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	report, err := prune.Fit(generic, opts, func(v interface{}) (int, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return len(data), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure spec: %w", err)
+	}
+
+	for _, step := range report.Steps {
+		fmt.Printf("✂️ Size budget: %s\n", step)
+	}
+	fmt.Printf("📦 Spec size: %s → %s\n", formatSize(report.Before), formatSize(report.After))
+	if report.Over(opts) {
+		fmt.Printf("⚠️ Spec is still over the %s budget; consider --split or omitting more routes\n", formatSize(opts.MaxBytes))
+	}
+	return generic, nil
+}
+
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&maxSize, "max-size", "", "Shrink the spec to fit this size, e.g. 2MB (shares schemas, then drops examples, then shortens descriptions)")
+	rootCmd.Flags().BoolVar(&omitExamples, "omit-examples", false, "Leave examples out of the written spec")
+	rootCmd.Flags().IntVar(&maxDescription, "max-description", 0, "Cut descriptions to this many characters (0 keeps them whole)")
+}
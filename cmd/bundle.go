@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/bundle"
+	"nextjs-to-openapi/internal/docs"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	bundleInput  string
+	bundleOutput string
+	bundleSource string
+	bundleOS     string
+	bundleArch   string
+)
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Compile a self-contained docs server with the spec and reference embedded",
+	Long: `Renders the HTML reference for a spec and compiles a small server that embeds
+it along with the spec, so the docs can be deployed as a single binary without
+this tool or the source repository. Building needs a Go toolchain; running the
+result needs nothing.
+
+The server listens on -addr (default :8080, or $PORT) and serves the reference
+at /, the spec at /openapi.json and /openapi.yaml, and /healthz.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := specfile.Read(bundleInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		var assets bundle.Assets
+		if assets.Spec, err = json.MarshalIndent(spec, "", "  "); err == nil {
+			if assets.YAML, err = specfile.MarshalYAML(spec); err == nil {
+				var page string
+				page, err = docs.HTML(spec)
+				assets.Page = []byte(page)
+			}
+		}
+		if err != nil {
+			fmt.Printf("❌ Error rendering docs: %v\n", err)
+			os.Exit(1)
+		}
+
+		dir := bundleSource
+		if dir == "" {
+			if dir, err = os.MkdirTemp("", "docsserver-"); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			defer os.RemoveAll(dir)
+		}
+		if err := bundle.Write(dir, assets); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if bundleSource != "" {
+			fmt.Printf("📁 Server source written to %s\n", bundleSource)
+		}
+
+		fmt.Printf("📦 Compiling docs server...\n")
+		if err := bundle.Build(dir, bundleOutput, bundleOS, bundleArch); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if info, err := os.Stat(bundleOutput); err == nil {
+			fmt.Printf("✅ Docs server written to %s (%.1f MB)\n", bundleOutput, float64(info.Size())/(1<<20))
+		}
+		fmt.Printf("   Run it with: %s -addr :8080\n", bundleOutput)
+	},
+}
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleInput, "input", "i", "openapi.json", "Spec to embed")
+	bundleCmd.Flags().StringVarP(&bundleOutput, "output", "o", "docs-server", "Binary to write")
+	bundleCmd.Flags().StringVar(&bundleSource, "source-dir", "", "Keep the server's Go source in this directory instead of a temporary one")
+	bundleCmd.Flags().StringVar(&bundleOS, "os", "", "Target operating system, e.g. linux (default the host's)")
+	bundleCmd.Flags().StringVar(&bundleArch, "arch", "", "Target architecture, e.g. amd64 or arm64 (default the host's)")
+	rootCmd.AddCommand(bundleCmd)
+}
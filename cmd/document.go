@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/scanner"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	documentStdin  bool
+	documentPath   string
+	documentType   string
+	documentFormat string
+)
+
+var documentCmd = &cobra.Command{
+	Use:   "document [route-file]",
+	Short: "Document a single route file and print its OpenAPI fragment",
+	Long: `Documents one route file and prints the resulting OpenAPI fragment (paths and
+components) to stdout. With --stdin the source is read from standard input and
+--path gives the route it serves. Progress output goes to stderr.`,
+	Example: `  cat app/api/users/[id]/route.ts | nextjs-to-openapi document --stdin --path /api/users/[id]
+  nextjs-to-openapi document app/api/users/route.ts --format yaml`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// Keep stdout clean for the fragment
+		stdout := os.Stdout
+		os.Stdout = os.Stderr
+
+		cfg := loadConfig(cmd)
+
+		var file, content string
+		switch {
+		case documentStdin:
+			if documentPath == "" {
+				fmt.Printf("❌ --stdin needs --path, e.g. --path /api/users/[id]\n")
+				os.Exit(1)
+			}
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				fmt.Printf("❌ Error reading stdin: %v\n", err)
+				os.Exit(1)
+			}
+			// Pretend the source lives where Next.js would expect it
+			file = path.Join("app", strings.Trim(documentPath, "/"), "route."+documentType)
+			content = string(data)
+		case len(args) == 1:
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				fmt.Printf("❌ Error reading %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			file, content = args[0], string(data)
+		default:
+			fmt.Printf("❌ Pass a route file or --stdin\n")
+			os.Exit(1)
+		}
+
+		route := scanner.NewScanner(".").Analyze(file, content)
+
+		builder, err := newBuilder(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error applying security config: %v\n", err)
+			os.Exit(1)
+		}
+
+		client, closeClient := newClient(cfg)
+		defer closeClient()
+		doc, err := documentWithTimeout(client, builder, route)
+		if err != nil {
+			fmt.Printf("❌ Error documenting route: %v\n", err)
+			os.Exit(1)
+		}
+		builder.AddRoute(route, doc)
+		spec := builder.Spec()
+		fragment := map[string]interface{}{"paths": spec.Paths}
+		if len(spec.Components) > 0 {
+			fragment["components"] = spec.Components
+		}
+
+		var out []byte
+		if documentFormat == "yaml" {
+			out, err = specfile.MarshalYAML(fragment)
+		} else {
+			out, err = json.MarshalIndent(fragment, "", "  ")
+			out = append(out, '\n')
+		}
+		if err != nil {
+			fmt.Printf("❌ Error encoding fragment: %v\n", err)
+			os.Exit(1)
+		}
+		stdout.Write(out)
+	},
+}
+
+func init() {
+	documentCmd.Flags().BoolVar(&documentStdin, "stdin", false, "Read the route source from stdin")
+	documentCmd.Flags().StringVar(&documentPath, "path", "", "Route the stdin source serves, e.g. /api/users/[id]")
+	documentCmd.Flags().StringVar(&documentType, "type", "ts", "File type of the stdin source: ts, js, tsx or jsx")
+	documentCmd.Flags().StringVar(&documentFormat, "format", "json", "Output format: json or yaml")
+	documentCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
+	documentCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	documentCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	documentCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache model responses in this directory (default the config's cache, or "+cache.DefaultDir+")")
+	documentCmd.Flags().BoolVar(&noCache, "no-cache", false, "Send every route to the model, neither reading nor filling the response cache")
+	rootCmd.AddCommand(documentCmd)
+}
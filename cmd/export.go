@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/graph"
+	"nextjs-to-openapi/internal/loadgen"
+	"nextjs-to-openapi/internal/specfile"
+	"nextjs-to-openapi/internal/testgen"
+)
+
+var (
+	exportInput  string
+	exportOutDir string
+	exportExt    string
+	exportForce  bool
+
+	loadFormat   string
+	loadOutput   string
+	loadVUs      int
+	loadDuration string
+	loadMethods  []string
+	loadRate     int
+
+	graphFormat string
+	graphOutput string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Generate artifacts from an existing spec",
+}
+
+var exportTestsCmd = &cobra.Command{
+	Use:   "tests",
+	Short: "Generate Jest/supertest contract test skeletons for every operation",
+	Long: `Writes one test file per path with a happy-path request and a skeleton for each
+documented error status. Existing files are left alone unless --force is given,
+so the generated tests can be edited freely.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportExt != "ts" && exportExt != "js" {
+			fmt.Printf("❌ --ext must be ts or js\n")
+			os.Exit(1)
+		}
+
+		spec, err := specfile.Read(exportInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		written, kept := 0, 0
+		for _, file := range testgen.Generate(spec, testgen.Options{Extension: exportExt}) {
+			path := filepath.Join(exportOutDir, filepath.FromSlash(file.Name))
+			if _, err := os.Stat(path); err == nil && !exportForce {
+				kept++
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				fmt.Printf("❌ Error creating %s: %v\n", filepath.Dir(path), err)
+				os.Exit(1)
+			}
+			if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+				fmt.Printf("❌ Error writing %s: %v\n", path, err)
+				os.Exit(1)
+			}
+			written++
+		}
+
+		fmt.Printf("✅ Wrote %d test files to %s", written, exportOutDir)
+		if kept > 0 {
+			fmt.Printf(" (%d existing files kept; use --force to overwrite)", kept)
+		}
+		fmt.Printf("\n")
+	},
+}
+
+var exportLoadTestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Generate a k6 or Artillery load-test script from the spec",
+	Long: `Generates a load-test script with one scenario per tag, using documented
+examples for path parameters, query strings and request bodies. Per-tag load can
+be set under loadTest.scenarios in the config file. Only GET operations are
+included unless --methods says otherwise.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := time.ParseDuration(loadDuration); err != nil {
+			fmt.Printf("❌ Invalid --duration %q\n", loadDuration)
+			os.Exit(1)
+		}
+
+		cfg := loadConfig(cmd)
+
+		spec, err := specfile.Read(exportInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		opts := loadgen.Options{
+			Scenarios:   cfg.LoadTest.Scenarios,
+			VUs:         loadVUs,
+			Duration:    loadDuration,
+			ArrivalRate: loadRate,
+		}
+		for _, m := range loadMethods {
+			opts.Methods = append(opts.Methods, strings.ToLower(m))
+		}
+
+		switch loadFormat {
+		case "k6":
+			if loadOutput == "" {
+				loadOutput = "loadtest.js"
+			}
+			err = os.WriteFile(loadOutput, []byte(loadgen.K6(spec, opts)), 0644)
+		case "artillery":
+			if loadOutput == "" {
+				loadOutput = "loadtest.yml"
+			}
+			err = specfile.Write(loadOutput, loadgen.Artillery(spec, opts))
+		default:
+			fmt.Printf("❌ --format must be k6 or artillery\n")
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Printf("❌ Error writing %s: %v\n", loadOutput, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s script written to %s\n", loadFormat, loadOutput)
+	},
+}
+
+var exportGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export a Mermaid or DOT diagram of the API surface",
+	Long: `Draws every path grouped by tag, with a badge per method and a lock on
+operations that require authentication. Paths are linked to their closest parent
+path, e.g. /api/users -> /api/users/{id}.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := specfile.Read(exportInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		var diagram string
+		switch graphFormat {
+		case "mermaid":
+			diagram = graph.Mermaid(spec)
+		case "dot":
+			diagram = graph.DOT(spec)
+		default:
+			fmt.Printf("❌ --format must be mermaid or dot\n")
+			os.Exit(1)
+		}
+
+		if graphOutput == "" || graphOutput == "-" {
+			fmt.Print(diagram)
+			return
+		}
+		if err := os.WriteFile(graphOutput, []byte(diagram), 0644); err != nil {
+			fmt.Printf("❌ Error writing %s: %v\n", graphOutput, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ %s diagram written to %s\n", graphFormat, graphOutput)
+	},
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVarP(&exportInput, "input", "i", "openapi.json", "Spec to export from")
+	exportTestsCmd.Flags().StringVar(&exportOutDir, "out-dir", "__tests__/api", "Directory to write test files to")
+	exportTestsCmd.Flags().StringVar(&exportExt, "ext", "ts", "Test file extension: ts or js")
+	exportTestsCmd.Flags().BoolVar(&exportForce, "force", false, "Overwrite existing test files")
+	exportCmd.AddCommand(exportTestsCmd)
+
+	exportLoadTestCmd.Flags().StringVar(&loadFormat, "format", "k6", "Script format: k6 or artillery")
+	exportLoadTestCmd.Flags().StringVarP(&loadOutput, "output", "o", "", "Output file (default loadtest.js or loadtest.yml)")
+	exportLoadTestCmd.Flags().IntVar(&loadVUs, "vus", 10, "Virtual users per tag unless configured")
+	exportLoadTestCmd.Flags().StringVar(&loadDuration, "duration", "30s", "Duration per tag unless configured")
+	exportLoadTestCmd.Flags().StringSliceVar(&loadMethods, "methods", []string{"get"}, "HTTP methods to include")
+	exportLoadTestCmd.Flags().IntVar(&loadRate, "rate", 5, "Artillery arrivals per second")
+	exportLoadTestCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	exportCmd.AddCommand(exportLoadTestCmd)
+
+	exportGraphCmd.Flags().StringVar(&graphFormat, "format", "mermaid", "Diagram format: mermaid or dot")
+	exportGraphCmd.Flags().StringVarP(&graphOutput, "output", "o", "", "Output file (stdout when empty)")
+	exportCmd.AddCommand(exportGraphCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/coverage"
+	"nextjs-to-openapi/internal/docs"
+	"nextjs-to-openapi/internal/postman"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	exportAllOut   string
+	artifactOutput string
+)
+
+// artifact is one file rendered from the spec
+type artifact struct {
+	name   string // Subcommand name
+	file   string // Default file name
+	short  string
+	render func(spec map[string]interface{}) ([]byte, error)
+}
+
+var artifacts = []artifact{
+	{"json", "openapi.json", "Write the spec as JSON", func(spec map[string]interface{}) ([]byte, error) {
+		return json.MarshalIndent(spec, "", "  ")
+	}},
+	{"yaml", "openapi.yaml", "Write the spec as YAML", func(spec map[string]interface{}) ([]byte, error) {
+		return specfile.MarshalYAML(spec)
+	}},
+	{"postman", "postman_collection.json", "Export a Postman v2.1 collection with example values", func(spec map[string]interface{}) ([]byte, error) {
+		return json.MarshalIndent(postman.Collection(spec), "", "  ")
+	}},
+	{"markdown", "API.md", "Export a Markdown API reference", func(spec map[string]interface{}) ([]byte, error) {
+		return []byte(docs.Markdown(spec)), nil
+	}},
+	{"html", "index.html", "Export a self-contained HTML API reference", func(spec map[string]interface{}) ([]byte, error) {
+		page, err := docs.HTML(spec)
+		return []byte(page), err
+	}},
+	{"coverage", "coverage.json", "Report how completely operations are documented", func(spec map[string]interface{}) ([]byte, error) {
+		return json.MarshalIndent(coverage.Measure(spec), "", "  ")
+	}},
+}
+
+var exportAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Write every artifact to one directory",
+	Long: `Reads the spec once and writes the JSON and YAML specs, a Postman collection,
+Markdown and HTML references and the documentation coverage report to --out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := specfile.Read(exportInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.MkdirAll(exportAllOut, 0755); err != nil {
+			fmt.Printf("❌ Error creating %s: %v\n", exportAllOut, err)
+			os.Exit(1)
+		}
+
+		for _, a := range artifacts {
+			path := filepath.Join(exportAllOut, a.file)
+			if err := writeArtifact(a, spec, path); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("📄 %s\n", path)
+		}
+		fmt.Printf("📊 %s\n", coverage.Measure(spec).Summary())
+		fmt.Printf("✅ Wrote %d artifacts to %s\n", len(artifacts), exportAllOut)
+	},
+}
+
+// writeArtifact renders one artifact to path
+func writeArtifact(a artifact, spec map[string]interface{}, path string) error {
+	data, err := a.render(spec)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %w", a.name, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// artifactCommand exports a single artifact, e.g. export postman
+func artifactCommand(a artifact) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   a.name,
+		Short: a.short,
+		Run: func(cmd *cobra.Command, args []string) {
+			spec, err := specfile.Read(exportInput)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			output := artifactOutput
+			if output == "" {
+				output = a.file
+			}
+			if err := writeArtifact(a, spec, output); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✅ %s written to %s\n", a.name, output)
+		},
+	}
+	cmd.Flags().StringVarP(&artifactOutput, "output", "o", "", "Output file (default "+a.file+")")
+	return cmd
+}
+
+func init() {
+	exportAllCmd.Flags().StringVar(&exportAllOut, "out", "dist", "Directory to write the artifacts to")
+	exportCmd.AddCommand(exportAllCmd)
+	for _, a := range artifacts {
+		if a.name == "json" || a.name == "yaml" {
+			continue // Already what the main command and specfile write
+		}
+		exportCmd.AddCommand(artifactCommand(a))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/diff"
+)
+
+var exportFlags []string
+
+// exportTarget is one artifact to write alongside the spec
+type exportTarget struct {
+	artifact artifact
+	path     string
+}
+
+// checkExports parses --export values of the form name[=file] against the
+// export subcommands' artifacts
+func checkExports() ([]exportTarget, error) {
+	var targets []exportTarget
+	for _, value := range exportFlags {
+		name, path, _ := strings.Cut(value, "=")
+		found := false
+		for _, a := range artifacts {
+			if a.name == name {
+				if path == "" {
+					path = a.file
+				}
+				targets = append(targets, exportTarget{a, path})
+				found = true
+				break
+			}
+		}
+		if !found {
+			var names []string
+			for _, a := range artifacts {
+				names = append(names, a.name)
+			}
+			return nil, fmt.Errorf("--export: unknown artifact %q (expected one of %s)", name, strings.Join(names, ", "))
+		}
+	}
+	return targets, nil
+}
+
+// writeExports renders each --export artifact from the written spec
+func writeExports(targets []exportTarget, spec interface{}) error {
+	generic, err := diff.ToMap(spec)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if err := writeArtifact(target.artifact, generic, target.path); err != nil {
+			return err
+		}
+		fmt.Printf("📦 Exported %s to %s\n", target.artifact.name, target.path)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.Flags().StringSliceVar(&exportFlags, "export", nil, "Also write an export artifact from the spec, as name[=file] (e.g. postman=collection.json); repeatable")
+}
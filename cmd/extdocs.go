@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nextjs-to-openapi/internal/extdocs"
+	"nextjs-to-openapi/internal/models"
+)
+
+var externalizeLength int
+
+// externalizeDescriptions moves operation descriptions over the configured
+// length into Markdown pages next to outputFile and returns the spec linking them
+func externalizeDescriptions(spec interface{}, settings models.ExternalDescriptions) (interface{}, error) {
+	if externalizeLength > 0 {
+		settings.MaxLength = externalizeLength
+	}
+	if settings.MaxLength <= 0 {
+		return spec, nil
+	}
+	if settings.Dir == "" {
+		settings.Dir = extdocs.DefaultDir
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+
+	pages := extdocs.Extract(generic, settings.MaxLength, filepath.ToSlash(settings.Dir), settings.BaseURL)
+	if len(pages) == 0 {
+		return generic, nil
+	}
+
+	dir := filepath.Join(filepath.Dir(outputFile), settings.Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	for _, page := range pages {
+		file := filepath.Join(dir, page.File)
+		if err := os.WriteFile(file, []byte(page.Content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+	fmt.Printf("📝 Moved %d long descriptions to %s\n", len(pages), dir)
+	return generic, nil
+}
+
+func init() {
+	rootCmd.Flags().IntVar(&externalizeLength, "externalize-descriptions", 0, "Move operation descriptions longer than this many characters to Markdown pages linked via externalDocs (0 keeps them inline)")
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+var polishGroups bool
+
+// polishGroupDescriptions has the model rewrite each folder overview once,
+// keeping the original text when that fails
+func polishGroupDescriptions(client llm.Provider, routes []models.APIRoute) {
+	polished := make(map[string]string) // Source -> overview
+	for _, route := range routes {
+		group := route.Group
+		if group == nil {
+			continue
+		}
+		if text, ok := polished[group.Source]; ok {
+			group.Description = text
+			continue
+		}
+		text, err := client.PolishGroupDescription(context.Background(), group.Name, group.Source, group.Description)
+		if err != nil {
+			fmt.Printf("⚠️ Keeping %s as written: %v\n", group.Source, err)
+			text = group.Description
+		} else {
+			fmt.Printf("✨ Polished the %s overview from %s\n", group.Name, group.Source)
+		}
+		polished[group.Source] = text
+		group.Description = text
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/archive"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	archiveDir   string
+	archiveLabel string
+	historyDir   string
+	showOutput   string
+)
+
+// archiveSpec stores the written spec in --archive-dir, stamped with the commit
+func archiveSpec(spec interface{}) error {
+	entry, stored, err := archive.Store(archiveDir, spec, archive.Commit(), archiveLabel, time.Now())
+	if err != nil {
+		return err
+	}
+	if !stored {
+		fmt.Printf("🗄️ Spec unchanged since %s, not archived again\n", entry.ID)
+		return nil
+	}
+	fmt.Printf("🗄️ Archived spec as %s in %s\n", entry.ID, archiveDir)
+	return nil
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the specs stored in the archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := archive.List(historyDir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			fmt.Printf("No archived specs in %s\n", historyDir)
+			return
+		}
+
+		fmt.Printf("%-26s  %-16s  %-9s  %-12s  %-10s  %s\n", "ID", "TIME", "COMMIT", "LABEL", "VERSION", "PATHS")
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			fmt.Printf("%-26s  %-16s  %-9s  %-12s  %-10s  %d\n",
+				entry.ID, entry.Time.Local().Format("2006-01-02 15:04"), dash(entry.Commit),
+				dash(entry.Label), dash(entry.Version), entry.Paths)
+		}
+	},
+}
+
+var showCmd = &cobra.Command{
+	Use:   "show <ref>",
+	Short: "Print an archived spec",
+	Long: `Prints the archived spec a reference points at. The reference is "latest",
+a label given with --archive-label (e.g. a release such as 1.8), the spec's
+info.version, a commit hash prefix or an archive ID prefix.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := archive.List(historyDir)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		entry, err := archive.Find(entries, args[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		if showOutput == "" {
+			data, err := archive.Load(historyDir, entry)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		// Re-encode so -o spec.yaml gets YAML
+		spec, err := specfile.Read(entry.File(historyDir))
+		if err == nil {
+			err = specfile.Write(showOutput, spec)
+		}
+		if err != nil {
+			fmt.Printf("❌ Error writing %s: %v\n", showOutput, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Spec %s written to: %s\n", entry.ID, showOutput)
+	},
+}
+
+// dash fills empty history columns
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&archiveDir, "archive-dir", "", "Also store each generated spec in this directory, stamped with time and commit")
+	rootCmd.Flags().StringVar(&archiveLabel, "archive-label", "", "Label for the archived spec, e.g. a release name, for show <label>")
+
+	historyCmd.Flags().StringVar(&historyDir, "archive-dir", archive.DefaultDir, "Archive directory")
+	rootCmd.AddCommand(historyCmd)
+
+	showCmd.Flags().StringVar(&historyDir, "archive-dir", archive.DefaultDir, "Archive directory")
+	showCmd.Flags().StringVarP(&showOutput, "output", "o", "", "Write the spec to this file (.yaml/.yml for YAML) instead of stdout")
+	rootCmd.AddCommand(showCmd)
+}
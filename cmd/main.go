@@ -1,142 +1,413 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"nextjs-to-openapi/internal/audit"
+	"nextjs-to-openapi/internal/breaker"
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/deployment"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/lock"
 	"nextjs-to-openapi/internal/models"
-	"nextjs-to-openapi/internal/ollama"
+	"nextjs-to-openapi/internal/nextconfig"
+	"nextjs-to-openapi/internal/openapi"
 	"nextjs-to-openapi/internal/scanner"
+	"nextjs-to-openapi/internal/specfile"
+	"nextjs-to-openapi/internal/throttle"
+	"nextjs-to-openapi/internal/tui"
 
 	"github.com/spf13/cobra"
 )
 
-// Simple OpenAPI structure
-type OpenAPISpec struct {
-	OpenAPI string                 `json:"openapi"`
-	Info    map[string]interface{} `json:"info"`
-	Paths   map[string]interface{} `json:"paths"`
+// routeFailure is a route that could not be documented, and why
+type routeFailure struct {
+	File string
+	Err  error
 }
 
-func buildOpenAPISpec(client *ollama.Client, routes []models.APIRoute) OpenAPISpec {
-	spec := OpenAPISpec{
-		OpenAPI: "3.0.0",
-		Info: map[string]interface{}{
-			"title":   "Next.js API Documentation",
-			"version": "1.0.0",
-		},
-		Paths: make(map[string]interface{}),
+// errSkipped is the reason given for routes skipped in the dashboard
+var errSkipped = errors.New("skipped in the dashboard")
+
+// documentWithTimeout documents one route: from its factory's template when one
+// applies, statically when triage finds it trivial, otherwise by asking the
+// model within --per-route-timeout
+func documentWithTimeout(client llm.Provider, builder *openapi.Builder, route models.APIRoute) (*llm.RouteDocumentation, error) {
+	if doc := builder.FactoryDocumentation(route); doc != nil {
+		fmt.Printf("🏭 %s: documented from the %s template\n", route.FilePath, route.Factory.Name)
+		return doc, nil
+	}
+	var provider llm.Provider = client
+	if routeTriage != nil {
+		class, classClient, doc := triaged(client, builder, route)
+		recordTriage(route, class)
+		if doc != nil {
+			fmt.Printf("⚡ %s: %s, documented without the model\n", route.FilePath, class)
+			return doc, nil
+		}
+		provider = classClient
+	}
+
+	if !historyInPrompt() {
+		route.RecentChanges = nil
+	}
+
+	ctx := context.Background()
+	if perRouteTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, perRouteTimeout)
+		defer cancel()
+	}
+
+	doc, err := provider.DocumentRoute(ctx, route)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("timed out after %s, skipped", perRouteTimeout)
+	}
+	return doc, err
+}
+
+// printFailures lists the routes missing from the spec at the end of a run
+func printFailures(failures []routeFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Printf("⚠️ %d routes could not be documented:\n", len(failures))
+	unavailable := false
+	for _, f := range failures {
+		fmt.Printf("   %s: %v\n", f.File, f.Err)
+		unavailable = unavailable || errors.Is(f.Err, llm.ErrProviderUnavailable)
 	}
+	if unavailable {
+		fmt.Printf("💡 %s\n", providerHint())
+	}
+}
 
-	fmt.Printf("\n🔄 Processing all %d routes...\n", len(routes))
+// documentRoute asks the model about one route that is not skipped, giving up
+// on it after --per-route-timeout. Safe to call from several goroutines;
+// assembleRoute adds the result to the spec.
+func documentRoute(client llm.Provider, builder *openapi.Builder, locks *lock.File, item documented) documented {
+	if builder.Excludes(item.route) || skipMinified(item.route) {
+		return item
+	}
+	if locks != nil {
+		locks.Keep(item.route)
+	}
+	item.doc, item.err = documentWithTimeout(client, builder, withExample(withPrevious(locks, item.route)))
+	return item
+}
 
-	for i, route := range routes {
-		fmt.Printf("Processing route %d/%d: %s\n", i+1, len(routes), route.FilePath)
+// withPrevious gives a changed route the accepted prose of its last version, so
+// the model updates it instead of rewriting it. Deterministic runs leave it out:
+// the prompt would differ once the lock file records the new version, missing the cache.
+func withPrevious(locks *lock.File, route models.APIRoute) models.APIRoute {
+	if locks == nil || determinism {
+		return route
+	}
+	if route.Previous = locks.Previous(route); route.Previous != nil {
+		fmt.Printf("✏️ %s changed; asking the model to update its accepted descriptions\n", route.FilePath)
+	}
+	return route
+}
+
+// printModuleStats reports how much parsing of shared validator files was reused
+func printModuleStats() {
+	if parsed, reused := scanner.ModuleStats(); parsed > 0 {
+		fmt.Printf("🧩 Parsed %d imported schema files once, reused %d times\n", parsed, reused)
+	}
+}
+
+// applyMiddlewareSecurity marks routes matched by an auth middleware as protected.
+// In a workspace the rules only apply to the app whose middleware declares
+// them, with its path prefix prepended to the matchers.
+func applyMiddlewareSecurity(builder *openapi.Builder, apiDir string, app models.WorkspaceApp) {
+	middleware, err := scanner.FindMiddleware(apiDir)
+	if err != nil || middleware == nil {
+		return
+	}
 
-		doc, err := client.DocumentRoute(route)
+	if !middleware.IsAuth || len(middleware.Matchers) == 0 {
+		fmt.Printf("ℹ️ Found %s but it does not look like auth middleware\n", middleware.FilePath)
+		return
+	}
+
+	fmt.Printf("🔒 Middleware %s protects: %s\n", middleware.FilePath, strings.Join(middleware.Matchers, ", "))
+
+	builder.EnsureDefaultScheme()
+	for _, pattern := range middleware.Matchers {
+		rule := openapi.SecurityRule{Pattern: strings.TrimSuffix(app.PathPrefix, "/") + pattern, App: app.Name, Scheme: openapi.DefaultBearerScheme}
+		if err := builder.AddSecurityRule(rule); err != nil {
+			fmt.Printf("⚠️ Skipping middleware matcher: %v\n", err)
+		}
+	}
+}
+
+// politeLimits describes the --max-rps and --max-inflight limits in effect
+func politeLimits() string {
+	var limits []string
+	if maxRPS > 0 {
+		limits = append(limits, fmt.Sprintf("at most %g requests/s", maxRPS))
+	}
+	if maxInflight > 0 {
+		limits = append(limits, fmt.Sprintf("at most %d in flight", maxInflight))
+	}
+	return strings.Join(limits, ", ")
+}
+
+// cachingEnabled reports whether model responses go through a cache: always in
+// deterministic mode, otherwise unless --no-cache is given
+func cachingEnabled() bool {
+	return determinism || !noCache
+}
+
+// newClient creates the model client configured by the global flags and the
+// project's cache config. The returned function releases the audit log.
+func newClient(cfg *models.Config) (*llm.Client, func()) {
+	backend, err := newBackend()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	client := llm.NewClient(backend, ollamaModel)
+	client.SetBreaker(breaker.New(breakerThreshold, breakerCooldown, retryBudget))
+	client.SetRetries(maxRetries, retryBackoff)
+	if maxRPS > 0 || maxInflight > 0 {
+		client.SetThrottle(throttle.New(maxRPS, maxInflight))
+		fmt.Printf("🐢 Polite mode: %s\n", politeLimits())
+	}
+
+	if simulateFailures != "" {
+		chaos, err := llm.ParseChaos(simulateFailures)
 		if err != nil {
-			fmt.Printf("⚠️ Error documenting %s: %v\n", route.FilePath, err)
-			continue
-		}
-
-		// Convert to proper OpenAPI structure
-		pathItem := make(map[string]interface{})
-		for method, details := range doc.Methods {
-			// Convert method to lowercase (OpenAPI requirement)
-			methodLower := strings.ToLower(method)
-
-			// Fix parameter structure
-			var fixedParams []map[string]interface{}
-			for _, param := range details.Parameters {
-				fixedParam := map[string]interface{}{
-					"name":     param.Name,
-					"in":       param.In,
-					"required": param.Required,
-					"schema": map[string]interface{}{
-						"type": param.Type,
-					},
-				}
-				fixedParams = append(fixedParams, fixedParam)
-			}
-
-			// Add required responses section
-			responses := map[string]interface{}{
-				"200": map[string]interface{}{
-					"description": "Successful response",
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]interface{}{
-								"type":        "object",
-								"description": "Response data",
-							},
-						},
-					},
-				},
-				"400": map[string]interface{}{
-					"description": "Bad request",
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]interface{}{
-								"type": "object",
-								"properties": map[string]interface{}{
-									"error": map[string]interface{}{
-										"type": "string",
-									},
-								},
-							},
-						},
-					},
-				},
-				"500": map[string]interface{}{
-					"description": "Internal server error",
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]interface{}{
-								"type": "object",
-								"properties": map[string]interface{}{
-									"error": map[string]interface{}{
-										"type": "string",
-									},
-								},
-							},
-						},
-					},
-				},
-			}
-
-			pathItem[methodLower] = map[string]interface{}{
-				"summary":     details.Summary,
-				"description": details.Description,
-				"parameters":  fixedParams,
-				"responses":   responses, // ✅ Required responses section
-			}
-		}
-
-		spec.Paths[doc.Path] = pathItem
-	}
-
-	return spec
-}
-
-func writeOpenAPIFile(filename string, spec OpenAPISpec) error {
-	data, err := json.MarshalIndent(spec, "", "  ")
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		client.SetChaos(chaos)
+		fmt.Printf("🎲 Simulating provider failures: %s\n", simulateFailures)
+	}
+
+	// Pin sampling and serve documentation from the cache
+	if determinism {
+		client.SetOptions(map[string]interface{}{"temperature": 0, "seed": deterministicSeed})
+	}
+	if cachingEnabled() {
+		settings := cfg.Cache
+		if cacheDir != "" {
+			settings.Backend, settings.Dir = cache.DefaultBackend, cacheDir
+		}
+		ttl, _ := time.ParseDuration(settings.TTL) // Validated with the config
+		responses, err := cache.Open(settings)
+		if err != nil {
+			fmt.Printf("❌ Error opening cache: %v\n", err)
+			os.Exit(1)
+		}
+		cacheLocation = cache.Location(settings)
+		client.SetCache(responses, ttl, determinism && !updateCache)
+		fmt.Printf("💾 Caching model responses in: %s\n", cacheLocation)
+	}
+
+	// Optionally record every outbound request
+	if auditLog == "" {
+		return client, func() {}
+	}
+	logger, err := audit.NewLogger(auditLog)
+	if err != nil {
+		fmt.Printf("❌ Error opening audit log: %v\n", err)
+		os.Exit(1)
+	}
+	client.SetAuditLogger(logger)
+	fmt.Printf("📝 Auditing outbound requests to: %s\n", auditLog)
+	return client, func() { logger.Close() }
+}
+
+// newBuilder creates a spec builder with the project's naming, security, header
+// and workspace settings applied
+func newBuilder(cfg *models.Config) (*openapi.Builder, error) {
+	builder := openapi.NewBuilder()
+	builder.SetSchemaNaming(cfg.Naming)
+	builder.SetVersion(cfg.SpecVersion)
+	builder.SetInfo(cfg.Info)
+	builder.SetWebhooks(cfg.Webhooks)
+	builder.SetSorted(determinism)
+	builder.SetPathTags(!noPathTags)
+	builder.SetDefaultResponses(cfg.Defaults)
+	builder.SetPathStyle(cfg.Paths)
+	builder.SetCodegenHints(cfg.Codegen)
+	builder.SetResponseEnvelope(cfg.Envelope)
+	builder.SetIdempotency(cfg.Idempotency)
+	builder.SetImplicitMethods(cfg.Implicit)
+	builder.SetVersioning(cfg.Versioning)
+	builder.SetCrudFactories(cfg.Factories)
+	builder.SetPathParamValues(cfg.ParamValues)
+	builder.SetExampleGeneration(models.ExampleGeneration{Generate: cfg.Examples.Generate || generateExamples})
+	builder.SetRecentChanges(gitHistory > 0 && historyMode != historyPrompt)
+	for _, prefix := range cfg.Prefixes {
+		builder.AddPathPrefix(prefix)
+	}
+	if cfg.Paths.TrailingSlash == nil {
+		if len(cfg.Workspace.Apps) == 0 {
+			applyNextConfig(builder, "", apiDir)
+		}
+		for _, app := range cfg.Workspace.Apps {
+			applyNextConfig(builder, app.Name, app.APIDir)
+		}
+	}
+	if err := builder.ApplySecurityConfig(cfg.Security); err != nil {
+		return nil, err
+	}
+	if err := builder.SetInternalRoutes(cfg.Internal); err != nil {
+		return nil, err
+	}
+	for _, rule := range cfg.Headers {
+		builder.AddHeaderRule(rule)
+	}
+	if len(cfg.Workspace.Apps) == 0 {
+		applyMiddlewareSecurity(builder, apiDir, models.WorkspaceApp{})
+		if err := applyDeploymentServers(builder, cfg); err != nil {
+			return nil, err
+		}
+	}
+	for _, app := range cfg.Workspace.Apps {
+		builder.AddWorkspaceApp(app)
+		applyMiddlewareSecurity(builder, app.APIDir, app)
+	}
+	return builder, nil
+}
+
+// applyDeploymentServers lists the production and preview URLs found in the
+// project's Vercel config and env files as servers, unless the config declares its own
+func applyDeploymentServers(builder *openapi.Builder, cfg *models.Config) error {
+	if len(cfg.Servers) > 0 {
+		for _, server := range cfg.Servers {
+			builder.AddServer(server.URL, server.Description)
+		}
+		return nil
+	}
+
+	environments, err := deployment.ParseEnvironments(cfg.Deployment.Environments)
 	if err != nil {
 		return err
 	}
+	if environments == nil {
+		environments = deployment.DefaultEnvironments
+	}
 
-	return os.WriteFile(filename, data, 0644)
+	servers, err := deployment.Find(apiDir, environments)
+	if err != nil {
+		fmt.Printf("⚠️ Could not read deployment config: %v\n", err)
+		return nil
+	}
+	for _, server := range servers {
+		fmt.Printf("🌐 %s server %s (from %s)\n", server.Description(), server.URL, server.Source)
+		builder.AddServer(server.URL, server.Description())
+	}
+	return nil
+}
+
+// applyNextConfig follows the trailingSlash setting of the app's next.config
+func applyNextConfig(builder *openapi.Builder, app, dir string) {
+	next, err := nextconfig.Find(dir)
+	if err != nil {
+		fmt.Printf("⚠️ Could not read next.config: %v\n", err)
+		return
+	}
+	if next != nil && next.TrailingSlash {
+		fmt.Printf("↪️ %s sets trailingSlash; paths end with /\n", next.Path)
+		builder.SetTrailingSlash(app, true)
+	}
+}
+
+// writeCheckpoint replaces the output with the spec documented so far. The file
+// is swapped in whole, so a viewer never reads it half-written.
+func writeCheckpoint(builder *openapi.Builder, done, total int) {
+	data, err := specfile.Encode(outputFile, builder.Spec())
+	if err == nil {
+		tmp := outputFile + ".partial"
+		if err = os.WriteFile(tmp, data, 0644); err == nil {
+			err = os.Rename(tmp, outputFile)
+		}
+	}
+	if err != nil {
+		fmt.Printf("⚠️ Could not write checkpoint: %v\n", err)
+		return
+	}
+	fmt.Printf("💾 Checkpoint: %d/%d routes written to %s\n", done, total, outputFile)
+}
+
+// writeOpenAPIFile writes a spec (typed or generic) as indented JSON, or as
+// YAML when the file name ends in .yaml or .yml
+func writeOpenAPIFile(filename string, spec interface{}) error {
+	return specfile.Write(filename, spec)
+}
+
+// checkSpecFormat reconciles --format with --output: yaml switches the default
+// output to openapi.yaml, and a format the output's extension contradicts is an error
+func checkSpecFormat(cmd *cobra.Command) error {
+	switch specFormat {
+	case "":
+	case "json":
+		if specfile.IsYAML(outputFile) {
+			return fmt.Errorf("--format json conflicts with --output %s", outputFile)
+		}
+	case "yaml":
+		if specfile.IsYAML(outputFile) {
+			return nil
+		}
+		if cmd.Flags().Changed("output") {
+			return fmt.Errorf("--format yaml needs an --output ending in .yaml or .yml, not %s", outputFile)
+		}
+		outputFile = strings.TrimSuffix(outputFile, filepath.Ext(outputFile)) + ".yaml"
+	default:
+		return fmt.Errorf("--format must be json or yaml")
+	}
+	return nil
 }
 
 var (
-	apiDir      string
-	outputFile  string
-	ollamaModel string
-	workers     int
-	ollamaURL   string
+	apiDir        string
+	outputFile    string
+	specFormat    string
+	ollamaModel   string
+	workers       int
+	ollamaURL     string
+	auditLog      string
+	configFile    string
+	lockFile      string
+	refreshLock   bool
+	cacheDir      string
+	noCache       bool
+	cacheLocation string
+	determinism   bool
+	updateCache   bool
+	sourceMap     string
+	useTUI        bool
+
+	perRouteTimeout time.Duration
+	checkpointEvery int
+
+	breakerThreshold int
+	breakerCooldown  time.Duration
+	retryBudget      int
+	maxRetries       int
+	retryBackoff     time.Duration
+	maxRPS           float64
+	maxInflight      int
+	simulateFailures string
+	generateExamples bool
+	serverEnvs       []string
 )
 
+// deterministicSeed is the fixed model seed used by --deterministic
+const deterministicSeed = 42
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -151,19 +422,71 @@ var rootCmd = &cobra.Command{
 OpenAPI specification using Ollama for intelligent documentation.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Printf("🚀 Starting Next.js to OpenAPI conversion...\n")
+
+		// Load project config (optional unless --config was given)
+		cfg := loadConfig(cmd)
+
 		fmt.Printf("API Directory: %s\n", apiDir)
 		fmt.Printf("Output File: %s\n", outputFile)
-		fmt.Printf("Ollama Model: %s\n", ollamaModel)
+		fmt.Printf("Model: %s (%s)\n", ollamaModel, providerName)
 		fmt.Printf("Workers: %d\n", workers)
 
-		// Create scanner and scan for routes
-		s := scanner.NewScanner(apiDir)
-		routes, err := s.ScanRoutes()
+		if determinism && (lockFile == "" || refreshLock) {
+			fmt.Printf("❌ --deterministic requires --lock-file and cannot be combined with --refresh-descriptions\n")
+			os.Exit(1)
+		}
+
+		outputs, err := splitOutputs(cfg)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		budget, err := sizeOptions(cfg)
+		if err == nil {
+			err = checkAudiences()
+		}
+		if err == nil {
+			err = checkSpecFormat(cmd)
+		}
+		if err == nil {
+			err = checkSpecVersion(cfg)
+		}
+		if err == nil {
+			err = checkSecurityFlags(cfg)
+		}
+		var exports []exportTarget
+		if err == nil {
+			exports, err = checkExports()
+		}
+		if err == nil {
+			err = checkMinifiedPolicy()
+		}
+		if err == nil {
+			err = checkHistoryMode()
+		}
+		if err == nil && archiveLabel != "" && archiveDir == "" {
+			err = fmt.Errorf("--archive-label requires --archive-dir")
+		}
+		if err == nil {
+			warned, err = newWarnings(cfg)
+		}
+		if err == nil && serverEnvs != nil {
+			cfg.Deployment.Environments = serverEnvs
+			if _, err = deployment.ParseEnvironments(serverEnvs); err != nil {
+				err = fmt.Errorf("--server-environments: %w", err)
+			}
+		}
 		if err != nil {
-			fmt.Printf("❌ Error scanning routes: %v\n", err)
+			fmt.Printf("❌ %v\n", err)
 			os.Exit(1)
 		}
 
+		// Find route files, or take the routes a scan job analyzed
+		routes, err := routesFrom(cfg)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
 		fmt.Printf("✅ Found %d routes\n", len(routes))
 
 		// Optional: Show route details (you can remove this debug section)
@@ -172,8 +495,21 @@ OpenAPI specification using Ollama for intelligent documentation.`,
 			for i, route := range routes {
 				fmt.Printf("%d. File: %s\n", i+1, route.FilePath)
 				fmt.Printf("   Type: %s\n", route.FileType)
-				fmt.Printf("   Content preview (first 50 chars): %s...\n",
-					route.Content[:min(50, len(route.Content))])
+				// Only analyzed routes (--from) know these yet
+				if len(route.QueryParams) > 0 {
+					names := make([]string, len(route.QueryParams))
+					for j, param := range route.QueryParams {
+						names[j] = param.Name
+					}
+					fmt.Printf("   Query: %s\n", strings.Join(names, ", "))
+				}
+				for _, page := range route.Pagination {
+					method := page.Method
+					if method == "" {
+						method = "GET"
+					}
+					fmt.Printf("   Paginated: %s returns a page of %s in %s\n", method, page.Model, page.Items)
+				}
 			}
 		}
 
@@ -182,31 +518,316 @@ OpenAPI specification using Ollama for intelligent documentation.`,
 			return
 		}
 
+		// Only document what a hand-written baseline does not cover
+		pending := routes
+		var baseline map[string]interface{}
+		if baselineFile != "" {
+			if baseline, err = loadBaseline(baselineFile); err != nil {
+				fmt.Printf("❌ Error loading baseline: %v\n", err)
+				os.Exit(1)
+			}
+			pending = undocumentedRoutes(routes, cfg, baseline)
+			fmt.Printf("📚 Baseline %s documents %d of %d routes\n", baselineFile, len(routes)-len(pending), len(routes))
+		}
+
+		if gitHistory > 0 {
+			attachRecentChanges(pending, cfg)
+		}
+
+		// Compare against the spec we are about to replace, before checkpoints overwrite it
+		var previous map[string]interface{}
+		if approvalMode || prCommentFile != "" || similarExamples || describeAPI || trackSchemas || mergeExisting {
+			previous, err = diff.LoadSpec(outputFile)
+			if err != nil {
+				fmt.Printf("❌ Error loading previous spec: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
 		// Create Ollama client
-		client := ollama.NewClient(ollamaURL, ollamaModel)
+		client, closeClient := newClient(cfg)
+		defer closeClient()
+		setupTriage(cfg)
+		if polishGroups {
+			polishGroupDescriptions(client, routes)
+		}
 
 		// Process all routes and build OpenAPI spec
 		fmt.Printf("\n🤖 Generating documentation for all routes...\n")
-		openAPISpec := buildOpenAPISpec(client, routes)
+		builder, err := newBuilder(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error applying security config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Reuse accepted prose for unchanged routes
+		var locks *lock.File
+		if lockFile != "" && refreshLock {
+			locks = lock.New()
+		} else if lockFile != "" {
+			if locks, err = lock.Load(lockFile); err != nil {
+				fmt.Printf("❌ Error loading lock file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if locks != nil && len(pending) < len(routes) {
+			// Routes covered by the baseline keep their entries
+			for _, route := range loadRoutes(coveredRoutes(routes, pending)) {
+				locks.Keep(route)
+			}
+		}
+
+		// Compare new and changed routes with accepted ones; deterministic runs
+		// leave examples out for the same reason as withPrevious
+		if similarExamples && locks == nil {
+			fmt.Printf("ℹ️ --similar-examples needs a lock file of accepted descriptions; skipping\n")
+		} else if similarExamples && !determinism {
+			pending = loadRoutes(pending)
+			routeExamples = newExampleIndex(pending, cfg, locks, previous)
+		}
+
+		// Optionally publish the spec so far while a long run continues
+		var progress func(done int)
+		if checkpointEvery > 0 {
+			progress = func(done int) {
+				if done%checkpointEvery == 0 && done < len(pending) {
+					writeCheckpoint(builder, done, len(pending))
+				}
+			}
+		}
+
+		var openAPISpec openapi.Spec
+		var failures []routeFailure
+		incomplete := 0 // Routes the dashboard ended with failed or skipped
+		if useTUI {
+			pending = loadRoutes(pending)
+			// The dashboard documents --workers routes at once and retries
+			// failed ones. Add routes to the spec in route order as they
+			// finish, like the plain pipeline, passing over failed ones; a
+			// retry that succeeds later is added when it finishes.
+			var mu sync.Mutex
+			index := make(map[string]int, len(pending))
+			for i, route := range pending {
+				index[route.FilePath] = i
+			}
+			results := make([]*documented, len(pending))
+			assembled := make([]bool, len(pending))
+			next, done := 0, 0
+			assemble := func(i int) {
+				assembled[i] = true
+				assembleRoute(builder, locks, *results[i])
+				if done++; progress != nil {
+					progress(done)
+				}
+			}
+			incomplete, err = tui.Run(pending, workers, func(route models.APIRoute) error {
+				item := documentRoute(client, builder, locks, documented{route: route})
+				mu.Lock()
+				defer mu.Unlock()
+				i := index[route.FilePath]
+				if assembled[i] {
+					return item.err
+				}
+				results[i] = &item
+				if item.err == nil && i < next {
+					assemble(i)
+				}
+				for ; next < len(pending) && results[next] != nil; next++ {
+					if results[next].err == nil {
+						assemble(next)
+					}
+				}
+				return item.err
+			})
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+			for i, item := range results {
+				switch {
+				case item == nil:
+					failures = append(failures, routeFailure{File: pending[i].FilePath, Err: errSkipped})
+				case item.err != nil:
+					failures = append(failures, routeFailure{File: item.route.FilePath, Err: item.err})
+				case !assembled[i]:
+					assemble(i)
+				}
+			}
+			openAPISpec = builder.Spec()
+		} else {
+			openAPISpec, failures = buildOpenAPISpec(client, builder, locks, pending, progress)
+		}
+		for _, name := range builder.MissingWebhookSchemas() {
+			fmt.Printf("⚠️ Webhook schema %s is not a component schema; its payload is documented as an object\n", name)
+		}
+		if baseline != nil {
+			if openAPISpec, err = applyBaseline(baseline, openAPISpec); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if mergeExisting {
+			if openAPISpec, err = preserveManualEdits(previous, openAPISpec); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if apiVersion != "" {
+			openAPISpec.Info["version"] = apiVersion
+		}
+		if tagDescriptions {
+			describeTags(client, openAPISpec)
+		}
+		if describeAPI {
+			describeSpec(client, openAPISpec, previous)
+		}
+		if trackSchemas {
+			if err := annotateSchemaChanges(openAPISpec, previous); err != nil {
+				fmt.Printf("❌ Error annotating schema changes: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if determinism && len(failures) > 0 {
+			printFailures(failures)
+			fmt.Printf("❌ %d routes could not be documented; refusing to write a partial spec in --deterministic mode\n", len(failures))
+			fmt.Printf("   Run locally with --deterministic --update-cache to fill %s\n", cacheLocation)
+			os.Exit(1)
+		}
+
+		if approvalMode {
+			if err := stageDescriptions(previous, openAPISpec); err != nil {
+				fmt.Printf("❌ Error staging descriptions: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if prCommentFile != "" {
+			if err := writePRComment(previous, openAPISpec); err != nil {
+				fmt.Printf("❌ Error writing PR comment: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
-		// Write to file
-		err = writeOpenAPIFile(outputFile, openAPISpec)
+		// Write to file with long descriptions moved out, shrunk to the size
+		// budget and filtered for the audience
+		written, err := externalizeDescriptions(openAPISpec, cfg.External)
+		if err == nil {
+			written, err = fitSpec(written, budget)
+		}
+		if err == nil {
+			written, err = writeAudiences(written, cfg.Redaction)
+		}
+		if err == nil {
+			if err = validateSpec(outputFile, written); err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(exitCode(err))
+			}
+			err = writeOpenAPIFile(outputFile, written)
+		}
 		if err != nil {
 			fmt.Printf("❌ Error writing OpenAPI file: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitCode(err))
+		}
+
+		if len(outputs) > 0 {
+			if err := writeSplitSpecs(outputs, written); err != nil {
+				fmt.Printf("❌ Error splitting spec: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+		}
+
+		if cfg.Versioning.Mode == openapi.VersioningSplit {
+			if err := writeVersionSpecs(written); err != nil {
+				fmt.Printf("❌ Error writing version specs: %v\n", err)
+				os.Exit(exitCode(err))
+			}
+		}
+
+		if len(exports) > 0 {
+			if err := writeExports(exports, written); err != nil {
+				fmt.Printf("❌ Error exporting: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if archiveDir != "" {
+			if err := archiveSpec(written); err != nil {
+				fmt.Printf("❌ Error archiving spec: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if locks != nil {
+			if err := locks.Save(lockFile); err != nil {
+				fmt.Printf("❌ Error writing lock file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if sourceMap != "" {
+			if err := writeOpenAPIFile(sourceMap, builder.SourceMap()); err != nil {
+				fmt.Printf("❌ Error writing source map: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("🗺️ Source map written to: %s\n", sourceMap)
 		}
 
 		fmt.Printf("✅ OpenAPI specification written to: %s\n", outputFile)
 		fmt.Printf("📁 File contains %d documented endpoints\n", len(openAPISpec.Paths))
+		printOwnershipSummary(openAPISpec)
+		printTriageSummary()
+		printUsage(client)
+		printFailures(failures)
+		finishWarnings()
+		if incomplete > 0 {
+			fmt.Printf("❌ %d routes failed or were skipped in the dashboard\n", incomplete)
+			os.Exit(1)
+		}
 	},
 }
 
 func init() {
 	rootCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes")
-	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "openapi.json", "Output file for OpenAPI specification")
+	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "openapi.json", "Output file for OpenAPI specification (YAML when it ends in .yaml or .yml)")
+	rootCmd.Flags().StringVar(&specFormat, "format", "", "Spec format: json or yaml (default from the --output extension)")
 	rootCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
-	rootCmd.Flags().IntVarP(&workers, "workers", "w", 3, "Number of worker goroutines")
+	rootCmd.Flags().IntVarP(&workers, "workers", "w", 3, "Number of routes documented by the model at once")
+	rootCmd.Flags().IntVar(&scanWorkers, "scan-workers", 4, "Number of route files read and analyzed at once")
+	rootCmd.Flags().IntVar(&queueSize, "queue-size", 16, "Routes buffered between pipeline stages")
 	rootCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	rootCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	rootCmd.Flags().StringVar(&lockFile, "lock-file", "", "Lock file of accepted descriptions, e.g. "+lock.DefaultFile+" (off unless set)")
+	rootCmd.Flags().BoolVar(&refreshLock, "refresh-descriptions", false, "Ignore locked descriptions and regenerate all prose")
+	rootCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache model responses in this directory (default the config's cache, or "+cache.DefaultDir+")")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Send every route to the model, neither reading nor filling the response cache")
+	rootCmd.Flags().BoolVar(&determinism, "deterministic", false, "Reproducible mode: temperature 0, fixed seed, sorted output, locked descriptions, cache required")
+	rootCmd.Flags().BoolVar(&updateCache, "update-cache", false, "With --deterministic, query the model for cache misses and store the results")
+	rootCmd.Flags().StringVar(&sourceMap, "source-map", "", "Write a map from operations and schemas to source locations (e.g. routes.map.json)")
+	rootCmd.Flags().BoolVar(&useTUI, "tui", false, "Show a live dashboard to skip, retry and inspect routes")
+	rootCmd.Flags().StringArrayVar(&splitRules, "split", nil, "Also write the paths matching a glob to their own spec, as PATTERN=FILE (repeatable)")
+	rootCmd.Flags().IntVar(&checkpointEvery, "checkpoint", 0, "Write the spec so far to --output after every N routes (0 only writes at the end)")
+	rootCmd.Flags().BoolVar(&generateExamples, "generate-examples", false, "Fill in realistic examples (emails, uuids, dates, enum members) where none were documented")
+	rootCmd.Flags().StringSliceVar(&serverEnvs, "server-environments", nil, "Deployment environments to list as servers: production, preview, development or none (default production)")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline", "", "Hand-written spec (Swagger 2.0 or OpenAPI 3) to keep; only undocumented routes are generated")
+	rootCmd.PersistentFlags().DurationVar(&perRouteTimeout, "per-route-timeout", 0, "Give up on a route after this long (e.g. 2m) and continue with the next; 0 waits indefinitely")
+	rootCmd.PersistentFlags().IntVar(&breakerThreshold, "breaker-threshold", 5, "Consecutive provider failures that pause dispatch (0 disables)")
+	rootCmd.PersistentFlags().DurationVar(&breakerCooldown, "breaker-cooldown", 10*time.Second, "First pause after the breaker opens; doubles on each consecutive trip")
+	rootCmd.PersistentFlags().IntVar(&retryBudget, "retry-budget", 20, "Total retries of failed provider calls per run")
+	rootCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", 3, "Retries of one route's failed or unparseable model call")
+	rootCmd.PersistentFlags().DurationVar(&retryBackoff, "retry-backoff", time.Second, "Wait before retrying a failed call; doubles on each further retry, with jitter (up to 30s)")
+	rootCmd.PersistentFlags().Float64Var(&maxRPS, "max-rps", 0, "Start at most this many model requests per second, e.g. 0.5 on a shared server (0 is unlimited)")
+	rootCmd.PersistentFlags().IntVar(&maxInflight, "max-inflight", 0, "Keep at most this many model requests outstanding, regardless of --workers (0 is unlimited)")
+	rootCmd.PersistentFlags().StringVar(&simulateFailures, "simulate-failures", "", "Fail provider calls on purpose for testing, e.g. rate=0.2,mode=malformed,seed=7")
+	rootCmd.PersistentFlags().MarkHidden("simulate-failures")
+	rootCmd.Flags().StringVar(&auditLog, "audit-log", "", "Append a record of every outbound LLM request to this file")
+	rootCmd.Flags().BoolVar(&triageEnabled, "triage", false, "Classify routes as trivial, standard or complex and document trivial ones without the model")
+	rootCmd.Flags().StringVar(&apiVersion, "api-version", "", "Set info.version, and name the version in x-changed-in annotations")
+	rootCmd.Flags().BoolVar(&trackSchemas, "track-schema-changes", false, "Stamp component schemas that changed since the previous spec with x-changed-in")
+	rootCmd.Flags().BoolVar(&describeAPI, "describe-api", false, "Have the model write info.description (overview, authentication, conventions) from the finished spec")
+	rootCmd.Flags().BoolVar(&polishGroups, "polish-tag-descriptions", false, "Have the model rewrite folder README overviews before using them as tag descriptions")
+	rootCmd.Flags().StringVar(&fromFile, "from", "", "Document the routes in a file written by scan instead of scanning --api-dir")
+	generateCmd.Flags().AddFlagSet(rootCmd.Flags())
+	rootCmd.AddCommand(generateCmd)
 }
 
 func main() {
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/merge"
+	"nextjs-to-openapi/internal/specfile"
+	"nextjs-to-openapi/internal/swagger"
+)
+
+var (
+	mergeOutput     string
+	mergeTagPrefix  []string
+	mergePrefixTags bool
+	mergeOnConflict string
+	mergeRename     bool
+	mergeTitle      string
+	mergeBase       string
+	mergeOurs       string
+	mergeTheirs     string
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <spec>...",
+	Short: "Combine several OpenAPI specs (JSON or YAML) into one",
+	Long: `Combines specs from several services into one document. Operations defined by
+more than one input and components with the same name but different content are
+reported as conflicts. Swagger 2.0 inputs are upgraded to OpenAPI 3 first.
+
+With --base, --ours and --theirs it instead merges two edits of the same spec,
+such as openapi.json regenerated on two branches, one operation, component, tag
+or server at a time. It can be registered as a git merge driver.`,
+	Example: `  nextjs-to-openapi merge a.json b.yaml -o combined.yaml
+  nextjs-to-openapi merge billing.json users.json --prefix-tags --rename-schemas
+  nextjs-to-openapi merge --base %O --ours %A --theirs %B -o %A`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if mergeBase != "" || mergeOurs != "" || mergeTheirs != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if mergeBase != "" || mergeOurs != "" || mergeTheirs != "" {
+			mergeThreeWay()
+			return
+		}
+		switch mergeOnConflict {
+		case merge.OnConflictError, merge.OnConflictFirst, merge.OnConflictLast:
+		default:
+			fmt.Printf("❌ Unknown --on-conflict %q (use error, first or last)\n", mergeOnConflict)
+			os.Exit(1)
+		}
+		if len(mergeTagPrefix) > len(args) {
+			fmt.Printf("❌ Got %d --tag-prefix values for %d specs\n", len(mergeTagPrefix), len(args))
+			os.Exit(1)
+		}
+
+		var inputs []merge.Input
+		for i, path := range args {
+			spec, err := specfile.Read(path)
+			if err == nil && swagger.IsV2(spec) {
+				fmt.Printf("⬆️ Upgrading %s from Swagger 2.0\n", path)
+				spec, err = swagger.Convert(spec, swagger.Options{})
+			}
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				os.Exit(1)
+			}
+
+			in := merge.Input{Name: path, Spec: spec}
+			stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			if i < len(mergeTagPrefix) {
+				in.TagPrefix = mergeTagPrefix[i]
+			} else if mergePrefixTags {
+				in.TagPrefix = stem
+			}
+			if mergeRename {
+				in.SchemaPrefix = stem
+				if in.TagPrefix != "" {
+					in.SchemaPrefix = in.TagPrefix
+				}
+			}
+			inputs = append(inputs, in)
+			fmt.Printf("📄 %s: %d paths\n", path, len(mapAt(spec, "paths")))
+		}
+
+		merged, conflicts := merge.Merge(inputs, merge.Options{OnConflict: mergeOnConflict})
+		for _, c := range conflicts {
+			fmt.Printf("⚠️ Conflict: %s\n", c)
+		}
+		if merged == nil {
+			fmt.Printf("❌ %d conflicts; resolve them or pass --on-conflict first|last or --rename-schemas\n", len(conflicts))
+			os.Exit(1)
+		}
+
+		if mergeTitle != "" {
+			info := mapAt(merged, "info")
+			info["title"] = mergeTitle
+			merged["info"] = info
+		}
+
+		if err := specfile.Write(mergeOutput, merged); err != nil {
+			fmt.Printf("❌ Error writing merged spec: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Merged %d specs (%d paths) into %s\n", len(inputs), len(mapAt(merged, "paths")), mergeOutput)
+	},
+}
+
+// mergeThreeWay merges --ours and --theirs against --base. Unresolved conflicts
+// keep our side in the output and exit 1, as git expects from a merge driver.
+func mergeThreeWay() {
+	if mergeBase == "" || mergeOurs == "" || mergeTheirs == "" {
+		fmt.Println("❌ A three-way merge needs --base, --ours and --theirs")
+		os.Exit(1)
+	}
+	strategy := mergeOnConflict
+	switch mergeOnConflict {
+	case merge.OnConflictError:
+		strategy = merge.OnConflictOurs
+	case merge.OnConflictOurs, merge.OnConflictTheirs:
+	default:
+		fmt.Printf("❌ Unknown --on-conflict %q for a three-way merge (use error, ours or theirs)\n", mergeOnConflict)
+		os.Exit(1)
+	}
+
+	var specs []map[string]interface{}
+	for _, path := range []string{mergeBase, mergeOurs, mergeTheirs} {
+		spec, err := specfile.Read(path)
+		if err == nil && swagger.IsV2(spec) {
+			spec, err = swagger.Convert(spec, swagger.Options{})
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		specs = append(specs, spec)
+	}
+
+	merged, conflicts := merge.ThreeWay(specs[0], specs[1], specs[2], merge.Options{OnConflict: strategy})
+	if mergeTitle != "" {
+		info := mapAt(merged, "info")
+		info["title"] = mergeTitle
+		merged["info"] = info
+	}
+	if err := specfile.Write(mergeOutput, merged); err != nil {
+		fmt.Printf("❌ Error writing merged spec: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, c := range conflicts {
+		fmt.Printf("⚠️ Conflict: %s\n", c)
+	}
+	if len(conflicts) > 0 && mergeOnConflict == merge.OnConflictError {
+		fmt.Printf("❌ %d conflicts; kept our side in %s. Regenerate the affected routes, e.g. with regenerate --path-prefix\n", len(conflicts), mergeOutput)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Merged %d paths into %s\n", len(mapAt(merged, "paths")), mergeOutput)
+}
+
+// mapAt returns a nested map, or an empty one when missing
+func mapAt(m map[string]interface{}, key string) map[string]interface{} {
+	if child, ok := m[key].(map[string]interface{}); ok {
+		return child
+	}
+	return map[string]interface{}{}
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "openapi.json", "Output file (.yaml/.yml for YAML)")
+	mergeCmd.Flags().StringSliceVar(&mergeTagPrefix, "tag-prefix", nil, "Tag prefix for each input, in order (repeatable)")
+	mergeCmd.Flags().BoolVar(&mergePrefixTags, "prefix-tags", false, "Prefix each input's tags with its file name")
+	mergeCmd.Flags().StringVar(&mergeOnConflict, "on-conflict", merge.OnConflictError, "How to resolve conflicts: error, first or last (error, ours or theirs with --base)")
+	mergeCmd.Flags().BoolVar(&mergeRename, "rename-schemas", false, "Rename clashing components with the input's prefix instead of failing")
+	mergeCmd.Flags().StringVar(&mergeTitle, "title", "", "Title of the merged spec (defaults to the first input's)")
+	mergeCmd.Flags().StringVar(&mergeBase, "base", "", "Common ancestor for a three-way merge of --ours and --theirs")
+	mergeCmd.Flags().StringVar(&mergeOurs, "ours", "", "Our edit of the spec in a three-way merge")
+	mergeCmd.Flags().StringVar(&mergeTheirs, "theirs", "", "Their edit of the spec in a three-way merge")
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Policies for route files that turn out to be minified or bundled
+const (
+	minifiedBeautify = "beautify"
+	minifiedSkip     = "skip"
+)
+
+var minifiedFiles string
+
+// checkMinifiedPolicy validates --minified-files
+func checkMinifiedPolicy() error {
+	switch minifiedFiles {
+	case minifiedBeautify, minifiedSkip:
+		return nil
+	}
+	return fmt.Errorf("--minified-files must be %s or %s", minifiedBeautify, minifiedSkip)
+}
+
+// skipMinified reports whether a route is left out because its file is minified
+func skipMinified(route models.APIRoute) bool {
+	return route.Minified && minifiedFiles == minifiedSkip
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&minifiedFiles, "minified-files", minifiedBeautify, "What to do with minified or bundled route files: beautify them before prompting, or skip them")
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/openapi"
+	"nextjs-to-openapi/internal/overview"
+)
+
+var describeAPI bool
+
+// describeSpec writes info.description from a digest of the finished spec,
+// updating the previous spec's overview rather than starting over. A
+// description that is already set, e.g. by a baseline, is kept.
+func describeSpec(client llm.Provider, spec openapi.Spec, previous map[string]interface{}) {
+	if description, _ := spec.Info["description"].(string); description != "" {
+		fmt.Printf("ℹ️ info.description is already set; not generating an overview\n")
+		return
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		fmt.Printf("⚠️ Could not describe the API: %v\n", err)
+		return
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		fmt.Printf("⚠️ Could not describe the API: %v\n", err)
+		return
+	}
+	info, _ := previous["info"].(map[string]interface{})
+	before, _ := info["description"].(string)
+
+	text, err := client.DescribeAPI(context.Background(), overview.Digest(generic), before)
+	if err != nil {
+		fmt.Printf("⚠️ Could not describe the API: %v\n", err)
+		return
+	}
+	spec.Info["description"] = text
+	fmt.Printf("📖 Wrote an API overview to info.description\n")
+}
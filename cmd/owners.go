@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"nextjs-to-openapi/internal/codeowners"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+// assignOwners stamps each route with its owners from the CODEOWNERS file
+// nearest to its app's API directory, so apps of a workspace living in
+// separate repositories each use their own
+func assignOwners(routes []models.APIRoute, cfg *models.Config) {
+	dirs := make(map[string]string)
+	for _, app := range cfg.Workspace.Apps {
+		dirs[app.Name] = app.APIDir
+	}
+
+	files := make(map[string]*codeowners.File)
+	for i := range routes {
+		dir, ok := dirs[routes[i].App]
+		if !ok {
+			dir = apiDir
+		}
+		owners, found := files[dir]
+		if !found {
+			var err error
+			if owners, err = codeowners.Find(dir); err != nil {
+				fmt.Printf("⚠️ Could not read CODEOWNERS for %s: %v\n", dir, err)
+			}
+			files[dir] = owners
+		}
+		if owners != nil {
+			routes[i].Owners = owners.Owners(routes[i].FilePath)
+		}
+	}
+}
+
+// printOwnershipSummary shows how many operations each owner has in the spec
+func printOwnershipSummary(spec openapi.Spec) {
+	counts := make(map[string]int)
+	for _, item := range spec.Paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, op := range pathItem {
+			operation, ok := op.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			owner, ok := operation["x-owner"].(string)
+			if !ok {
+				owner = "(unowned)"
+			}
+			counts[owner]++
+		}
+	}
+
+	if len(counts) == 1 && counts["(unowned)"] > 0 {
+		return
+	}
+
+	owners := make([]string, 0, len(counts))
+	for owner := range counts {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+
+	fmt.Printf("\n👥 Operations by owner:\n")
+	for _, owner := range owners {
+		fmt.Printf("   %s: %d\n", owner, counts[owner])
+	}
+}
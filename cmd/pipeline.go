@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/lock"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+	"nextjs-to-openapi/internal/scanner"
+)
+
+var (
+	scanWorkers int
+	queueSize   int
+)
+
+// documented is one route leaving the document stage
+type documented struct {
+	index int
+	route models.APIRoute
+	doc   *llm.RouteDocumentation
+	err   error
+}
+
+// buildOpenAPISpec documents every route and returns the spec along with the
+// routes that could not be documented. A non-nil progress is called after each route.
+//
+// The stages run as a pipeline connected by channels holding --queue-size
+// routes: --scan-workers read and analyze route files, --workers ask the model,
+// and a single assembler adds the results to the spec in route order, so slow
+// model calls overlap with static analysis without the spec depending on which
+// call finishes first.
+func buildOpenAPISpec(client llm.Provider, builder *openapi.Builder, locks *lock.File, routes []models.APIRoute, progress func(done int)) (openapi.Spec, []routeFailure) {
+	fmt.Printf("\n🔄 Processing all %d routes...\n", len(routes))
+
+	queue := max(1, queueSize)
+	indexes := make(chan int, queue)
+	analyzed := make(chan documented, queue)
+	results := make(chan documented, queue)
+
+	go func() {
+		for i := range routes {
+			indexes <- i
+		}
+		close(indexes)
+	}()
+
+	var scanning sync.WaitGroup
+	var scanned atomic.Bool
+	for range max(1, min(scanWorkers, len(routes))) {
+		scanning.Add(1)
+		go func() {
+			defer scanning.Done()
+			for i := range indexes {
+				route, err := routes[i], error(nil)
+				// Routes scanned up front are already analyzed
+				if route.Content == "" {
+					route, err = scanner.Load(route)
+					scanned.Store(true)
+				}
+				analyzed <- documented{index: i, route: route, err: err}
+			}
+		}()
+	}
+	go func() {
+		scanning.Wait()
+		close(analyzed)
+	}()
+
+	var documenting sync.WaitGroup
+	for range max(1, min(workers, len(routes))) {
+		documenting.Add(1)
+		go func() {
+			defer documenting.Done()
+			for item := range analyzed {
+				if item.err == nil {
+					if !builder.Excludes(item.route) && !skipMinified(item.route) {
+						fmt.Printf("Processing route %d/%d: %s\n", item.index+1, len(routes), item.route.FilePath)
+					}
+					item = documentRoute(client, builder, locks, item)
+				}
+				results <- item
+			}
+		}()
+	}
+	go func() {
+		documenting.Wait()
+		close(results)
+	}()
+
+	// Assemble in route order; results that arrive early wait for their turn
+	var failures []routeFailure
+	waiting := make(map[int]documented)
+	next := 0
+	for item := range results {
+		waiting[item.index] = item
+		for {
+			item, ok := waiting[next]
+			if !ok {
+				break
+			}
+			delete(waiting, next)
+			next++
+
+			if err := assembleRoute(builder, locks, item); err != nil {
+				fmt.Printf("⚠️ Error documenting %s: %v\n", item.route.FilePath, err)
+				failures = append(failures, routeFailure{File: item.route.FilePath, Err: err})
+			}
+			if progress != nil {
+				progress(next)
+			}
+		}
+	}
+
+	if scanned.Load() {
+		printModuleStats()
+	}
+	return builder.Spec(), failures
+}
+
+// assembleRoute adds one documented route to the spec
+func assembleRoute(builder *openapi.Builder, locks *lock.File, item documented) error {
+	if item.err != nil {
+		return item.err
+	}
+	if builder.Excludes(item.route) {
+		fmt.Printf("⏭️ Skipping internal route %s\n", item.route.Path)
+		return nil
+	}
+	if skipMinified(item.route) {
+		fmt.Printf("⏭️ Skipping minified %s\n", item.route.FilePath)
+		return nil
+	}
+	if locks != nil && locks.Apply(item.route, item.doc) {
+		fmt.Printf("🔒 %s unchanged, keeping accepted descriptions\n", item.route.FilePath)
+	}
+
+	checkRoute(item.route, item.doc)
+	builder.AddRoute(item.route, item.doc)
+	return nil
+}
+
+// discoverAll lists the route files in the API directory and --pages-dir, or
+// in every app when a workspace is configured, without reading them
+func discoverAll(cfg *models.Config) ([]models.APIRoute, error) {
+	if len(cfg.Workspace.Apps) == 0 {
+		routes, err := scanner.NewScanner(apiDir).Discover()
+		if err != nil {
+			return nil, err
+		}
+		if pagesDir != "" {
+			pages, err := scanner.NewScanner(pagesDir).Discover()
+			if err != nil {
+				return nil, fmt.Errorf("pages router: %w", err)
+			}
+			fmt.Printf("📄 %d pages router routes in %s\n", len(pages), pagesDir)
+			routes = append(routes, pages...)
+		}
+		return resolveRouterConflicts(routes)
+	}
+
+	var routes []models.APIRoute
+	for _, app := range cfg.Workspace.Apps {
+		appRoutes, err := scanner.NewScanner(app.APIDir).Discover()
+		if err != nil {
+			return nil, fmt.Errorf("app %s: %w", app.Name, err)
+		}
+		fmt.Printf("📦 App %s: %d routes in %s\n", app.Name, len(appRoutes), app.APIDir)
+		for i := range appRoutes {
+			appRoutes[i].App = app.Name
+		}
+		routes = append(routes, appRoutes...)
+	}
+	return resolveRouterConflicts(routes)
+}
+
+// scanAll discovers and analyzes every route before anything is documented
+func scanAll(cfg *models.Config) ([]models.APIRoute, error) {
+	routes, err := discoverAll(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return loadRoutes(routes), nil
+}
+
+// loadRoutes analyzes discovered routes with --scan-workers, dropping files
+// that cannot be read or are not source code. Routes that are already analyzed are kept as they are.
+func loadRoutes(routes []models.APIRoute) []models.APIRoute {
+	defer printModuleStats()
+
+	loaded := make([]models.APIRoute, len(routes))
+	ok := make([]bool, len(routes))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range max(1, min(scanWorkers, len(routes))) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if routes[i].Content != "" {
+					loaded[i], ok[i] = routes[i], true
+					continue
+				}
+				route, err := scanner.Load(routes[i])
+				if err != nil {
+					fmt.Printf("⚠️ Skipping %v\n", err)
+				}
+				loaded[i], ok[i] = route, err == nil
+			}
+		}()
+	}
+	for i := range routes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var kept []models.APIRoute
+	for i, route := range loaded {
+		if ok[i] {
+			kept = append(kept, route)
+		}
+	}
+	return kept
+}
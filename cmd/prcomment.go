@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/github"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var (
+	prCommentFile string
+	prCommentPost bool
+	prNumber      int
+)
+
+// writePRComment renders the diff between the previous and new spec as Markdown,
+// writing it to --pr-comment and optionally posting it to the pull request
+func writePRComment(previous map[string]interface{}, spec openapi.Spec) error {
+	current, err := diff.ToMap(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+
+	body := diff.Markdown(diff.Compare(previous, current))
+
+	if prCommentFile == "-" {
+		fmt.Println(body)
+	} else if err := os.WriteFile(prCommentFile, []byte(body), 0644); err != nil {
+		return fmt.Errorf("failed to write PR comment: %w", err)
+	} else {
+		fmt.Printf("💬 PR comment written to: %s\n", prCommentFile)
+	}
+
+	if !prCommentPost {
+		return nil
+	}
+
+	client, err := github.NewClientFromEnv()
+	if err != nil {
+		return err
+	}
+	pr := prNumber
+	if pr == 0 {
+		if pr, err = github.PullRequestFromEnv(); err != nil {
+			return err
+		}
+	}
+	if err := client.UpsertComment(pr, diff.CommentMarker, body); err != nil {
+		return err
+	}
+
+	fmt.Printf("💬 Posted spec diff to pull request #%d\n", pr)
+	return nil
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&prCommentFile, "pr-comment", "", "Write the spec diff as a Markdown PR comment to this file (- for stdout)")
+	rootCmd.Flags().BoolVar(&prCommentPost, "pr-comment-post", false, "Post the PR comment via the GitHub API (uses GITHUB_TOKEN, GITHUB_REPOSITORY)")
+	rootCmd.Flags().IntVar(&prNumber, "pr-number", 0, "Pull request number for --pr-comment-post (defaults to GITHUB_REF)")
+}
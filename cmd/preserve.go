@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"nextjs-to-openapi/internal/merge"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var mergeExisting bool
+
+// preserveManualEdits keeps the hand edits of the spec being replaced: objects
+// marked x-manual, including paths the generator does not produce
+func preserveManualEdits(existing map[string]interface{}, generated openapi.Spec) (openapi.Spec, error) {
+	if len(existing) == 0 {
+		return generated, nil
+	}
+	data, err := json.Marshal(generated)
+	if err != nil {
+		return generated, err
+	}
+	var fresh map[string]interface{}
+	if err := json.Unmarshal(data, &fresh); err != nil {
+		return generated, err
+	}
+
+	kept := merge.PreserveManual(existing, fresh)
+
+	if data, err = json.Marshal(fresh); err != nil {
+		return generated, err
+	}
+	var spec openapi.Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return generated, fmt.Errorf("failed to merge with %s: %w", outputFile, err)
+	}
+	fmt.Printf("🧷 Kept %d hand-written paths, %d components and %d x-manual edits from %s\n", kept.Paths, kept.Components, kept.Manual, outputFile)
+	if kept.Dropped > 0 {
+		fmt.Printf("🗑️ Dropped %d paths whose routes are gone; mark a path x-manual to keep it\n", kept.Dropped)
+	}
+	return spec, nil
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&mergeExisting, "merge", false, "Keep objects marked x-manual: true, including paths the generator does not produce, from the existing output")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/ollama"
+	"nextjs-to-openapi/internal/openai"
+)
+
+// Model servers --provider can talk to
+const (
+	providerOllama = "ollama"
+	providerOpenAI = "openai"
+)
+
+var (
+	providerName string
+	openAIURL    string
+	apiKeyEnv    string
+)
+
+// newBackend creates the backend chosen by --provider. The OpenAI-compatible
+// backend reads its API key from the environment variable named by --api-key-env.
+func newBackend() (llm.Backend, error) {
+	switch providerName {
+	case providerOllama:
+		return ollama.New(ollamaURL), nil
+	case providerOpenAI:
+		key := os.Getenv(apiKeyEnv)
+		if key == "" {
+			fmt.Printf("⚠️ $%s is not set; sending requests without an API key\n", apiKeyEnv)
+		}
+		fmt.Printf("🌐 OpenAI-compatible server: %s\n", openAIURL)
+		return openai.New(openAIURL, key), nil
+	}
+	return nil, fmt.Errorf("--provider must be %s or %s", providerOllama, providerOpenAI)
+}
+
+// providerHint suggests what to check when the provider could not be reached
+func providerHint() string {
+	if providerName == providerOpenAI {
+		return fmt.Sprintf("Check that %s serves %s and that $%s holds a valid API key", openAIURL, ollamaModel, apiKeyEnv)
+	}
+	return fmt.Sprintf("Check that Ollama is running at %s and serves %s", ollamaURL, ollamaModel)
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", providerOllama, "Model server: ollama, or openai for any OpenAI-compatible /v1/chat/completions API (OpenAI, vLLM, LM Studio, Groq)")
+	rootCmd.PersistentFlags().StringVar(&openAIURL, "openai-url", openai.DefaultURL, "Base URL of the OpenAI-compatible API, e.g. http://localhost:1234/v1 for LM Studio")
+	rootCmd.PersistentFlags().StringVar(&apiKeyEnv, "api-key-env", "OPENAI_API_KEY", "Environment variable holding the API key for --provider openai")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"nextjs-to-openapi/internal/githistory"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Where the commits read by --git-history go
+const (
+	historyPrompt = "prompt"
+	historySpec   = "spec"
+	historyBoth   = "both"
+)
+
+var (
+	gitHistory  int
+	historyMode string
+)
+
+// checkHistoryMode validates --git-history-in
+func checkHistoryMode() error {
+	switch historyMode {
+	case historyPrompt, historySpec, historyBoth:
+		return nil
+	}
+	return fmt.Errorf("--git-history-in must be %s, %s or %s", historyPrompt, historySpec, historyBoth)
+}
+
+// historyInPrompt reports whether the model sees a route's recent commits.
+// Deterministic runs leave them out, like withPrevious, so the prompt matches
+// the one warm-cache sent.
+func historyInPrompt() bool {
+	return historyMode != historySpec && !determinism
+}
+
+// attachRecentChanges stamps each route with the newest commits touching its file
+func attachRecentChanges(routes []models.APIRoute, cfg *models.Config) {
+	dirs := []string{apiDir}
+	if len(cfg.Workspace.Apps) > 0 {
+		dirs = nil
+		for _, app := range cfg.Workspace.Apps {
+			dirs = append(dirs, app.APIDir)
+		}
+	}
+
+	changes := make(map[string][]models.RecentChange)
+	for _, dir := range dirs {
+		recent, err := githistory.Recent(dir, gitHistory)
+		if err != nil {
+			fmt.Printf("⚠️ Could not read git history: %v\n", err)
+			return
+		}
+		for path, commits := range recent {
+			changes[path] = commits
+		}
+	}
+
+	found := 0
+	for i := range routes {
+		path, err := filepath.Abs(routes[i].FilePath)
+		if err != nil {
+			continue
+		}
+		if commits := changes[path]; len(commits) > 0 {
+			routes[i].RecentChanges = commits
+			found++
+		}
+	}
+	fmt.Printf("📜 Read recent commits for %d of %d routes\n", found, len(routes))
+}
+
+func init() {
+	rootCmd.Flags().IntVar(&gitHistory, "git-history", 0, "Read this many recent commits touching each route file (0 to disable)")
+	rootCmd.Flags().StringVar(&historyMode, "git-history-in", historyPrompt, "Where the commits go: prompt (context for the model), spec (x-recent-changes) or both")
+}
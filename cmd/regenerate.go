@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/lock"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var (
+	regenerateTag    string
+	regeneratePrefix string
+)
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// samePath compares two URL templates, ignoring parameter names, case and a
+// trailing slash, which path normalization may have changed
+func samePath(a, b string) bool {
+	a = strings.TrimSuffix(pathParam.ReplaceAllString(a, "{}"), "/")
+	b = strings.TrimSuffix(pathParam.ReplaceAllString(b, "{}"), "/")
+	return strings.EqualFold(a, b)
+}
+
+// taggedPaths lists the paths in a spec with at least one operation carrying tag
+func taggedPaths(spec map[string]interface{}, tag string) []string {
+	var matched []string
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, _ := item.(map[string]interface{})
+		for _, op := range operations {
+			operation, _ := op.(map[string]interface{})
+			tags, _ := operation["tags"].([]interface{})
+			if containsTag(tags, tag) {
+				matched = append(matched, path)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func containsTag(tags []interface{}, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// routeURL is the path a route is published under, including its app's prefix
+// and any configured path prefix rewrite
+func routeURL(route models.APIRoute, apps map[string]models.WorkspaceApp, prefixes []models.PathPrefix) string {
+	path := route.Path
+	if app, ok := apps[route.App]; ok {
+		path = strings.TrimSuffix(app.PathPrefix, "/") + path
+	}
+	path, _ = openapi.RewritePrefix(path, prefixes)
+	return path
+}
+
+// selectRoutes picks the routes served under the path prefix or tagged with the tag
+func selectRoutes(routes []models.APIRoute, cfg *models.Config, existing map[string]interface{}) []models.APIRoute {
+	apps := make(map[string]models.WorkspaceApp)
+	for _, app := range cfg.Workspace.Apps {
+		apps[app.Name] = app
+	}
+	tagged := taggedPaths(existing, regenerateTag)
+
+	var selected []models.APIRoute
+	for _, route := range routes {
+		app, inApp := apps[route.App]
+		path := routeURL(route, apps, cfg.Prefixes)
+
+		if regeneratePrefix != "" && !strings.HasPrefix(path, regeneratePrefix) {
+			continue
+		}
+		if regenerateTag != "" {
+			match := inApp && (app.TagPrefix == regenerateTag || (app.TagPrefix == "" && app.Name == regenerateTag))
+			for _, p := range tagged {
+				if samePath(p, path) {
+					match = true
+				}
+			}
+			if !match {
+				continue
+			}
+		}
+		selected = append(selected, route)
+	}
+	return selected
+}
+
+// mergeSpec overlays freshly generated paths, components and tags onto an existing spec
+func mergeSpec(existing map[string]interface{}, fresh openapi.Spec) (map[string]interface{}, error) {
+	generated, err := diff.ToMap(fresh)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return generated, nil
+	}
+
+	paths, _ := existing["paths"].(map[string]interface{})
+	if paths == nil {
+		paths = make(map[string]interface{})
+		existing["paths"] = paths
+	}
+	freshPaths, _ := generated["paths"].(map[string]interface{})
+	for path, item := range freshPaths {
+		// Drop an older spelling of the same path, e.g. {id} renamed to {userId}
+		for old := range paths {
+			if old != path && samePath(old, path) {
+				delete(paths, old)
+			}
+		}
+		paths[path] = item
+	}
+
+	components, _ := existing["components"].(map[string]interface{})
+	freshComponents, _ := generated["components"].(map[string]interface{})
+	if components == nil && len(freshComponents) > 0 {
+		components = make(map[string]interface{})
+		existing["components"] = components
+	}
+	for section, entries := range freshComponents {
+		merged, _ := components[section].(map[string]interface{})
+		if merged == nil {
+			merged = make(map[string]interface{})
+			components[section] = merged
+		}
+		for name, value := range entries.(map[string]interface{}) {
+			merged[name] = value
+		}
+	}
+
+	tags, _ := existing["tags"].([]interface{})
+	freshTags, _ := generated["tags"].([]interface{})
+	for _, tag := range freshTags {
+		name := tag.(map[string]interface{})["name"]
+		known := false
+		for _, t := range tags {
+			if t.(map[string]interface{})["name"] == name {
+				known = true
+			}
+		}
+		if !known {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		existing["tags"] = tags
+	}
+
+	return existing, nil
+}
+
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate",
+	Short: "Re-document a subset of routes and merge them into the existing spec",
+	Long: `Re-documents only the routes selected by --tag and/or --path-prefix and merges
+the result into the existing spec, leaving every other operation untouched.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if regenerateTag == "" && regeneratePrefix == "" {
+			fmt.Printf("❌ Pass --tag or --path-prefix to select routes\n")
+			os.Exit(1)
+		}
+
+		cfg := loadConfig(cmd)
+
+		existing, err := diff.LoadSpec(outputFile)
+		if err != nil {
+			fmt.Printf("❌ Error loading spec: %v\n", err)
+			os.Exit(1)
+		}
+
+		routes, err := scanAll(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error scanning routes: %v\n", err)
+			os.Exit(1)
+		}
+		assignOwners(routes, cfg)
+
+		selected := selectRoutes(routes, cfg, existing)
+		if len(selected) == 0 {
+			fmt.Printf("ℹ️ No routes match the selection\n")
+			return
+		}
+		fmt.Printf("🎯 Regenerating %d of %d routes\n", len(selected), len(routes))
+
+		client, closeClient := newClient(cfg)
+		defer closeClient()
+
+		builder, err := newBuilder(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error applying security config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Keep every route's locked prose, but let the selected ones take fresh text
+		var locks *lock.File
+		if lockFile != "" {
+			if locks, err = lock.Load(lockFile); err != nil {
+				fmt.Printf("❌ Error loading lock file: %v\n", err)
+				os.Exit(1)
+			}
+			for _, route := range routes {
+				locks.Keep(route)
+			}
+			for _, route := range selected {
+				locks.Forget(route)
+			}
+		}
+
+		fresh, failures := buildOpenAPISpec(client, builder, locks, selected, nil)
+		if trackSchemas {
+			if err := annotateSchemaChanges(fresh, existing); err != nil {
+				fmt.Printf("❌ Error annotating schema changes: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		merged, err := mergeSpec(existing, fresh)
+		if err != nil {
+			fmt.Printf("❌ Error merging spec: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := validateSpec(outputFile, merged); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		if err := writeOpenAPIFile(outputFile, merged); err != nil {
+			fmt.Printf("❌ Error writing OpenAPI file: %v\n", err)
+			os.Exit(1)
+		}
+		if locks != nil {
+			if err := locks.Save(lockFile); err != nil {
+				fmt.Printf("❌ Error writing lock file: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		fmt.Printf("✅ Merged %d regenerated routes into %s", len(selected)-len(failures), outputFile)
+		if len(failures) > 0 {
+			fmt.Printf(" (%d failed and kept their previous documentation)", len(failures))
+		}
+		fmt.Printf("\n")
+		printFailures(failures)
+	},
+}
+
+func init() {
+	regenerateCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes")
+	regenerateCmd.Flags().StringVarP(&outputFile, "output", "o", "openapi.json", "Spec file to merge into")
+	regenerateCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
+	regenerateCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	regenerateCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	regenerateCmd.Flags().StringVar(&lockFile, "lock-file", "", "Lock file of accepted descriptions, e.g. "+lock.DefaultFile+" (off unless set)")
+	regenerateCmd.Flags().BoolVar(&noCache, "no-cache", false, "Send every route to the model, neither reading nor filling the response cache")
+	regenerateCmd.Flags().StringVar(&auditLog, "audit-log", "", "Append a record of every outbound LLM request to this file")
+	regenerateCmd.Flags().StringVar(&regenerateTag, "tag", "", "Only regenerate operations with this tag")
+	regenerateCmd.Flags().StringVar(&regeneratePrefix, "path-prefix", "", "Only regenerate routes under this path")
+	regenerateCmd.Flags().BoolVar(&trackSchemas, "track-schema-changes", false, "Stamp regenerated schemas that changed with x-changed-in")
+	regenerateCmd.Flags().StringVar(&apiVersion, "api-version", "", "Version to name in x-changed-in annotations (default the commit)")
+	rootCmd.AddCommand(regenerateCmd)
+}
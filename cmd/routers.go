@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Policies for a path served by both the app and the pages router
+const (
+	routersPreferApp = "app"
+	routersMerge     = "merge"
+	routersError     = "error"
+)
+
+var (
+	pagesDir        string
+	routerConflicts string
+)
+
+// resolveRouterConflicts applies --router-conflicts to paths that both routers
+// serve, as happens while an API moves from pages/api to the app router. With
+// "app" the pages router file is dropped, with "merge" both are documented and
+// the app router's operation wins for a method both define, and with "error"
+// the run stops.
+func resolveRouterConflicts(routes []models.APIRoute) ([]models.APIRoute, error) {
+	switch routerConflicts {
+	case routersPreferApp, routersMerge, routersError:
+	default:
+		return nil, fmt.Errorf("--router-conflicts must be %s, %s or %s", routersPreferApp, routersMerge, routersError)
+	}
+
+	app := make(map[string]string)
+	for _, route := range routes {
+		if route.Router != models.PagesRouter {
+			app[route.App+" "+route.Path] = route.FilePath
+		}
+	}
+
+	var kept []models.APIRoute
+	var conflicts []string
+	for _, route := range routes {
+		file, ok := app[route.App+" "+route.Path]
+		if route.Router != models.PagesRouter || !ok {
+			kept = append(kept, route)
+			continue
+		}
+		switch routerConflicts {
+		case routersMerge:
+			fmt.Printf("🔀 %s is served by both routers; merging %s into %s\n", route.Path, route.FilePath, file)
+			kept = append(kept, route)
+		case routersError:
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s and %s)", route.Path, file, route.FilePath))
+		default:
+			fmt.Printf("🔀 %s is served by both routers; documenting %s, skipping %s\n", route.Path, file, route.FilePath)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("%d paths are served by both the app and pages router: %s", len(conflicts), strings.Join(conflicts, ", "))
+	}
+	return kept, nil
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&pagesDir, "pages-dir", "", "Also document the pages router API routes in this directory, e.g. ./pages/api")
+	rootCmd.Flags().StringVar(&routerConflicts, "router-conflicts", routersPreferApp, "Paths served by both routers: app (document the app router file), merge or error")
+	scanCmd.Flags().StringVar(&pagesDir, "pages-dir", "", "Also analyze the pages router API routes in this directory, e.g. ./pages/api")
+	scanCmd.Flags().StringVar(&routerConflicts, "router-conflicts", routersPreferApp, "Paths served by both routers: app (keep the app router file), merge or error")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/scanner"
+)
+
+var (
+	scanOut  string
+	fromFile string
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Analyze every route without documenting it, for a later generate --from",
+	Long: `Runs only the scanning phase: discovers and analyzes every route and writes
+the result, source included, to a routes file. Documenting those routes with
+generate --from can then happen in another job or on another machine, e.g. one
+with a GPU, without access to the project.`,
+	Example: `  nextjs-to-openapi scan -d app/api --out routes.json
+  nextjs-to-openapi generate --from routes.json -o openapi.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+
+		routes, err := scanAll(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error scanning routes: %v\n", err)
+			os.Exit(1)
+		}
+		assignOwners(routes, cfg)
+
+		if err := scanner.SaveArtifact(scanOut, routes); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Wrote %d analyzed routes to %s\n", len(routes), scanOut)
+	},
+}
+
+// generateCmd runs the root command under an explicit name, for pipelines
+// that split scanning and documenting
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Document routes and write the spec (the default command)",
+	Long: `Same as running nextjs-to-openapi without a command. With --from it documents
+the routes in a file written by scan instead of scanning --api-dir.`,
+	Example: `  nextjs-to-openapi generate --from routes.json -o openapi.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		rootCmd.Run(cmd, args)
+	},
+}
+
+// routesFrom lists the routes to document: those in --from, or the route files
+// found by scanning, which are read as the pipeline reaches them
+func routesFrom(cfg *models.Config) ([]models.APIRoute, error) {
+	if fromFile == "" {
+		routes, err := discoverAll(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning routes: %w", err)
+		}
+		assignOwners(routes, cfg)
+		return routes, nil
+	}
+
+	routes, err := scanner.LoadArtifact(fromFile)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("📦 Loaded %d analyzed routes from %s\n", len(routes), fromFile)
+	return routes, nil
+}
+
+func init() {
+	scanCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes")
+	scanCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	scanCmd.Flags().IntVar(&scanWorkers, "scan-workers", 4, "Number of route files read and analyzed at once")
+	scanCmd.Flags().StringVar(&scanOut, "out", scanner.DefaultArtifact, "File to write the analyzed routes to")
+	rootCmd.AddCommand(scanCmd)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"nextjs-to-openapi/internal/archive"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var (
+	trackSchemas bool
+	apiVersion   string
+)
+
+// changeVersion names this run in x-changed-in: --api-version, otherwise the
+// commit, otherwise the date
+func changeVersion() string {
+	if apiVersion != "" {
+		return apiVersion
+	}
+	if commit := archive.Commit(); commit != "" {
+		return commit
+	}
+	return time.Now().UTC().Format("2006-01-02")
+}
+
+// annotateSchemaChanges stamps the component schemas that are new or changed
+// since the previous spec with x-changed-in, and carries the previous stamp
+// over to the unchanged ones
+func annotateSchemaChanges(spec openapi.Spec, previous map[string]interface{}) error {
+	current, err := diff.ToMap(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert spec: %w", err)
+	}
+
+	changed := make(map[string]bool)
+	for _, c := range diff.CompareSchemas(previous, current) {
+		changed[c.Name] = c.Kind != diff.Removed
+	}
+	before := diff.Schemas(previous)
+	version := changeVersion()
+
+	count := 0
+	schemas, _ := spec.Components["schemas"].(map[string]interface{})
+	for name, s := range schemas {
+		schema, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if changed[name] {
+			schema[diff.ChangedIn] = version
+			count++
+		} else if stamp, ok := before[name][diff.ChangedIn]; ok {
+			schema[diff.ChangedIn] = stamp
+		}
+	}
+	if count > 0 {
+		fmt.Printf("🧬 %d schemas new or changed in %s\n", count, version)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var (
+	securitySchemes []string
+	securePaths     []string
+)
+
+// flagScheme parses a --security-scheme value: bearer, basic,
+// apikey:<header> or oauth2:<token url>
+func flagScheme(value string) (string, models.SecurityScheme, error) {
+	kind, arg, _ := strings.Cut(value, ":")
+	switch strings.ToLower(kind) {
+	case "bearer", "jwt":
+		return openapi.DefaultBearerScheme, models.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}, nil
+	case "basic":
+		return "basicAuth", models.SecurityScheme{Type: "http", Scheme: "basic"}, nil
+	case "apikey":
+		if arg == "" {
+			arg = "X-API-Key"
+		}
+		return "apiKeyAuth", models.SecurityScheme{Type: "apiKey", In: "header", Name: arg}, nil
+	case "oauth2":
+		if arg == "" {
+			return "", models.SecurityScheme{}, fmt.Errorf("oauth2 needs a token URL, e.g. oauth2:https://auth.example.com/token")
+		}
+		flow := models.OAuthFlow{TokenURL: arg, Scopes: map[string]string{}}
+		return "oauth2", models.SecurityScheme{Type: "oauth2", Flows: map[string]models.OAuthFlow{"clientCredentials": flow}}, nil
+	}
+	return "", models.SecurityScheme{}, fmt.Errorf("unknown scheme %q (expected bearer, basic, apikey:<header> or oauth2:<token url>)", value)
+}
+
+// checkSecurityFlags adds the schemes given with --security-scheme to the
+// config, required on the --secure-path prefixes or, without any, on every operation
+func checkSecurityFlags(cfg *models.Config) error {
+	if len(securePaths) > 0 && len(securitySchemes) == 0 {
+		return fmt.Errorf("--secure-path needs a --security-scheme")
+	}
+	for _, path := range securePaths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("--secure-path %q must start with /", path)
+		}
+	}
+
+	for _, value := range securitySchemes {
+		name, scheme, err := flagScheme(value)
+		if err != nil {
+			return fmt.Errorf("--security-scheme: %w", err)
+		}
+		if cfg.Security.Schemes == nil {
+			cfg.Security.Schemes = make(map[string]models.SecurityScheme)
+		}
+		cfg.Security.Schemes[name] = scheme
+
+		if len(securePaths) == 0 {
+			cfg.Security.Global = append(cfg.Security.Global, models.SecurityRequirement{Scheme: name})
+		}
+		for _, path := range securePaths {
+			cfg.Security.Rules = append(cfg.Security.Rules, models.SecurityRule{PathPrefix: path, Scheme: name})
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.Flags().StringSliceVar(&securitySchemes, "security-scheme", nil, "Declare a security scheme: bearer, basic, apikey:<header> or oauth2:<token url> (repeatable)")
+	rootCmd.Flags().StringSliceVar(&securePaths, "secure-path", nil, "Require the --security-scheme schemes under this path prefix only, instead of on every operation (repeatable)")
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/docs"
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/preview"
+	"nextjs-to-openapi/internal/scanner"
+	"nextjs-to-openapi/internal/specfile"
+)
+
+var (
+	serveInput   string
+	serveListen  string
+	serveWatch   bool
+	serveOffline bool
+)
+
+// specServer holds the spec being served, which --watch replaces as routes change
+type specServer struct {
+	mu   sync.RWMutex
+	spec map[string]interface{}
+}
+
+func (s *specServer) current() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.spec
+}
+
+func (s *specServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		data, err := json.MarshalIndent(s.current(), "", "  ")
+		s.write(w, "application/json", data, err)
+	})
+	mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		data, err := specfile.MarshalYAML(s.current())
+		s.write(w, "application/yaml", data, err)
+	})
+	mux.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
+		var page string
+		var err error
+		if serveOffline {
+			page, err = docs.HTML(s.current())
+		} else {
+			page, err = docs.SwaggerUI(specTitle(s.current()), "/openapi.json")
+		}
+		s.write(w, "text/html; charset=utf-8", []byte(page), err)
+	})
+	mux.Handle(docs.SwaggerAssetsPath, docs.SwaggerAssets())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, "/docs", http.StatusFound)
+	})
+	return mux
+}
+
+func (s *specServer) write(w http.ResponseWriter, contentType string, data []byte, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(data)
+}
+
+// update re-documents one route file and merges it into the served spec and
+// the input file. The merge works on a copy so requests in flight keep a
+// consistent spec.
+func (s *specServer) update(client *llm.Client, cfg *models.Config, file string) error {
+	route, err := scanner.NewScanner(apiDir).ScanFile(file)
+	if err != nil {
+		return err
+	}
+	assignOwners([]models.APIRoute{route}, cfg)
+
+	builder, err := newBuilder(cfg)
+	if err != nil {
+		return err
+	}
+	doc, err := documentWithTimeout(client, builder, route)
+	if err != nil {
+		return err
+	}
+	builder.AddRoute(route, doc)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	spec, err := diff.ToMap(s.spec)
+	if err != nil {
+		return err
+	}
+	if spec, err = mergeSpec(spec, builder.Spec()); err != nil {
+		return err
+	}
+	if err := writeOpenAPIFile(serveInput, spec); err != nil {
+		return fmt.Errorf("failed to write %s: %w", serveInput, err)
+	}
+	s.spec = spec
+	return nil
+}
+
+func specTitle(spec map[string]interface{}) string {
+	info, _ := spec["info"].(map[string]interface{})
+	if title, _ := info["title"].(string); title != "" {
+		return title
+	}
+	return "API Reference"
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the spec and a Swagger UI for browsing it locally",
+	Long: `Serves a generated spec over HTTP so it can be browsed without other tooling:
+
+  GET /openapi.json   the spec as JSON
+  GET /openapi.yaml   the spec as YAML
+  GET /docs           Swagger UI (or the self-contained reference with --offline)
+
+With --watch, route files saved under --api-dir are re-documented and merged
+into the served spec and the --input file, so reloading /docs shows the change.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		spec, err := specfile.Read(serveInput)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		server := &specServer{spec: spec}
+
+		listener, err := net.Listen("tcp", serveListen)
+		if err != nil {
+			fmt.Printf("❌ Error listening: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📖 Serving %s at http://%s/docs\n", serveInput, listener.Addr())
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if serveWatch {
+			cfg := loadConfig(cmd)
+			client, closeClient := newClient(cfg)
+			defer closeClient()
+
+			fmt.Printf("👀 Watching %s for changes\n", apiDir)
+			go func() {
+				err := preview.Watch(ctx, apiDir, scanner.IsRouteFile,
+					func(file string) {
+						fmt.Printf("💾 %s saved, re-documenting\n", file)
+						if err := server.update(client, cfg, file); err != nil {
+							fmt.Printf("⚠️ Error documenting %s: %v\n", file, err)
+							return
+						}
+						fmt.Printf("✅ Updated %s\n", serveInput)
+					},
+					func(file string) {
+						fmt.Printf("🗑️ %s removed; run a full generation to drop its paths\n", file)
+					})
+				if err != nil {
+					fmt.Printf("❌ %v\n", err)
+					stop()
+				}
+			}()
+		}
+
+		httpServer := &http.Server{Handler: server.handler()}
+		go func() {
+			<-ctx.Done()
+			httpServer.Close()
+		}()
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("❌ Server error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("👋 Stopped serving\n")
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVarP(&serveInput, "input", "i", "openapi.json", "Spec to serve")
+	serveCmd.Flags().StringVar(&serveListen, "listen", "127.0.0.1:8080", "Local address to serve on")
+	serveCmd.Flags().BoolVar(&serveOffline, "offline", false, "Serve the self-contained HTML reference at /docs instead of Swagger UI, which loads from a CDN")
+	serveCmd.Flags().BoolVar(&serveWatch, "watch", false, "Re-document route files when they are saved and update the served spec")
+	serveCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes (with --watch)")
+	serveCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
+	serveCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	serveCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	serveCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache model responses in this directory (default the config's cache, or "+cache.DefaultDir+")")
+	serveCmd.Flags().BoolVar(&noCache, "no-cache", false, "Send every route to the model, neither reading nor filling the response cache")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/models"
+)
+
+// envPrefix starts the environment variables that override config settings,
+// e.g. NEXTJS_OPENAPI_MODEL for --model
+const envPrefix = "NEXTJS_OPENAPI_"
+
+// setting is a flag the config file can set
+type setting struct {
+	flag  string
+	value func(cfg *models.Config) string
+}
+
+var settings = []setting{
+	{"api-dir", func(cfg *models.Config) string { return cfg.APIDir }},
+	{"output", func(cfg *models.Config) string { return cfg.OutputFile }},
+	{"model", func(cfg *models.Config) string { return cfg.OllamaModel }},
+	{"workers", func(cfg *models.Config) string {
+		if cfg.Workers == 0 {
+			return ""
+		}
+		return strconv.Itoa(cfg.Workers)
+	}},
+	{"ollama-url", func(cfg *models.Config) string { return cfg.OllamaURL }},
+	{"provider", func(cfg *models.Config) string { return cfg.Provider }},
+	{"openai-url", func(cfg *models.Config) string { return cfg.OpenAIURL }},
+}
+
+// loadConfig reads the project config (optional unless --config was given) and
+// fills the flags that were not given on the command line from it, exiting on errors
+func loadConfig(cmd *cobra.Command) *models.Config {
+	cfg, err := config.Load(configFile, cmd.Flags().Changed("config"))
+	if err != nil {
+		fmt.Printf("❌ Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applySettings(cmd, cfg); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
+}
+
+// applySettings sets each flag of cmd that was not given on the command line
+// from its environment variable, or else from the config
+func applySettings(cmd *cobra.Command, cfg *models.Config) error {
+	for _, s := range settings {
+		flag := cmd.Flags().Lookup(s.flag)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		source := envName(s.flag)
+		value := os.Getenv(source)
+		if value == "" {
+			source, value = "the config", s.value(cfg)
+		}
+		if value == "" {
+			continue
+		}
+		if err := cmd.Flags().Set(s.flag, value); err != nil {
+			return fmt.Errorf("invalid %s from %s: %w", s.flag, source, err)
+		}
+	}
+	return nil
+}
+
+// envName is the environment variable overriding a flag's config setting
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
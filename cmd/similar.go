@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/lock"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/similarity"
+)
+
+var similarExamples bool
+
+// routeExamples holds the accepted routes that new and changed routes are
+// compared with; nil unless --similar-examples is set
+var routeExamples *exampleIndex
+
+// exampleIndex finds the accepted route most like a given one
+type exampleIndex struct {
+	locks    *lock.File
+	index    similarity.Index
+	examples map[string]models.RouteExample // By file path
+}
+
+// newExampleIndex embeds every route that is unchanged since its prose was
+// accepted and that the previous spec documents
+func newExampleIndex(routes []models.APIRoute, cfg *models.Config, locks *lock.File, previous map[string]interface{}) *exampleIndex {
+	apps := make(map[string]models.WorkspaceApp)
+	for _, app := range cfg.Workspace.Apps {
+		apps[app.Name] = app
+	}
+	paths, _ := previous["paths"].(map[string]interface{})
+
+	idx := &exampleIndex{locks: locks, examples: make(map[string]models.RouteExample)}
+	for _, route := range routes {
+		if !locks.Accepted(route) {
+			continue
+		}
+		url := routeURL(route, apps, cfg.Prefixes)
+		for _, path := range slices.Sorted(maps.Keys(paths)) {
+			pathItem, ok := paths[path].(map[string]interface{})
+			if !ok || !samePath(path, url) {
+				continue
+			}
+			idx.index.Add(route.FilePath, route.Content)
+			idx.examples[route.FilePath] = models.RouteExample{File: route.FilePath, Documentation: exampleDocumentation(path, pathItem)}
+			break
+		}
+	}
+	fmt.Printf("🧭 Embedded %d accepted routes as examples for similar routes\n", idx.index.Len())
+	return idx
+}
+
+// withExample gives a route without accepted prose the most similar accepted
+// route as an example to follow
+func withExample(route models.APIRoute) models.APIRoute {
+	if routeExamples == nil || routeExamples.locks.Accepted(route) {
+		return route
+	}
+	file, score, ok := routeExamples.index.Nearest(route.Content, route.FilePath)
+	if !ok {
+		return route
+	}
+	example := routeExamples.examples[file]
+	route.Example = &example
+	fmt.Printf("🧭 %s: following %s (similarity %.2f)\n", route.FilePath, file, score)
+	return route
+}
+
+// exampleDocumentation renders a documented path item in the shape the prompt
+// asks the model for
+func exampleDocumentation(path string, pathItem map[string]interface{}) string {
+	methods := make(map[string]interface{})
+	for method, op := range pathItem {
+		operation, ok := op.(map[string]interface{})
+		if !ok || method == "parameters" || method == "servers" || method == "head" || method == "options" {
+			continue
+		}
+		documented := map[string]interface{}{
+			"summary":     operation["summary"],
+			"description": operation["description"],
+		}
+
+		var params []interface{}
+		list, _ := operation["parameters"].([]interface{})
+		for _, p := range list {
+			param, _ := p.(map[string]interface{})
+			if param == nil || param["$ref"] != nil {
+				continue
+			}
+			schema, _ := param["schema"].(map[string]interface{})
+			params = append(params, map[string]interface{}{
+				"name": param["name"], "in": param["in"], "type": schema["type"],
+				"required": param["required"], "description": param["description"],
+			})
+		}
+		if len(params) > 0 {
+			documented["parameters"] = params
+		}
+
+		responses := make(map[string]interface{})
+		all, _ := operation["responses"].(map[string]interface{})
+		for status, r := range all {
+			if response, ok := r.(map[string]interface{}); ok && response["description"] != nil {
+				responses[status] = map[string]interface{}{"description": response["description"]}
+			}
+		}
+		if len(responses) > 0 {
+			documented["responses"] = responses
+		}
+		methods[strings.ToUpper(method)] = documented
+	}
+
+	data, _ := json.MarshalIndent(map[string]interface{}{"path": path, "methods": methods}, "", "  ")
+	return string(data)
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&similarExamples, "similar-examples", false, "Show the model the most similar accepted route as an example (needs a lock file and the previous spec)")
+}
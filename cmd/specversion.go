@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var specVersion string
+
+// checkSpecVersion validates --spec-version and lets it override the
+// openapiVersion of the config
+func checkSpecVersion(cfg *models.Config) error {
+	switch specVersion {
+	case "":
+	case "3.0", "3.0.0":
+		cfg.SpecVersion = "3.0.0"
+	case "3.1", "3.1.0":
+		cfg.SpecVersion = "3.1.0"
+	default:
+		return fmt.Errorf("--spec-version must be 3.0 or 3.1")
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&specVersion, "spec-version", "", "OpenAPI version to emit: 3.0 or 3.1 (default from openapiVersion in the config, else 3.0)")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/specfile"
+	"nextjs-to-openapi/internal/split"
+)
+
+// splitRules holds --split PATTERN=FILE flags
+var splitRules []string
+
+// splitOutputs returns the configured outputs followed by those given as flags
+func splitOutputs(cfg *models.Config) ([]models.SpecOutput, error) {
+	outputs := cfg.Outputs
+	for _, rule := range splitRules {
+		pattern, file, ok := strings.Cut(rule, "=")
+		if !ok || pattern == "" || file == "" {
+			return nil, fmt.Errorf("invalid --split %q (expected PATTERN=FILE, e.g. /api/v1/**=openapi-v1.json)", rule)
+		}
+		outputs = append(outputs, models.SpecOutput{Paths: pattern, File: file})
+	}
+
+	// Catch bad patterns before any route is documented
+	for _, output := range outputs {
+		if _, err := split.Compile(output.Paths); err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
+
+// writeSplitSpecs writes one spec per output rule alongside the full spec
+func writeSplitSpecs(outputs []models.SpecOutput, spec interface{}) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	parts, unmatched, err := split.Split(generic, outputs)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range parts {
+		if err := validateSpec(part.Output.File, part.Spec); err != nil {
+			return err
+		}
+		if err := specfile.Write(part.Output.File, part.Spec); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.Output.File, err)
+		}
+		fmt.Printf("✂️ %s: %d paths matching %s\n", part.Output.File, part.Paths, part.Output.Paths)
+	}
+	if len(unmatched) > 0 {
+		fmt.Printf("⚠️ %d paths match no output rule and are only in %s: %s\n", len(unmatched), outputFile, strings.Join(unmatched, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+var (
+	noPathTags      bool
+	tagDescriptions bool
+)
+
+// describeTags has the model describe each tag that has no description yet,
+// from the summaries of the operations carrying it
+func describeTags(client llm.Provider, spec openapi.Spec) {
+	operations := make(map[string][]string)
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		pathItem, _ := spec.Paths[path].(map[string]interface{})
+		for _, method := range slices.Sorted(maps.Keys(pathItem)) {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tags, _ := operation["tags"].([]string)
+			summary, _ := operation["summary"].(string)
+			for _, tag := range tags {
+				operations[tag] = append(operations[tag], strings.TrimSpace(fmt.Sprintf("%s %s: %s", strings.ToUpper(method), path, summary)))
+			}
+		}
+	}
+
+	described := 0
+	for _, tag := range spec.Tags {
+		name, _ := tag["name"].(string)
+		if description, _ := tag["description"].(string); description != "" || len(operations[name]) == 0 {
+			continue
+		}
+		text, err := client.DescribeTag(context.Background(), name, operations[name])
+		if err != nil {
+			fmt.Printf("⚠️ Could not describe tag %s: %v\n", name, err)
+			continue
+		}
+		tag["description"] = strings.TrimSpace(text)
+		described++
+	}
+	if described > 0 {
+		fmt.Printf("🏷️ Described %d tags\n", described)
+	}
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&noPathTags, "no-path-tags", false, "Leave operations untagged instead of tagging them with the first segment of their path")
+	rootCmd.Flags().BoolVar(&tagDescriptions, "tag-descriptions", false, "Have the model describe tags that have no description")
+}
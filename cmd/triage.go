@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+	"nextjs-to-openapi/internal/triage"
+)
+
+var (
+	triageEnabled bool
+	routeTriage   *triage.Rules
+
+	triageMu      sync.Mutex
+	triageClasses = make(map[string]string)      // Route file -> class
+	triageClients = make(map[string]llm.Provider) // Model -> client
+)
+
+// setupTriage classifies routes when --triage or routeTriage.enabled is set
+func setupTriage(cfg *models.Config) {
+	if !triageEnabled && !cfg.Triage.Enabled {
+		return
+	}
+	routeTriage = triage.New(cfg.Triage)
+
+	var strategies []string
+	for _, name := range triage.Classes {
+		class := routeTriage.Class(name)
+		strategy := class.Strategy
+		if class.Model != "" {
+			strategy += " (" + class.Model + ")"
+		}
+		strategies = append(strategies, name+": "+strategy)
+	}
+	fmt.Printf("🧮 Triaging routes: %s\n", strings.Join(strategies, ", "))
+}
+
+// triaged decides how a loaded route is documented: it returns the route's class
+// and either static documentation, when the class skips the model, or the
+// client for the class's model
+func triaged(client llm.Provider, builder *openapi.Builder, route models.APIRoute) (string, llm.Provider, *llm.RouteDocumentation) {
+	class, _ := routeTriage.Classify(route)
+	settings := routeTriage.Class(class)
+	if settings.Strategy == triage.Static {
+		if doc := builder.StaticDocumentation(route); doc != nil {
+			return class, client, doc
+		}
+	}
+	if settings.Model == "" {
+		return class, client, nil
+	}
+
+	triageMu.Lock()
+	defer triageMu.Unlock()
+	if triageClients[settings.Model] == nil {
+		triageClients[settings.Model] = client.WithModel(settings.Model)
+	}
+	return class, triageClients[settings.Model], nil
+}
+
+// recordTriage remembers a route's class for the summary
+func recordTriage(route models.APIRoute, class string) {
+	triageMu.Lock()
+	defer triageMu.Unlock()
+	triageClasses[route.FilePath] = class
+}
+
+// printTriageSummary counts the routes of each class
+func printTriageSummary() {
+	if routeTriage == nil || len(triageClasses) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	for _, class := range triageClasses {
+		counts[class]++
+	}
+	var parts []string
+	for _, name := range triage.Classes {
+		part := fmt.Sprintf("%d %s", counts[name], name)
+		if routeTriage.Class(name).Strategy == triage.Static {
+			part += " (without the model)"
+		}
+		parts = append(parts, part)
+	}
+	fmt.Printf("🧮 Triage: %s\n", strings.Join(parts, ", "))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"nextjs-to-openapi/internal/advisor"
+	"nextjs-to-openapi/internal/llm"
+)
+
+// printUsage reports how much the cache saved and how the provider performed,
+// with hints for tuning the next run
+func printUsage(client *llm.Client) {
+	if shared := client.Shared(); shared > 0 {
+		fmt.Printf("♻️ %d routes reused the documentation of an identical file\n", shared)
+	}
+	stats := client.Stats()
+	if stats.Requests == 0 && stats.CacheHits == 0 {
+		return
+	}
+
+	fmt.Printf("📊 Model usage: %d requests", stats.Requests)
+	if lookups := stats.CacheHits + stats.CacheMisses; lookups > 0 {
+		fmt.Printf(", %d of %d cache lookups hit (%.0f%%)", stats.CacheHits, lookups, stats.HitRate()*100)
+	}
+	if stats.SavedTokens > 0 {
+		fmt.Printf(", ≈%s tokens saved", tokens(stats.SavedTokens))
+	}
+	fmt.Printf("\n")
+	if stats.Requests > 0 {
+		fmt.Printf("⏱️ Provider latency: p50 %s, p95 %s", advisor.Round(stats.Latency(50)), advisor.Round(stats.Latency(95)))
+		if stats.PromptTokens+stats.ResponseTokens > 0 {
+			fmt.Printf("; %s prompt and %s response tokens", tokens(stats.PromptTokens), tokens(stats.ResponseTokens))
+		}
+		fmt.Printf("\n")
+	}
+
+	hints := advisor.Advise(stats, advisor.Settings{
+		Workers:         workers,
+		MaxInflight:     maxInflight,
+		MaxRPS:          maxRPS,
+		PerRouteTimeout: perRouteTimeout,
+		Caching:         cachingEnabled(),
+		Triage:          routeTriage != nil,
+	})
+	for _, hint := range hints {
+		fmt.Printf("💡 %s\n", hint)
+	}
+}
+
+// tokens abbreviates a token count, e.g. 12.3k
+func tokens(n int) string {
+	if n < 1000 {
+		return fmt.Sprint(n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/validate"
+)
+
+var strictSpec bool
+
+// ErrSpecInvalid is matched by --strict failures of specs that break OpenAPI rules
+var ErrSpecInvalid = errors.New("spec breaks OpenAPI rules")
+
+// exitSpecInvalid is the exit code of runs stopped by ErrSpecInvalid, so CI can
+// tell them apart from errors (1) and failing warnings (2)
+const exitSpecInvalid = 3
+
+// exitCode is the exit code of a run that failed with err
+func exitCode(err error) int {
+	if errors.Is(err, ErrSpecInvalid) {
+		return exitSpecInvalid
+	}
+	return 1
+}
+
+// validateSpec checks a spec about to be written to file and reports its
+// problems by path. It returns an error when there are problems and --strict is set.
+func validateSpec(file string, spec interface{}) error {
+	generic, err := diff.ToMap(spec)
+	if err != nil {
+		return err
+	}
+	problems := validate.Spec(generic)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n🩺 %s breaks %d OpenAPI rules:\n", file, len(problems))
+	last := "\x00"
+	for _, problem := range problems {
+		if problem.Path != last {
+			if problem.Path == "" {
+				fmt.Printf("  Document:\n")
+			} else {
+				fmt.Printf("  %s:\n", problem.Path)
+			}
+			last = problem.Path
+		}
+		fmt.Printf("    - %s\n", problem)
+	}
+	if strictSpec {
+		return fmt.Errorf("%s is invalid; not writing it in --strict mode: %w", file, ErrSpecInvalid)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.Flags().BoolVar(&strictSpec, "strict", false, "Fail instead of writing a spec that breaks OpenAPI rules")
+}
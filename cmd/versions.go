@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/openapi"
+)
+
+// writeVersionSpecs writes one spec per API version that handlers branch on,
+// next to outputFile, e.g. openapi.v2.json
+func writeVersionSpecs(spec interface{}) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	versions := openapi.SpecVersions(generic)
+	if len(versions) == 0 {
+		fmt.Printf("ℹ️ No handler branches on an API version header; no version specs written\n")
+		return nil
+	}
+	for _, version := range versions {
+		variant, err := openapi.VersionedSpec(generic, version)
+		if err != nil {
+			return err
+		}
+		file := audienceFile(outputFile, "v"+strings.TrimPrefix(version, "v"))
+		if err := validateSpec(file, variant); err != nil {
+			return err
+		}
+		if err := writeOpenAPIFile(file, variant); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+		fmt.Printf("🔀 Version %s spec written to: %s\n", version, file)
+	}
+	return nil
+}
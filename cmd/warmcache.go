@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+var warmCacheCmd = &cobra.Command{
+	Use:   "warm-cache",
+	Short: "Pre-generate documentation for every route into the response cache",
+	Long: `Documents every route whose response is not cached yet and stores the result,
+so later runs with the same cache, model and route files are cache hits.
+Meant for scheduled jobs (e.g. nightly); pass --deterministic to warm the cache
+that --deterministic CI runs read.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+
+		routes, err := scanAll(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error scanning routes: %v\n", err)
+			os.Exit(1)
+		}
+
+		builder, err := newBuilder(cfg)
+		if err != nil {
+			fmt.Printf("❌ Error applying config: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Misses are always fetched, even in deterministic mode
+		updateCache = true
+		client, closeClient := newClient(cfg)
+		defer closeClient()
+		setupTriage(cfg)
+
+		var missing []models.APIRoute
+		for _, route := range routes {
+			if builder.Excludes(route) || skipMinified(route) || builder.FactoryDocumentation(route) != nil {
+				continue
+			}
+			var routeClient llm.Provider = client
+			if routeTriage != nil {
+				var doc *llm.RouteDocumentation
+				if _, routeClient, doc = triaged(client, builder, route); doc != nil {
+					continue
+				}
+			}
+			if !routeClient.Cached(route) {
+				missing = append(missing, route)
+			}
+		}
+		fmt.Printf("🔥 %d of %d routes need documenting\n", len(missing), len(routes))
+
+		queue := make(chan models.APIRoute)
+		var failed atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < max(1, min(workers, len(missing))); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for route := range queue {
+					if _, err := documentWithTimeout(client, builder, route); err != nil {
+						fmt.Printf("⚠️ Error documenting %s: %v\n", route.FilePath, err)
+						failed.Add(1)
+					}
+				}
+			}()
+		}
+		for _, route := range missing {
+			queue <- route
+		}
+		close(queue)
+		wg.Wait()
+
+		printUsage(client)
+		fmt.Printf("✅ Cached %d routes in %s", len(missing)-int(failed.Load()), cacheLocation)
+		if failed.Load() > 0 {
+			fmt.Printf(" (%d failed)\n", failed.Load())
+			os.Exit(1)
+		}
+		fmt.Printf("\n")
+	},
+}
+
+func init() {
+	warmCacheCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes")
+	warmCacheCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
+	warmCacheCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	warmCacheCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	warmCacheCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache directory to fill (default the config's cache, or "+cache.DefaultDir+")")
+	warmCacheCmd.Flags().IntVarP(&workers, "workers", "w", 3, "Number of routes documented at once")
+	warmCacheCmd.Flags().BoolVar(&determinism, "deterministic", false, "Warm the cache read by --deterministic runs (temperature 0, fixed seed)")
+	warmCacheCmd.Flags().BoolVar(&triageEnabled, "triage", false, "Skip routes that triage documents without the model")
+	warmCacheCmd.Flags().StringVar(&auditLog, "audit-log", "", "Append a record of every outbound LLM request to this file")
+	rootCmd.AddCommand(warmCacheCmd)
+}
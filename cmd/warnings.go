@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/warnings"
+)
+
+var (
+	warningsReport   string
+	suppressWarnings []string
+	failOnWarnings   []string
+
+	// warned collects the warnings of the current run; nil outside the root command
+	warned *warnings.Collector
+)
+
+// newWarnings combines the config's warning policy with the flags
+func newWarnings(cfg *models.Config) (*warnings.Collector, error) {
+	suppress := append(append([]string{}, cfg.Warnings.Suppress...), suppressWarnings...)
+	failOn := append(append([]string{}, cfg.Warnings.FailOn...), failOnWarnings...)
+	return warnings.NewCollector(suppress, failOn)
+}
+
+// checkRoute records the warnings for one documented route
+func checkRoute(route models.APIRoute, doc *llm.RouteDocumentation) {
+	if warned == nil {
+		return
+	}
+	for _, w := range warnings.Check(route, doc) {
+		warned.Add(w)
+	}
+}
+
+// finishWarnings prints the summary, writes --warnings-report and exits when
+// an enforced code was raised
+func finishWarnings() {
+	if warned == nil {
+		return
+	}
+	report := warned.Report()
+	if len(report.Warnings) > 0 || report.Suppressed > 0 {
+		fmt.Printf("⚠️ %s\n", report.Summary())
+	}
+	if warningsReport != "" {
+		if err := report.Write(warningsReport); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📋 Warnings report written to: %s\n", warningsReport)
+	}
+	if len(report.Enforced) > 0 {
+		fmt.Printf("❌ Failing on warnings: %v\n", report.Enforced)
+		os.Exit(2)
+	}
+}
+
+func init() {
+	rootCmd.Flags().StringVar(&warningsReport, "warnings-report", "", "Write every warning as JSON to this file")
+	rootCmd.Flags().StringSliceVar(&suppressWarnings, "suppress-warning", nil, "Warning codes to hide, e.g. W004,W010 (or all)")
+	rootCmd.Flags().StringSliceVar(&failOnWarnings, "fail-on-warning", nil, "Warning codes that fail the run with exit code 2, e.g. W001,W012 (or all)")
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/config"
+	"nextjs-to-openapi/internal/diff"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/preview"
+	"nextjs-to-openapi/internal/scanner"
+)
+
+var (
+	watchListen string
+	watchSocket string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Re-document routes on save and serve live previews to editors",
+	Long: `Runs a long-lived server that re-documents a single route whenever its file is
+saved and exposes the per-file OpenAPI preview over a local HTTP socket:
+
+  GET  /routes          every known preview
+  GET  /route?file=...  the preview of one file (documented on first request)
+  POST /route?file=...  re-document one file now
+  GET  /events          server-sent events for every status change`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig(cmd)
+
+		client, closeClient := newClient(cfg)
+		defer closeClient()
+		scan := scanner.NewScanner(apiDir)
+
+		server := preview.NewServer(apiDir, func(file string) (interface{}, error) {
+			if !scanner.IsRouteFile(file) {
+				return nil, fmt.Errorf("%s is not a route file", file)
+			}
+			route, err := scan.ScanFile(file)
+			if err != nil {
+				return nil, err
+			}
+			assignOwners([]models.APIRoute{route}, cfg)
+
+			builder, err := newBuilder(cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			doc, err := documentWithTimeout(client, builder, route)
+			if err != nil {
+				return nil, err
+			}
+			builder.AddRoute(route, doc)
+			return diff.ToMap(builder.Spec())
+		})
+
+		var listener net.Listener
+		var err error
+		if watchSocket != "" {
+			os.Remove(watchSocket)
+			listener, err = net.Listen("unix", watchSocket)
+		} else {
+			listener, err = net.Listen("tcp", watchListen)
+		}
+		if err != nil {
+			fmt.Printf("❌ Error listening: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("👀 Watching %s, previews served on %s\n", apiDir, listener.Addr())
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		go func() {
+			err := preview.Watch(ctx, apiDir, scanner.IsRouteFile,
+				func(file string) {
+					fmt.Printf("💾 %s saved, re-documenting\n", file)
+					if result := server.Update(file); result.Status == preview.StatusError {
+						fmt.Printf("⚠️ Error documenting %s: %s\n", file, result.Error)
+					}
+				},
+				server.Forget)
+			if err != nil {
+				fmt.Printf("❌ %v\n", err)
+				stop()
+			}
+		}()
+
+		httpServer := &http.Server{Handler: server.Handler()}
+		go func() {
+			<-ctx.Done()
+			httpServer.Close()
+		}()
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("❌ Server error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("👋 Stopped watching\n")
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVarP(&apiDir, "api-dir", "d", "./api", "Directory containing Next.js API routes")
+	watchCmd.Flags().StringVarP(&ollamaModel, "model", "m", "llama3.1", "Ollama model to use for documentation generation")
+	watchCmd.Flags().StringVar(&ollamaURL, "ollama-url", "http://localhost:11434", "Ollama server URL")
+	watchCmd.Flags().StringVarP(&configFile, "config", "c", config.DefaultFile, "Project config file")
+	watchCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache model responses in this directory (default the config's cache, or "+cache.DefaultDir+")")
+	watchCmd.Flags().BoolVar(&noCache, "no-cache", false, "Send every route to the model, neither reading nor filling the response cache")
+	watchCmd.Flags().StringVar(&watchListen, "listen", "127.0.0.1:4477", "Local address to serve previews on")
+	watchCmd.Flags().StringVar(&watchSocket, "socket", "", "Serve previews on this Unix socket instead of TCP")
+	rootCmd.AddCommand(watchCmd)
+}
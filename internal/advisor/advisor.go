@@ -0,0 +1,80 @@
+package advisor
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"nextjs-to-openapi/internal/llm"
+)
+
+// Settings are the run options the hints may suggest changing
+type Settings struct {
+	Workers         int
+	MaxInflight     int
+	MaxRPS          float64
+	PerRouteTimeout time.Duration
+	Caching         bool
+	Triage          bool
+}
+
+// Thresholds behind the hints
+const (
+	fastLatency     = 3 * time.Second  // Quick enough to keep more requests in flight
+	slowLatency     = 20 * time.Second // Long enough that requests are probably queueing
+	largePrompt     = 32 * 1024        // Likely to be slow and to crowd the context window
+	minRequests     = 10               // Fewer say little about the provider
+	lowHitRate      = 0.2
+	smallPromptRate = 0.3
+)
+
+// Advise turns a run's usage into setting recommendations, most impactful first
+func Advise(stats llm.Stats, s Settings) []string {
+	var hints []string
+	p50, p95 := stats.Latency(50), stats.Latency(95)
+
+	switch {
+	case !s.Caching && stats.Requests > 0:
+		hints = append(hints, "Drop --no-cache so unchanged routes are answered from the response cache")
+	case stats.CacheMisses >= minRequests && stats.HitRate() < lowHitRate:
+		hints = append(hints, fmt.Sprintf("Only %.0f%% of lookups were cache hits; run warm-cache on a schedule, and check that --model and the config match the runs that filled the cache", stats.HitRate()*100))
+	}
+
+	if stats.Requests >= minRequests {
+		if stats.Failures*10 >= stats.Requests {
+			limit := max(1, s.Workers/2)
+			if s.MaxInflight > 0 {
+				limit = max(1, s.MaxInflight/2)
+			}
+			hints = append(hints, fmt.Sprintf("%d of %d requests failed; if the server is shared or overloaded, cap concurrency with --max-inflight %d", stats.Failures, stats.Requests, limit))
+		}
+		switch {
+		case p50 >= slowLatency && s.Workers > 2:
+			hints = append(hints, fmt.Sprintf("Requests take %s at the median with %d workers; Ollama answers OLLAMA_NUM_PARALLEL requests at once and queues the rest, so lower --workers to match it or raise OLLAMA_NUM_PARALLEL", Round(p50), s.Workers))
+		case p50 <= fastLatency && stats.CacheMisses >= 4*s.Workers && s.MaxRPS == 0 && (s.MaxInflight == 0 || s.MaxInflight > s.Workers):
+			hints = append(hints, fmt.Sprintf("Requests take %s at the median; --workers %d would likely finish sooner", Round(p50), s.Workers*2))
+		}
+		if s.PerRouteTimeout == 0 && p95 > 3*p50 && p95 >= 10*time.Second {
+			hints = append(hints, fmt.Sprintf("The slowest 5%% of requests take over %s; --per-route-timeout %s would skip stragglers instead of waiting for them", Round(p95), Round(3*p50)))
+		}
+		if !s.Triage && float64(stats.SmallPrompts) >= smallPromptRate*float64(stats.Requests) {
+			hints = append(hints, fmt.Sprintf("%d of %d prompts were for short route files; --triage documents trivial routes without the model", stats.SmallPrompts, stats.Requests))
+		}
+	}
+
+	if stats.LargestPrompt >= largePrompt {
+		hints = append(hints, fmt.Sprintf("%s sent a %d KB prompt; very large route files are slow and can overflow the model's context window, so consider moving helpers into imported modules", filepath.ToSlash(stats.LargestPromptFile), stats.LargestPrompt/1024))
+	}
+	return hints
+}
+
+// Round shortens a latency for display
+func Round(d time.Duration) time.Duration {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond)
+	case d < 10*time.Second:
+		return d.Round(100 * time.Millisecond)
+	}
+	return d.Round(time.Second)
+}
@@ -0,0 +1,131 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultFile is where pending descriptions are stored
+const DefaultFile = "pending.json"
+
+// reviewedFields are the operation fields written by the model that need approval
+var reviewedFields = []string{"summary", "description"}
+
+// Entry is one generated text awaiting review
+type Entry struct {
+	Path     string `json:"path"`
+	Method   string `json:"method"`
+	Field    string `json:"field"`
+	Current  string `json:"current"`  // Text currently in the spec (empty for new operations)
+	Proposed string `json:"proposed"` // Newly generated text
+}
+
+// Key identifies the spec field an entry applies to
+func (e Entry) Key() string {
+	return e.Method + " " + e.Path + " " + e.Field
+}
+
+// Pending is the content of pending.json
+type Pending struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads pending entries; a missing file means nothing is pending
+func Load(path string) (*Pending, error) {
+	pending := &Pending{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return pending, nil
+		}
+		return nil, fmt.Errorf("failed to read pending file: %w", err)
+	}
+	if err := json.Unmarshal(data, pending); err != nil {
+		return nil, fmt.Errorf("failed to parse pending file %s: %w", path, err)
+	}
+	return pending, nil
+}
+
+// Save writes the entries, removing the file once nothing is pending
+func (p *Pending) Save(path string) error {
+	if len(p.Entries) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+
+	sort.Slice(p.Entries, func(i, j int) bool { return p.Entries[i].Key() < p.Entries[j].Key() })
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Stage holds back generated text that differs from the previously published spec.
+// The new paths keep the previous text, and each held-back change is added to the
+// pending entries, replacing older proposals for the same field.
+func (p *Pending) Stage(previousPaths, newPaths map[string]interface{}) int {
+	index := make(map[string]int)
+	for i, e := range p.Entries {
+		index[e.Key()] = i
+	}
+
+	staged := 0
+	for path, item := range newPaths {
+		pathItem, _ := item.(map[string]interface{})
+		for method, op := range pathItem {
+			operation, ok := op.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			previous := lookupOperation(previousPaths, path, method)
+
+			for _, field := range reviewedFields {
+				proposed, _ := operation[field].(string)
+				current, _ := previous[field].(string)
+				if proposed == current || proposed == "" {
+					continue
+				}
+
+				// Publish the reviewed text until the proposal is approved
+				if current == "" {
+					delete(operation, field)
+				} else {
+					operation[field] = current
+				}
+
+				entry := Entry{Path: path, Method: strings.ToUpper(method), Field: field, Current: current, Proposed: proposed}
+				if i, ok := index[entry.Key()]; ok {
+					p.Entries[i] = entry
+				} else {
+					index[entry.Key()] = len(p.Entries)
+					p.Entries = append(p.Entries, entry)
+				}
+				staged++
+			}
+		}
+	}
+	return staged
+}
+
+// Apply writes an approved entry into the spec paths, reporting whether the operation still exists
+func Apply(paths map[string]interface{}, entry Entry) bool {
+	operation := lookupOperation(paths, entry.Path, entry.Method)
+	if operation == nil {
+		return false
+	}
+	operation[entry.Field] = entry.Proposed
+	return true
+}
+
+func lookupOperation(paths map[string]interface{}, path, method string) map[string]interface{} {
+	pathItem, _ := paths[path].(map[string]interface{})
+	operation, _ := pathItem[strings.ToLower(method)].(map[string]interface{})
+	return operation
+}
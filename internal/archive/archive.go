@@ -0,0 +1,185 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDir is where the history subcommands look when --archive-dir is not given
+const DefaultDir = ".openapi-archive"
+
+const indexFile = "index.json"
+
+// Entry describes one archived spec
+type Entry struct {
+	ID      string    `json:"id"` // <timestamp>-<commit>, also the file name without .json
+	Time    time.Time `json:"time"`
+	Commit  string    `json:"commit,omitempty"`
+	Label   string    `json:"label,omitempty"`   // e.g. a release name
+	Version string    `json:"version,omitempty"` // info.version of the spec
+	Paths   int       `json:"paths"`
+	Hash    string    `json:"hash"` // SHA-256 of the archived file
+}
+
+// File returns the entry's spec file inside dir
+func (e Entry) File(dir string) string {
+	return filepath.Join(dir, e.ID+".json")
+}
+
+// Store archives a spec under dir, returning its entry. A spec identical to the
+// latest one is not stored again unless it carries a new label; the latest
+// entry is returned with stored false.
+func Store(dir string, spec interface{}, commit, label string, now time.Time) (Entry, bool, error) {
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("failed to encode spec: %w", err)
+	}
+
+	entries, err := List(dir)
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if n := len(entries); n > 0 && entries[n-1].Hash == hash && (label == "" || entries[n-1].Label == label) {
+		return entries[n-1], false, nil
+	}
+
+	entry := Entry{
+		ID:     now.UTC().Format("20060102T150405Z"),
+		Time:   now.UTC(),
+		Commit: commit,
+		Label:  label,
+		Hash:   hash,
+	}
+	if commit != "" {
+		entry.ID += "-" + commit
+	}
+	entry.Version, entry.Paths = summarize(data)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := os.WriteFile(entry.File(dir), data, 0644); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to write archived spec: %w", err)
+	}
+
+	entries = append(entries, entry)
+	index, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return Entry{}, false, fmt.Errorf("failed to encode archive index: %w", err)
+	}
+	// Replace the index in one step so an interrupted run never truncates it
+	tmp := filepath.Join(dir, indexFile+".tmp")
+	if err := os.WriteFile(tmp, index, 0644); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to write archive index: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, indexFile)); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to write archive index: %w", err)
+	}
+	return entry, true, nil
+}
+
+// List returns the archived entries in dir, oldest first. A missing archive is empty.
+func List(dir string) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive index: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse archive index: %w", err)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// Find resolves a reference to an entry: "latest", a label, a spec version, a
+// commit prefix or an ID (timestamp) prefix. Labels and versions pick the most
+// recent match; prefixes must be unambiguous.
+func Find(entries []Entry, ref string) (Entry, error) {
+	if len(entries) == 0 {
+		return Entry{}, fmt.Errorf("the archive is empty")
+	}
+	if ref == "latest" {
+		return entries[len(entries)-1], nil
+	}
+
+	for _, exact := range []func(Entry) string{
+		func(e Entry) string { return e.Label },
+		func(e Entry) string { return e.Version },
+	} {
+		for i := len(entries) - 1; i >= 0; i-- {
+			if exact(entries[i]) == ref {
+				return entries[i], nil
+			}
+		}
+	}
+
+	var matches []Entry
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.ID, ref) || (entry.Commit != "" && strings.HasPrefix(entry.Commit, ref)) {
+			matches = append(matches, entry)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Entry{}, fmt.Errorf("no archived spec matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.ID
+	}
+	return Entry{}, fmt.Errorf("%q is ambiguous: %s", ref, strings.Join(ids, ", "))
+}
+
+// Load reads an archived spec
+func Load(dir string, entry Entry) ([]byte, error) {
+	data, err := os.ReadFile(entry.File(dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archived spec: %w", err)
+	}
+	return data, nil
+}
+
+// Commit returns the short hash of the checked-out commit, or "" outside a git repository
+func Commit() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		if sha := os.Getenv("GITHUB_SHA"); len(sha) >= 7 {
+			return sha[:7]
+		}
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// summarize reads the version and path count the history listing shows
+func summarize(data []byte) (string, int) {
+	var spec struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+		Paths map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return "", 0
+	}
+	return spec.Info.Version, len(spec.Paths)
+}
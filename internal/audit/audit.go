@@ -0,0 +1,67 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry represents a single outbound LLM request in the audit log
+type Entry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model"`
+	RouteFile  string    `json:"route_file"`
+	PromptHash string    `json:"prompt_hash"`
+	BytesSent  int       `json:"bytes_sent"`
+}
+
+// Logger appends audit entries to a JSON Lines file
+type Logger struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewLogger opens (or creates) the audit log in append-only mode
+func NewLogger(path string) (*Logger, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Logger{file: file}, nil
+}
+
+// Log writes one entry as a single JSON line
+func (l *Logger) Log(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return l.file.Sync()
+}
+
+// Close closes the underlying log file
+func (l *Logger) Close() error {
+	return l.file.Close()
+}
+
+// HashPrompt returns the hex-encoded SHA-256 of a prompt
+func HashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
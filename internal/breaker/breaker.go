@@ -0,0 +1,158 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned once the breaker has given up on the provider
+var ErrOpen = errors.New("circuit breaker open: provider keeps failing, giving up")
+
+// Breaker pauses dispatch after consecutive provider failures, backing off
+// exponentially between attempts, and spends a shared budget on retries
+type Breaker struct {
+	threshold int           // Consecutive failures that open the circuit
+	cooldown  time.Duration // First pause; doubled on every consecutive trip
+	maxPause  time.Duration
+	maxTrips  int // Consecutive trips before giving up for the rest of the run
+
+	mu        sync.Mutex
+	failures  int
+	trips     int
+	openUntil time.Time
+	openedAt  time.Time // When the circuit last opened
+	probe     time.Time // Start of the half-open probe in flight, if any
+	changed   chan struct{}
+	gaveUp    bool
+	budget    int
+}
+
+// New creates a breaker. A threshold of 0 disables pausing.
+func New(threshold int, cooldown time.Duration, retryBudget int) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		maxPause:  5 * time.Minute,
+		maxTrips:  6,
+		budget:    retryBudget,
+		changed:   make(chan struct{}),
+	}
+}
+
+// Wait blocks while the circuit is open, or until ctx is done, and returns
+// when the call it lets through started, to pass to Record or Abandon. Once a
+// pause is over a single probe call goes through; the others wait for its
+// outcome.
+func (b *Breaker) Wait(ctx context.Context) (time.Time, error) {
+	for {
+		b.mu.Lock()
+		if b.gaveUp {
+			b.mu.Unlock()
+			return time.Time{}, ErrOpen
+		}
+		if err := ctx.Err(); err != nil {
+			b.mu.Unlock()
+			return time.Time{}, err
+		}
+		now := time.Now()
+		pause := b.openUntil.Sub(now)
+		if b.openUntil.IsZero() || pause <= 0 && b.probe.IsZero() {
+			if !b.openUntil.IsZero() {
+				b.probe = now
+			}
+			b.mu.Unlock()
+			return now, nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		// Wait out the pause, or for the probe's outcome
+		var timeout <-chan time.Time
+		if pause > 0 {
+			timeout = time.After(pause)
+		}
+		select {
+		case <-timeout:
+		case <-changed:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// Record updates the breaker with the outcome of a provider call that started
+// at started. Failures of calls sent before the circuit last opened are
+// ignored: they failed for the reason it opened.
+func (b *Breaker) Record(started time.Time, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		if b.trips > 0 {
+			fmt.Printf("▶️ Provider recovered, resuming\n")
+		}
+		b.failures, b.trips = 0, 0
+		b.openUntil, b.probe = time.Time{}, time.Time{}
+		b.broadcast()
+		return
+	}
+	if started.Before(b.openedAt) {
+		return
+	}
+	if started.Equal(b.probe) {
+		b.probe = time.Time{}
+	}
+
+	b.failures++
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return
+	}
+
+	b.trips++
+	b.openedAt = time.Now()
+	defer b.broadcast()
+	if b.trips > b.maxTrips {
+		b.gaveUp = true
+		fmt.Printf("🛑 Provider failed %d times in a row after %d pauses, skipping remaining routes\n", b.failures, b.maxTrips)
+		return
+	}
+
+	pause := b.cooldown << (b.trips - 1)
+	if pause > b.maxPause || pause <= 0 {
+		pause = b.maxPause
+	}
+	b.openUntil = b.openedAt.Add(pause)
+	// Half-open: a failing probe after the pause trips again
+	b.failures = b.threshold - 1
+	fmt.Printf("⏸️ Provider failing (%v), pausing dispatch for %s\n", err, pause)
+}
+
+// Abandon releases a call that ended without an outcome, such as one whose
+// route ran out of time, letting another probe through if it was the probe
+func (b *Breaker) Abandon(started time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.probe.IsZero() && started.Equal(b.probe) {
+		b.probe = time.Time{}
+		b.broadcast()
+	}
+}
+
+// broadcast wakes the calls waiting in Wait to look at the state again
+func (b *Breaker) broadcast() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Retry reports whether a failed call may be retried, spending one unit of the budget
+func (b *Breaker) Retry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.gaveUp || b.budget <= 0 {
+		return false
+	}
+	b.budget--
+	return true
+}
@@ -0,0 +1,124 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GoVersion is the language version of the generated server module
+const GoVersion = "1.21"
+
+// Files embedded into the server, next to its main.go
+const (
+	SpecFile = "openapi.json"
+	YAMLFile = "openapi.yaml"
+	PageFile = "index.html"
+)
+
+// Assets are the files a docs server serves
+type Assets struct {
+	Spec []byte // JSON spec
+	YAML []byte // YAML spec
+	Page []byte // Rendered HTML reference
+}
+
+// Write lays out a standalone Go module in dir that embeds the assets. It
+// imports nothing outside the standard library, so it builds offline.
+func Write(dir string, assets Assets) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	files := map[string][]byte{
+		"go.mod":  []byte("module docsserver\n\ngo " + GoVersion + "\n"),
+		"main.go": []byte(serverSource),
+		SpecFile:  assets.Spec,
+		YAMLFile:  assets.YAML,
+		PageFile:  assets.Page,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Build compiles the module in dir into a static binary at out, for goos and
+// goarch when set (the host platform otherwise)
+func Build(dir, out, goos, goarch string) error {
+	out, err := filepath.Abs(out)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", out, err)
+	}
+	cmd := exec.Command("go", "build", "-trimpath", "-ldflags=-s -w", "-o", out, ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0", "GOWORK=off")
+	if goos != "" {
+		cmd.Env = append(cmd.Env, "GOOS="+goos)
+	}
+	if goarch != "" {
+		cmd.Env = append(cmd.Env, "GOARCH="+goarch)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to build docs server: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// serverSource serves the embedded reference at /, and the spec at
+// /openapi.json and /openapi.yaml, on -addr or $PORT
+const serverSource = `// Command docsserver serves an API reference generated by nextjs-to-openapi.
+// Everything it serves is embedded; it needs no other files.
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+)
+
+var (
+	//go:embed ` + SpecFile + `
+	spec []byte
+	//go:embed ` + YAMLFile + `
+	specYAML []byte
+	//go:embed ` + PageFile + `
+	page []byte
+)
+
+func serve(contentType string, body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}
+}
+
+func main() {
+	addr := ":8080"
+	if port := os.Getenv("PORT"); port != "" {
+		addr = ":" + port
+	}
+	flag.StringVar(&addr, "addr", addr, "Address to listen on")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/openapi.json", serve("application/json", spec))
+	mux.HandleFunc("/openapi.yaml", serve("application/yaml", specYAML))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) })
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" && r.URL.Path != "/index.html" {
+			http.NotFound(w, r)
+			return
+		}
+		serve("text/html; charset=utf-8", page)(w, r)
+	})
+
+	log.Printf("Serving API docs on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+`
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// DefaultDir is the cache directory used when none is given
+const DefaultDir = ".nextjs-openapi-cache"
+
+// DefaultBackend stores responses as files in a directory
+const DefaultBackend = "dir"
+
+// Cache stores raw model responses by key
+type Cache interface {
+	// Get returns the response stored under key unless it is missing or expired
+	Get(key string) (string, bool)
+	// Set stores a response; a positive ttl lets it expire after that long
+	Set(key, response string, ttl time.Duration) error
+}
+
+// Factory opens a backend from the cache config
+type Factory func(cfg models.CacheConfig) (Cache, error)
+
+var (
+	mu       sync.RWMutex
+	backends = map[string]Factory{
+		DefaultBackend: func(cfg models.CacheConfig) (Cache, error) { return New(cfg.Dir) },
+		"redis":        func(cfg models.CacheConfig) (Cache, error) { return NewRedis(cfg.Redis) },
+		"s3":           func(cfg models.CacheConfig) (Cache, error) { return NewS3(cfg.S3) },
+	}
+)
+
+// Register makes a backend selectable by name in the config, replacing any
+// backend registered under the same name
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	backends[name] = factory
+}
+
+// Backends lists the registered backend names
+func Backends() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var names []string
+	for name := range backends {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Open opens the backend the config selects, the directory backend by default
+func Open(cfg models.CacheConfig) (Cache, error) {
+	name := cfg.Backend
+	if name == "" {
+		name = DefaultBackend
+	}
+	if name == DefaultBackend && cfg.Dir == "" {
+		cfg.Dir = DefaultDir
+	}
+
+	mu.RLock()
+	factory, ok := backends[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown cache backend %q (expected %s)", name, strings.Join(Backends(), ", "))
+	}
+	return factory(cfg)
+}
+
+// Location describes where the config puts cached responses, for messages
+func Location(cfg models.CacheConfig) string {
+	switch cfg.Backend {
+	case "", DefaultBackend:
+		if cfg.Dir == "" {
+			return DefaultDir
+		}
+		return cfg.Dir
+	case "redis":
+		return redisLocation(cfg.Redis)
+	case "s3":
+		return "s3://" + cfg.S3.Bucket + "/" + cfg.S3.Prefix
+	}
+	return cfg.Backend + " cache"
+}
+
+// Key derives a cache key from everything that influences a response
+func Key(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// entry is a stored response in backends without native expiry
+type entry struct {
+	Response string    `json:"response"`
+	Expires  time.Time `json:"expires,omitzero"`
+}
+
+func encodeEntry(response string, ttl time.Duration) ([]byte, error) {
+	e := entry{Response: response}
+	if ttl > 0 {
+		e.Expires = time.Now().Add(ttl).UTC()
+	}
+	return json.Marshal(e)
+}
+
+func decodeEntry(data []byte) (string, bool) {
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return "", false
+	}
+	if !e.Expires.IsZero() && time.Now().After(e.Expires) {
+		return "", false
+	}
+	return e.Response, true
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dir stores responses on disk, one file per key
+type Dir struct {
+	dir string
+}
+
+// New opens (creating if needed) a cache directory
+func New(dir string) (*Dir, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Dir{dir: dir}, nil
+}
+
+// Get returns the cached response for key
+func (c *Dir) Get(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	return decodeEntry(data)
+}
+
+// Set stores a response, writing atomically so concurrent runs never see partial files
+func (c *Dir) Set(key, response string, ttl time.Duration) error {
+	data, err := encodeEntry(response, ttl)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	tmp.Close()
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *Dir) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
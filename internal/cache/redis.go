@@ -0,0 +1,231 @@
+package cache
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// DefaultRedisPrefix namespaces keys in a shared Redis database
+const DefaultRedisPrefix = "nextjs-openapi:"
+
+// redisTimeout bounds each command, so an unreachable server only costs a cache miss
+const redisTimeout = 5 * time.Second
+
+// Redis stores responses in a Redis database, letting it expire entries
+type Redis struct {
+	addr     string
+	tls      bool
+	username string
+	password string
+	db       int
+	prefix   string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedis connects to the server named by a redis:// or rediss:// URL. The
+// password may also come from REDIS_PASSWORD.
+func NewRedis(cfg models.RedisCache) (*Redis, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("redis cache needs a url")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+		return nil, fmt.Errorf("invalid redis url %q (expected redis://host:port/db)", cfg.URL)
+	}
+
+	r := &Redis{addr: u.Host, tls: u.Scheme == "rediss", prefix: cfg.Prefix}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if r.prefix == "" {
+		r.prefix = DefaultRedisPrefix
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if r.password == "" {
+		r.password = os.Getenv("REDIS_PASSWORD")
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid redis database %q", db)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.connect(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", r.addr, err)
+	}
+	// A server that needs a password only refuses at the first command, and
+	// anything not speaking RESP fails to parse the reply
+	if reply, err := r.roundTrip([]string{"PING"}); err != nil || reply == nil || *reply != "PONG" {
+		r.close()
+		if err == nil {
+			err = fmt.Errorf("unexpected reply to PING")
+		}
+		return nil, fmt.Errorf("redis at %s did not answer PING: %w", r.addr, err)
+	}
+	return r, nil
+}
+
+// Get returns the cached response for key
+func (r *Redis) Get(key string) (string, bool) {
+	reply, err := r.do("GET", r.prefix+key)
+	if err != nil || reply == nil {
+		return "", false
+	}
+	return *reply, true
+}
+
+// Set stores a response, expiring it after ttl when positive
+func (r *Redis) Set(key, response string, ttl time.Duration) error {
+	args := []string{"SET", r.prefix + key, response}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	if _, err := r.do(args...); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
+
+// do sends one command, reconnecting once if the connection was lost
+func (r *Redis) do(args ...string) (*string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reply, err := r.roundTrip(args)
+	var replyErr redisError
+	if err != nil && !errors.As(err, &replyErr) {
+		r.close()
+		if err := r.connect(); err != nil {
+			return nil, err
+		}
+		reply, err = r.roundTrip(args)
+	}
+	return reply, err
+}
+
+// connect dials the server, then authenticates and selects the database
+func (r *Redis) connect() error {
+	dialer := &net.Dialer{Timeout: redisTimeout}
+	var err error
+	if r.tls {
+		r.conn, err = tls.DialWithDialer(dialer, "tcp", r.addr, &tls.Config{})
+	} else {
+		r.conn, err = dialer.Dial("tcp", r.addr)
+	}
+	if err != nil {
+		return err
+	}
+	r.reader = bufio.NewReader(r.conn)
+
+	if r.password != "" {
+		auth := []string{"AUTH", r.password}
+		if r.username != "" {
+			auth = []string{"AUTH", r.username, r.password}
+		}
+		if _, err := r.roundTrip(auth); err != nil {
+			r.close()
+			return err
+		}
+	}
+	if r.db != 0 {
+		if _, err := r.roundTrip([]string{"SELECT", strconv.Itoa(r.db)}); err != nil {
+			r.close()
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Redis) close() {
+	if r.conn != nil {
+		r.conn.Close()
+		r.conn = nil
+	}
+}
+
+// roundTrip writes a command as a RESP array and reads its reply; a nil reply
+// is a missing key
+func (r *Redis) roundTrip(args []string) (*string, error) {
+	if r.conn == nil {
+		return nil, io.ErrClosedPipe
+	}
+	r.conn.SetDeadline(time.Now().Add(redisTimeout))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(r.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	line, err := r.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		value := line[1:]
+		return &value, nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis reply %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r.reader, data); err != nil {
+			return nil, err
+		}
+		value := string(data[:size])
+		return &value, nil
+	}
+	return nil, fmt.Errorf("unexpected redis reply %q", line)
+}
+
+// redisError is an error reply; the connection is still usable after one
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisLocation names the server without its credentials
+func redisLocation(cfg models.RedisCache) string {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return "redis"
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,278 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// s3ErrorCode finds the code in an S3 XML error body
+var s3ErrorCode = regexp.MustCompile(`<Code>([^<]+)</Code>`)
+
+// S3 stores responses as objects in an S3 bucket, or any service speaking the
+// S3 API (MinIO, R2) when an endpoint is given. Objects carry their own expiry;
+// pair a TTL with a bucket lifecycle rule to delete them.
+type S3 struct {
+	bucket   string
+	prefix   string
+	region   string
+	endpoint string // Path-style base URL; AWS URLs when empty
+
+	accessKey    string
+	secretKey    string
+	sessionToken string
+
+	httpClient *http.Client
+}
+
+// NewS3 opens a bucket with credentials from the standard AWS environment
+// variables or the shared credentials file, and checks that the bucket accepts
+// them, so a signing or permission problem shows up once instead of as a
+// cache miss per route
+func NewS3(cfg models.S3Cache) (*S3, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 cache needs a bucket")
+	}
+	s := &S3{
+		bucket:       cfg.Bucket,
+		prefix:       cfg.Prefix,
+		region:       firstSet(cfg.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		endpoint:     strings.TrimSuffix(cfg.Endpoint, "/"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		profile, err := sharedCredentials()
+		if err != nil {
+			return nil, err
+		}
+		s.accessKey, s.secretKey, s.sessionToken = profile["aws_access_key_id"], profile["aws_secret_access_key"], profile["aws_session_token"]
+	}
+	if s.accessKey == "" || s.secretKey == "" {
+		return nil, fmt.Errorf("s3 cache needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or a profile in the shared credentials file")
+	}
+
+	// A missing object answers NoSuchKey, or AccessDenied without list
+	// permission; wrong keys or a bad signature have their own codes
+	resp, err := s.request("GET", ".verify", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach s3 bucket %s: %w", s.bucket, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return s, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	switch code := s3ErrorCode.FindSubmatch(body); {
+	case code == nil && resp.StatusCode == http.StatusNotFound:
+		return s, nil // Services that answer a missing object without a body
+	case code == nil:
+		return nil, fmt.Errorf("s3 bucket %s answered status %d", s.bucket, resp.StatusCode)
+	case string(code[1]) == "NoSuchKey" || string(code[1]) == "AccessDenied":
+		return s, nil
+	default:
+		return nil, fmt.Errorf("s3 bucket %s rejected the request: %s", s.bucket, code[1])
+	}
+}
+
+// sharedCredentials reads the AWS_PROFILE profile, or default, from the shared
+// credentials file; a missing file has no profiles
+func sharedCredentials() (map[string]string, error) {
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, nil
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	profile := firstSet(os.Getenv("AWS_PROFILE"), "default")
+	values := make(map[string]string)
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && section == profile {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return values, nil
+}
+
+// Get returns the cached response for key
+func (s *S3) Get(key string) (string, bool) {
+	resp, err := s.request("GET", key, nil)
+	if err != nil {
+		return "", false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", false
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false
+	}
+	return decodeEntry(data)
+}
+
+// Set stores a response as an object
+func (s *S3) Set(key, response string, ttl time.Duration) error {
+	data, err := encodeEntry(response, ttl)
+	if err != nil {
+		return err
+	}
+	resp, err := s.request("PUT", key, data)
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to write cache entry: s3 returned status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// request sends a signed request for the object holding key
+func (s *S3) request(method, key string, body []byte) (*http.Response, error) {
+	return s.send(method, strings.TrimSuffix(s.bucketPath(), "/")+"/"+uriEncode(s.prefix+key+".json", false), body)
+}
+
+// send sends a signed request for a path below base
+func (s *S3) send(method, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.base()+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, path, body, time.Now().UTC())
+	return s.httpClient.Do(req)
+}
+
+// base is the URL requests are sent to. AWS buckets are addressed by host,
+// except those with dots in their name, which the wildcard certificate of the
+// virtual-hosted style does not cover.
+func (s *S3) base() string {
+	switch {
+	case s.endpoint != "":
+		return s.endpoint
+	case s.pathStyle():
+		return "https://s3." + s.region + ".amazonaws.com"
+	}
+	return "https://" + s.bucket + ".s3." + s.region + ".amazonaws.com"
+}
+
+// bucketPath is the path of the bucket below base
+func (s *S3) bucketPath() string {
+	if s.endpoint != "" || s.pathStyle() {
+		return "/" + uriEncode(s.bucket, true) + "/"
+	}
+	return "/"
+}
+
+func (s *S3) pathStyle() bool {
+	return s.endpoint == "" && strings.Contains(s.bucket, ".")
+}
+
+// sign adds an AWS Signature Version 4 authorization header
+func (s *S3) sign(req *http.Request, canonicalPath string, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("x-amz-date", amzDate)
+	if s.sessionToken != "" {
+		req.Header.Set("x-amz-security-token", s.sessionToken)
+	}
+
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{"host": req.URL.Host, "x-amz-content-sha256": payloadHash, "x-amz-date": amzDate}
+	if s.sessionToken != "" {
+		headers = append(headers, "x-amz-security-token")
+		values["x-amz-security-token"] = s.sessionToken
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headers {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+	canonicalRequest := strings.Join([]string{req.Method, canonicalPath, "", canonicalHeaders.String(), signedHeaders, payloadHash}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// uriEncode percent-encodes everything but unreserved characters, and slashes
+// unless encodeSlash is set, as SigV4 canonical paths require
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
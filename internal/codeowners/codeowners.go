@@ -0,0 +1,122 @@
+package codeowners
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations GitHub checks for a CODEOWNERS file, relative to the repository root
+var locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// File is a parsed CODEOWNERS file
+type File struct {
+	Root  string // Repository root the patterns are relative to
+	rules []rule
+}
+
+type rule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// Find walks up from dir to the first directory containing a CODEOWNERS file
+func Find(dir string) (*File, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, location := range locations {
+			path := filepath.Join(dir, location)
+			if f, err := os.Open(path); err == nil {
+				defer f.Close()
+				return parse(dir, f)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func parse(root string, f *os.File) (*File, error) {
+	file := &File{Root: root}
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		var owners []string
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			owners = append(owners, owner)
+		}
+		file.rules = append(file.rules, rule{pattern: compile(fields[0]), owners: owners})
+	}
+	return file, lines.Err()
+}
+
+// compile converts a gitignore-style CODEOWNERS pattern into a regular expression
+func compile(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var expr strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && i+1 < len(pattern) && pattern[i+1] == '*':
+			i++
+			if i+1 < len(pattern) && pattern[i+1] == '/' {
+				i++
+				expr.WriteString("(?:.*/)?") // "**/" matches zero or more directories
+			} else {
+				expr.WriteString(".*")
+			}
+		case c == '*':
+			expr.WriteString("[^/]*")
+		case c == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	prefix := "^"
+	if !anchored {
+		prefix = "^(?:.*/)?"
+	}
+	suffix := "(?:/.*)?$" // A matched directory owns everything below it
+	if !directory && strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, "**") {
+		suffix = "$"
+	}
+	return regexp.MustCompile(prefix + expr.String() + suffix)
+}
+
+// Owners returns the owners of path; the last matching rule wins, as on GitHub
+func (f *File) Owners(path string) []string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(f.Root, abs); err == nil {
+			path = rel
+		}
+	}
+	path = filepath.ToSlash(path)
+
+	for i := len(f.rules) - 1; i >= 0; i-- {
+		if f.rules[i].pattern.MatchString(path) {
+			return f.rules[i].owners
+		}
+	}
+	return nil
+}
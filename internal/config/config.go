@@ -0,0 +1,379 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/deployment"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/prune"
+	"nextjs-to-openapi/internal/triage"
+	"nextjs-to-openapi/internal/warnings"
+)
+
+// DefaultFile is the project-level config file used when --config is not given
+const DefaultFile = "nextjs-openapi.yaml"
+
+// Load reads a YAML config file. A missing file is only an error when required.
+func Load(path string, required bool) (*models.Config, error) {
+	cfg := &models.Config{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := checkKeys(data); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if err := Validate(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// checkKeys rejects top-level keys Config has no field for, such as a flag
+// the file cannot set, rather than ignoring them
+func checkKeys(data []byte) error {
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	supported := configKeys()
+	var unknown []string
+	for key := range fields {
+		if !slices.Contains(supported, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	return fmt.Errorf("unknown key %s (supported keys: %s)", strings.Join(unknown, ", "), strings.Join(supported, ", "))
+}
+
+// configKeys lists the top-level keys of a config file in alphabetical order
+func configKeys() []string {
+	t := reflect.TypeOf(models.Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Validate checks that security rules reference declared schemes and scopes
+func Validate(cfg *models.Config) error {
+	for name, scheme := range cfg.Security.Schemes {
+		switch scheme.Type {
+		case "oauth2":
+			if len(scheme.Flows) == 0 {
+				return fmt.Errorf("oauth2 scheme %q declares no flows", name)
+			}
+			for flowType, flow := range scheme.Flows {
+				if err := validateFlow(flowType, flow); err != nil {
+					return fmt.Errorf("scheme %q: %w", name, err)
+				}
+			}
+		case "http", "apiKey", "openIdConnect":
+		default:
+			return fmt.Errorf("scheme %q has unsupported type %q", name, scheme.Type)
+		}
+	}
+
+	for i, rule := range cfg.Security.Rules {
+		scheme, ok := cfg.Security.Schemes[rule.Scheme]
+		if !ok {
+			return fmt.Errorf("security rule %d references unknown scheme %q", i+1, rule.Scheme)
+		}
+		if rule.PathPrefix == "" && rule.Tag == "" {
+			return fmt.Errorf("security rule %d needs a pathPrefix or tag", i+1)
+		}
+		if scheme.Type != "oauth2" {
+			continue
+		}
+		for _, scope := range rule.Scopes {
+			if !hasScope(scheme, scope) {
+				return fmt.Errorf("security rule %d uses scope %q not declared by scheme %q", i+1, scope, rule.Scheme)
+			}
+		}
+	}
+
+	for i, requirement := range cfg.Security.Global {
+		scheme, ok := cfg.Security.Schemes[requirement.Scheme]
+		if !ok {
+			return fmt.Errorf("global security requirement %d references unknown scheme %q", i+1, requirement.Scheme)
+		}
+		if scheme.Type != "oauth2" {
+			continue
+		}
+		for _, scope := range requirement.Scopes {
+			if !hasScope(scheme, scope) {
+				return fmt.Errorf("global security requirement %d uses scope %q not declared by scheme %q", i+1, scope, requirement.Scheme)
+			}
+		}
+	}
+
+	for i, scenario := range cfg.LoadTest.Scenarios {
+		if scenario.Tag == "" {
+			return fmt.Errorf("load test scenario %d needs a tag", i+1)
+		}
+		if scenario.VUs < 0 || scenario.Weight < 0 {
+			return fmt.Errorf("load test scenario %q has a negative vus or weight", scenario.Tag)
+		}
+		if scenario.Duration != "" {
+			if _, err := time.ParseDuration(scenario.Duration); err != nil {
+				return fmt.Errorf("load test scenario %q: invalid duration %q", scenario.Tag, scenario.Duration)
+			}
+		}
+	}
+
+	if cfg.SizeBudget.MaxSize != "" {
+		if _, err := prune.ParseSize(cfg.SizeBudget.MaxSize); err != nil {
+			return fmt.Errorf("sizeBudget: %w", err)
+		}
+	}
+	if cfg.SizeBudget.MaxDescription < 0 {
+		return fmt.Errorf("sizeBudget.maxDescription cannot be negative")
+	}
+	if cfg.External.MaxLength < 0 {
+		return fmt.Errorf("externalDescriptions.maxLength cannot be negative")
+	}
+
+	switch cfg.Internal.Mode {
+	case "", "tag", "exclude":
+	default:
+		return fmt.Errorf("unknown internalRoutes.mode %q (expected tag or exclude)", cfg.Internal.Mode)
+	}
+	for _, path := range cfg.Internal.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("internalRoutes: path %q must start with /", path)
+		}
+	}
+
+	for i, prefix := range cfg.Prefixes {
+		if !strings.HasPrefix(prefix.Prefix, "/") || !strings.HasPrefix(prefix.PublishedAs, "/") {
+			return fmt.Errorf("path prefix %d needs a prefix and publishedAs starting with /", i+1)
+		}
+		for _, param := range prefix.Parameters {
+			if !strings.Contains(prefix.PublishedAs, "{"+param.Name+"}") {
+				return fmt.Errorf("path prefix %q declares parameter %q that publishedAs does not contain", prefix.PublishedAs, param.Name)
+			}
+		}
+	}
+
+	if cfg.Envelope.DataField == "" && (len(cfg.Envelope.Properties) > 0 || cfg.Envelope.Name != "") {
+		return fmt.Errorf("responseEnvelope needs a dataField naming the payload property")
+	}
+
+	for i, factory := range cfg.Factories {
+		if factory.Name == "" {
+			return fmt.Errorf("crud factory %d needs a name", i+1)
+		}
+		for method, op := range factory.Operations {
+			switch op.Returns {
+			case "", "item", "list", "none":
+			default:
+				return fmt.Errorf("crud factory %s: %s returns %q (expected item, list or none)", factory.Name, method, op.Returns)
+			}
+		}
+	}
+
+	switch cfg.Versioning.Mode {
+	case "", "header", "split", "off":
+	default:
+		return fmt.Errorf("unknown apiVersioning.mode %q (expected header, split or off)", cfg.Versioning.Mode)
+	}
+
+	for i, rule := range cfg.Redaction {
+		if rule.Field == "" {
+			return fmt.Errorf("redaction rule %d needs a field", i+1)
+		}
+		switch rule.Action {
+		case "", "strip", "mask":
+		default:
+			return fmt.Errorf("redaction rule %d: unknown action %q (expected strip or mask)", i+1, rule.Action)
+		}
+	}
+
+	for i, rule := range cfg.ParamValues {
+		if rule.Name == "" || len(rule.Values) == 0 {
+			return fmt.Errorf("pathParamValues %d needs a name and values", i+1)
+		}
+	}
+
+	if err := validateCache(cfg.Cache); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+
+	if err := triage.Validate(cfg.Triage); err != nil {
+		return fmt.Errorf("routeTriage: %w", err)
+	}
+
+	if _, err := deployment.ParseEnvironments(cfg.Deployment.Environments); err != nil {
+		return fmt.Errorf("deploymentServers: %w", err)
+	}
+
+	if _, err := warnings.ParseCodes(cfg.Warnings.Suppress); err != nil {
+		return fmt.Errorf("warnings.suppress: %w", err)
+	}
+	if _, err := warnings.ParseCodes(cfg.Warnings.FailOn); err != nil {
+		return fmt.Errorf("warnings.failOn: %w", err)
+	}
+
+	for i, rule := range cfg.Idempotency.Rules {
+		if rule.Tag == "" && rule.PathPrefix == "" {
+			return fmt.Errorf("idempotency rule %d needs a tag or pathPrefix", i+1)
+		}
+	}
+
+	files := make(map[string]bool)
+	for i, output := range cfg.Outputs {
+		if output.Paths == "" || output.File == "" {
+			return fmt.Errorf("output %d needs paths and file", i+1)
+		}
+		if !strings.HasPrefix(output.Paths, "/") {
+			return fmt.Errorf("output %d: paths %q must start with /", i+1, output.Paths)
+		}
+		if files[output.File] {
+			return fmt.Errorf("output file %q is declared twice", output.File)
+		}
+		files[output.File] = true
+	}
+
+	names := make(map[string]bool)
+	for i, app := range cfg.Workspace.Apps {
+		if app.Name == "" || app.APIDir == "" {
+			return fmt.Errorf("workspace app %d needs a name and apiDir", i+1)
+		}
+		if names[app.Name] {
+			return fmt.Errorf("workspace app %q is declared twice", app.Name)
+		}
+		names[app.Name] = true
+	}
+
+	if cfg.Workers < 0 {
+		return fmt.Errorf("workers cannot be negative")
+	}
+	switch cfg.Provider {
+	case "", "ollama", "openai":
+	default:
+		return fmt.Errorf("unknown provider %q (expected ollama or openai)", cfg.Provider)
+	}
+	if cfg.Info.License != nil && cfg.Info.License.Name == "" {
+		return fmt.Errorf("info.license needs a name")
+	}
+	for i, server := range cfg.Servers {
+		if server.URL == "" {
+			return fmt.Errorf("server %d needs a url", i+1)
+		}
+	}
+
+	switch cfg.SpecVersion {
+	case "", "3.0.0", "3.1.0":
+	default:
+		return fmt.Errorf("unsupported openapiVersion %q (expected 3.0.0 or 3.1.0)", cfg.SpecVersion)
+	}
+
+	hooks := make(map[string]bool)
+	for i, hook := range cfg.Webhooks {
+		if hook.Name == "" {
+			return fmt.Errorf("webhook %d needs a name", i+1)
+		}
+		if hooks[hook.Name] {
+			return fmt.Errorf("webhook %q is declared twice", hook.Name)
+		}
+		hooks[hook.Name] = true
+		switch strings.ToUpper(hook.Method) {
+		case "", "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			return fmt.Errorf("webhook %q has unsupported method %q", hook.Name, hook.Method)
+		}
+	}
+
+	switch cfg.Naming.Strategy {
+	case "", "pascal", "path":
+	default:
+		return fmt.Errorf("unknown schemaNaming.strategy %q (expected pascal or path)", cfg.Naming.Strategy)
+	}
+	switch cfg.Naming.Collision {
+	case "", "suffix", "path":
+	default:
+		return fmt.Errorf("unknown schemaNaming.collision %q (expected suffix or path)", cfg.Naming.Collision)
+	}
+
+	return nil
+}
+
+func validateCache(c models.CacheConfig) error {
+	if c.TTL != "" {
+		if ttl, err := time.ParseDuration(c.TTL); err != nil || ttl < 0 {
+			return fmt.Errorf("invalid ttl %q", c.TTL)
+		}
+	}
+	switch c.Backend {
+	case "", cache.DefaultBackend:
+	case "redis":
+		if c.Redis.URL == "" {
+			return fmt.Errorf("the redis backend needs redis.url")
+		}
+	case "s3":
+		if c.S3.Bucket == "" {
+			return fmt.Errorf("the s3 backend needs s3.bucket")
+		}
+	default:
+		if !slices.Contains(cache.Backends(), c.Backend) {
+			return fmt.Errorf("unknown backend %q (expected %s)", c.Backend, strings.Join(cache.Backends(), ", "))
+		}
+	}
+	return nil
+}
+
+func validateFlow(flowType string, flow models.OAuthFlow) error {
+	switch flowType {
+	case "authorizationCode":
+		if flow.AuthorizationURL == "" || flow.TokenURL == "" {
+			return fmt.Errorf("authorizationCode flow needs authorizationUrl and tokenUrl")
+		}
+	case "implicit":
+		if flow.AuthorizationURL == "" {
+			return fmt.Errorf("implicit flow needs authorizationUrl")
+		}
+	case "password", "clientCredentials":
+		if flow.TokenURL == "" {
+			return fmt.Errorf("%s flow needs tokenUrl", flowType)
+		}
+	default:
+		return fmt.Errorf("unknown oauth2 flow %q", flowType)
+	}
+	return nil
+}
+
+func hasScope(scheme models.SecurityScheme, scope string) bool {
+	for _, flow := range scheme.Flows {
+		if _, ok := flow.Scopes[scope]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "settings", content: "apiDir: ./app/api\nworkers: 4\nollamaUrl: http://localhost:11434\n"},
+		{name: "empty", content: ""},
+		{name: "flag without a setting", content: "model: llama3\nmaxRps: 2\n", wantErr: "unknown key maxRps"},
+		{name: "flag spelling", content: "api-dir: ./app/api\n", wantErr: "unknown key api-dir"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), DefaultFile)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path, true)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), "supported keys: apiDir, apiVersioning,") {
+				t.Errorf("error does not list the supported keys: %v", err)
+			}
+		})
+	}
+}
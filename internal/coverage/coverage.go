@@ -0,0 +1,159 @@
+package coverage
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/examples"
+)
+
+var methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// Report measures how completely a spec documents its operations
+type Report struct {
+	Operations         int     `json:"operations"`
+	WithSummary        int     `json:"with_summary"`
+	WithDescription    int     `json:"with_description"`
+	Parameters         int     `json:"parameters"`
+	DescribedParams    int     `json:"described_parameters"`
+	WithResponseSchema int     `json:"with_response_schema"` // A 2xx response with a schema more specific than a bare object
+	WithExamples       int     `json:"with_examples"`        // A 2xx response with an example
+	Score              float64 `json:"score"`                // Percentage of checks passed
+	Gaps               []Gap   `json:"gaps,omitempty"`
+}
+
+// Gap is an operation and what its documentation lacks
+type Gap struct {
+	Method  string   `json:"method"`
+	Path    string   `json:"path"`
+	Missing []string `json:"missing"`
+}
+
+// Measure checks every operation for a summary, a description, described
+// parameters, a specific success schema and a success example
+func Measure(spec map[string]interface{}) Report {
+	var r Report
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+
+	passed, checks := 0, 0
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range methodOrder {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			r.Operations++
+			var missing []string
+			check := func(ok bool, what string) {
+				checks++
+				if ok {
+					passed++
+				} else {
+					missing = append(missing, what)
+				}
+			}
+
+			summary, _ := op["summary"].(string)
+			description, _ := op["description"].(string)
+			check(strings.TrimSpace(summary) != "", "summary")
+			check(strings.TrimSpace(description) != "", "description")
+			if strings.TrimSpace(summary) != "" {
+				r.WithSummary++
+			}
+			if strings.TrimSpace(description) != "" {
+				r.WithDescription++
+			}
+
+			params, _ := op["parameters"].([]interface{})
+			for _, raw := range params {
+				param := examples.Parameter(raw, components)
+				r.Parameters++
+				d, _ := param["description"].(string)
+				check(strings.TrimSpace(d) != "", fmt.Sprintf("description of parameter %v", param["name"]))
+				if strings.TrimSpace(d) != "" {
+					r.DescribedParams++
+				}
+			}
+
+			schema, example := successResponse(op, components)
+			check(schema, "success response schema")
+			check(example, "success response example")
+			if schema {
+				r.WithResponseSchema++
+			}
+			if example {
+				r.WithExamples++
+			}
+
+			if len(missing) > 0 {
+				r.Gaps = append(r.Gaps, Gap{Method: strings.ToUpper(method), Path: path, Missing: missing})
+			}
+		}
+	}
+
+	if checks > 0 {
+		r.Score = float64(passed*1000/checks) / 10
+	}
+	return r
+}
+
+// successResponse reports whether a 2xx response has a specific schema and an example
+func successResponse(op map[string]interface{}, components map[string]interface{}) (schema, example bool) {
+	responses, _ := op["responses"].(map[string]interface{})
+	for status, raw := range responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		response, _ := raw.(map[string]interface{})
+		if ref, ok := response["$ref"].(string); ok {
+			response, _ = examples.Resolve(ref, components).(map[string]interface{})
+		}
+		if status == "204" {
+			schema, example = true, true
+			continue
+		}
+		content, _ := response["content"].(map[string]interface{})
+		for _, m := range content {
+			media, _ := m.(map[string]interface{})
+			s, _ := media["schema"].(map[string]interface{})
+			if specific(s) {
+				schema = true
+			}
+			if _, ok := media["example"]; ok {
+				example = true
+			} else if _, ok := media["examples"]; ok {
+				example = true
+			} else if _, ok := s["example"]; ok {
+				example = true
+			}
+		}
+	}
+	return schema, example
+}
+
+// specific reports whether a schema says more than "some object"
+func specific(schema map[string]interface{}) bool {
+	if schema == nil {
+		return false
+	}
+	if _, ok := schema["$ref"]; ok {
+		return true
+	}
+	for _, key := range []string{"properties", "items", "oneOf", "anyOf", "allOf", "enum", "format"} {
+		if _, ok := schema[key]; ok {
+			return true
+		}
+	}
+	return schema["type"] != nil && schema["type"] != "object"
+}
+
+// Summary is a one-line description of the report
+func (r Report) Summary() string {
+	return fmt.Sprintf("%.1f%% documented: %d/%d operations with summaries, %d/%d with descriptions, %d/%d parameters described, %d/%d with response schemas, %d/%d with examples",
+		r.Score, r.WithSummary, r.Operations, r.WithDescription, r.Operations, r.DescribedParams, r.Parameters,
+		r.WithResponseSchema, r.Operations, r.WithExamples, r.Operations)
+}
@@ -0,0 +1,280 @@
+package deployment
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environments a deployment URL can belong to, in the order servers are listed
+const (
+	Production  = "production"
+	Preview     = "preview"
+	Development = "development"
+)
+
+// Environments lists every known environment
+var Environments = []string{Production, Preview, Development}
+
+// DefaultEnvironments are listed as servers unless another selection is made.
+// Preview URLs change with every deployment, so they are opt-in.
+var DefaultEnvironments = []string{Production}
+
+// envFiles maps the files Next.js and `vercel env pull` write to an environment,
+// most specific first so local overrides win
+var envFiles = []struct {
+	name        string
+	environment string
+}{
+	{".env.production.local", Production},
+	{".env.production", Production},
+	{".env.preview.local", Preview},
+	{".env.preview", Preview},
+	{".env.development.local", Development},
+	{".env.local", Development},
+	{".env.development", Development},
+	{".env", Development},
+}
+
+// urlVariables hold the public URL of the app, in order of preference. API base
+// URLs are left out: their path would be repeated by the spec's /api paths.
+var urlVariables = []string{
+	"NEXT_PUBLIC_SITE_URL",
+	"NEXT_PUBLIC_APP_URL",
+	"NEXT_PUBLIC_BASE_URL",
+	"NEXT_PUBLIC_VERCEL_URL",
+	"NEXTAUTH_URL",
+	"SITE_URL",
+	"APP_URL",
+	"BASE_URL",
+	"VERCEL_PROJECT_PRODUCTION_URL",
+	"VERCEL_BRANCH_URL",
+	"VERCEL_URL",
+}
+
+// Project root markers; the search stops at the first directory with one
+var markers = []string{"vercel.json", ".vercel", "package.json", "next.config.js", "next.config.mjs", "next.config.ts"}
+
+// Server is one deployment URL to list in the spec
+type Server struct {
+	URL         string
+	Environment string
+	Source      string // File the URL was read from
+}
+
+// Description names the environment for the servers list
+func (s Server) Description() string {
+	return strings.ToUpper(s.Environment[:1]) + s.Environment[1:]
+}
+
+// Find walks up from dir to the project root and reads a server for each
+// selected environment from its env files and Vercel config. Environments
+// without a configured URL are left out; none are made up from the project name.
+func Find(dir string, environments []string) ([]Server, error) {
+	root, err := projectRoot(dir)
+	if err != nil || root == "" {
+		return nil, err
+	}
+
+	found := make(map[string]Server)
+	for _, file := range envFiles {
+		if _, ok := found[file.environment]; ok {
+			continue
+		}
+		path := filepath.Join(root, file.name)
+		vars, err := readEnv(path)
+		if err != nil {
+			return nil, err
+		}
+		if u := pickURL(vars); u != "" {
+			found[file.environment] = Server{URL: u, Environment: file.environment, Source: path}
+		}
+	}
+
+	if _, ok := found[Production]; !ok {
+		project, err := readVercel(root)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			found[Production] = Server{URL: project.production, Environment: Production, Source: project.path}
+		}
+	}
+
+	var servers []Server
+	for _, environment := range Environments {
+		server, ok := found[environment]
+		if ok && selected(environments, environment) {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+// None selects no environment
+const None = "none"
+
+// ParseEnvironments reads a comma-separated selection such as "production,preview".
+// It returns nil for an empty selection and an empty list for "none".
+func ParseEnvironments(list []string) ([]string, error) {
+	var environments []string
+	for _, item := range list {
+		for _, name := range strings.Split(item, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if name == None {
+				return []string{}, nil
+			}
+			if !selected(Environments, name) {
+				return nil, fmt.Errorf("unknown environment %q (expected production, preview or development)", name)
+			}
+			environments = append(environments, name)
+		}
+	}
+	return environments, nil
+}
+
+func selected(environments []string, environment string) bool {
+	for _, e := range environments {
+		if e == environment {
+			return true
+		}
+	}
+	return false
+}
+
+// projectRoot is the closest directory at or above dir with a project marker
+func projectRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		for _, marker := range markers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// readEnv parses KEY=value lines; a missing file has no variables
+func readEnv(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer file.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted := strings.Trim(value, `"'`); len(unquoted) < len(value) {
+			value = unquoted
+		} else if i := strings.Index(value, " #"); i >= 0 {
+			value = strings.TrimSpace(value[:i])
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return vars, nil
+}
+
+// pickURL returns the origin of the first URL variable that is set. Values that
+// reference other variables are only known at runtime and are skipped.
+func pickURL(vars map[string]string) string {
+	for _, name := range urlVariables {
+		value := vars[name]
+		if value == "" || strings.Contains(value, "$") {
+			continue
+		}
+		if origin := origin(value); origin != "" {
+			return origin
+		}
+	}
+	return ""
+}
+
+// origin keeps the scheme and host of value; Vercel system variables have no scheme
+func origin(value string) string {
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// vercelProject is what the Vercel config says about where the app is deployed
+type vercelProject struct {
+	path       string
+	production string
+}
+
+// readVercel reads the production domain from the alias in vercel.json or
+// .vercel/project.json, written by `vercel link`, or returns nil without one
+func readVercel(root string) (*vercelProject, error) {
+	for _, name := range []string{"vercel.json", filepath.Join(".vercel", "project.json")} {
+		path := filepath.Join(root, name)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var config struct {
+			Alias interface{} `json:"alias"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if production := origin(firstAlias(config.Alias)); production != "" {
+			return &vercelProject{path: path, production: production}, nil
+		}
+	}
+	return nil, nil
+}
+
+// firstAlias reads the alias field, a domain or a list of domains
+func firstAlias(alias interface{}) string {
+	switch a := alias.(type) {
+	case string:
+		return a
+	case []interface{}:
+		for _, item := range a {
+			if s, ok := item.(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
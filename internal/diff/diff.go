@@ -0,0 +1,232 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/specfile"
+)
+
+// Kinds of change between two specs
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Change describes one operation-level difference
+type Change struct {
+	Kind     string   `json:"kind"`
+	Method   string   `json:"method"`
+	Path     string   `json:"path"`
+	Details  []string `json:"details,omitempty"`
+	Breaking bool     `json:"breaking"`
+}
+
+// Result holds every change between two specs, sorted by path and method,
+// and the component schema changes, sorted by name
+type Result struct {
+	Changes []Change       `json:"changes"`
+	Schemas []SchemaChange `json:"schemas,omitempty"`
+}
+
+// HasChanges reports whether the specs differ at all
+func (r Result) HasChanges() bool {
+	return len(r.Changes) > 0 || len(r.Schemas) > 0
+}
+
+// Breaking returns the operation changes that may break existing clients
+func (r Result) Breaking() []Change {
+	var breaking []Change
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// BreakingSchemas returns the schema changes that may break existing clients
+func (r Result) BreakingSchemas() []SchemaChange {
+	var breaking []SchemaChange
+	for _, c := range r.Schemas {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// LoadSpec reads a JSON or YAML spec into a generic map. A missing file yields an empty spec.
+func LoadSpec(path string) (map[string]interface{}, error) {
+	spec, err := specfile.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]interface{}{}, nil
+	}
+	return spec, err
+}
+
+// ToMap converts any spec value into the generic form used by Compare
+func ToMap(spec interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+// Compare returns the operation-level and schema differences between two specs
+func Compare(oldSpec, newSpec map[string]interface{}) Result {
+	oldOps := operations(oldSpec)
+	newOps := operations(newSpec)
+
+	var result Result
+	for key, newOp := range newOps {
+		oldOp, ok := oldOps[key]
+		if !ok {
+			result.Changes = append(result.Changes, Change{Kind: Added, Method: key.method, Path: key.path, Details: describeParameters(newOp)})
+			continue
+		}
+		if details, breaking := compareOperation(oldOp, newOp); len(details) > 0 {
+			result.Changes = append(result.Changes, Change{
+				Kind: Changed, Method: key.method, Path: key.path, Details: details, Breaking: breaking,
+			})
+		}
+	}
+	for key := range oldOps {
+		if _, ok := newOps[key]; !ok {
+			result.Changes = append(result.Changes, Change{Kind: Removed, Method: key.method, Path: key.path, Breaking: true})
+		}
+	}
+
+	sort.Slice(result.Changes, func(i, j int) bool {
+		a, b := result.Changes[i], result.Changes[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	result.Schemas = CompareSchemas(oldSpec, newSpec)
+	return result
+}
+
+type opKey struct {
+	path   string
+	method string
+}
+
+func operations(spec map[string]interface{}) map[opKey]map[string]interface{} {
+	ops := make(map[opKey]map[string]interface{})
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, method := range methods {
+			if op, ok := pathItem[method].(map[string]interface{}); ok {
+				ops[opKey{path: path, method: strings.ToUpper(method)}] = op
+			}
+		}
+	}
+	return ops
+}
+
+// compareOperation lists human-readable differences and whether any is breaking
+func compareOperation(oldOp, newOp map[string]interface{}) ([]string, bool) {
+	var details []string
+	breaking := false
+
+	if oldOp["summary"] != newOp["summary"] {
+		details = append(details, "summary changed")
+	}
+	if oldOp["description"] != newOp["description"] {
+		details = append(details, "description changed")
+	}
+
+	oldParams, newParams := parameters(oldOp), parameters(newOp)
+	for name, required := range newParams {
+		if _, ok := oldParams[name]; !ok {
+			if required {
+				details = append(details, "required parameter `"+name+"` added")
+				breaking = true
+			} else {
+				details = append(details, "parameter `"+name+"` added")
+			}
+		}
+	}
+	for name := range oldParams {
+		if _, ok := newParams[name]; !ok {
+			details = append(details, "parameter `"+name+"` removed")
+			breaking = true
+		}
+	}
+
+	oldResponses, _ := oldOp["responses"].(map[string]interface{})
+	newResponses, _ := newOp["responses"].(map[string]interface{})
+	for status, response := range newResponses {
+		old, ok := oldResponses[status]
+		switch {
+		case !ok:
+			details = append(details, "response "+status+" added")
+		case !reflect.DeepEqual(old, response):
+			details = append(details, "response "+status+" changed")
+		}
+	}
+	for status := range oldResponses {
+		if _, ok := newResponses[status]; !ok {
+			details = append(details, "response "+status+" removed")
+			if strings.HasPrefix(status, "2") {
+				breaking = true
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(oldOp["requestBody"], newOp["requestBody"]) {
+		details = append(details, "request body changed")
+	}
+	if !reflect.DeepEqual(oldOp["security"], newOp["security"]) {
+		details = append(details, "security changed")
+		breaking = true
+	}
+
+	sort.Strings(details)
+	return details, breaking
+}
+
+// describeParameters lists the parameters of a new operation, e.g. the page and
+// limit of a list endpoint, so reviewers see how it is called
+func describeParameters(op map[string]interface{}) []string {
+	params := parameters(op)
+	if len(params) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(params))
+	for name, required := range params {
+		if required {
+			name += " (required)"
+		}
+		names = append(names, "`"+name+"`")
+	}
+	sort.Strings(names)
+	return []string{"parameters " + strings.Join(names, ", ")}
+}
+
+// parameters maps "in:name" to whether the parameter is required
+func parameters(op map[string]interface{}) map[string]bool {
+	params := make(map[string]bool)
+	list, _ := op["parameters"].([]interface{})
+	for _, p := range list {
+		param, _ := p.(map[string]interface{})
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+		params[in+":"+name] = required
+	}
+	return params
+}
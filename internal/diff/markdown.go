@@ -0,0 +1,64 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommentMarker identifies comments written by this tool so they can be updated in place
+const CommentMarker = "<!-- nextjs-to-openapi:spec-diff -->"
+
+// Markdown renders the changes as a pull request comment body
+func Markdown(result Result) string {
+	var b strings.Builder
+	b.WriteString(CommentMarker + "\n")
+	b.WriteString("## 📘 OpenAPI changes\n\n")
+
+	if !result.HasChanges() {
+		b.WriteString("No changes to the API surface.\n")
+		return b.String()
+	}
+
+	counts := map[string]int{}
+	for _, c := range result.Changes {
+		counts[c.Kind]++
+	}
+	fmt.Fprintf(&b, "**%d added**, **%d removed**, **%d changed**", counts[Added], counts[Removed], counts[Changed])
+	if len(result.Schemas) > 0 {
+		fmt.Fprintf(&b, ", **%d schemas changed**", len(result.Schemas))
+	}
+	if breaking := len(result.Breaking()) + len(result.BreakingSchemas()); breaking > 0 {
+		fmt.Fprintf(&b, " — ⚠️ **%d potentially breaking**", breaking)
+	}
+	b.WriteString("\n\n")
+
+	if len(result.Changes) > 0 {
+		b.WriteString("| | Method | Path | Details |\n")
+		b.WriteString("|---|---|---|---|\n")
+		for _, c := range result.Changes {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` | %s |\n", icon(c.Kind, c.Breaking), c.Method, c.Path, strings.Join(c.Details, "<br>"))
+		}
+	}
+
+	if len(result.Schemas) > 0 {
+		if len(result.Changes) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("### Schemas\n\n")
+		b.WriteString("| | Schema | Details |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, c := range result.Schemas {
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", icon(c.Kind, c.Breaking), c.Name, strings.Join(c.Details, "<br>"))
+		}
+	}
+
+	return b.String()
+}
+
+func icon(kind string, breaking bool) string {
+	icon := map[string]string{Added: "🟢", Removed: "🔴", Changed: "🟡"}[kind]
+	if breaking {
+		icon += " ⚠️"
+	}
+	return icon
+}
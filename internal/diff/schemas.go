@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// ChangedIn is the extension recording the version in which a component
+// schema last changed
+const ChangedIn = "x-changed-in"
+
+// SchemaChange describes one component schema difference
+type SchemaChange struct {
+	Kind     string   `json:"kind"`
+	Name     string   `json:"name"`
+	Details  []string `json:"details,omitempty"`
+	Breaking bool     `json:"breaking"`
+}
+
+// CompareSchemas returns the component schema differences between two specs,
+// sorted by name. x-changed-in annotations are ignored.
+func CompareSchemas(oldSpec, newSpec map[string]interface{}) []SchemaChange {
+	oldSchemas, newSchemas := Schemas(oldSpec), Schemas(newSpec)
+
+	var changes []SchemaChange
+	for name, schema := range newSchemas {
+		old, ok := oldSchemas[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Kind: Added, Name: name})
+			continue
+		}
+		if details, breaking := compareSchema(old, schema); len(details) > 0 {
+			changes = append(changes, SchemaChange{Kind: Changed, Name: name, Details: details, Breaking: breaking})
+		}
+	}
+	for name := range oldSchemas {
+		if _, ok := newSchemas[name]; !ok {
+			changes = append(changes, SchemaChange{Kind: Removed, Name: name, Breaking: true})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+	return changes
+}
+
+// Schemas returns a spec's component schemas by name
+func Schemas(spec map[string]interface{}) map[string]map[string]interface{} {
+	components, _ := spec["components"].(map[string]interface{})
+	all, _ := components["schemas"].(map[string]interface{})
+	schemas := make(map[string]map[string]interface{}, len(all))
+	for name, s := range all {
+		if schema, ok := s.(map[string]interface{}); ok {
+			schemas[name] = schema
+		}
+	}
+	return schemas
+}
+
+// compareSchema lists property-level differences and whether any is breaking
+func compareSchema(oldSchema, newSchema map[string]interface{}) ([]string, bool) {
+	oldSchema, newSchema = withoutChangedIn(oldSchema), withoutChangedIn(newSchema)
+	if reflect.DeepEqual(oldSchema, newSchema) {
+		return nil, false
+	}
+
+	var details []string
+	breaking := false
+	if oldSchema["type"] != newSchema["type"] {
+		details = append(details, fmt.Sprintf("type changed from %v to %v", oldSchema["type"], newSchema["type"]))
+		breaking = true
+	}
+
+	oldProps, _ := oldSchema["properties"].(map[string]interface{})
+	newProps, _ := newSchema["properties"].(map[string]interface{})
+	oldRequired, newRequired := requiredSet(oldSchema), requiredSet(newSchema)
+	for _, name := range slices.Sorted(maps.Keys(newProps)) {
+		old, ok := oldProps[name]
+		switch {
+		case !ok && newRequired[name]:
+			details = append(details, "required property `"+name+"` added")
+			breaking = true
+		case !ok:
+			details = append(details, "property `"+name+"` added")
+		case !reflect.DeepEqual(old, newProps[name]):
+			oldType, newType := typeOf(old), typeOf(newProps[name])
+			if oldType != newType {
+				details = append(details, fmt.Sprintf("property `%s` type changed from %s to %s", name, oldType, newType))
+				breaking = true
+			} else {
+				details = append(details, "property `"+name+"` changed")
+			}
+		}
+		if ok && newRequired[name] && !oldRequired[name] {
+			details = append(details, "property `"+name+"` is now required")
+			breaking = true
+		}
+		if ok && oldRequired[name] && !newRequired[name] {
+			details = append(details, "property `"+name+"` is no longer required")
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(oldProps)) {
+		if _, ok := newProps[name]; !ok {
+			details = append(details, "property `"+name+"` removed")
+			breaking = true
+		}
+	}
+
+	if len(details) == 0 {
+		details = append(details, "schema changed")
+	}
+	return details, breaking
+}
+
+func withoutChangedIn(schema map[string]interface{}) map[string]interface{} {
+	if _, ok := schema[ChangedIn]; !ok {
+		return schema
+	}
+	schema = maps.Clone(schema)
+	delete(schema, ChangedIn)
+	return schema
+}
+
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	set := make(map[string]bool)
+	list, _ := schema["required"].([]interface{})
+	for _, name := range list {
+		if s, ok := name.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// typeOf names a property's type, its reference or "unknown"
+func typeOf(property interface{}) string {
+	p, _ := property.(map[string]interface{})
+	if ref, ok := p["$ref"].(string); ok {
+		return ref
+	}
+	if t, ok := p["type"]; ok {
+		return fmt.Sprint(t)
+	}
+	return "unknown"
+}
@@ -0,0 +1,154 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/examples"
+)
+
+// UntaggedSection holds operations without tags
+const UntaggedSection = "Other"
+
+var (
+	methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+	nonWord     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// page is the documentation rendered as Markdown or HTML
+type page struct {
+	Title       string
+	Version     string
+	Description string
+	Sections    []section
+}
+
+type section struct {
+	Tag        string
+	Operations []operation
+}
+
+type operation struct {
+	Anchor      string
+	Method      string // Uppercase
+	Path        string
+	Summary     string
+	Description string
+	Deprecated  bool
+	Parameters  []parameter
+	Body        string // Example request body as indented JSON
+	Responses   []response
+}
+
+type parameter struct {
+	Name, In, Type, Description string
+	Required                    bool
+}
+
+type response struct {
+	Status, Description, Example string
+}
+
+// collect reads the spec into sections by first tag, in path then method order
+func collect(spec map[string]interface{}) page {
+	info, _ := spec["info"].(map[string]interface{})
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+
+	p := page{Title: "API", Version: str(info["version"]), Description: str(info["description"])}
+	if title := str(info["title"]); title != "" {
+		p.Title = title
+	}
+
+	byTag := make(map[string][]operation)
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range methodOrder {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tag := UntaggedSection
+			if tags, _ := op["tags"].([]interface{}); len(tags) > 0 {
+				tag = fmt.Sprint(tags[0])
+			}
+
+			o := operation{
+				Anchor:      strings.Trim(nonWord.ReplaceAllString(strings.ToLower(method+" "+path), "-"), "-"),
+				Method:      strings.ToUpper(method),
+				Path:        path,
+				Summary:     str(op["summary"]),
+				Description: str(op["description"]),
+				Deprecated:  op["deprecated"] == true,
+			}
+			params, _ := item["parameters"].([]interface{})
+			opParams, _ := op["parameters"].([]interface{})
+			for _, raw := range append(params, opParams...) {
+				param := examples.Parameter(raw, components)
+				schema, _ := param["schema"].(map[string]interface{})
+				o.Parameters = append(o.Parameters, parameter{
+					Name:        str(param["name"]),
+					In:          str(param["in"]),
+					Type:        str(schema["type"]),
+					Description: str(param["description"]),
+					Required:    param["required"] == true,
+				})
+			}
+			if body := examples.RequestBody(op, components); body != nil {
+				o.Body = indent(body)
+			}
+			responses, _ := op["responses"].(map[string]interface{})
+			for _, status := range slices.Sorted(maps.Keys(responses)) {
+				r, _ := responses[status].(map[string]interface{})
+				if ref, ok := r["$ref"].(string); ok {
+					r, _ = examples.Resolve(ref, components).(map[string]interface{})
+				}
+				o.Responses = append(o.Responses, response{Status: status, Description: str(r["description"]), Example: responseExample(r, components)})
+			}
+			byTag[tag] = append(byTag[tag], o)
+		}
+	}
+
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		if tag != UntaggedSection {
+			p.Sections = append(p.Sections, section{Tag: tag, Operations: byTag[tag]})
+		}
+	}
+	if ops, ok := byTag[UntaggedSection]; ok {
+		p.Sections = append(p.Sections, section{Tag: UntaggedSection, Operations: ops})
+	}
+	return p
+}
+
+// responseExample renders the JSON example of a response, if it has a JSON schema
+func responseExample(r map[string]interface{}, components map[string]interface{}) string {
+	content, _ := r["content"].(map[string]interface{})
+	media, _ := content["application/json"].(map[string]interface{})
+	if media == nil {
+		return ""
+	}
+	if ex, ok := media["example"]; ok {
+		return indent(ex)
+	}
+	if ex := examples.Fake(media["schema"], components); ex != nil {
+		return indent(ex)
+	}
+	return ""
+}
+
+func indent(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
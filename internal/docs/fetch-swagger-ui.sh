@@ -0,0 +1,13 @@
+#!/bin/sh
+# Downloads the swagger-ui-dist files the Swagger UI page embeds; commit the result.
+# Usage: fetch-swagger-ui.sh <version>
+set -eu
+
+version="$1"
+dir="$(dirname "$0")/swagger-ui"
+tmp="$(mktemp -d)"
+trap 'rm -rf "$tmp"' EXIT
+
+curl -fsSL "https://registry.npmjs.org/swagger-ui-dist/-/swagger-ui-dist-$version.tgz" | tar -xz -C "$tmp"
+cp "$tmp/package/swagger-ui.css" "$tmp/package/swagger-ui-bundle.js" "$dir/"
+echo "swagger-ui-dist $version -> $dir"
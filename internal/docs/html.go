@@ -0,0 +1,69 @@
+package docs
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+)
+
+// htmlTemplate is a self-contained page: styles are inline and nothing is
+// loaded from a CDN, so the file can be opened offline or attached to a release
+var htmlTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; color: #1f2328; display: flex; }
+nav { width: 260px; height: 100vh; overflow-y: auto; position: sticky; top: 0; background: #f6f8fa; border-right: 1px solid #d0d7de; padding: 16px; box-sizing: border-box; flex-shrink: 0; }
+nav h2 { font-size: 13px; text-transform: uppercase; color: #656d76; margin: 16px 0 4px; }
+nav a { display: block; color: inherit; text-decoration: none; font-size: 13px; padding: 2px 0; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+main { padding: 24px 40px; max-width: 960px; min-width: 0; }
+section.op { border: 1px solid #d0d7de; border-radius: 6px; padding: 16px; margin: 16px 0; }
+.method { display: inline-block; min-width: 56px; text-align: center; border-radius: 4px; color: #fff; font-weight: 600; font-size: 12px; padding: 2px 6px; margin-right: 8px; }
+.get { background: #2e7d32; } .post { background: #1565c0; } .put { background: #ef6c00; } .patch { background: #6a1b9a; } .delete { background: #c62828; } .head, .options { background: #57606a; }
+.deprecated { text-decoration: line-through; color: #656d76; }
+code, pre { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; font-size: 13px; }
+pre { background: #f6f8fa; padding: 12px; border-radius: 6px; overflow-x: auto; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { text-align: left; border-bottom: 1px solid #d0d7de; padding: 4px 8px; vertical-align: top; }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{.Title}}</strong>{{if .Version}} <small>{{.Version}}</small>{{end}}
+{{range .Sections}}<h2>{{.Tag}}</h2>
+{{range .Operations}}<a href="#{{.Anchor}}"><span class="method {{lower .Method}}">{{.Method}}</span>{{.Path}}</a>
+{{end}}{{end}}</nav>
+<main>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{range .Sections}}<h2>{{.Tag}}</h2>
+{{range .Operations}}<section class="op" id="{{.Anchor}}">
+<h3{{if .Deprecated}} class="deprecated"{{end}}><span class="method {{lower .Method}}">{{.Method}}</span><code>{{.Path}}</code></h3>
+{{if .Summary}}<p><strong>{{.Summary}}</strong></p>{{end}}
+{{if and .Description (ne .Description .Summary)}}<p>{{.Description}}</p>{{end}}
+{{if .Parameters}}<table><tr><th>Parameter</th><th>In</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .Parameters}}<tr><td><code>{{.Name}}</code></td><td>{{.In}}</td><td>{{.Type}}</td><td>{{if .Required}}yes{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Body}}<p>Request body:</p><pre>{{.Body}}</pre>{{end}}
+{{if .Responses}}<table><tr><th>Status</th><th>Description</th></tr>
+{{range .Responses}}<tr><td>{{.Status}}</td><td>{{.Description}}{{if .Example}}<pre>{{.Example}}</pre>{{end}}</td></tr>
+{{end}}</table>{{end}}
+</section>
+{{end}}{{end}}</main>
+</body>
+</html>
+`))
+
+// HTML renders the spec as a single self-contained HTML page
+func HTML(spec map[string]interface{}) (string, error) {
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, collect(spec)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
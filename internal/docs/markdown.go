@@ -0,0 +1,79 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the spec as a single Markdown reference, one section per tag
+func Markdown(spec map[string]interface{}) string {
+	p := collect(spec)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", p.Title)
+	if p.Version != "" {
+		fmt.Fprintf(&b, "Version %s\n\n", p.Version)
+	}
+	if p.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", p.Description)
+	}
+
+	for _, s := range p.Sections {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", s.Tag, anchor(s.Tag))
+	}
+
+	for _, s := range p.Sections {
+		fmt.Fprintf(&b, "\n## %s\n", s.Tag)
+		for _, o := range s.Operations {
+			fmt.Fprintf(&b, "\n### `%s %s`\n\n", o.Method, o.Path)
+			if o.Deprecated {
+				b.WriteString("> **Deprecated**\n\n")
+			}
+			if o.Summary != "" {
+				fmt.Fprintf(&b, "**%s**\n\n", o.Summary)
+			}
+			if o.Description != "" && o.Description != o.Summary {
+				fmt.Fprintf(&b, "%s\n\n", o.Description)
+			}
+
+			if len(o.Parameters) > 0 {
+				b.WriteString("| Parameter | In | Type | Required | Description |\n|---|---|---|---|---|\n")
+				for _, param := range o.Parameters {
+					required := ""
+					if param.Required {
+						required = "yes"
+					}
+					fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n", param.Name, param.In, param.Type, required, cell(param.Description))
+				}
+				b.WriteString("\n")
+			}
+			if o.Body != "" {
+				fmt.Fprintf(&b, "Request body:\n\n```json\n%s\n```\n\n", o.Body)
+			}
+			if len(o.Responses) > 0 {
+				b.WriteString("| Status | Description |\n|---|---|\n")
+				for _, r := range o.Responses {
+					fmt.Fprintf(&b, "| %s | %s |\n", r.Status, cell(r.Description))
+				}
+				b.WriteString("\n")
+			}
+			for _, r := range o.Responses {
+				if r.Example != "" && strings.HasPrefix(r.Status, "2") {
+					fmt.Fprintf(&b, "Example `%s` response:\n\n```json\n%s\n```\n\n", r.Status, r.Example)
+					break
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// anchor is the heading anchor GitHub generates
+func anchor(heading string) string {
+	return strings.Trim(nonWord.ReplaceAllString(strings.ToLower(heading), "-"), "-")
+}
+
+// cell keeps text on one table row
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\n", " "), "|", `\|`)
+}
@@ -0,0 +1,63 @@
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+)
+
+//go:generate sh fetch-swagger-ui.sh 5.18.2
+
+// SwaggerUIVersion is the swagger-ui-dist release built into the binary
+const SwaggerUIVersion = "5.18.2"
+
+// SwaggerAssetsPath is where SwaggerAssets is served; the page loads the
+// Swagger UI scripts from there
+const SwaggerAssetsPath = "/swagger-ui/"
+
+// swaggerAssets holds the swagger-ui-dist files the page loads
+//
+//go:embed swagger-ui/swagger-ui-bundle.js swagger-ui/swagger-ui.css
+var swaggerAssets embed.FS
+
+// swaggerTemplate is an embedded Swagger UI shell. It loads the Swagger UI
+// scripts from SwaggerAssetsPath, so the page works offline.
+var swaggerTemplate = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Assets}}swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"><noscript>Swagger UI needs JavaScript; the spec is at <a href="{{.SpecURL}}">{{.SpecURL}}</a>.</noscript></div>
+<script src="{{.Assets}}swagger-ui-bundle.js"></script>
+<script>
+if (window.SwaggerUIBundle) {
+  window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui", deepLinking: true });
+} else {
+  document.getElementById("swagger-ui").textContent = "Swagger UI could not be loaded; the spec is at {{.SpecURL}}.";
+}
+</script>
+</body>
+</html>
+`))
+
+// SwaggerUI renders a Swagger UI page titled title that loads the spec from specURL
+func SwaggerUI(title, specURL string) (string, error) {
+	var b bytes.Buffer
+	err := swaggerTemplate.Execute(&b, struct{ Title, Assets, SpecURL string }{title, SwaggerAssetsPath, specURL})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// SwaggerAssets serves the built-in swagger-ui-dist files under SwaggerAssetsPath
+func SwaggerAssets() http.Handler {
+	dir, _ := fs.Sub(swaggerAssets, "swagger-ui")
+	return http.StripPrefix(SwaggerAssetsPath, http.FileServerFS(dir))
+}
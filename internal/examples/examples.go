@@ -0,0 +1,143 @@
+package examples
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+const maxExampleDepth = 4
+
+// Example builds a sample value for a schema, following $refs into components
+func Example(schema interface{}, components map[string]interface{}) interface{} {
+	return example(schema, components, 0)
+}
+
+func example(v interface{}, components map[string]interface{}, depth int) interface{} {
+	schema, _ := v.(map[string]interface{})
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		return example(Resolve(ref, components), components, depth+1)
+	}
+	if ex, ok := schema["example"]; ok {
+		return ex
+	}
+	if values, ok := schema["enum"].([]interface{}); ok && len(values) > 0 {
+		return values[0]
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		if variants, ok := schema[key].([]interface{}); ok && len(variants) > 0 {
+			if key != "allOf" {
+				return example(variants[0], components, depth+1)
+			}
+			merged := make(map[string]interface{})
+			for _, variant := range variants {
+				if obj, ok := example(variant, components, depth+1).(map[string]interface{}); ok {
+					for k, v := range obj {
+						merged[k] = v
+					}
+				}
+			}
+			return merged
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		obj := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, prop := range properties {
+			obj[name] = example(prop, components, depth+1)
+		}
+		return obj
+	case "array":
+		if item := example(schema["items"], components, depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	case "string":
+		return stringExample(schema)
+	}
+	return nil
+}
+
+// schemaType reads "type", which is a list in OpenAPI 3.1
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	return ""
+}
+
+func stringExample(schema map[string]interface{}) string {
+	switch schema["format"] {
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uri", "url":
+		return "https://example.com"
+	case "binary":
+		return ""
+	}
+	return "example"
+}
+
+// Resolve looks up a local #/components/... reference
+func Resolve(ref string, components map[string]interface{}) interface{} {
+	parts := strings.Split(strings.TrimPrefix(ref, "#/components/"), "/")
+	var current interface{} = components
+	for _, part := range parts {
+		m, _ := current.(map[string]interface{})
+		current = m[part]
+	}
+	return current
+}
+
+// RequestBody builds a sample body for an operation, preferring JSON content
+func RequestBody(operation map[string]interface{}, components map[string]interface{}) interface{} {
+	body, _ := operation["requestBody"].(map[string]interface{})
+	if ref, ok := body["$ref"].(string); ok {
+		body, _ = Resolve(ref, components).(map[string]interface{})
+	}
+	content, _ := body["content"].(map[string]interface{})
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		media, _ := content[mediaType].(map[string]interface{})
+		if strings.Contains(mediaType, "json") || len(content) == 1 {
+			return Example(media["schema"], components)
+		}
+	}
+	return nil
+}
+
+// Parameter returns a parameter object, following a $ref
+func Parameter(p interface{}, components map[string]interface{}) map[string]interface{} {
+	param, _ := p.(map[string]interface{})
+	if ref, ok := param["$ref"].(string); ok {
+		param, _ = Resolve(ref, components).(map[string]interface{})
+	}
+	return param
+}
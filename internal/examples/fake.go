@@ -0,0 +1,295 @@
+package examples
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Fake builds a realistic sample value for a schema: enum members, values for
+// its format, and values guessed from property names (email, createdAt, price),
+// kept within minimum/maximum and length bounds. Examples already in the schema
+// win. The output is fixed, so repeated runs produce the same spec.
+func Fake(schema interface{}, components map[string]interface{}) interface{} {
+	return fake("", schema, components, 0)
+}
+
+// FakeNamed is Fake for a value with a name, such as a parameter, that hints
+// at what it holds
+func FakeNamed(name string, schema interface{}, components map[string]interface{}) interface{} {
+	return fake(name, schema, components, 0)
+}
+
+func fake(name string, v interface{}, components map[string]interface{}, depth int) interface{} {
+	schema, _ := v.(map[string]interface{})
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		return fake(name, Resolve(ref, components), components, depth+1)
+	}
+	if ex, ok := schema["example"]; ok {
+		return ex
+	}
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+	if value, ok := firstEnum(schema["enum"]); ok {
+		return value
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		variants, _ := schema[key].([]interface{})
+		if len(variants) == 0 {
+			continue
+		}
+		if key != "allOf" {
+			return fake(name, variants[0], components, depth+1)
+		}
+		merged := make(map[string]interface{})
+		for _, variant := range variants {
+			if obj, ok := fake(name, variant, components, depth+1).(map[string]interface{}); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		obj := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for prop, propSchema := range properties {
+			obj[prop] = fake(prop, propSchema, components, depth+1)
+		}
+		return obj
+	case "array":
+		if item := fake(singular(name), schema["items"], components, depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "integer":
+		return int(bounded(schema, fakeNumber(name, true)))
+	case "number":
+		return bounded(schema, fakeNumber(name, false))
+	case "boolean":
+		return true
+	case "string":
+		return fitLength(schema, fakeString(name, schema))
+	}
+	return nil
+}
+
+// firstEnum returns the first member of an enum, however it is typed
+func firstEnum(enum interface{}) (interface{}, bool) {
+	switch values := enum.(type) {
+	case []interface{}:
+		if len(values) > 0 {
+			return values[0], true
+		}
+	case []string:
+		if len(values) > 0 {
+			return values[0], true
+		}
+	}
+	return nil, false
+}
+
+// fakeString picks a value by format, then by property name
+func fakeString(name string, schema map[string]interface{}) string {
+	switch schema["format"] {
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "email":
+		return "jane.doe@example.com"
+	case "date-time":
+		return "2024-05-14T09:30:00Z"
+	case "date":
+		return "2024-05-14"
+	case "time":
+		return "09:30:00"
+	case "uri", "url":
+		return "https://example.com/" + strings.ReplaceAll(words(name), " ", "-")
+	case "hostname":
+		return "api.example.com"
+	case "ipv4":
+		return "192.168.1.10"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "aGVsbG8gd29ybGQ="
+	case "password":
+		return "correct-horse-battery"
+	case "binary":
+		return ""
+	}
+
+	n := words(name)
+	last := n
+	if i := strings.LastIndex(n, " "); i >= 0 {
+		last = n[i+1:]
+	}
+	switch {
+	case last == "email":
+		return "jane.doe@example.com"
+	case n == "first name":
+		return "Jane"
+	case n == "last name" || n == "surname":
+		return "Doe"
+	case n == "username" || n == "user name" || n == "handle":
+		return "janedoe"
+	case last == "name":
+		return "Jane Doe"
+	case last == "phone" || last == "mobile":
+		return "+1-555-0100"
+	case last == "url" || last == "website" || last == "avatar" || last == "image" || last == "link":
+		return "https://example.com/" + strings.ReplaceAll(n, " ", "-")
+	case last == "at" || last == "timestamp":
+		return "2024-05-14T09:30:00Z"
+	case last == "date" || last == "birthday":
+		return "2024-05-14"
+	case last == "id" || last == "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case last == "slug":
+		return "getting-started"
+	case last == "title" || last == "subject":
+		return "Getting started"
+	case last == "description" || last == "summary" || last == "bio" || last == "message" || last == "content" || last == "body":
+		return "A short description."
+	case last == "error" || last == "reason":
+		return "Something went wrong"
+	case last == "city":
+		return "Springfield"
+	case last == "country":
+		return "US"
+	case last == "address" || last == "street":
+		return "742 Evergreen Terrace"
+	case last == "zip" || n == "postal code" || last == "zipcode":
+		return "94105"
+	case last == "currency":
+		return "USD"
+	case last == "color" || last == "colour":
+		return "#3366ff"
+	case last == "token" || last == "secret" || last == "key":
+		return "tok_1a2b3c4d5e"
+	case last == "password":
+		return "correct-horse-battery"
+	case last == "locale" || last == "language" || last == "lang":
+		return "en-US"
+	case last == "ip":
+		return "192.168.1.10"
+	case last == "role":
+		return "admin"
+	case last == "status" || last == "state":
+		return "active"
+	case last == "type" || last == "kind":
+		return "default"
+	case last == "cursor":
+		return "eyJpZCI6NDJ9"
+	case n == "":
+		return "example"
+	}
+	return n
+}
+
+// fakeNumber picks a value by property name
+func fakeNumber(name string, integer bool) float64 {
+	n := words(name)
+	switch {
+	case strings.HasSuffix(n, "price") || strings.HasSuffix(n, "amount") || strings.HasSuffix(n, "total") || strings.HasSuffix(n, "cost"):
+		if integer {
+			return 1999
+		}
+		return 19.99
+	case strings.HasSuffix(n, "count") || strings.HasSuffix(n, "quantity") || strings.HasSuffix(n, "qty"):
+		return 3
+	case strings.HasSuffix(n, "age"):
+		return 32
+	case strings.HasSuffix(n, "year"):
+		return 2024
+	case strings.HasSuffix(n, "page"):
+		return 1
+	case strings.HasSuffix(n, "limit") || strings.HasSuffix(n, "size") || strings.HasSuffix(n, "per page"):
+		return 20
+	case strings.HasSuffix(n, "lat") || strings.HasSuffix(n, "latitude"):
+		return 37.7749
+	case strings.HasSuffix(n, "lng") || strings.HasSuffix(n, "lon") || strings.HasSuffix(n, "longitude"):
+		return -122.4194
+	case strings.HasSuffix(n, "rating") || strings.HasSuffix(n, "score"):
+		if integer {
+			return 4
+		}
+		return 4.5
+	case strings.HasSuffix(n, "percent") || strings.HasSuffix(n, "percentage"):
+		return 25
+	case strings.HasSuffix(n, "id"):
+		return 42
+	}
+	if integer {
+		return 1
+	}
+	return 1.5
+}
+
+// bounded moves a number into the schema's minimum/maximum
+func bounded(schema map[string]interface{}, value float64) float64 {
+	if minimum, ok := toFloat(schema["minimum"]); ok && value < minimum {
+		value = minimum
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok && value > maximum {
+		value = maximum
+	}
+	return value
+}
+
+// fitLength pads or cuts a string to the schema's length bounds
+func fitLength(schema map[string]interface{}, value string) string {
+	if maxLength, ok := toFloat(schema["maxLength"]); ok && len(value) > int(maxLength) {
+		value = value[:int(maxLength)]
+	}
+	if minLength, ok := toFloat(schema["minLength"]); ok && len(value) < int(minLength) {
+		value += strings.Repeat("x", int(minLength)-len(value))
+	}
+	return value
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case *float64:
+		if n != nil {
+			return *n, true
+		}
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// words splits camelCase, snake_case and kebab-case names into lowercase words
+func words(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			b.WriteRune(' ')
+			continue
+		case unicode.IsUpper(r) && i > 0:
+			b.WriteRune(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// singular names an array's items, e.g. "tags" -> "tag"
+func singular(name string) string {
+	if strings.HasSuffix(name, "ies") {
+		return strings.TrimSuffix(name, "ies") + "y"
+	}
+	return strings.TrimSuffix(name, "s")
+}
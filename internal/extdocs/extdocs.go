@@ -0,0 +1,111 @@
+package extdocs
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultDir is where pages are written, next to the spec, when none is configured
+const DefaultDir = "api-docs"
+
+// shortLength caps the description left in the operation
+const shortLength = 200
+
+// Page is a Markdown file holding the full description of one operation
+type Page struct {
+	File    string // Relative to the pages directory
+	Content string
+}
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Extract replaces operation descriptions longer than maxLength characters with
+// their first sentence and links a page holding the full text via externalDocs.
+// Links are baseURL followed by the page name, or the page's path under dir
+// when baseURL is empty. Operations that already link external docs are left alone.
+func Extract(spec map[string]interface{}, maxLength int, dir, baseURL string) []Page {
+	if maxLength <= 0 {
+		return nil
+	}
+	paths, _ := spec["paths"].(map[string]interface{})
+
+	var pages []Page
+	used := make(map[string]bool)
+	for _, p := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[p].(map[string]interface{})
+		for _, method := range slices.Sorted(maps.Keys(item)) {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok || method == "parameters" || method == "servers" {
+				continue
+			}
+			description, _ := operation["description"].(string)
+			if len([]rune(description)) <= maxLength || operation["externalDocs"] != nil {
+				continue
+			}
+			if internal, _ := operation["x-internal"].(bool); internal {
+				continue // Pages are published apart from the spec's audiences
+			}
+
+			file := pageName(operation, method, p, used)
+			pages = append(pages, Page{File: file, Content: page(operation, method, p, description)})
+
+			operation["description"] = shorten(description)
+			operation["externalDocs"] = map[string]interface{}{
+				"description": "Full description",
+				"url":         link(baseURL, dir, file),
+			}
+		}
+	}
+	return pages
+}
+
+// pageName is the operationId, or the method and path, as a unique file name
+func pageName(operation map[string]interface{}, method, p string, used map[string]bool) string {
+	name, _ := operation["operationId"].(string)
+	if name == "" {
+		name = method + " " + p
+	}
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	file := slug + ".md"
+	for i := 2; used[file]; i++ {
+		file = fmt.Sprintf("%s-%d.md", slug, i)
+	}
+	used[file] = true
+	return file
+}
+
+// page renders the full description under the operation's summary
+func page(operation map[string]interface{}, method, p, description string) string {
+	title, _ := operation["summary"].(string)
+	if title == "" {
+		title = strings.ToUpper(method) + " " + p
+	}
+	return fmt.Sprintf("# %s\n\n`%s %s`\n\n%s\n", title, strings.ToUpper(method), p, strings.TrimSpace(description))
+}
+
+// shorten keeps the first sentence of the first paragraph, cut to shortLength characters
+func shorten(description string) string {
+	short := strings.TrimSpace(description)
+	if end := strings.Index(short, "\n\n"); end >= 0 {
+		short = short[:end]
+	}
+	if end := strings.Index(short, ". "); end >= 0 {
+		short = short[:end+1]
+	}
+	if runes := []rune(short); len(runes) > shortLength {
+		short = strings.TrimSpace(string(runes[:shortLength-1])) + "…"
+	}
+	return short
+}
+
+// link is where the page will be served
+func link(baseURL, dir, file string) string {
+	if baseURL == "" {
+		return path.Join(dir, file)
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + file
+}
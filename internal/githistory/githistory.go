@@ -0,0 +1,65 @@
+package githistory
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// MaxCommits bounds how far back the log is read, so old files in a long
+// history do not slow every run down
+const MaxCommits = 2000
+
+// Recent returns up to limit of the newest non-merge commits touching each
+// file below dir, keyed by absolute path
+func Recent(dir string, limit int) (map[string][]models.RecentChange, error) {
+	top, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	top = strings.TrimSpace(top)
+
+	// Each commit is a record separator, a "hash<TAB>date<TAB>subject" line and
+	// the files it touched
+	out, err := git(dir, "log", "--no-merges", fmt.Sprintf("--max-count=%d", MaxCommits),
+		"--format=%x1e%h%x09%as%x09%s", "--name-only", "--", ".")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string][]models.RecentChange)
+	for _, record := range strings.Split(out, "\x1e") {
+		header, files, _ := strings.Cut(record, "\n")
+		fields := strings.SplitN(header, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		change := models.RecentChange{Commit: fields[0], Date: fields[1], Subject: fields[2]}
+		for _, file := range strings.Split(files, "\n") {
+			if file = strings.TrimSpace(file); file == "" {
+				continue
+			}
+			path := filepath.Join(top, filepath.FromSlash(file))
+			if len(changes[path]) < limit {
+				changes[path] = append(changes[path], change)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// git runs a git command in dir and returns its output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok && len(exit.Stderr) > 0 {
+			return "", fmt.Errorf("failed to run git %s: %s", args[0], strings.TrimSpace(string(exit.Stderr)))
+		}
+		return "", fmt.Errorf("failed to run git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiURL = "https://api.github.com"
+
+// Client posts pull request comments through the GitHub REST API
+type Client struct {
+	token      string
+	repo       string // "owner/name"
+	httpClient *http.Client
+}
+
+// NewClientFromEnv uses GITHUB_TOKEN and GITHUB_REPOSITORY as set by GitHub Actions
+func NewClientFromEnv() (*Client, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	repo := os.Getenv("GITHUB_REPOSITORY")
+	if token == "" || repo == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN and GITHUB_REPOSITORY must be set to post comments")
+	}
+	return &Client{token: token, repo: repo, httpClient: &http.Client{Timeout: 30 * time.Second}}, nil
+}
+
+var pullRefPattern = regexp.MustCompile(`^refs/pull/(\d+)/`)
+
+// PullRequestFromEnv reads the PR number from GITHUB_REF (refs/pull/<n>/merge)
+func PullRequestFromEnv() (int, error) {
+	m := pullRefPattern.FindStringSubmatch(os.Getenv("GITHUB_REF"))
+	if m == nil {
+		return 0, fmt.Errorf("could not determine pull request number from GITHUB_REF; pass --pr-number")
+	}
+	return strconv.Atoi(m[1])
+}
+
+type comment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// UpsertComment updates the comment containing marker, or creates a new one
+func (c *Client) UpsertComment(pr int, marker, body string) error {
+	comments, err := c.listComments(pr)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range comments {
+		if strings.Contains(existing.Body, marker) {
+			url := fmt.Sprintf("%s/repos/%s/issues/comments/%d", apiURL, c.repo, existing.ID)
+			return c.send("PATCH", url, body)
+		}
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments", apiURL, c.repo, pr)
+	return c.send("POST", url, body)
+}
+
+func (c *Client) listComments(pr int) ([]comment, error) {
+	url := fmt.Sprintf("%s/repos/%s/issues/%d/comments?per_page=100", apiURL, c.repo, pr)
+	req, err := c.newRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub returned status %d listing comments", resp.StatusCode)
+	}
+
+	var comments []comment
+	if err := json.NewDecoder(resp.Body).Decode(&comments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (c *Client) send(method, url, body string) error {
+	payload, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(method, url, payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post comment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("GitHub returned status %d posting comment", resp.StatusCode)
+	}
+	return nil
+}
+
+func (c *Client) newRequest(method, url string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
@@ -0,0 +1,189 @@
+package graph
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// UntaggedGroup holds paths whose operations have no tags
+const UntaggedGroup = "untagged"
+
+var methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+// methodColors are the badge colors used in DOT output
+var methodColors = map[string]string{
+	"get":    "#2e7d32",
+	"post":   "#1565c0",
+	"put":    "#ef6c00",
+	"patch":  "#6a1b9a",
+	"delete": "#c62828",
+}
+
+type operation struct {
+	method  string
+	secured bool
+}
+
+type node struct {
+	id         string
+	path       string
+	operations []operation
+	parent     string // Closest ancestor path that is also in the spec
+}
+
+type group struct {
+	name  string
+	nodes []*node
+}
+
+// collect groups paths by the first tag of their first operation
+func collect(spec map[string]interface{}) []group {
+	paths, _ := spec["paths"].(map[string]interface{})
+	globalSecurity, _ := spec["security"].([]interface{})
+	sorted := slices.Sorted(maps.Keys(paths))
+
+	byTag := make(map[string]*group)
+	for i, path := range sorted {
+		item, _ := paths[path].(map[string]interface{})
+		n := &node{id: fmt.Sprintf("p%d", i), path: path, parent: ancestor(path, sorted)}
+		tag := ""
+
+		for _, method := range methodOrder {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			security, overridden := op["security"].([]interface{})
+			if !overridden {
+				security = globalSecurity
+			}
+			n.operations = append(n.operations, operation{method: method, secured: len(security) > 0})
+			if tags, _ := op["tags"].([]interface{}); tag == "" && len(tags) > 0 {
+				tag = fmt.Sprint(tags[0])
+			}
+		}
+		if len(n.operations) == 0 {
+			continue
+		}
+		if tag == "" {
+			tag = UntaggedGroup
+		}
+		if byTag[tag] == nil {
+			byTag[tag] = &group{name: tag}
+		}
+		byTag[tag].nodes = append(byTag[tag].nodes, n)
+	}
+
+	var groups []group
+	for _, name := range slices.Sorted(maps.Keys(byTag)) {
+		groups = append(groups, *byTag[name])
+	}
+	return groups
+}
+
+// ancestor finds the longest other path that is a prefix of path
+func ancestor(path string, paths []string) string {
+	best := ""
+	for _, candidate := range paths {
+		if candidate != path && strings.HasPrefix(path, strings.TrimSuffix(candidate, "/")+"/") && len(candidate) > len(best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// nodeIDs maps paths to node ids across groups
+func nodeIDs(groups []group) map[string]string {
+	ids := make(map[string]string)
+	for _, g := range groups {
+		for _, n := range g.nodes {
+			ids[n.path] = n.id
+		}
+	}
+	return ids
+}
+
+// Mermaid renders the API surface as a Mermaid flowchart
+func Mermaid(spec map[string]interface{}) string {
+	groups := collect(spec)
+	ids := nodeIDs(groups)
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, g := range groups {
+		fmt.Fprintf(&b, "  subgraph g%d[\"%s\"]\n", i, escapeMermaid(g.name))
+		for _, n := range g.nodes {
+			var badges []string
+			for _, op := range n.operations {
+				badge := strings.ToUpper(op.method)
+				if op.secured {
+					badge += " 🔒"
+				}
+				badges = append(badges, badge)
+			}
+			fmt.Fprintf(&b, "    %s[\"%s<br/>%s\"]\n", n.id, escapeMermaid(n.path), strings.Join(badges, " · "))
+		}
+		b.WriteString("  end\n")
+	}
+	for _, g := range groups {
+		for _, n := range g.nodes {
+			if n.parent != "" {
+				fmt.Fprintf(&b, "  %s --> %s\n", ids[n.parent], n.id)
+			}
+		}
+	}
+	return b.String()
+}
+
+// DOT renders the API surface as a Graphviz digraph with colored method badges
+func DOT(spec map[string]interface{}) string {
+	groups := collect(spec)
+	ids := nodeIDs(groups)
+
+	var b strings.Builder
+	b.WriteString("digraph API {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded, fontname=\"Helvetica\"];\n")
+	for i, g := range groups {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n    label=\"%s\";\n", i, escapeDOT(g.name))
+		for _, n := range g.nodes {
+			var badges []string
+			for _, op := range n.operations {
+				color := methodColors[op.method]
+				if color == "" {
+					color = "#455a64"
+				}
+				badge := fmt.Sprintf(`<font color="%s"><b>%s</b></font>`, color, strings.ToUpper(op.method))
+				if op.secured {
+					badge += " 🔒"
+				}
+				badges = append(badges, badge)
+			}
+			fmt.Fprintf(&b, "    %s [label=<%s<br/>%s>];\n", n.id, escapeHTML(n.path), strings.Join(badges, " "))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, g := range groups {
+		for _, n := range g.nodes {
+			if n.parent != "" {
+				fmt.Fprintf(&b, "  %s -> %s;\n", ids[n.parent], n.id)
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func escapeMermaid(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func escapeDOT(s string) string {
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+func escapeHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,82 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSimulated is returned for provider calls failed on purpose by SetChaos; it
+// matches ErrProviderUnavailable like a real outage
+var ErrSimulated error = &ProviderError{Err: errors.New("simulated provider failure")}
+
+// Chaos modes: fail the call outright, or answer with a response that does not parse
+const (
+	ChaosError     = "error"
+	ChaosMalformed = "malformed"
+)
+
+// Chaos fails a share of provider calls on purpose, so scripts wrapping the
+// tool can exercise retries, resumes and failure reporting without a flaky model
+type Chaos struct {
+	Rate float64 // Share of calls to fail, 0 to 1
+	Mode string  // ChaosError (default) or ChaosMalformed
+	Seed int64   // Makes the failing calls repeatable; 0 picks a random seed
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// ParseChaos reads a spec such as "rate=0.2" or "rate=0.5,mode=malformed,seed=7"
+func ParseChaos(spec string) (*Chaos, error) {
+	chaos := &Chaos{Mode: ChaosError}
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid failure simulation %q: expected key=value", part)
+		}
+		var err error
+		switch key {
+		case "rate":
+			chaos.Rate, err = strconv.ParseFloat(value, 64)
+			if err == nil && (chaos.Rate < 0 || chaos.Rate > 1) {
+				err = fmt.Errorf("must be between 0 and 1")
+			}
+		case "mode":
+			if value != ChaosError && value != ChaosMalformed {
+				err = fmt.Errorf("must be %s or %s", ChaosError, ChaosMalformed)
+			}
+			chaos.Mode = value
+		case "seed":
+			chaos.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			err = fmt.Errorf("unknown key (use rate, mode or seed)")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid failure simulation %s: %w", key, err)
+		}
+	}
+
+	seed := chaos.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	chaos.rng = rand.New(rand.NewSource(seed))
+	return chaos, nil
+}
+
+// SetChaos makes the client fail calls to the provider as chaos describes
+func (c *Client) SetChaos(chaos *Chaos) {
+	c.chaos = chaos
+}
+
+// strike decides whether the next call fails
+func (ch *Chaos) strike() bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.rng.Float64() < ch.Rate
+}
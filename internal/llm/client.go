@@ -0,0 +1,555 @@
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"nextjs-to-openapi/internal/audit"
+	"nextjs-to-openapi/internal/breaker"
+	"nextjs-to-openapi/internal/cache"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/throttle"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned when a cached response is required but missing
+var ErrCacheMiss = errors.New("no cached response (cache is required)")
+
+// PromptVersion is part of every documentation cache key. Bump it when the
+// documentation prompt changes enough that cached answers should be refreshed.
+const PromptVersion = "2"
+
+// Client documents routes with a model served by a Backend
+type Client struct {
+	backend   Backend
+	model     string
+	auditLog  *audit.Logger
+	options   map[string]interface{}
+	cache     cache.Cache
+	cacheTTL  time.Duration
+	cacheOnly bool
+	breaker   *breaker.Breaker
+	throttle  *throttle.Throttle
+	chaos     *Chaos
+	usage     *usage
+
+	maxRetries int
+	backoff    time.Duration
+
+	mu      sync.Mutex
+	flights map[string]*flight // By prompt without the file path
+	shared  int
+}
+
+// flight is one model request that routes with identical prompts share
+type flight struct {
+	done     chan struct{}
+	file     string // Route that sent it
+	response string
+	err      error
+}
+
+// NewClient creates a client asking model on backend
+func NewClient(backend Backend, model string) *Client {
+	return &Client{
+		backend: backend,
+		model:   model,
+		usage:   &usage{},
+		flights: make(map[string]*flight),
+	}
+}
+
+// WithModel returns a client for another model on the same server, sharing
+// this client's cache, breaker, throttle, options, audit log and stats
+func (c *Client) WithModel(model string) Provider {
+	return &Client{
+		backend:   c.backend,
+		model:     model,
+		auditLog:  c.auditLog,
+		options:   c.options,
+		cache:     c.cache,
+		cacheTTL:  c.cacheTTL,
+		cacheOnly: c.cacheOnly,
+		breaker:   c.breaker,
+		throttle:  c.throttle,
+		chaos:     c.chaos,
+		usage:     c.usage,
+		flights:   make(map[string]*flight),
+
+		maxRetries: c.maxRetries,
+		backoff:    c.backoff,
+	}
+}
+
+// SetAuditLogger records every outbound request in the given audit log
+func (c *Client) SetAuditLogger(logger *audit.Logger) {
+	c.auditLog = logger
+}
+
+// SetOptions sets model options such as temperature and seed
+func (c *Client) SetOptions(options map[string]interface{}) {
+	c.options = options
+}
+
+// SetCache serves responses from the cache, storing new ones for ttl (forever
+// when 0); when required, a miss is an error instead of a request to the model
+func (c *Client) SetCache(responses cache.Cache, ttl time.Duration, required bool) {
+	c.cache = responses
+	c.cacheTTL = ttl
+	c.cacheOnly = required
+}
+
+// SetBreaker pauses and retries requests according to the circuit breaker
+func (c *Client) SetBreaker(b *breaker.Breaker) {
+	c.breaker = b
+}
+
+// SetThrottle paces requests and caps how many are outstanding, to share a
+// server politely
+func (c *Client) SetThrottle(t *throttle.Throttle) {
+	c.throttle = t
+}
+
+// RouteDocumentation represents the structured response we want from the model
+type RouteDocumentation struct {
+	Path        string            `json:"path"`
+	Methods     map[string]Method `json:"methods"`
+	Description string            `json:"description"`
+}
+
+// Method represents an HTTP method documentation
+type Method struct {
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses,omitempty"` // Keyed by status code
+	Versions    map[string]string   `json:"versions,omitempty"`  // API version -> how it behaves differently
+}
+
+// Response represents the documented response for one status code
+type Response struct {
+	Description string                   `json:"description"`
+	Schemas     []map[string]interface{} `json:"schemas,omitempty"` // One entry per distinct response shape
+}
+
+// Parameter represents an API parameter
+type Parameter struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	In          string        `json:"in"` // "path", "query", "body"
+	Required    bool          `json:"required"`
+	Description string        `json:"description,omitempty"`
+	Minimum     *float64      `json:"minimum,omitempty"`
+	Maximum     *float64      `json:"maximum,omitempty"`
+	Pattern     string        `json:"pattern,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+}
+
+// Cached reports whether documentation for the route is already in the cache
+func (c *Client) Cached(route models.APIRoute) bool {
+	if c.cache == nil {
+		return false
+	}
+	_, ok := c.cache.Get(c.documentationKey(route))
+	return ok
+}
+
+// documentationKey identifies a route's documentation by the content of its
+// file rather than its whole prompt, so context that changes between runs, such
+// as similar routes or recent commits, does not send an unchanged file again
+func (c *Client) documentationKey(route models.APIRoute) string {
+	options, _ := json.Marshal(c.options)
+	content := sha256.Sum256([]byte(route.Content))
+	return cache.Key(c.backend.Name(), c.model, string(options), PromptVersion, route.Router, route.Path, hex.EncodeToString(content[:]))
+}
+
+// cacheKey identifies a response by everything that shapes it
+func (c *Client) cacheKey(prompt string) string {
+	options, _ := json.Marshal(c.options)
+	return cache.Key(c.model, string(options), prompt)
+}
+
+// DocumentRoute documents a route, giving up with ctx's error once it is
+// done, including while waiting out an open circuit or between retries
+func (c *Client) DocumentRoute(ctx context.Context, route models.APIRoute) (*RouteDocumentation, error) {
+	prompt := c.buildPrompt(route)
+
+	// Serve from cache when possible
+	var key string
+	if c.cache != nil {
+		key = c.documentationKey(route)
+		if cached, ok := c.cache.Get(key); ok {
+			c.usage.hit(prompt, cached)
+			// Cached answers may predate structured output
+			return c.parseResponse(cached, false)
+		}
+		c.usage.miss()
+		if c.cacheOnly {
+			return nil, ErrCacheMiss
+		}
+	}
+
+	// Send request to the model, unless an identical file already was
+	response, err := c.sendOnce(ctx, route, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", c.backend.Name(), err)
+	}
+
+	// Parse the response, asking again while the answer is not documentation
+	doc, err := c.parseResponse(response, c.structured())
+	for attempt := 0; err != nil && attempt < c.maxRetries; attempt++ {
+		fmt.Printf("🔁 Asking again for %s: %v\n", route.FilePath, err)
+		// One request per answer that did not parse; provider failures here
+		// are not retried again
+		if response, err = c.sendAttempt(ctx, route.FilePath, prompt, documentationSchema); err != nil {
+			return nil, fmt.Errorf("failed to send request to %s: %w", c.backend.Name(), err)
+		}
+		doc, err = c.parseResponse(response, c.structured())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s response: %w", c.backend.Name(), err)
+	}
+
+	// Only cache responses that parsed
+	if c.cache != nil {
+		if err := c.cache.Set(key, response, c.cacheTTL); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+	}
+
+	return doc, nil
+}
+
+// sendOnce sends a prompt once per run for all routes serving the same URL
+// path with the same content, such as identical catch-all files in several
+// apps: routes arriving while it is in flight wait for its response, and later
+// ones reuse it. Failed requests are not shared, so each waiting route then
+// sends its own.
+func (c *Client) sendOnce(ctx context.Context, route models.APIRoute, prompt string) (string, error) {
+	// The prompt names the file, which differs between apps
+	anonymous := route
+	anonymous.FilePath = route.Path
+	key := c.cacheKey(c.buildPrompt(anonymous))
+
+	c.mu.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		if f.err == nil {
+			c.mu.Lock()
+			c.shared++
+			c.mu.Unlock()
+			fmt.Printf("♻️ %s is identical to %s, reusing its documentation\n", route.FilePath, f.file)
+			return f.response, nil
+		}
+		return c.send(ctx, route.FilePath, prompt, documentationSchema)
+	}
+	f := &flight{done: make(chan struct{}), file: route.FilePath}
+	c.flights[key] = f
+	c.mu.Unlock()
+
+	f.response, f.err = c.send(ctx, route.FilePath, prompt, documentationSchema)
+	if f.err != nil {
+		c.mu.Lock()
+		delete(c.flights, key)
+		c.mu.Unlock()
+	}
+	close(f.done)
+	return f.response, f.err
+}
+
+// Shared returns how many routes reused the request of an identical route
+func (c *Client) Shared() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.shared
+}
+
+// send dispatches a prompt, waiting out an open circuit and retrying provider
+// failures with backoff while both the route's retries and the retry budget last
+func (c *Client) send(ctx context.Context, routeFile, prompt string, schema json.RawMessage) (string, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := c.sendAttempt(ctx, routeFile, prompt, schema)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if err == nil || !c.retry(ctx, attempt) {
+			return response, err
+		}
+		fmt.Printf("🔁 Retrying %s: %v\n", routeFile, err)
+	}
+}
+
+// sendAttempt sends a prompt once, waiting out an open circuit first and
+// telling the breaker how it went
+func (c *Client) sendAttempt(ctx context.Context, routeFile, prompt string, schema json.RawMessage) (string, error) {
+	var started time.Time
+	if c.breaker != nil {
+		var err error
+		if started, err = c.breaker.Wait(ctx); err != nil {
+			if errors.Is(err, breaker.ErrOpen) {
+				return "", &ProviderError{Err: err}
+			}
+			return "", err
+		}
+	}
+	response, err := c.sendRequest(ctx, routeFile, prompt, schema)
+	if ctx.Err() != nil {
+		// The route ran out of time; that says nothing about the provider
+		if c.breaker != nil {
+			c.breaker.Abandon(started)
+		}
+		return "", ctx.Err()
+	}
+	if c.breaker != nil {
+		c.breaker.Record(started, err)
+	}
+	return response, err
+}
+
+// buildPrompt creates a smart prompt for the model
+func (c *Client) buildPrompt(route models.APIRoute) string {
+	return fmt.Sprintf(`Analyze this Next.js API route file and extract OpenAPI information.
+
+File: %s
+File Type: %s
+Content:
+%s
+
+IMPORTANT: Return ONLY valid JSON with no markdown formatting, no backticks, no code blocks.
+
+Return this exact JSON structure:
+{
+  "path": "/api/path/here",
+  "description": "Brief description of what this API endpoint does",
+  "methods": {
+    "GET": {
+      "summary": "Brief summary",
+      "description": "Detailed description", 
+      "parameters": [
+        {
+          "name": "paramName",
+          "type": "string",
+          "in": "query",
+          "required": false,
+          "description": "Short description of the parameter",
+          "minimum": 1,
+          "maximum": 100,
+          "default": 20
+        }
+      ],
+      "responses": {
+        "200": {
+          "description": "What a successful response contains",
+          "schemas": [
+            {"type": "object", "title": "User", "properties": {"id": {"type": "string"}}}
+          ]
+        }
+      }
+    }
+  }
+}
+
+Rules:
+1. Convert [id] to {id} in the path
+2. Convert [...slug] to {slug} in the path
+3. Only include methods that actually exist in the code
+4. Return ONLY the JSON, no markdown, no explanations, no code blocks
+5. Include one entry in "responses" per status code the handler returns
+6. If a status code can return different shapes, add one JSON schema per shape to "schemas";
+   when the shapes are told apart by a field (e.g. "type" or "status"), give that field an "enum" with its single value
+7. Mark schema properties that can be null with "nullable": true
+8. Give every parameter a one-sentence "description"; add "minimum", "maximum", "pattern",
+   "default" or "enum" only when the code enforces or assigns them, and leave them out otherwise
+9. If the handler behaves differently depending on an API version header (e.g. Accept-Version
+   or X-API-Version), add "versions" mapping each version value to one sentence on how it differs
+10. Give object schemas for a named model or type (e.g. a User, or a TypeScript interface the handler
+   returns) a "title" with its name in PascalCase, and use the same title and shape wherever the same
+   model appears; leave "title" out of one-off objects
+`, route.FilePath, route.FileType, route.Content) + pagesRouter(route) + similarRoute(route.Example) + previousProse(route.Previous) + recentChanges(route.RecentChanges)
+}
+
+// pagesRouter explains how a pages router file maps to methods, since it has
+// one default export instead of a function per method
+func pagesRouter(route models.APIRoute) string {
+	if route.Router != models.PagesRouter {
+		return ""
+	}
+	return `
+This file uses the pages router: its default export serves every method. Document the methods it
+handles by checking req.method, and list none that it rejects (e.g. with a 405).
+`
+}
+
+// similarRoute shows how the most similar accepted route was documented, so
+// alike endpoints get alike wording, parameter names and response shapes
+func similarRoute(example *models.RouteExample) string {
+	if example == nil {
+		return ""
+	}
+	return fmt.Sprintf(`
+For consistency, this is the accepted documentation of a similar route in the same project (%s).
+Follow its conventions for wording, naming and response shapes where they apply, but describe only
+what the code above does:
+%s
+`, example.File, example.Documentation)
+}
+
+// previousProse asks the model to update the accepted descriptions of a changed
+// route rather than rewrite them, so small code changes make small review diffs
+func previousProse(previous map[string]models.OperationProse) string {
+	if len(previous) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`
+The code has changed since these summaries and descriptions were accepted:
+`)
+	for _, method := range slices.Sorted(maps.Keys(previous)) {
+		prose := previous[method]
+		fmt.Fprintf(&b, "%s:\n  summary: %s\n  description: %s\n", method, prose.Summary, prose.Description)
+	}
+	b.WriteString(`
+Reuse this wording word for word where it is still accurate. Only change the sentences the code
+change makes wrong, and only write new text for methods that are not listed.
+`)
+	return b.String()
+}
+
+// recentChanges lists the newest commits touching the file, which often say why
+// an endpoint behaves the way it does, e.g. a deprecation or a new field
+func recentChanges(changes []models.RecentChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(`
+Recent commits touching this file, newest first:
+`)
+	for _, change := range changes {
+		fmt.Fprintf(&b, "- %s (%s): %s\n", change.Date, change.Commit, change.Subject)
+	}
+	b.WriteString(`
+Use them to understand what consumers should know, such as deprecations, new fields or changed
+behavior, but describe only what the code above does.
+`)
+	return b.String()
+}
+
+// sendRequest sends the prompt to the backend, asking for an answer following
+// schema unless it is nil
+func (c *Client) sendRequest(ctx context.Context, routeFile, prompt string, schema json.RawMessage) (string, error) {
+	// Simulated failures happen before anything leaves the machine
+	if c.chaos != nil && c.chaos.strike() {
+		if c.chaos.Mode == ChaosMalformed {
+			return "this is not the JSON you asked for", nil
+		}
+		return "", ErrSimulated
+	}
+
+	for {
+		// A rejected format is retried with the looser one the backend now encodes
+		text, err := c.sendEncoded(ctx, routeFile, prompt, schema)
+		if !errors.Is(err, ErrFormatRejected) {
+			return text, err
+		}
+	}
+}
+
+// sendEncoded encodes and sends one request, throttled and audited
+func (c *Client) sendEncoded(ctx context.Context, routeFile, prompt string, schema json.RawMessage) (string, error) {
+	body, err := c.backend.Encode(Request{Model: c.model, Prompt: prompt, Options: c.options, Schema: schema})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Wait for our turn on a shared server
+	if c.throttle != nil {
+		release, err := c.throttle.Acquire(ctx)
+		if err != nil {
+			return "", err
+		}
+		defer release()
+	}
+
+	// Record what is about to leave the machine
+	if c.auditLog != nil {
+		entry := audit.Entry{
+			Provider:   strings.ToLower(c.backend.Name()),
+			Model:      c.model,
+			RouteFile:  routeFile,
+			PromptHash: audit.HashPrompt(prompt),
+			BytesSent:  len(body),
+		}
+		if err := c.auditLog.Log(entry); err != nil {
+			return "", err
+		}
+	}
+
+	// Send request, timing it for the usage report
+	start := time.Now()
+	completion, err := c.backend.Send(ctx, body)
+	c.usage.request(routeFile, len(prompt), time.Since(start), completion, err)
+	if err != nil {
+		return "", err
+	}
+	return completion.Text, nil
+}
+
+// structured reports whether the backend constrains answers for this client's
+// model to documentationSchema
+func (c *Client) structured() bool {
+	backend, ok := c.backend.(StructuredBackend)
+	return ok && backend.Structured(c.model)
+}
+
+// parseResponse reads the model's response as documentation. Structured
+// responses are JSON as they are; others are extracted from around the JSON first.
+func (c *Client) parseResponse(response string, structured bool) (*RouteDocumentation, error) {
+	cleanedResponse := response
+	if !structured {
+		// Clean up the response - remove markdown code blocks
+		cleanedResponse = cleanMarkdownJSON(response)
+	}
+
+	var doc RouteDocumentation
+	if err := json.Unmarshal([]byte(cleanedResponse), &doc); err != nil {
+		return nil, &ParseError{Response: response, Err: err}
+	}
+
+	return &doc, nil
+}
+
+// cleanMarkdownJSON removes markdown code block formatting from JSON
+func cleanMarkdownJSON(response string) string {
+	// Remove various markdown patterns
+
+	response = strings.ReplaceAll(response, "```JSON", "")
+
+	// Remove any leading/trailing whitespace
+	response = strings.TrimSpace(response)
+
+	// Find the first { and last } to extract just the JSON part
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+
+	if start != -1 && end != -1 && end > start {
+		response = response[start : end+1]
+	}
+
+	return response
+}
@@ -0,0 +1,57 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of documentation failure, for errors.Is
+var (
+	// ErrProviderUnavailable means the model could not be reached or refused the request
+	ErrProviderUnavailable = errors.New("provider unavailable")
+	// ErrParseFailure means the model answered with something that is not route documentation
+	ErrParseFailure = errors.New("unparseable model response")
+	// ErrFormatRejected means the backend refused the answer format of a request
+	// and will use a looser one when the request is encoded again
+	ErrFormatRejected = errors.New("answer format rejected")
+)
+
+// ProviderError is a request to the provider that got no usable answer. It
+// matches ErrProviderUnavailable.
+type ProviderError struct {
+	Provider   string // Backend name, e.g. "Ollama"
+	StatusCode int    // Status of the answer, 0 when there was none
+	Err        error  // Cause, or the server's explanation of the status
+}
+
+func (e *ProviderError) Error() string {
+	if e.StatusCode != 0 && e.Err != nil {
+		return fmt.Sprintf("%s returned status %d: %v", e.Provider, e.StatusCode, e.Err)
+	}
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s returned status %d", e.Provider, e.StatusCode)
+	}
+	return e.Err.Error()
+}
+
+func (e *ProviderError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrProviderUnavailable}
+	}
+	return []error{ErrProviderUnavailable, e.Err}
+}
+
+// ParseError is a model response that could not be read as documentation. It
+// matches ErrParseFailure.
+type ParseError struct {
+	Response string // Raw model output
+	Err      error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse JSON response: %v", e.Err)
+}
+
+func (e *ParseError) Unwrap() []error {
+	return []error{ErrParseFailure, e.Err}
+}
@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DescribeAPI asks the model for the landing section of the docs, written from
+// a digest of the whole spec. A non-empty previous overview is updated rather
+// than rewritten, so small API changes make small review diffs.
+func (c *Client) DescribeAPI(ctx context.Context, digest, previous string) (string, error) {
+	prompt := fmt.Sprintf(`Write the introduction of the reference documentation for this HTTP API. It is shown
+above every operation, so it should orient a developer who has never used the API.
+
+The API, summarized from its OpenAPI document:
+%s
+
+Rules:
+1. Use Markdown with exactly these level-2 headings, in this order: ## Overview, ## Authentication, ## Conventions
+2. Overview: two to four sentences on what the API is for and its main resources, based on the tags and operations
+3. Authentication: the security schemes and which operations need them; say so plainly if none are declared
+4. Conventions: shared patterns such as pagination, error responses, idempotency keys and versioning, only if they appear above
+5. Do not list individual operations and do not invent behavior that is not in the summary
+6. Return ONLY the Markdown, no preamble and no code fences
+`, digest)
+	if previous != "" {
+		prompt += fmt.Sprintf(`
+This is the current introduction. Reuse its wording where it is still accurate and only change
+what the API changes make wrong:
+%s
+`, previous)
+	}
+	return c.complete(ctx, "info.description", prompt)
+}
+
+// DescribeTag asks the model for the one or two sentences shown above the
+// operations grouped under a tag, given a line per operation
+func (c *Client) DescribeTag(ctx context.Context, name string, operations []string) (string, error) {
+	prompt := fmt.Sprintf(`Write the description of the %q group of operations in the reference documentation
+of an HTTP API. It is shown above the group's operations.
+
+The operations in the group:
+%s
+
+Rules:
+1. Write one or two plain sentences saying what the operations are for, as a whole
+2. Do not list the operations, and do not invent behavior they do not suggest
+3. Return ONLY the description, no preamble, no Markdown headings and no code fences
+`, name, "- "+strings.Join(operations, "\n- "))
+	return c.complete(ctx, "tag "+name, prompt)
+}
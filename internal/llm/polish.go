@@ -0,0 +1,25 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// PolishGroupDescription asks the model to turn a folder's README or index
+// comment into a short overview for its tag. Responses are cached like route
+// documentation.
+func (c *Client) PolishGroupDescription(ctx context.Context, name, source, text string) (string, error) {
+	prompt := fmt.Sprintf(`Rewrite the following notes about the %q group of API endpoints as the overview
+shown above its operations in API documentation.
+
+Notes (from %s):
+%s
+
+Rules:
+1. Write two to five sentences of Markdown prose; no headings, no code blocks
+2. Say what the endpoints in the group are for and any rules they share (authentication, limits, conventions)
+3. Leave out setup instructions, file layouts and anything else only a maintainer of the code needs
+4. Return ONLY the overview text, no preamble
+`, name, source, text)
+	return c.complete(ctx, source, prompt)
+}
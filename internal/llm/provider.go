@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Provider documents routes with a language model. The generation pipeline
+// only depends on this, so a new kind of model server does not touch it.
+type Provider interface {
+	DocumentRoute(ctx context.Context, route models.APIRoute) (*RouteDocumentation, error)
+	// DescribeTag writes the description of a group of operations
+	DescribeTag(ctx context.Context, name string, operations []string) (string, error)
+	// DescribeAPI writes info.description from a digest of the whole spec
+	DescribeAPI(ctx context.Context, digest, previous string) (string, error)
+	// PolishGroupDescription rewrites a folder's notes as a tag description
+	PolishGroupDescription(ctx context.Context, name, source, text string) (string, error)
+	// Cached reports whether DocumentRoute would answer route from the cache
+	Cached(route models.APIRoute) bool
+	// WithModel returns a provider for another model on the same server
+	WithModel(model string) Provider
+}
+
+// Backend sends one prompt to a model server. Client wraps a backend with the
+// prompts, caching, retries and accounting every server shares, so supporting
+// another server only means implementing this.
+type Backend interface {
+	Name() string // Shown in errors and the audit log, e.g. "Ollama"
+	// Encode builds the request body, which is audited before Send sends it
+	Encode(req Request) ([]byte, error)
+	// Send errors matching ErrFormatRejected ask for the request to be encoded
+	// and sent again
+	Send(ctx context.Context, body []byte) (Completion, error)
+}
+
+// StructuredBackend is a Backend that can constrain answers to Request.Schema.
+// Structured reports whether it currently does for model, so answers that are
+// not constrained can be cleaned up before parsing.
+type StructuredBackend interface {
+	Backend
+	Structured(model string) bool
+}
+
+// Request is one prompt for a backend
+type Request struct {
+	Model   string
+	Prompt  string
+	Options map[string]interface{} // Model options such as temperature and seed
+	Schema  json.RawMessage        // JSON schema the answer must follow; nil for free text
+}
+
+// Completion is a backend's answer to a Request
+type Completion struct {
+	Text           string
+	PromptTokens   int // As reported by the server, 0 when it does not say
+	ResponseTokens int
+}
+
+var _ Provider = (*Client)(nil)
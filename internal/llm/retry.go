@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// maxBackoff caps the wait between two attempts of one request
+const maxBackoff = 30 * time.Second
+
+// SetRetries retries a failed or unparseable call up to max times, waiting
+// backoff before the first retry and doubling the wait, with jitter, after that
+func (c *Client) SetRetries(max int, backoff time.Duration) {
+	c.maxRetries = max
+	c.backoff = backoff
+}
+
+// retry reports whether a failed attempt may be retried, waiting out its
+// backoff first. Provider failures also spend the breaker's retry budget.
+func (c *Client) retry(ctx context.Context, attempt int) bool {
+	if attempt >= c.maxRetries {
+		return false
+	}
+	if c.breaker != nil && !c.breaker.Retry() {
+		return false
+	}
+
+	timer := time.NewTimer(backoff(c.backoff, attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// backoff is the wait before retry number attempt+1: base doubled attempt
+// times, capped at maxBackoff, then drawn at random from its upper half so
+// routes that failed together do not retry together
+func backoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	wait := base << attempt
+	if wait > maxBackoff || wait <= 0 {
+		wait = maxBackoff
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
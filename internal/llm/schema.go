@@ -0,0 +1,70 @@
+package llm
+
+import "encoding/json"
+
+// documentationSchema is the JSON schema of RouteDocumentation, for backends
+// that can constrain the model's answer to it
+var documentationSchema = mustSchema(map[string]interface{}{
+	"type":     "object",
+	"required": []string{"path", "methods", "description"},
+	"properties": map[string]interface{}{
+		"path":        map[string]interface{}{"type": "string"},
+		"description": map[string]interface{}{"type": "string"},
+		"methods": map[string]interface{}{
+			"type": "object",
+			"additionalProperties": map[string]interface{}{
+				"type":     "object",
+				"required": []string{"summary", "description"},
+				"properties": map[string]interface{}{
+					"summary":     map[string]interface{}{"type": "string"},
+					"description": map[string]interface{}{"type": "string"},
+					"parameters": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"name", "type", "in", "required"},
+							"properties": map[string]interface{}{
+								"name":        map[string]interface{}{"type": "string"},
+								"type":        map[string]interface{}{"type": "string"},
+								"in":          map[string]interface{}{"type": "string", "enum": []string{"path", "query", "body"}},
+								"required":    map[string]interface{}{"type": "boolean"},
+								"description": map[string]interface{}{"type": "string"},
+								"minimum":     map[string]interface{}{"type": "number"},
+								"maximum":     map[string]interface{}{"type": "number"},
+								"pattern":     map[string]interface{}{"type": "string"},
+								"default":     map[string]interface{}{},
+								"enum":        map[string]interface{}{"type": "array"},
+							},
+						},
+					},
+					"responses": map[string]interface{}{
+						"type": "object",
+						"additionalProperties": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"description"},
+							"properties": map[string]interface{}{
+								"description": map[string]interface{}{"type": "string"},
+								"schemas": map[string]interface{}{
+									"type":  "array",
+									"items": map[string]interface{}{"type": "object"},
+								},
+							},
+						},
+					},
+					"versions": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": map[string]interface{}{"type": "string"},
+					},
+				},
+			},
+		},
+	},
+})
+
+func mustSchema(schema map[string]interface{}) json.RawMessage {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// smallPrompt is the size under which a prompt is mostly the instructions
+// around a short route file
+const smallPrompt = 3000
+
+// Stats counts what a run asked of the model and the cache
+type Stats struct {
+	CacheHits      int
+	CacheMisses    int
+	Requests       int // HTTP requests sent, retries included
+	Failures       int
+	SmallPrompts   int
+	PromptTokens   int // As reported by the server
+	ResponseTokens int
+	SavedTokens    int // Estimated for cache hits
+	Latencies      []time.Duration
+
+	LargestPrompt     int // Bytes
+	LargestPromptFile string
+}
+
+// HitRate is the share of lookups the cache answered, from 0 to 1
+func (s Stats) HitRate() float64 {
+	if s.CacheHits+s.CacheMisses == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(s.CacheHits+s.CacheMisses)
+}
+
+// Latency returns the given percentile (0-100) of request latencies
+func (s Stats) Latency(percentile int) time.Duration {
+	if len(s.Latencies) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(s.Latencies))
+	return sorted[min(len(sorted)-1, len(sorted)*percentile/100)]
+}
+
+// usage collects Stats for a client and the clients derived from it
+type usage struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+// hit records a cache hit, estimating the tokens it saved at four bytes each
+func (u *usage) hit(prompt, response string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.stats.CacheHits++
+	u.stats.SavedTokens += (len(prompt) + len(response)) / 4
+}
+
+func (u *usage) miss() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.stats.CacheMisses++
+}
+
+// request records one HTTP request to the provider
+func (u *usage) request(file string, promptBytes int, latency time.Duration, completion Completion, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	s := &u.stats
+	s.Requests++
+	s.Latencies = append(s.Latencies, latency)
+	if err != nil {
+		s.Failures++
+		return
+	}
+	s.PromptTokens += completion.PromptTokens
+	s.ResponseTokens += completion.ResponseTokens
+	if promptBytes < smallPrompt {
+		s.SmallPrompts++
+	}
+	if promptBytes > s.LargestPrompt {
+		s.LargestPrompt, s.LargestPromptFile = promptBytes, file
+	}
+}
+
+// Stats returns the cache and provider usage so far, including that of
+// clients made with WithModel
+func (c *Client) Stats() Stats {
+	c.usage.mu.Lock()
+	defer c.usage.mu.Unlock()
+	stats := c.usage.stats
+	stats.Latencies = slices.Clone(stats.Latencies)
+	return stats
+}
@@ -0,0 +1,41 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// complete asks the model for free text rather than route documentation,
+// serving and filling the cache the same way. label names the request in the
+// audit log and retries.
+func (c *Client) complete(ctx context.Context, label, prompt string) (string, error) {
+	var key string
+	if c.cache != nil {
+		key = c.cacheKey(prompt)
+		if cached, ok := c.cache.Get(key); ok {
+			c.usage.hit(prompt, cached)
+			return cached, nil
+		}
+		c.usage.miss()
+		if c.cacheOnly {
+			return "", ErrCacheMiss
+		}
+	}
+
+	response, err := c.send(ctx, label, prompt, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to %s: %w", c.backend.Name(), err)
+	}
+	response = strings.TrimSpace(response)
+	if response == "" {
+		return "", fmt.Errorf("the model returned no text")
+	}
+
+	if c.cache != nil {
+		if err := c.cache.Set(key, response, c.cacheTTL); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+	}
+	return response, nil
+}
@@ -0,0 +1,63 @@
+package loadgen
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// Artillery builds an Artillery test definition with one weighted scenario per tag.
+// Path parameters are config variables that can be edited or fed from a payload file.
+func Artillery(spec map[string]interface{}, opts Options) map[string]interface{} {
+	scenarios, params := collect(spec, opts)
+
+	duration := 0
+	for _, s := range scenarios {
+		duration = max(duration, seconds(s.Duration))
+	}
+
+	variables := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		variables[name] = []interface{}{value}
+	}
+
+	config := map[string]interface{}{
+		"target": "{{ $processEnvironment.BASE_URL }}",
+		"phases": []interface{}{
+			map[string]interface{}{"duration": max(duration, 1), "arrivalRate": max(opts.ArrivalRate, 1)},
+		},
+	}
+	if len(variables) > 0 {
+		config["variables"] = variables
+	}
+
+	var list []interface{}
+	for _, s := range scenarios {
+		var flow []interface{}
+		for _, r := range s.requests {
+			step := map[string]interface{}{
+				"url": pathParam.ReplaceAllString(r.path, "{{ $1 }}"),
+			}
+			if len(r.query) > 0 {
+				step["qs"] = r.query
+			}
+			if r.body != nil {
+				step["json"] = r.body
+			}
+			if len(r.headers) > 0 {
+				headers := make(map[string]interface{})
+				for _, name := range slices.Sorted(maps.Keys(r.headers)) {
+					headers[name] = r.auth[name] + "{{ $processEnvironment." + r.headers[name] + " }}"
+				}
+				step["headers"] = headers
+			}
+			if code, err := strconv.Atoi(r.status); err == nil {
+				step["expect"] = []interface{}{map[string]interface{}{"statusCode": code}}
+			}
+			flow = append(flow, map[string]interface{}{r.method: step})
+		}
+		list = append(list, map[string]interface{}{"name": s.Tag, "weight": s.Weight, "flow": flow})
+	}
+
+	return map[string]interface{}{"config": config, "scenarios": list}
+}
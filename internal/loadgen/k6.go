@@ -0,0 +1,89 @@
+package loadgen
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// K6 renders a k6 script with one scenario per tag. Path parameters can be
+// overridden with PARAM_<NAME> environment variables.
+func K6(spec map[string]interface{}, opts Options) string {
+	scenarios, params := collect(spec, opts)
+
+	var b strings.Builder
+	b.WriteString("import http from 'k6/http';\n")
+	b.WriteString("import { check, sleep } from 'k6';\n\n")
+	b.WriteString("// Generated from the OpenAPI spec. Run with: k6 run -e BASE_URL=https://staging.example.com <this file>\n")
+	b.WriteString("const BASE_URL = __ENV.BASE_URL || 'http://localhost:3000';\n\n")
+
+	b.WriteString("const PARAMS = {\n")
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		fmt.Fprintf(&b, "  %s: __ENV.PARAM_%s || %s,\n", jsValue(name), strings.ToUpper(nonWord.ReplaceAllString(name, "_")), jsValue(fmt.Sprint(params[name])))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString("export const options = {\n  scenarios: {\n")
+	for _, s := range scenarios {
+		fmt.Fprintf(&b, "    %s: { executor: 'constant-vus', vus: %d, duration: '%s', exec: '%s' },\n",
+			jsValue(s.Tag), s.VUs, s.Duration, identifier(s.Tag))
+	}
+	b.WriteString("  },\n};\n")
+
+	for _, s := range scenarios {
+		fmt.Fprintf(&b, "\nexport function %s() {\n", identifier(s.Tag))
+		for _, r := range s.requests {
+			writeK6Request(&b, r)
+		}
+		b.WriteString("  sleep(1);\n}\n")
+	}
+	return b.String()
+}
+
+func writeK6Request(b *strings.Builder, r request) {
+	target := pathParam.ReplaceAllString(r.path, "${PARAMS['$1']}")
+	if len(r.query) > 0 {
+		// Encoded values also cannot end the template literal or open a ${}
+		query := url.Values{}
+		for name, value := range r.query {
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					query.Add(name, fmt.Sprint(item))
+				}
+				continue
+			}
+			query.Set(name, fmt.Sprint(value))
+		}
+		target += "?" + query.Encode()
+	}
+
+	headers := []string{}
+	if r.body != nil {
+		headers = append(headers, "'Content-Type': 'application/json'")
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.headers)) {
+		headers = append(headers, fmt.Sprintf("%s: `%s${__ENV.%s}`", jsValue(name), r.auth[name], r.headers[name]))
+	}
+	params := fmt.Sprintf("{ headers: { %s } }", strings.Join(headers, ", "))
+
+	method := r.method
+	if method == "delete" {
+		method = "del"
+	}
+	body := "null"
+	if r.body != nil {
+		body = "JSON.stringify(" + jsValue(r.body) + ")"
+	}
+
+	if method == "get" || method == "head" || method == "options" {
+		fmt.Fprintf(b, "  {\n    const res = http.%s(`${BASE_URL}%s`, %s);\n", method, target, params)
+	} else {
+		fmt.Fprintf(b, "  {\n    const res = http.%s(`${BASE_URL}%s`, %s, %s);\n", method, target, body, params)
+	}
+	if r.status != "" {
+		fmt.Fprintf(b, "    check(res, { '%s %s is %s': (r) => r.status === %s });\n", strings.ToUpper(r.method), r.path, r.status, r.status)
+	}
+	b.WriteString("  }\n")
+}
@@ -0,0 +1,203 @@
+package loadgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"nextjs-to-openapi/internal/examples"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Options control the generated scripts
+type Options struct {
+	Scenarios   []models.LoadScenario // Per-tag overrides from the config file
+	VUs         int                   // Default virtual users per tag
+	Duration    string                // Default duration per tag
+	Methods     []string              // Methods to include, e.g. ["get"]
+	ArrivalRate int                   // Artillery arrivals per second
+}
+
+// UntaggedScenario groups operations without tags
+const UntaggedScenario = "untagged"
+
+var (
+	methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+	pathParam   = regexp.MustCompile(`\{([^}]+)\}`)
+	nonWord     = regexp.MustCompile(`\W+`)
+)
+
+type request struct {
+	method  string
+	path    string
+	status  string
+	query   map[string]interface{}
+	body    interface{}
+	headers map[string]string // Header -> environment variable
+	auth    map[string]string // Header -> value prefix, e.g. "Bearer "
+}
+
+type scenario struct {
+	models.LoadScenario
+	requests []request
+}
+
+// collect groups the selected operations into one scenario per tag
+func collect(spec map[string]interface{}, opts Options) ([]scenario, map[string]interface{}) {
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	globalSecurity, _ := spec["security"].([]interface{})
+	params := make(map[string]interface{})
+	byTag := make(map[string]*scenario)
+
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range methodOrder {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok || !slices.Contains(opts.Methods, method) {
+				continue
+			}
+
+			r := request{method: method, path: path, status: successStatus(operation), query: map[string]interface{}{}}
+			for _, p := range append(asList(item["parameters"]), asList(operation["parameters"])...) {
+				param := examples.Parameter(p, components)
+				name := fmt.Sprint(param["name"])
+				value := examples.Example(param["schema"], components)
+				if value == nil {
+					value = "example"
+				}
+				switch {
+				case param["in"] == "path":
+					if _, seen := params[name]; !seen {
+						params[name] = value
+					}
+				case param["in"] == "query" && param["required"] == true:
+					r.query[name] = value
+				}
+			}
+			r.body = examples.RequestBody(operation, components)
+
+			security := globalSecurity
+			if s, ok := operation["security"].([]interface{}); ok {
+				security = s
+			}
+			r.headers, r.auth = authHeaders(security, components)
+
+			tag := UntaggedScenario
+			if tags := asList(operation["tags"]); len(tags) > 0 {
+				tag = fmt.Sprint(tags[0])
+			}
+			if byTag[tag] == nil {
+				byTag[tag] = &scenario{LoadScenario: settingsFor(tag, opts)}
+			}
+			byTag[tag].requests = append(byTag[tag].requests, r)
+		}
+	}
+
+	var scenarios []scenario
+	for _, tag := range slices.Sorted(maps.Keys(byTag)) {
+		scenarios = append(scenarios, *byTag[tag])
+	}
+	return scenarios, params
+}
+
+// settingsFor applies the configured scenario for a tag over the defaults
+func settingsFor(tag string, opts Options) models.LoadScenario {
+	s := models.LoadScenario{Tag: tag, VUs: opts.VUs, Duration: opts.Duration}
+	for _, configured := range opts.Scenarios {
+		if configured.Tag != tag {
+			continue
+		}
+		if configured.VUs > 0 {
+			s.VUs = configured.VUs
+		}
+		if configured.Duration != "" {
+			s.Duration = configured.Duration
+		}
+		s.Weight = configured.Weight
+	}
+	if s.Weight == 0 {
+		s.Weight = max(s.VUs, 1)
+	}
+	return s
+}
+
+// authHeaders maps the first security requirement to headers filled from
+// API_<SCHEME> environment variables
+func authHeaders(security []interface{}, components map[string]interface{}) (map[string]string, map[string]string) {
+	headers, prefixes := make(map[string]string), make(map[string]string)
+	if len(security) == 0 {
+		return headers, prefixes
+	}
+	requirement, _ := security[0].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+
+	for name := range requirement {
+		scheme, _ := schemes[name].(map[string]interface{})
+		env := envName(name)
+		switch {
+		case scheme["type"] == "apiKey" && scheme["in"] == "header":
+			headers[fmt.Sprint(scheme["name"])] = env
+		case scheme["type"] == "http" && scheme["scheme"] == "basic":
+			headers["Authorization"], prefixes["Authorization"] = env, "Basic "
+		default:
+			headers["Authorization"], prefixes["Authorization"] = env, "Bearer "
+		}
+	}
+	return headers, prefixes
+}
+
+func envName(scheme string) string {
+	return "API_" + strings.ToUpper(nonWord.ReplaceAllString(scheme, "_"))
+}
+
+func successStatus(operation map[string]interface{}) string {
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, code := range slices.Sorted(maps.Keys(responses)) {
+		if strings.HasPrefix(code, "2") {
+			return code
+		}
+	}
+	return ""
+}
+
+// identifier turns a tag into a JavaScript function name
+func identifier(tag string) string {
+	words := strings.Fields(nonWord.ReplaceAllString(tag, " "))
+	if len(words) == 0 {
+		return "scenario"
+	}
+	for i := 1; i < len(words); i++ {
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+	name := strings.ToLower(words[0][:1]) + words[0][1:] + strings.Join(words[1:], "")
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "tag" + name
+	}
+	return name
+}
+
+func seconds(duration string) int {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 30
+	}
+	return max(int(d.Seconds()), 1)
+}
+
+func jsValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "undefined"
+	}
+	return string(data)
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
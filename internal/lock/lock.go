@@ -0,0 +1,166 @@
+package lock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// DefaultFile is the lock file written next to the project config
+const DefaultFile = "nextjs-openapi.lock.json"
+
+// Prose is the accepted text for one operation
+type Prose struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
+
+// Entry holds the accepted prose for one version of a route file
+type Entry struct {
+	File    string           `json:"file"`
+	Methods map[string]Prose `json:"methods"`
+}
+
+// File maps route hashes to accepted prose
+type File struct {
+	Routes map[string]Entry `json:"routes"`
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// New returns an empty lock
+func New() *File {
+	return &File{Routes: make(map[string]Entry), seen: make(map[string]bool)}
+}
+
+// Load reads the lock file; a missing file starts an empty lock
+func Load(path string) (*File, error) {
+	f := New()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return f, nil
+		}
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse lock file %s: %w", path, err)
+	}
+	if f.Routes == nil {
+		f.Routes = make(map[string]Entry)
+	}
+	return f, nil
+}
+
+// RouteHash identifies a route by its file path and content, so any edit or move
+// produces a new hash and fresh text
+func RouteHash(route models.APIRoute) string {
+	sum := sha256.Sum256([]byte(route.FilePath + "\x00" + route.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Keep marks a route as still present so its entry survives Save, even if
+// documenting it failed in this run
+func (f *File) Keep(route models.APIRoute) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seen[RouteHash(route)] = true
+}
+
+// Accepted reports whether the route's current code has accepted prose
+func (f *File) Accepted(route models.APIRoute) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.Routes[RouteHash(route)]
+	return ok
+}
+
+// Previous returns the accepted prose of a route's last version when its code
+// has changed since, and nil for unchanged or new routes
+func (f *File) Previous(route models.APIRoute) map[string]models.OperationProse {
+	hash := RouteHash(route)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.Routes[hash]; ok {
+		return nil
+	}
+	for _, old := range slices.Sorted(maps.Keys(f.Routes)) {
+		entry := f.Routes[old]
+		if entry.File != route.FilePath || len(entry.Methods) == 0 {
+			continue
+		}
+		previous := make(map[string]models.OperationProse, len(entry.Methods))
+		for method, prose := range entry.Methods {
+			previous[method] = models.OperationProse(prose)
+		}
+		return previous
+	}
+	return nil
+}
+
+// Forget drops the locked prose of a route so the next Apply records fresh text
+func (f *File) Forget(route models.APIRoute) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.Routes, RouteHash(route))
+}
+
+// Apply replaces generated prose with locked prose for unchanged routes and
+// records the generated prose for routes seen for the first time. It returns
+// whether locked text was used.
+func (f *File) Apply(route models.APIRoute, doc *llm.RouteDocumentation) bool {
+	hash := RouteHash(route)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seen[hash] = true
+
+	if entry, ok := f.Routes[hash]; ok {
+		for method, details := range doc.Methods {
+			if prose, ok := entry.Methods[strings.ToUpper(method)]; ok {
+				details.Summary = prose.Summary
+				details.Description = prose.Description
+				doc.Methods[method] = details
+			}
+		}
+		return true
+	}
+
+	entry := Entry{File: route.FilePath, Methods: make(map[string]Prose)}
+	for method, details := range doc.Methods {
+		entry.Methods[strings.ToUpper(method)] = Prose{Summary: details.Summary, Description: details.Description}
+	}
+	f.Routes[hash] = entry
+	return false
+}
+
+// Save writes the lock, dropping entries for routes not seen in this run
+func (f *File) Save(path string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for hash := range f.Routes {
+		if !f.seen[hash] {
+			delete(f.Routes, hash)
+		}
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,238 @@
+package merge
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ManualMarker marks an object in a spec as edited by hand
+const ManualMarker = "x-manual"
+
+// Preserved counts what PreserveManual carried over from the existing spec
+type Preserved struct {
+	Paths      int // Paths the generator did not produce
+	Components int // Components the generator did not produce
+	Manual     int // Objects marked x-manual: true
+	Dropped    int // Generated paths whose routes are gone
+}
+
+var (
+	pathParamName = regexp.MustCompile(`\{[^}]*\}`)
+	componentRef  = regexp.MustCompile(`^#/components/([^/]+)/([^/]+)$`)
+)
+
+// PreserveManual carries hand edits from an existing spec into a freshly
+// generated one: every object marked x-manual: true replaces its generated
+// counterpart. Of the paths the generator no longer produces, only those
+// marked x-manual, or their operations marked x-manual, are kept, along with
+// the components and tags they use; the rest were generated for routes that
+// are gone. generated is updated in place.
+func PreserveManual(existing, generated map[string]interface{}) Preserved {
+	var kept Preserved
+
+	paths := child(generated, "paths")
+	oldPaths, _ := existing["paths"].(map[string]interface{})
+	var keptPaths []interface{}
+	for _, path := range slices.Sorted(maps.Keys(oldPaths)) {
+		if hasPath(paths, path) {
+			continue
+		}
+		item := manualPath(oldPaths[path])
+		if item == nil {
+			kept.Dropped++
+			continue
+		}
+		paths[path] = item
+		keptPaths = append(keptPaths, item)
+		kept.Paths++
+	}
+
+	// Components the kept paths refer to, directly or through other components
+	oldComponents, _ := existing["components"].(map[string]interface{})
+	components := child(generated, "components")
+	pending := refsOf(keptPaths, nil)
+	for section, entries := range oldComponents {
+		for name, entry := range mapOf(entries) {
+			if isManual(mapOf(entry)) {
+				pending = append(pending, [2]string{section, name})
+			}
+		}
+	}
+	for len(pending) > 0 {
+		ref := pending[0]
+		pending = pending[1:]
+		entry, ok := mapOf(oldComponents[ref[0]])[ref[1]]
+		if !ok {
+			continue
+		}
+		merged := child(components, ref[0])
+		if _, exists := merged[ref[1]]; exists {
+			continue
+		}
+		merged[ref[1]] = entry
+		kept.Components++
+		pending = refsOf(entry, pending)
+	}
+
+	tags, _ := generated["tags"].([]interface{})
+	used := usedTags(paths)
+	oldTags, _ := existing["tags"].([]interface{})
+	for _, tag := range oldTags {
+		if t, ok := tag.(map[string]interface{}); ok && !hasKey(tags, "name", t["name"]) && (used[t["name"]] || isManual(t)) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		generated["tags"] = tags
+	}
+
+	kept.Manual = overlayManual(existing, generated)
+	return kept
+}
+
+// manualPath returns what is kept of a path the generator no longer produces:
+// all of it when the path item is marked x-manual, otherwise its operations
+// marked x-manual with the path-level fields, or nil when there are none
+func manualPath(v interface{}) map[string]interface{} {
+	item := mapOf(v)
+	if isManual(item) {
+		return item
+	}
+	kept := make(map[string]interface{})
+	operations := 0
+	for key, value := range item {
+		if !httpMethods[key] {
+			kept[key] = value
+		} else if isManual(mapOf(value)) {
+			kept[key] = value
+			operations++
+		}
+	}
+	if operations == 0 {
+		return nil
+	}
+	return kept
+}
+
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// refsOf appends the components v refers to with $ref
+func refsOf(v interface{}, refs [][2]string) [][2]string {
+	switch node := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := node["$ref"].(string); ok {
+			if m := componentRef.FindStringSubmatch(ref); m != nil {
+				refs = append(refs, [2]string{m[1], m[2]})
+			}
+		}
+		for _, value := range node {
+			refs = refsOf(value, refs)
+		}
+	case []interface{}:
+		for _, value := range node {
+			refs = refsOf(value, refs)
+		}
+	}
+	return refs
+}
+
+// usedTags returns the tags the operations of paths are listed under
+func usedTags(paths map[string]interface{}) map[interface{}]bool {
+	used := make(map[interface{}]bool)
+	for _, item := range paths {
+		for method, op := range mapOf(item) {
+			if !httpMethods[method] {
+				continue
+			}
+			tags, _ := mapOf(op)["tags"].([]interface{})
+			for _, tag := range tags {
+				used[tag] = true
+			}
+		}
+	}
+	return used
+}
+
+// hasPath reports whether paths has path, ignoring parameter names, which
+// may have been renamed since
+func hasPath(paths map[string]interface{}, path string) bool {
+	if _, ok := paths[path]; ok {
+		return true
+	}
+	normalized := pathParamName.ReplaceAllString(path, "{}")
+	for p := range paths {
+		if strings.EqualFold(pathParamName.ReplaceAllString(p, "{}"), normalized) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlayManual copies the objects of existing marked x-manual into the same
+// place in generated, returning how many it copied. Items of lists, such as
+// parameters and servers, are matched by name and location or by URL.
+func overlayManual(existing, generated map[string]interface{}) int {
+	count := 0
+	for _, key := range slices.Sorted(maps.Keys(existing)) {
+		switch old := existing[key].(type) {
+		case map[string]interface{}:
+			if isManual(old) {
+				generated[key] = old
+				count++
+			} else if fresh, ok := generated[key].(map[string]interface{}); ok {
+				count += overlayManual(old, fresh)
+			}
+		case []interface{}:
+			fresh, isList := generated[key].([]interface{})
+			if !isList && generated[key] != nil {
+				continue
+			}
+			if merged, n := overlayList(old, fresh); n > 0 {
+				generated[key] = merged
+				count += n
+			}
+		}
+	}
+	return count
+}
+
+func overlayList(existing, generated []interface{}) ([]interface{}, int) {
+	count := 0
+	for _, item := range existing {
+		old, ok := item.(map[string]interface{})
+		if !ok || !isManual(old) {
+			continue
+		}
+		count++
+		replaced := false
+		for i, candidate := range generated {
+			if fresh, ok := candidate.(map[string]interface{}); ok && sameItem(old, fresh) {
+				generated[i] = old
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			generated = append(generated, old)
+		}
+	}
+	return generated, count
+}
+
+// sameItem matches parameters and headers by name and location, and servers by URL
+func sameItem(a, b map[string]interface{}) bool {
+	if a["name"] != nil {
+		return a["name"] == b["name"] && a["in"] == b["in"]
+	}
+	return a["url"] != nil && a["url"] == b["url"]
+}
+
+func isManual(v map[string]interface{}) bool {
+	manual, _ := v[ManualMarker].(bool)
+	return manual
+}
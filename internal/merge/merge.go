@@ -0,0 +1,304 @@
+package merge
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Conflict strategies
+const (
+	OnConflictError = "error" // Report conflicts and refuse to merge
+	OnConflictFirst = "first" // Keep the definition from the earliest input
+	OnConflictLast  = "last"  // Keep the definition from the latest input
+)
+
+// Input is one spec to combine
+type Input struct {
+	Name      string                 // Shown in conflict reports, usually the file name
+	Spec      map[string]interface{} // Parsed OpenAPI 3.x document
+	TagPrefix string                 // Prepended to every tag of this input when set
+	// SchemaPrefix renames this input's components that clash with different ones
+	// from earlier inputs, e.g. User -> BillingUser, instead of reporting them
+	SchemaPrefix string
+}
+
+// Options control how inputs are combined
+type Options struct {
+	OnConflict string // OnConflictError (default), OnConflictFirst or OnConflictLast
+}
+
+// Conflict is a path operation or component defined differently by several inputs
+type Conflict struct {
+	Kind    string   `json:"kind"` // "operation" or "component"
+	Name    string   `json:"name"` // "GET /api/users" or "schemas/User"
+	Sources []string `json:"sources"`
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s defined differently in %s", c.Kind, c.Name, strings.Join(c.Sources, " and "))
+}
+
+var operationKeys = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Merge combines specs into one. Conflicts are always returned; with
+// OnConflictError the merged spec is nil when there are any.
+func Merge(inputs []Input, opts Options) (map[string]interface{}, []Conflict) {
+	merged := map[string]interface{}{
+		"paths": map[string]interface{}{},
+	}
+	origin := make(map[string]string) // Operation or component -> input that defined it
+	var conflicts []Conflict
+
+	// clash records a conflict and reports whether the incoming value should win
+	clash := func(kind, name, source string) bool {
+		conflicts = append(conflicts, Conflict{Kind: kind, Name: name, Sources: []string{origin[name], source}})
+		if opts.OnConflict == OnConflictLast {
+			origin[name] = source
+			return true
+		}
+		return false
+	}
+
+	for _, in := range inputs {
+		spec := in.Spec
+		if in.SchemaPrefix != "" {
+			spec = renameClashes(spec, merged, in.SchemaPrefix)
+		}
+
+		// Single-valued fields, externalDocs and extensions come from the
+		// first input that has them
+		for _, key := range slices.Sorted(maps.Keys(spec)) {
+			switch key {
+			case "servers", "components", "paths", "tags":
+				continue
+			}
+			if _, ok := merged[key]; !ok && spec[key] != nil {
+				merged[key] = spec[key]
+			}
+		}
+		merged["servers"] = appendUnique(merged["servers"], spec["servers"], "url")
+
+		// Components, section by section
+		components, _ := spec["components"].(map[string]interface{})
+		for _, section := range slices.Sorted(maps.Keys(components)) {
+			entries, _ := components[section].(map[string]interface{})
+			target := child(child(merged, "components"), section)
+			for _, name := range slices.Sorted(maps.Keys(entries)) {
+				id := section + "/" + name
+				existing, ok := target[name]
+				if ok && !reflect.DeepEqual(existing, entries[name]) {
+					if !clash("component", id, in.Name) {
+						continue
+					}
+				} else if !ok {
+					origin[id] = in.Name
+				}
+				target[name] = entries[name]
+			}
+		}
+
+		// Paths, operation by operation
+		paths, _ := spec["paths"].(map[string]interface{})
+		untagged := false
+		mergedPaths := merged["paths"].(map[string]interface{})
+		for _, path := range slices.Sorted(maps.Keys(paths)) {
+			item, _ := paths[path].(map[string]interface{})
+			target := child(mergedPaths, path)
+			for _, key := range slices.Sorted(maps.Keys(item)) {
+				value := item[key]
+				if slices.Contains(operationKeys, key) {
+					if op, ok := value.(map[string]interface{}); ok && op["tags"] == nil {
+						untagged = true
+					}
+					value = prefixTags(value, in.TagPrefix)
+					id := strings.ToUpper(key) + " " + path
+					existing, ok := target[key]
+					if ok && !reflect.DeepEqual(existing, value) {
+						if !clash("operation", id, in.Name) {
+							continue
+						}
+					} else if !ok {
+						origin[id] = in.Name
+					}
+				} else if _, ok := target[key]; ok {
+					continue // Path-level servers/parameters: first input wins
+				}
+				target[key] = value
+			}
+		}
+
+		// Top-level tags, prefixed like the operations using them
+		var tags []interface{}
+		existing, _ := spec["tags"].([]interface{})
+		for _, t := range existing {
+			tag, _ := t.(map[string]interface{})
+			if tag == nil {
+				continue
+			}
+			renamed := make(map[string]interface{}, len(tag))
+			for k, v := range tag {
+				renamed[k] = v
+			}
+			renamed["name"] = prefixed(in.TagPrefix, fmt.Sprint(tag["name"]))
+			tags = append(tags, renamed)
+		}
+		if in.TagPrefix != "" && untagged && !hasTag(tags, in.TagPrefix) {
+			tags = append(tags, map[string]interface{}{"name": in.TagPrefix})
+		}
+		merged["tags"] = appendUnique(merged["tags"], tags, "name")
+	}
+
+	for _, key := range []string{"servers", "tags"} {
+		if list, _ := merged[key].([]interface{}); len(list) == 0 {
+			delete(merged, key)
+		}
+	}
+
+	if len(conflicts) > 0 && (opts.OnConflict == "" || opts.OnConflict == OnConflictError) {
+		return nil, conflicts
+	}
+	return merged, conflicts
+}
+
+// prefixTags prefixes an operation's tags; untagged operations get the prefix itself
+func prefixTags(op interface{}, prefix string) interface{} {
+	operation, ok := op.(map[string]interface{})
+	if !ok || prefix == "" {
+		return op
+	}
+
+	copied := make(map[string]interface{}, len(operation))
+	for k, v := range operation {
+		copied[k] = v
+	}
+	tags, _ := operation["tags"].([]interface{})
+	if len(tags) == 0 {
+		copied["tags"] = []interface{}{prefix}
+		return copied
+	}
+	renamed := make([]interface{}, len(tags))
+	for i, t := range tags {
+		renamed[i] = prefixed(prefix, fmt.Sprint(t))
+	}
+	copied["tags"] = renamed
+	return copied
+}
+
+func prefixed(prefix, tag string) string {
+	if prefix == "" {
+		return tag
+	}
+	return prefix + "/" + tag
+}
+
+func hasTag(tags []interface{}, name string) bool {
+	for _, t := range tags {
+		if tag, ok := t.(map[string]interface{}); ok && tag["name"] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique appends list entries whose key field is not present yet
+func appendUnique(existing, incoming interface{}, key string) []interface{} {
+	list, _ := existing.([]interface{})
+	more, _ := incoming.([]interface{})
+	for _, entry := range more {
+		m, _ := entry.(map[string]interface{})
+		if m == nil || hasKey(list, key, m[key]) {
+			continue
+		}
+		list = append(list, entry)
+	}
+	return list
+}
+
+func hasKey(list []interface{}, key string, value interface{}) bool {
+	for _, entry := range list {
+		if m, ok := entry.(map[string]interface{}); ok && m[key] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// child returns (creating if needed) a nested map
+func child(parent map[string]interface{}, key string) map[string]interface{} {
+	m, ok := parent[key].(map[string]interface{})
+	if !ok {
+		m = make(map[string]interface{})
+		parent[key] = m
+	}
+	return m
+}
+
+// renameClashes renames components of spec that clash with different ones already
+// merged, rewriting every $ref in spec to match
+func renameClashes(spec, merged map[string]interface{}, prefix string) map[string]interface{} {
+	components, _ := spec["components"].(map[string]interface{})
+	mergedComponents, _ := merged["components"].(map[string]interface{})
+	renames := make(map[string]string)
+
+	for section, entries := range components {
+		existing, _ := mergedComponents[section].(map[string]interface{})
+		for name, value := range entries.(map[string]interface{}) {
+			if current, ok := existing[name]; ok && !reflect.DeepEqual(current, value) {
+				newName := componentName(prefix) + name
+				renames["#/components/"+section+"/"+name] = "#/components/" + section + "/" + newName
+			}
+		}
+	}
+	if len(renames) == 0 {
+		return spec
+	}
+
+	renamed := rewrite(spec, renames).(map[string]interface{})
+	components = renamed["components"].(map[string]interface{})
+	for from, to := range renames {
+		parts := strings.Split(strings.TrimPrefix(from, "#/components/"), "/")
+		section := components[parts[0]].(map[string]interface{})
+		section[to[strings.LastIndex(to, "/")+1:]] = section[parts[1]]
+		delete(section, parts[1])
+	}
+	return renamed
+}
+
+// rewrite deep-copies v, replacing $ref values found in renames
+func rewrite(v interface{}, renames map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			if ref, ok := child.(string); ok && k == "$ref" {
+				if to, ok := renames[ref]; ok {
+					m[k] = to
+					continue
+				}
+			}
+			m[k] = rewrite(child, renames)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, child := range v {
+			list[i] = rewrite(child, renames)
+		}
+		return list
+	}
+	return v
+}
+
+// componentName turns a tag prefix into a component name prefix, e.g. "billing-api" -> "BillingApi"
+func componentName(prefix string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(prefix, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
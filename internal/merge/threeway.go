@@ -0,0 +1,164 @@
+package merge
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Sides of a three-way merge, also the strategies that prefer them on conflict
+const (
+	OnConflictOurs   = "ours"
+	OnConflictTheirs = "theirs"
+)
+
+// listKeys are top-level lists merged entry by entry, keyed by a field
+var listKeys = map[string]string{"tags": "name", "servers": "url"}
+
+// ThreeWay merges two edits of a spec made from a common base, such as the
+// openapi.json regenerated on two branches. Changes are compared unit by unit:
+// each operation and path-level field, each component, each tag and server,
+// and each other top-level field. A unit changed on one side takes that side's
+// version, including removal; a unit changed differently on both sides is a
+// conflict, resolved by opts.OnConflict (OnConflictOurs or OnConflictTheirs).
+// With OnConflictError the merged spec is nil when there are conflicts.
+func ThreeWay(base, ours, theirs map[string]interface{}, opts Options) (map[string]interface{}, []Conflict) {
+	m := threeWay{preferTheirs: opts.OnConflict == OnConflictTheirs}
+	merged := make(map[string]interface{})
+
+	for _, key := range unionKeys(base, ours, theirs) {
+		switch {
+		case key == "paths":
+			m.nested(merged, key, base, ours, theirs, func(path string, b, o, t map[string]interface{}, out map[string]interface{}) {
+				m.nested(out, path, b, o, t, func(field string, b, o, t map[string]interface{}, out map[string]interface{}) {
+					kind, name := "path field", path+" "+field
+					if slices.Contains(operationKeys, field) {
+						kind, name = "operation", strings.ToUpper(field)+" "+path
+					}
+					m.unit(out, field, b, o, t, kind, name)
+				})
+			})
+		case key == "components":
+			m.nested(merged, key, base, ours, theirs, func(section string, b, o, t map[string]interface{}, out map[string]interface{}) {
+				m.nested(out, section, b, o, t, func(name string, b, o, t map[string]interface{}, out map[string]interface{}) {
+					m.unit(out, name, b, o, t, "component", section+"/"+name)
+				})
+			})
+		case listKeys[key] != "":
+			m.list(merged, key, listKeys[key], base, ours, theirs)
+		default:
+			m.unit(merged, key, base, ours, theirs, "field", key)
+		}
+	}
+
+	if len(m.conflicts) > 0 && (opts.OnConflict == "" || opts.OnConflict == OnConflictError) {
+		return nil, m.conflicts
+	}
+	return merged, m.conflicts
+}
+
+type threeWay struct {
+	preferTheirs bool
+	conflicts    []Conflict
+}
+
+// unit merges the value under key as a whole
+func (m *threeWay) unit(out map[string]interface{}, key string, base, ours, theirs map[string]interface{}, kind, name string) {
+	b, inBase := base[key]
+	o, inOurs := ours[key]
+	t, inTheirs := theirs[key]
+
+	value, present := o, inOurs
+	switch {
+	case inOurs == inTheirs && reflect.DeepEqual(o, t):
+	case inBase == inOurs && reflect.DeepEqual(b, o):
+		value, present = t, inTheirs
+	case inBase == inTheirs && reflect.DeepEqual(b, t):
+	default:
+		m.conflicts = append(m.conflicts, Conflict{Kind: kind, Name: name, Sources: []string{OnConflictOurs, OnConflictTheirs}})
+		if m.preferTheirs {
+			value, present = t, inTheirs
+		}
+	}
+	if present {
+		out[key] = value
+	}
+}
+
+// nested merges the map under key entry by entry with each; it is dropped when
+// both sides empty it
+func (m *threeWay) nested(out map[string]interface{}, key string, base, ours, theirs map[string]interface{},
+	each func(key string, base, ours, theirs, out map[string]interface{})) {
+	b, o, t := mapOf(base[key]), mapOf(ours[key]), mapOf(theirs[key])
+	merged := make(map[string]interface{})
+	for _, k := range unionKeys(b, o, t) {
+		each(k, b, o, t, merged)
+	}
+	_, inOurs := ours[key]
+	_, inTheirs := theirs[key]
+	if len(merged) > 0 || (inOurs && inTheirs) {
+		out[key] = merged
+	}
+}
+
+// list merges a list of objects entry by entry, keyed by field, keeping our
+// order with their new entries after it
+func (m *threeWay) list(out map[string]interface{}, key, field string, base, ours, theirs map[string]interface{}) {
+	b, _ := keyed(base[key], field)
+	o, order := keyed(ours[key], field)
+	t, theirOrder := keyed(theirs[key], field)
+	for _, k := range theirOrder {
+		if !slices.Contains(order, k) {
+			order = append(order, k)
+		}
+	}
+
+	merged := make(map[string]interface{})
+	for _, k := range order {
+		m.unit(merged, k, b, o, t, strings.TrimSuffix(key, "s"), k)
+	}
+	var list []interface{}
+	for _, k := range order {
+		if entry, ok := merged[k]; ok {
+			list = append(list, entry)
+		}
+	}
+	if len(list) > 0 {
+		out[key] = list
+	}
+}
+
+// keyed indexes a list of objects by field, returning the keys in list order
+func keyed(v interface{}, field string) (map[string]interface{}, []string) {
+	list, _ := v.([]interface{})
+	entries := make(map[string]interface{}, len(list))
+	var order []string
+	for _, item := range list {
+		k := fmt.Sprint(mapOf(item)[field])
+		if _, dup := entries[k]; !dup {
+			order = append(order, k)
+		}
+		entries[k] = item
+	}
+	return entries, order
+}
+
+func mapOf(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+// unionKeys lists the keys of all maps, sorted
+func unionKeys(specs ...map[string]interface{}) []string {
+	keys := make(map[string]bool)
+	for _, m := range specs {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(keys))
+}
@@ -2,12 +2,187 @@ package models
 
 // APIRoute represents a discovered API route in Next.js
 type APIRoute struct {
-	Path       string   `json:"path"`
-	Method     string   `json:"method"`
-	FilePath   string   `json:"file_path"`
-	FileType   string   `json:"file_type"` // "ts", "js", "tsx", "jsx"
-	Parameters []string `json:"parameters,omitempty"`
-	Content    string   `json:"content"`
+	Path       string              `json:"path"`
+	Method     string              `json:"method"`
+	FilePath   string              `json:"file_path"`
+	FileType   string              `json:"file_type"`        // "ts", "js", "tsx", "jsx"
+	Router     string              `json:"router,omitempty"` // PagesRouter for pages/api files, empty for the app router
+	Parameters []string            `json:"parameters,omitempty"`
+	Content    string              `json:"content"`
+	Headers    map[string][]string `json:"headers,omitempty"`     // Response headers set by method ("*" outside handlers)
+	Types      []TypeDecl          `json:"types,omitempty"`       // TypeScript interfaces/object types declared in the file
+	Nullable   []NullableField     `json:"nullable,omitempty"`    // Fields that may be null
+	Formats    map[string]string   `json:"formats,omitempty"`     // Field name -> string format (uuid, email, ...)
+	BinaryType string              `json:"binary_type,omitempty"` // Media type when the handler returns a file
+	App        string              `json:"app,omitempty"`         // Workspace app the route belongs to
+	Owners     []string            `json:"owners,omitempty"`      // Owners from CODEOWNERS
+	Handlers   []Handler           `json:"handlers,omitempty"`    // Exported method handlers and where they are
+	Internal   bool                `json:"internal,omitempty"`    // Marked @internal or under an (internal) route group
+	Idempotent []string            `json:"idempotent,omitempty"`  // Methods reading an Idempotency-Key header ("*" for all)
+	// Methods wrapped in an auth helper or checking a session ("*" for all)
+	Authenticated []string `json:"authenticated,omitempty"`
+	// Roles/scopes checked by method ("*" for checks outside any handler)
+	Roles map[string][]string `json:"roles,omitempty"`
+	// Validation rules by parameter or field name, from validators and code checks
+	Constraints map[string]Constraint `json:"constraints,omitempty"`
+	QueryParams []QueryParam          `json:"query_params,omitempty"` // Query parameters read in the code
+	Zod         []ZodSchema           `json:"zod,omitempty"`          // Zod schemas request bodies are parsed with and responses built from
+	Annotations []Annotation          `json:"annotations,omitempty"`  // `@openapi key:value` comments
+	Versioning  *VersionHeader        `json:"versioning,omitempty"`   // API version header the handlers branch on
+	Factory     *FactoryCall          `json:"factory,omitempty"`      // Factory the handlers are created by
+	Pagination  []Pagination          `json:"pagination,omitempty"`   // Handlers returning a page of a findMany
+	// Accepted prose of the route's previous version by method, when its code changed
+	Previous map[string]OperationProse `json:"previous,omitempty"`
+	Example  *RouteExample             `json:"example,omitempty"`  // Similar accepted route to follow
+	Group    *RouteGroup               `json:"group,omitempty"`    // Nearest folder with a README or index comment
+	Segment  *SegmentConfig            `json:"segment,omitempty"`  // Exported route segment config
+	Minified bool                      `json:"minified,omitempty"` // The file was minified or bundled and has been beautified
+	// Newest commits touching the file, from --git-history
+	RecentChanges []RecentChange `json:"recent_changes,omitempty"`
+}
+
+// PagesRouter is the Router of a route from a pages/api file
+const PagesRouter = "pages"
+
+// RecentChange is a commit that touched a route file
+type RecentChange struct {
+	Commit  string `json:"commit"`  // Short hash
+	Date    string `json:"date"`    // Author date, YYYY-MM-DD
+	Subject string `json:"subject"` // First line of the commit message
+}
+
+// SegmentConfig is the route segment config a route file exports, e.g.
+// export const maxDuration = 30
+type SegmentConfig struct {
+	Dynamic         string   `json:"dynamic,omitempty"`          // auto, force-dynamic, error or force-static
+	Revalidate      string   `json:"revalidate,omitempty"`       // Seconds, or "false" to cache indefinitely
+	MaxDuration     int      `json:"max_duration,omitempty"`     // Seconds the handler may run
+	PreferredRegion []string `json:"preferred_region,omitempty"` // auto, global, home or region IDs
+}
+
+// RouteGroup is a route folder documented by a README.md or a leading comment
+// in its index file; its routes share a tag described by that text
+type RouteGroup struct {
+	Name        string `json:"name"`        // Tag name, from the folder name
+	Description string `json:"description"` // Markdown overview
+	Source      string `json:"source"`      // File the description was read from
+}
+
+// RouteExample is an accepted route whose documentation a similar route should follow
+type RouteExample struct {
+	File          string `json:"file"`
+	Documentation string `json:"documentation"` // JSON in the shape the prompt asks for
+}
+
+// OperationProse is the accepted summary and description of one operation
+type OperationProse struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+}
+
+// Pagination is a handler returning one page of a Prisma findMany together
+// with a count of the same model
+type Pagination struct {
+	Method string            `json:"method,omitempty"` // Handler; empty when it could not be located
+	Model  string            `json:"model"`            // Prisma model, e.g. user
+	Items  string            `json:"items"`            // Response field holding the page, dotted when nested
+	Fields map[string]string `json:"fields"`           // Other response fields by dotted path -> JSON type, "" when unknown
+}
+
+// FactoryCall is a call creating a route's handlers, e.g. createCrudHandlers(prisma.user)
+type FactoryCall struct {
+	Name     string   `json:"name"`               // Function called, e.g. createCrudHandlers
+	Argument string   `json:"argument,omitempty"` // First argument, quotes removed
+	Methods  []string `json:"methods"`            // Handlers exported from its result
+	Line     int      `json:"line"`
+}
+
+// VersionHeader is a request header that selects an API version
+type VersionHeader struct {
+	Name     string   `json:"name"`
+	Default  string   `json:"default,omitempty"` // Version used when the header is missing
+	Versions []string `json:"versions"`          // Values the code compares against
+	Methods  []string `json:"methods,omitempty"` // Handlers reading it; empty means all
+}
+
+// Annotation is one key:value pair from an `@openapi` comment
+type Annotation struct {
+	Method string `json:"method,omitempty"` // Handler the comment belongs to; empty for the whole route
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+// QueryParam is a query parameter read by a handler
+type QueryParam struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type,omitempty"`        // Type the value is converted to, if any
+	Default     interface{} `json:"default,omitempty"`     // Value used when the parameter is absent
+	Required    bool        `json:"required,omitempty"`    // Rejected when absent, e.g. by a Zod query schema
+	Description string      `json:"description,omitempty"` // From a Zod .describe()
+	Line        int         `json:"line"`                  // Where it is read, to find the handler
+}
+
+// ZodSchema is a Zod schema a handler parses its request body with or builds
+// a response from, translated to JSON Schema
+type ZodSchema struct {
+	Method string                 `json:"method"`           // Handler; "*" when outside any
+	Name   string                 `json:"name"`             // Variable the schema is declared as, with [] for a list of it
+	Target string                 `json:"target"`           // ZodRequestBody or ZodResponse
+	Status int                    `json:"status,omitempty"` // Response status; 0 for the success response
+	Schema map[string]interface{} `json:"schema"`
+	Line   int                    `json:"line"`
+}
+
+// Targets of a ZodSchema
+const (
+	ZodRequestBody = "requestBody"
+	ZodResponse    = "response"
+)
+
+// Constraint holds validation rules detected for a parameter or field
+type Constraint struct {
+	Minimum          *float64    `json:"minimum,omitempty"`
+	Maximum          *float64    `json:"maximum,omitempty"`
+	ExclusiveMinimum bool        `json:"exclusive_minimum,omitempty"`
+	ExclusiveMaximum bool        `json:"exclusive_maximum,omitempty"`
+	MinLength        *int        `json:"min_length,omitempty"`
+	MaxLength        *int        `json:"max_length,omitempty"`
+	Pattern          string      `json:"pattern,omitempty"`
+	Default          interface{} `json:"default,omitempty"`
+	Enum             []string    `json:"enum,omitempty"`
+	Integer          bool        `json:"integer,omitempty"`
+}
+
+// Handler locates an exported HTTP method handler in a route file
+type Handler struct {
+	Method    string `json:"method"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// TypeDecl is a TypeScript interface or object type alias
+type TypeDecl struct {
+	Name      string      `json:"name"`
+	Fields    []TypeField `json:"fields"`
+	File      string      `json:"file,omitempty"` // Set when declared in an imported file
+	StartLine int         `json:"start_line,omitempty"`
+	EndLine   int         `json:"end_line,omitempty"`
+}
+
+// TypeField is a property declared in a TypeDecl
+type TypeField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // TypeScript type as written, e.g. "string | null"
+	Optional bool   `json:"optional,omitempty"`
+	Nullable bool   `json:"nullable,omitempty"`
+}
+
+// NullableField is a field that may be null, either declared `T | null` in a
+// type or read with optional chaining in a handler
+type NullableField struct {
+	Field  string `json:"field"`
+	Type   string `json:"type,omitempty"`   // Declared type the field belongs to
+	Method string `json:"method,omitempty"` // Handler reading it with ?. ("*" outside handlers)
 }
 
 // DocumentedRoute represents an API route with generated documentation
@@ -20,9 +195,354 @@ type DocumentedRoute struct {
 
 // Config holds CLI configuration
 type Config struct {
-	APIDir      string `json:"api_dir"`
-	OutputFile  string `json:"output_file"`
-	OllamaModel string `json:"ollama_model"`
-	Workers     int    `json:"workers"`
-	OllamaURL   string `json:"ollama_url"`
+	// Defaults for the flags of the same name; the command line and the
+	// NEXTJS_OPENAPI_* variables win (see applySettings in cmd)
+	APIDir      string               `json:"api_dir" yaml:"apiDir"`
+	OutputFile  string               `json:"output_file" yaml:"output"`
+	OllamaModel string               `json:"ollama_model" yaml:"model"`
+	Workers     int                  `json:"workers" yaml:"workers"`
+	OllamaURL   string               `json:"ollama_url" yaml:"ollamaUrl"`
+	Provider    string               `json:"provider" yaml:"provider"`
+	OpenAIURL   string               `json:"openai_url" yaml:"openaiUrl"`
+	Info        SpecInfo             `json:"info" yaml:"info"`
+	Servers     []SpecServer         `json:"servers" yaml:"servers"`
+	Security    SecurityConfig       `json:"security" yaml:"security"`
+	Headers     []HeaderRule         `json:"response_headers" yaml:"responseHeaders"`
+	Naming      SchemaNaming         `json:"schema_naming" yaml:"schemaNaming"`
+	SpecVersion string               `json:"openapi_version" yaml:"openapiVersion"` // "3.0.0" (default) or "3.1.0"
+	Workspace   Workspace            `json:"workspace" yaml:"workspace"`
+	LoadTest    LoadTest             `json:"load_test" yaml:"loadTest"`
+	Outputs     []SpecOutput         `json:"outputs" yaml:"outputs"`
+	SizeBudget  SizeBudget           `json:"size_budget" yaml:"sizeBudget"`
+	External    ExternalDescriptions `json:"external_descriptions" yaml:"externalDescriptions"`
+	Internal    InternalRoutes       `json:"internal_routes" yaml:"internalRoutes"`
+	Defaults    DefaultResponses     `json:"default_responses" yaml:"defaultResponses"`
+	Paths       PathStyle            `json:"paths" yaml:"paths"`
+	Prefixes    []PathPrefix         `json:"path_prefixes" yaml:"pathPrefixes"`
+	Codegen     CodegenHints         `json:"codegen" yaml:"codegen"`
+	Envelope    ResponseEnvelope     `json:"response_envelope" yaml:"responseEnvelope"`
+	Idempotency Idempotency          `json:"idempotency" yaml:"idempotency"`
+	Implicit    ImplicitMethods      `json:"implicit_methods" yaml:"implicitMethods"`
+	Versioning  APIVersioning        `json:"api_versioning" yaml:"apiVersioning"`
+	Factories   []CrudFactory        `json:"crud_factories" yaml:"crudFactories"`
+	Warnings    WarningPolicy        `json:"warnings" yaml:"warnings"`
+	Examples    ExampleGeneration    `json:"examples" yaml:"examples"`
+	ParamValues []PathParamValues    `json:"path_param_values" yaml:"pathParamValues"`
+	Redaction   []RedactionRule      `json:"redaction" yaml:"redaction"`
+	Deployment  DeploymentServers    `json:"deployment_servers" yaml:"deploymentServers"`
+	Cache       CacheConfig          `json:"cache" yaml:"cache"`
+	Triage      RouteTriage          `json:"route_triage" yaml:"routeTriage"`
+	Webhooks    []Webhook            `json:"webhooks" yaml:"webhooks"`
+}
+
+// RouteTriage sorts routes into trivial, standard and complex by size and
+// complexity, and documents each class its own way
+type RouteTriage struct {
+	Enabled  bool        `json:"enabled" yaml:"enabled"`
+	Trivial  TriageClass `json:"trivial" yaml:"trivial"`   // Limits a route must stay within to be trivial
+	Standard TriageClass `json:"standard" yaml:"standard"` // Only the strategy and model apply
+	Complex  TriageClass `json:"complex" yaml:"complex"`   // Limits any one of which makes a route complex
+}
+
+// TriageClass bounds one class and says how its routes are documented; zero
+// limits take the defaults
+type TriageClass struct {
+	Lines      int    `json:"lines,omitempty" yaml:"lines"`           // Non-blank lines
+	Branches   int    `json:"branches,omitempty" yaml:"branches"`     // if, case, catch, ternaries and loops
+	Validators int    `json:"validators,omitempty" yaml:"validators"` // Schema validations and checked constraints
+	Strategy   string `json:"strategy,omitempty" yaml:"strategy"`     // "static" or "llm"
+	Model      string `json:"model,omitempty" yaml:"model"`           // Model for the llm strategy instead of --model
+}
+
+// CacheConfig selects where model responses are cached
+type CacheConfig struct {
+	Backend string     `json:"backend,omitempty" yaml:"backend"` // "dir" (default), "redis", "s3" or a registered backend
+	TTL     string     `json:"ttl,omitempty" yaml:"ttl"`         // e.g. 168h; entries never expire when empty
+	Dir     string     `json:"dir,omitempty" yaml:"dir"`
+	Redis   RedisCache `json:"redis" yaml:"redis"`
+	S3      S3Cache    `json:"s3" yaml:"s3"`
+}
+
+// RedisCache locates a Redis database, e.g. redis://localhost:6379/0
+type RedisCache struct {
+	URL    string `json:"url" yaml:"url"`
+	Prefix string `json:"prefix,omitempty" yaml:"prefix"` // Key prefix, "nextjs-openapi:" by default
+}
+
+// S3Cache locates a bucket; credentials come from the AWS environment variables
+type S3Cache struct {
+	Bucket   string `json:"bucket" yaml:"bucket"`
+	Prefix   string `json:"prefix,omitempty" yaml:"prefix"`
+	Region   string `json:"region,omitempty" yaml:"region"`
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint"` // S3-compatible service, e.g. MinIO
+}
+
+// DeploymentServers selects the environments whose deployment URLs are listed as servers
+type DeploymentServers struct {
+	Environments []string `json:"environments,omitempty" yaml:"environments"` // production, preview, development; "none" lists no servers
+}
+
+// RedactionRule hides a schema field from the public spec variant
+type RedactionRule struct {
+	Field  string `json:"field" yaml:"field"`             // Property name, or Schema.property for one component
+	Action string `json:"action,omitempty" yaml:"action"` // "strip" (default) or "mask"
+}
+
+// SpecInfo is the info object of the generated spec; empty fields keep the defaults
+type SpecInfo struct {
+	Title          string       `json:"title,omitempty" yaml:"title"`
+	Version        string       `json:"version,omitempty" yaml:"version"`
+	Description    string       `json:"description,omitempty" yaml:"description"`
+	TermsOfService string       `json:"termsOfService,omitempty" yaml:"termsOfService"`
+	Contact        *SpecContact `json:"contact,omitempty" yaml:"contact"`
+	License        *SpecLicense `json:"license,omitempty" yaml:"license"`
+}
+
+// SpecContact is the contact of the API's maintainers
+type SpecContact struct {
+	Name  string `json:"name,omitempty" yaml:"name"`
+	URL   string `json:"url,omitempty" yaml:"url"`
+	Email string `json:"email,omitempty" yaml:"email"`
+}
+
+// SpecLicense is the license the API is offered under
+type SpecLicense struct {
+	Name       string `json:"name" yaml:"name"`
+	URL        string `json:"url,omitempty" yaml:"url"`
+	Identifier string `json:"identifier,omitempty" yaml:"identifier"` // SPDX expression, OpenAPI 3.1 only
+}
+
+// SpecServer is a server declared in the config, listed instead of the detected deployment servers
+type SpecServer struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description"`
+}
+
+// Webhook is a request the API sends to its subscribers, e.g. order.created
+type Webhook struct {
+	Name        string   `json:"name" yaml:"name"`
+	Method      string   `json:"method,omitempty" yaml:"method"` // POST when empty
+	Summary     string   `json:"summary,omitempty" yaml:"summary"`
+	Description string   `json:"description,omitempty" yaml:"description"`
+	Schema      string   `json:"schema,omitempty" yaml:"schema"` // Component schema of the payload
+	Tags        []string `json:"tags,omitempty" yaml:"tags"`
+}
+
+// PathParamValues lists the valid values of a path parameter the code cannot show,
+// e.g. locales loaded from a CMS
+type PathParamValues struct {
+	Name       string   `json:"name" yaml:"name"`
+	PathPrefix string   `json:"path_prefix,omitempty" yaml:"pathPrefix"` // Every path with the parameter when empty
+	Values     []string `json:"values" yaml:"values"`
+}
+
+// ExampleGeneration fills in realistic examples where neither the code nor the model gave one
+type ExampleGeneration struct {
+	Generate bool `json:"generate,omitempty" yaml:"generate"`
+}
+
+// WarningPolicy selects warning codes to hide or to fail the run on; "all" matches every code
+type WarningPolicy struct {
+	Suppress []string `json:"suppress,omitempty" yaml:"suppress"`
+	FailOn   []string `json:"fail_on,omitempty" yaml:"failOn"`
+}
+
+// CrudFactory tells how to document routes whose handlers a factory creates
+type CrudFactory struct {
+	Name       string                      `json:"name" yaml:"name"`                       // Function name, e.g. createCrudHandlers
+	Resource   string                      `json:"resource,omitempty" yaml:"resource"`     // Defaults to the factory's first argument
+	Operations map[string]FactoryOperation `json:"operations,omitempty" yaml:"operations"` // By method; replaces the built-in CRUD template
+}
+
+// FactoryOperation templates one operation of a factory; {resource} and
+// {resources} in the text are replaced with the resource name
+type FactoryOperation struct {
+	Summary     string `json:"summary,omitempty" yaml:"summary"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	Status      string `json:"status,omitempty" yaml:"status"`   // Success status code
+	Returns     string `json:"returns,omitempty" yaml:"returns"` // "item", "list" or "none"
+}
+
+// APIVersioning controls how version headers handlers branch on are documented
+type APIVersioning struct {
+	Mode string `json:"mode,omitempty" yaml:"mode"` // "header" (default), "split" or "off"
+}
+
+// Idempotency documents an idempotency key header and its 409/422 responses
+type Idempotency struct {
+	Header   string            `json:"header,omitempty" yaml:"header"` // Defaults to "Idempotency-Key"
+	Required bool              `json:"required,omitempty" yaml:"required"`
+	Rules    []IdempotencyRule `json:"rules,omitempty" yaml:"rules"`
+}
+
+// IdempotencyRule applies the header to operations selected by tag or path
+type IdempotencyRule struct {
+	Tag        string   `json:"tag,omitempty" yaml:"tag"`
+	PathPrefix string   `json:"path_prefix,omitempty" yaml:"pathPrefix"`
+	Methods    []string `json:"methods,omitempty" yaml:"methods"` // Defaults to POST
+}
+
+// ResponseEnvelope is the team's standard wrapper around success payloads,
+// e.g. { data, error, meta }
+type ResponseEnvelope struct {
+	DataField  string                 `json:"data_field" yaml:"dataField"`            // Property holding the payload; empty disables wrapping
+	Name       string                 `json:"name,omitempty" yaml:"name"`             // Component name, defaults to "Envelope"
+	Properties map[string]interface{} `json:"properties,omitempty" yaml:"properties"` // The other fields, as JSON schemas
+	Required   []string               `json:"required,omitempty" yaml:"required"`     // Required fields, may include DataField
+}
+
+// CodegenHints selects extensions read by client and server generators
+type CodegenHints struct {
+	EnumVarNames bool `json:"enum_var_names,omitempty" yaml:"enumVarNames"` // x-enum-varnames on string enums
+	GoNames      bool `json:"go_names,omitempty" yaml:"goNames"`            // x-go-name where Go initialisms apply
+	Nullable     bool `json:"nullable,omitempty" yaml:"nullable"`           // x-nullable next to nullable schemas
+}
+
+// PathPrefix publishes the paths under Prefix under PublishedAs instead, for
+// segments such as a tenant ID that middleware rewrites away
+type PathPrefix struct {
+	Prefix      string          `json:"prefix" yaml:"prefix"`            // e.g. "/api"
+	PublishedAs string          `json:"published_as" yaml:"publishedAs"` // e.g. "/api/{orgId}"
+	Parameters  []PathParameter `json:"parameters,omitempty" yaml:"parameters"`
+}
+
+// PathParameter describes a parameter of a PathPrefix
+type PathParameter struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	Type        string `json:"type,omitempty" yaml:"type"`     // Defaults to "string"
+	Format      string `json:"format,omitempty" yaml:"format"` // e.g. "uuid"
+}
+
+// PathStyle normalizes emitted paths to match how the deployment resolves URLs
+type PathStyle struct {
+	TrailingSlash *bool `json:"trailing_slash,omitempty" yaml:"trailingSlash"` // Unset follows next.config
+	Lowercase     bool  `json:"lowercase,omitempty" yaml:"lowercase"`          // Lowercase static segments for case-insensitive hosts
+}
+
+// DefaultResponses toggles responses Next.js returns on its own; unset means on
+type DefaultResponses struct {
+	NotFound         *bool `json:"not_found,omitempty" yaml:"notFound"`                  // 404 on routes with path parameters
+	MethodNotAllowed *bool `json:"method_not_allowed,omitempty" yaml:"methodNotAllowed"` // 405 for methods the file does not export
+}
+
+// ImplicitMethods chooses which handlers Next.js adds on its own are documented.
+// Both default to off; HEAD and OPTIONS handlers a route exports are always documented.
+type ImplicitMethods struct {
+	Head    bool `json:"head,omitempty" yaml:"head"`       // HEAD mirroring GET without a body
+	Options bool `json:"options,omitempty" yaml:"options"` // OPTIONS answering with the Allow header
+}
+
+// InternalRoutes controls health, readiness and metrics endpoints
+type InternalRoutes struct {
+	Mode  string   `json:"mode,omitempty" yaml:"mode"`   // "tag", "exclude", or empty to document them like any route
+	Tag   string   `json:"tag,omitempty" yaml:"tag"`     // Tag for "tag" mode, defaults to "internal"
+	Paths []string `json:"paths,omitempty" yaml:"paths"` // Extra globs to treat as internal, e.g. "/api/admin/**"
+}
+
+// SizeBudget keeps the written spec under a size some gateways enforce
+type SizeBudget struct {
+	MaxSize        string `json:"max_size,omitempty" yaml:"maxSize"`               // e.g. "2MB", "500KB"
+	OmitExamples   bool   `json:"omit_examples,omitempty" yaml:"omitExamples"`     // Always drop examples
+	MaxDescription int    `json:"max_description,omitempty" yaml:"maxDescription"` // Always cut descriptions to this length
+}
+
+// ExternalDescriptions moves long operation descriptions into Markdown pages
+// linked through externalDocs
+type ExternalDescriptions struct {
+	MaxLength int    `json:"max_length,omitempty" yaml:"maxLength"` // Move descriptions longer than this; 0 keeps them inline
+	Dir       string `json:"dir,omitempty" yaml:"dir"`              // Pages directory, relative to the spec
+	BaseURL   string `json:"base_url,omitempty" yaml:"baseUrl"`     // Where the pages are published; links are relative when empty
+}
+
+// SpecOutput writes the paths matching a pattern to a separate spec file
+type SpecOutput struct {
+	Paths   string `json:"paths" yaml:"paths"`               // Glob such as "/api/v1/**"
+	File    string `json:"file" yaml:"file"`                 // .json, .yaml or .yml
+	Title   string `json:"title,omitempty" yaml:"title"`     // Overrides info.title
+	Version string `json:"version,omitempty" yaml:"version"` // Overrides info.version
+}
+
+// LoadTest configures generated load-test scripts
+type LoadTest struct {
+	Scenarios []LoadScenario `json:"scenarios" yaml:"scenarios"`
+}
+
+// LoadScenario sets the load for the operations of one tag
+type LoadScenario struct {
+	Tag      string `json:"tag" yaml:"tag"`
+	VUs      int    `json:"vus,omitempty" yaml:"vus"`           // Concurrent virtual users
+	Duration string `json:"duration,omitempty" yaml:"duration"` // Go/k6 duration, e.g. "30s", "2m"
+	Weight   int    `json:"weight,omitempty" yaml:"weight"`     // Share of Artillery arrivals
+}
+
+// Workspace aggregates several Next.js apps of a monorepo into one spec
+type Workspace struct {
+	Apps []WorkspaceApp `json:"apps" yaml:"apps"`
+}
+
+// WorkspaceApp describes one app in a workspace
+type WorkspaceApp struct {
+	Name       string `json:"name" yaml:"name"`
+	APIDir     string `json:"api_dir" yaml:"apiDir"`
+	TagPrefix  string `json:"tag_prefix,omitempty" yaml:"tagPrefix"`   // Prefix of the app's operation tags, defaults to Name
+	Server     string `json:"server,omitempty" yaml:"server"`          // Base URL the app is deployed at
+	PathPrefix string `json:"path_prefix,omitempty" yaml:"pathPrefix"` // Prepended to every path of the app
+}
+
+// SchemaNaming controls how schemas hoisted into components are named
+type SchemaNaming struct {
+	Strategy  string `json:"strategy,omitempty" yaml:"strategy"`   // "pascal" (default) or "path"
+	Prefix    string `json:"prefix,omitempty" yaml:"prefix"`       // Prepended to every name
+	Suffix    string `json:"suffix,omitempty" yaml:"suffix"`       // Appended to every name
+	Collision string `json:"collision,omitempty" yaml:"collision"` // "suffix" (default) or "path"
+}
+
+// HeaderRule documents a response header on matching operations
+type HeaderRule struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	Type        string `json:"type,omitempty" yaml:"type"`              // Schema type, defaults to "string"
+	PathPrefix  string `json:"path_prefix,omitempty" yaml:"pathPrefix"` // Empty applies to every operation
+	Status      string `json:"status,omitempty" yaml:"status"`          // Response code, defaults to every 2xx
+}
+
+// SecurityConfig declares security schemes and where they apply
+type SecurityConfig struct {
+	Schemes map[string]SecurityScheme `json:"schemes" yaml:"schemes"`
+	Rules   []SecurityRule            `json:"rules" yaml:"rules"`
+	Global  []SecurityRequirement     `json:"global,omitempty" yaml:"global"` // Top-level requirements, any one of which applies to every operation
+}
+
+// SecurityScheme mirrors an OpenAPI security scheme object
+type SecurityScheme struct {
+	Type         string               `json:"type" yaml:"type"` // "oauth2", "http", "apiKey"
+	Description  string               `json:"description,omitempty" yaml:"description"`
+	Scheme       string               `json:"scheme,omitempty" yaml:"scheme"`              // http: "bearer", "basic"
+	BearerFormat string               `json:"bearer_format,omitempty" yaml:"bearerFormat"` // http: "JWT"
+	In           string               `json:"in,omitempty" yaml:"in"`                      // apiKey: "header", "query", "cookie"
+	Name         string               `json:"name,omitempty" yaml:"name"`                  // apiKey: header/query/cookie name
+	Flows        map[string]OAuthFlow `json:"flows,omitempty" yaml:"flows"`                // oauth2: keyed by flow type
+}
+
+// OAuthFlow describes one OAuth2 flow (authorizationCode, clientCredentials, implicit, password)
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorization_url,omitempty" yaml:"authorizationUrl"`
+	TokenURL         string            `json:"token_url,omitempty" yaml:"tokenUrl"`
+	RefreshURL       string            `json:"refresh_url,omitempty" yaml:"refreshUrl"`
+	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+}
+
+// SecurityRequirement names a scheme and the scopes it must grant
+type SecurityRequirement struct {
+	Scheme string   `json:"scheme" yaml:"scheme"`
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes"`
+}
+
+// SecurityRule attaches a scheme (and scopes) to operations selected by path or tag
+type SecurityRule struct {
+	PathPrefix string   `json:"path_prefix,omitempty" yaml:"pathPrefix"`
+	Tag        string   `json:"tag,omitempty" yaml:"tag"`
+	Scheme     string   `json:"scheme" yaml:"scheme"`
+	Scopes     []string `json:"scopes,omitempty" yaml:"scopes"`
 }
@@ -0,0 +1,54 @@
+package nextconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Names Next.js loads its config from
+var names = []string{"next.config.js", "next.config.mjs", "next.config.cjs", "next.config.ts", "next.config.mts"}
+
+var trailingSlashPattern = regexp.MustCompile(`\btrailingSlash\s*:\s*(true|false)\b`)
+
+// Config holds the next.config settings that affect published URLs
+type Config struct {
+	Path          string // File the settings were read from
+	TrailingSlash bool
+}
+
+// Find walks up from dir to the first directory containing a next.config file.
+// It returns nil when there is none.
+func Find(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if data, err := os.ReadFile(path); err == nil {
+				return parse(path, string(data)), nil
+			} else if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parse reads literal settings; values computed at build time are not evaluated
+func parse(path, content string) *Config {
+	cfg := &Config{Path: path}
+	if m := trailingSlashPattern.FindStringSubmatch(content); m != nil {
+		cfg.TrailingSlash = m[1] == "true"
+	}
+	return cfg
+}
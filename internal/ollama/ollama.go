@@ -0,0 +1,175 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"nextjs-to-openapi/internal/llm"
+)
+
+// Ways of constraining answers, from the strictest; servers that reject one
+// are asked with the next
+const (
+	formatSchema = iota // format holds the JSON schema (Ollama 0.5 and later)
+	formatJSON          // format: "json", any JSON object
+	formatNone          // Free text, cleaned up before parsing
+)
+
+// Backend sends prompts to the /api/generate endpoint of an Ollama server
+type Backend struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	formats map[string]int // Model -> strictest format it accepted
+}
+
+type OllamaRequest struct {
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	Stream  bool                   `json:"stream"`
+	Format  json.RawMessage        `json:"format,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type OllamaResponse struct {
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int    `json:"prompt_eval_count,omitempty"` // Prompt tokens
+	EvalCount       int    `json:"eval_count,omitempty"`        // Response tokens
+	Error           string `json:"error,omitempty"`
+}
+
+// New creates a backend for the Ollama server at baseURL
+func New(baseURL string) *Backend {
+	return &Backend{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		formats:    make(map[string]int),
+	}
+}
+
+func (b *Backend) Name() string {
+	return "Ollama"
+}
+
+// Structured reports whether answers of model are constrained to JSON
+func (b *Backend) Structured(model string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.formats[model] != formatNone
+}
+
+// Encode builds the /api/generate payload, constraining the answer to
+// req.Schema as strictly as the model accepts
+func (b *Backend) Encode(req llm.Request) ([]byte, error) {
+	ollamaReq := OllamaRequest{
+		Model:   req.Model,
+		Prompt:  req.Prompt,
+		Stream:  false, // We want the complete response at once
+		Options: req.Options,
+	}
+	if req.Schema != nil {
+		b.mu.Lock()
+		ollamaReq.Format = formatValue(b.formats[req.Model], req.Schema)
+		b.mu.Unlock()
+	}
+	return json.Marshal(ollamaReq)
+}
+
+// formatValue is the format field for a level, nil when there is none
+func formatValue(level int, schema json.RawMessage) json.RawMessage {
+	switch level {
+	case formatSchema:
+		return schema
+	case formatJSON:
+		return json.RawMessage(`"json"`)
+	}
+	return nil
+}
+
+// Send posts an encoded request and decodes Ollama's reply. When the server
+// rejects the format, the error matches llm.ErrFormatRejected and encoding the
+// request again uses the next one.
+func (b *Backend) Send(ctx context.Context, body []byte) (llm.Completion, error) {
+	completion, err := b.send(ctx, body)
+	var providerErr *llm.ProviderError
+	if !errors.As(err, &providerErr) || providerErr.StatusCode != http.StatusBadRequest || !strings.Contains(strings.ToLower(err.Error()), "format") {
+		return completion, err
+	}
+	if !b.downgrade(body) {
+		return completion, err
+	}
+	return completion, fmt.Errorf("%w: %w", llm.ErrFormatRejected, err)
+}
+
+// downgrade records that the model of a request rejected its format, and
+// reports whether there is a looser one to try
+func (b *Backend) downgrade(body []byte) bool {
+	var req OllamaRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.Format == nil {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	level := b.formats[req.Model]
+	if level == formatNone {
+		return false
+	}
+	// Another request may already have moved the model past this format
+	if bytes.Equal(req.Format, formatValue(level, req.Format)) {
+		level++
+		b.formats[req.Model] = level
+		if level == formatJSON {
+			fmt.Printf("⚠️ Ollama does not accept JSON schema formats for %s; asking for plain JSON\n", req.Model)
+		} else {
+			fmt.Printf("⚠️ Ollama does not accept JSON formats for %s; cleaning up its answers instead\n", req.Model)
+		}
+	}
+	return true
+}
+
+// send posts one encoded request
+func (b *Backend) send(ctx context.Context, body []byte) (llm.Completion, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", b.baseURL+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return llm.Completion{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return llm.Completion{}, &llm.ProviderError{Provider: b.Name(), Err: fmt.Errorf("failed to send HTTP request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	var ollamaResp OllamaResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&ollamaResp)
+
+	// Check status code, keeping Ollama's explanation when it gave one
+	if resp.StatusCode != http.StatusOK {
+		providerErr := &llm.ProviderError{Provider: b.Name(), StatusCode: resp.StatusCode}
+		if decodeErr == nil && ollamaResp.Error != "" {
+			providerErr.Err = errors.New(ollamaResp.Error)
+		}
+		return llm.Completion{}, providerErr
+	}
+
+	// Parse response
+	if decodeErr != nil {
+		return llm.Completion{}, &llm.ProviderError{Provider: b.Name(), Err: fmt.Errorf("failed to decode response: %w", decodeErr)}
+	}
+	return llm.Completion{
+		Text:           ollamaResp.Response,
+		PromptTokens:   ollamaResp.PromptEvalCount,
+		ResponseTokens: ollamaResp.EvalCount,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nextjs-to-openapi/internal/audit"
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+func TestRejectedFormatIsRetriedThroughTheClient(t *testing.T) {
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		formats = append(formats, string(req.Format))
+		if bytes.HasPrefix(req.Format, []byte("{")) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(OllamaResponse{Error: "invalid format"})
+			return
+		}
+		json.NewEncoder(w).Encode(OllamaResponse{Response: `{"path":"/api/users","methods":{"get":{"summary":"List users"}}}`, Done: true})
+	}))
+	defer server.Close()
+
+	logPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	logger, err := audit.NewLogger(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logger.Close()
+
+	client := llm.NewClient(New(server.URL), "llama3")
+	client.SetAuditLogger(logger)
+	route := models.APIRoute{
+		FilePath: "app/api/users/route.ts",
+		Path:     "/api/users",
+		Content:  "export async function GET() { return Response.json([]) }",
+	}
+	doc, err := client.DocumentRoute(context.Background(), route)
+	if err != nil {
+		t.Fatalf("DocumentRoute: %v", err)
+	}
+	if doc.Methods["get"].Summary != "List users" {
+		t.Errorf("summary = %q, want List users", doc.Methods["get"].Summary)
+	}
+
+	if len(formats) != 2 || formats[1] != `"json"` {
+		t.Errorf("formats sent = %q, want the schema and then \"json\"", formats)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != len(formats) {
+		t.Errorf("audit log has %d entries for %d requests", lines, len(formats))
+	}
+}
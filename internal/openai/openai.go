@@ -0,0 +1,117 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"nextjs-to-openapi/internal/llm"
+)
+
+// DefaultURL is the base URL of the OpenAI API; vLLM, LM Studio, Groq and other
+// compatible servers serve the same endpoints under their own /v1
+const DefaultURL = "https://api.openai.com/v1"
+
+// chatOptions are the model options with a chat completions equivalent
+var chatOptions = []string{"temperature", "seed", "top_p"}
+
+// Backend sends prompts to the /chat/completions endpoint of an
+// OpenAI-compatible server
+type Backend struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type ChatResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// New creates a backend for the server at baseURL, e.g. http://localhost:1234/v1.
+// apiKey is sent as a bearer token; local servers usually accept an empty one.
+func New(baseURL, apiKey string) *Backend {
+	return &Backend{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (b *Backend) Name() string {
+	return "OpenAI"
+}
+
+// Encode builds the chat completions payload, with the prompt as the only message
+func (b *Backend) Encode(req llm.Request) ([]byte, error) {
+	payload := map[string]interface{}{
+		"model":    req.Model,
+		"messages": []Message{{Role: "user", Content: req.Prompt}},
+		"stream":   false,
+	}
+	for _, option := range chatOptions {
+		if value, ok := req.Options[option]; ok {
+			payload[option] = value
+		}
+	}
+	return json.Marshal(payload)
+}
+
+// Send posts an encoded request and returns the first choice
+func (b *Backend) Send(ctx context.Context, body []byte) (llm.Completion, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", b.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return llm.Completion{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return llm.Completion{}, &llm.ProviderError{Provider: b.Name(), Err: fmt.Errorf("failed to send HTTP request: %w", err)}
+	}
+	defer resp.Body.Close()
+
+	var chat ChatResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&chat)
+
+	// Check status code, keeping the server's explanation when it gave one
+	if resp.StatusCode != http.StatusOK {
+		providerErr := &llm.ProviderError{Provider: b.Name(), StatusCode: resp.StatusCode}
+		if decodeErr == nil && chat.Error != nil && chat.Error.Message != "" {
+			providerErr.Err = errors.New(chat.Error.Message)
+		}
+		return llm.Completion{}, providerErr
+	}
+
+	if decodeErr != nil {
+		return llm.Completion{}, &llm.ProviderError{Provider: b.Name(), Err: fmt.Errorf("failed to decode response: %w", decodeErr)}
+	}
+	if len(chat.Choices) == 0 {
+		return llm.Completion{}, &llm.ProviderError{Provider: b.Name(), Err: errors.New("the response has no choices")}
+	}
+	return llm.Completion{
+		Text:           chat.Choices[0].Message.Content,
+		PromptTokens:   chat.Usage.PromptTokens,
+		ResponseTokens: chat.Usage.CompletionTokens,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// applyAnnotations carries `@openapi` comments onto an operation: tag (or tags,
+// comma separated) adds tags, deprecated and operationId set the standard
+// fields, and any other key becomes an x- extension
+func (b *Builder) applyAnnotations(method string, route models.APIRoute, operation map[string]interface{}) {
+	for _, a := range route.Annotations {
+		if a.Method != "" && !strings.EqualFold(a.Method, method) {
+			continue
+		}
+
+		switch a.Key {
+		case "tag", "tags":
+			tags, _ := operation["tags"].([]string)
+			for _, tag := range strings.Split(a.Value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" && !hasTag(operation, tag) {
+					tags = append(tags, tag)
+					operation["tags"] = tags
+					b.AddTag(tag, "")
+				}
+			}
+		case "deprecated":
+			operation["deprecated"] = a.Value != "false"
+		case "operationId":
+			operation["operationId"] = a.Value
+		default:
+			key := a.Key
+			if !strings.HasPrefix(key, "x-") {
+				key = "x-" + key
+			}
+			operation[key] = annotationValue(a.Value)
+		}
+	}
+}
+
+// annotationValue keeps true and false as booleans and everything else as text
+func annotationValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
@@ -0,0 +1,313 @@
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Spec is a simple OpenAPI document
+type Spec struct {
+	OpenAPI    string                   `json:"openapi"`
+	Dialect    string                   `json:"jsonSchemaDialect,omitempty"`
+	Info       map[string]interface{}   `json:"info"`
+	Servers    []map[string]interface{} `json:"servers,omitempty"`
+	Tags       []map[string]interface{} `json:"tags,omitempty"`
+	Security   []map[string]interface{} `json:"security,omitempty"`
+	Paths      map[string]interface{}   `json:"paths"`
+	Webhooks   map[string]interface{}   `json:"webhooks,omitempty"`
+	XWebhooks  map[string]interface{}   `json:"x-webhooks,omitempty"`
+	Components map[string]interface{}   `json:"components,omitempty"`
+	// Other top-level fields, such as externalDocs and x- extensions of a
+	// baseline, kept through JSON round trips
+	Extra map[string]interface{} `json:"-"`
+}
+
+// Builder assembles an OpenAPI document from documented routes
+type Builder struct {
+	spec     Spec
+	security []SecurityRule
+	headers  []models.HeaderRule
+	naming   models.SchemaNaming
+	apps     map[string]models.WorkspaceApp
+	sorted   bool
+	pathTags bool
+	origins  map[string]models.APIRoute // Path -> route it was generated from
+	internal *internalRoutes
+	defaults models.DefaultResponses
+
+	prefixes      []models.PathPrefix
+	pathStyle     models.PathStyle
+	codegen       models.CodegenHints
+	envelope      models.ResponseEnvelope
+	idempotency   models.Idempotency
+	implicit      models.ImplicitMethods
+	versioning    models.APIVersioning
+	factories     []models.CrudFactory
+	examples      models.ExampleGeneration
+	paramValues   []models.PathParamValues
+	trailingSlash map[string]bool // App -> next.config trailingSlash
+	recentChanges bool
+	webhooks      []models.Webhook
+	missingHooks  []string // Webhook schemas not found in components
+}
+
+func NewBuilder() *Builder {
+	return &Builder{
+		spec: Spec{
+			OpenAPI: "3.0.0",
+			Info: map[string]interface{}{
+				"title":   "Next.js API Documentation",
+				"version": "1.0.0",
+			},
+			Paths: make(map[string]interface{}),
+		},
+		apps:    make(map[string]models.WorkspaceApp),
+		origins: make(map[string]models.APIRoute),
+
+		trailingSlash: make(map[string]bool),
+	}
+}
+
+// AddRoute converts one documented route into an OpenAPI path item
+func (b *Builder) AddRoute(route models.APIRoute, doc *llm.RouteDocumentation) {
+	if b.Excludes(route) {
+		return
+	}
+
+	app, inApp := b.apps[route.App]
+	// The path comes from the file's location rather than the model, which
+	// often gets it wrong; the model's path is only used for routes without one
+	path := doc.Path
+	if route.Path != "" {
+		path = route.Path
+	}
+	renames := PathParamRenames(doc.Path, path)
+	appPath := path // Before the app's prefix, for tagging
+	if inApp {
+		path = strings.TrimSuffix(app.PathPrefix, "/") + path
+	}
+	path, prefixRule := RewritePrefix(path, b.prefixes)
+	path = b.normalizePath(path, route.App)
+
+	pathItem := make(map[string]interface{})
+	// Visit methods in a fixed order so component names never depend on map iteration
+	for _, method := range slices.Sorted(maps.Keys(doc.Methods)) {
+		if implicitMethod(route, method) {
+			// Replaced below according to the configured policy
+			continue
+		}
+		if !handles(route, method) {
+			// The model documented a method the file does not handle (W002)
+			continue
+		}
+		details := doc.Methods[method]
+		// Convert method to lowercase (OpenAPI requirement)
+		methodLower := strings.ToLower(method)
+
+		// Fix parameter structure
+		var fixedParams []map[string]interface{}
+		for _, param := range details.Parameters {
+			if name, ok := renames[param.Name]; ok && param.In == "path" {
+				param.Name = name
+			}
+			paramSchema := map[string]interface{}{
+				"type": param.Type,
+			}
+			if format := paramFormat(param.Name, route.Formats); format != "" && param.Type == "string" {
+				paramSchema["format"] = format
+			}
+			b.applyConstraints(paramSchema, param, route.Constraints)
+			if param.In == "path" {
+				b.applyParamValues(path, param.Name, paramSchema, route.Constraints)
+			}
+			fixedParam := map[string]interface{}{
+				"name":     param.Name,
+				"in":       param.In,
+				"required": param.Required,
+				"schema":   paramSchema,
+			}
+			if param.Description != "" {
+				fixedParam["description"] = param.Description
+			}
+			fixedParams = append(fixedParams, fixedParam)
+		}
+		fixedParams, requestBody := extractRequestBody(fixedParams)
+		fixedParams = b.applyQueryParams(method, route, len(doc.Methods) == 1, fixedParams)
+		fixedParams = append(prefixParams(prefixRule, fixedParams), fixedParams...)
+
+		operation := map[string]interface{}{
+			"summary":     details.Summary,
+			"description": details.Description,
+			"responses":   b.defaultResponses(), // ✅ Required responses section
+		}
+		if len(fixedParams) > 0 {
+			operation["parameters"] = fixedParams // A nil list would encode as null
+		}
+		if requestBody != nil {
+			operation["requestBody"] = requestBody
+		}
+
+		// Tags of workspace apps are prefixed with the app's tag
+		tagPrefix := ""
+		if inApp {
+			tagPrefix = appTag(app)
+		}
+		if route.Group != nil {
+			name := prefixedTag(tagPrefix, route.Group.Name)
+			operation["tags"] = []string{name}
+			b.AddTag(name, route.Group.Description)
+		}
+		b.applyPathTag(appPath, tagPrefix, operation)
+		if tags, _ := operation["tags"].([]string); inApp && len(tags) == 0 {
+			operation["tags"] = []string{tagPrefix}
+			b.AddTag(tagPrefix, "Operations served by the "+app.Name+" app")
+		}
+		if len(route.Owners) > 0 {
+			operation["x-owner"] = route.Owners[0]
+			if len(route.Owners) > 1 {
+				operation["x-owners"] = route.Owners
+			}
+		}
+
+		b.applyAnnotations(method, route, operation)
+		applySegmentConfig(method, route.Segment, operation)
+		b.applyRecentChanges(route, operation)
+		b.applyFrameworkResponses(path, route, doc, operation)
+		b.applyResponses(path, method, details.Responses, operation)
+		b.applyPagination(method, route, operation)
+		b.applyZodSchemas(method, route, operation)
+		b.applyEnvelope(operation)
+		if route.BinaryType != "" && (methodLower == "get" || !hasMethod(doc, "GET")) {
+			applyBinaryResponse(route.BinaryType, operation)
+		}
+		b.markInternal(path, route, operation)
+		b.applyIdempotency(path, method, route, operation)
+		b.applyVersioning(method, route, details, operation)
+		b.applySecurity(path, route.App, operation)
+		b.applyRoles(method, route.Roles, operation)
+		b.applyAuthentication(method, route, operation)
+		b.applyHeaders(path, method, route.Headers, operation)
+		markNullable(method, operation, route.Nullable, route.Types)
+		applyFormats(operation, route.Formats)
+		b.hoistNamedSchemas(path, method, operation)
+
+		pathItem[methodLower] = operation
+	}
+	b.addImplicitMethods(route, doc, pathItem)
+	if len(pathItem) == 0 {
+		// Nothing the model documented is handled by the file
+		return
+	}
+
+	if inApp && app.Server != "" {
+		pathItem["servers"] = []map[string]interface{}{{"url": app.Server}}
+	}
+
+	b.spec.Paths[path], b.origins[path] = b.mergeRouters(path, route, pathItem)
+}
+
+// Spec returns the assembled document
+func (b *Builder) Spec() Spec {
+	b.addLinks()
+	b.fillExamples()
+	b.addWebhooks()
+	b.spec.Dialect = ""
+	if b.is31() {
+		b.spec.Dialect = Dialect31
+	}
+	normalizeNullable(b.spec.Paths, b.is31())
+	normalizeNullable(b.spec.Components, b.is31())
+	normalizeNullable(b.spec.Webhooks, b.is31())
+	applyCodegenHints(b.spec.Paths, b.codegen)
+	applyCodegenHints(b.spec.Components, b.codegen)
+	if b.sorted {
+		sort.SliceStable(b.spec.Tags, func(i, j int) bool {
+			return fmt.Sprint(b.spec.Tags[i]["name"]) < fmt.Sprint(b.spec.Tags[j]["name"])
+		})
+	}
+	return b.spec
+}
+
+// defaultResponses returns the standard responses every operation gets
+func (b *Builder) defaultResponses() map[string]interface{} {
+	return map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": "Successful response",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type":        "object",
+						"description": "Response data",
+					},
+				},
+			},
+		},
+		"400": map[string]interface{}{
+			"description": "Bad request",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": b.errorSchemaRef(),
+				},
+			},
+		},
+		"500": map[string]interface{}{
+			"description": "Internal server error",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": b.errorSchemaRef(),
+				},
+			},
+		},
+	}
+}
+
+// errorSchemaRef returns a $ref to the shared error envelope
+func (b *Builder) errorSchemaRef() map[string]interface{} {
+	return b.schemaRef(SchemaHint{TypeName: "Error", Role: "Error"}, errorSchema())
+}
+
+func errorSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"error": map[string]interface{}{
+				"type": "string",
+			},
+		},
+	}
+}
+
+// SetSorted orders list-valued top-level sections (tags) by name so output is
+// independent of route discovery order
+func (b *Builder) SetSorted(sorted bool) {
+	b.sorted = sorted
+}
+
+// component returns (creating if needed) a named section under components
+func (b *Builder) component(section string) map[string]interface{} {
+	if b.spec.Components == nil {
+		b.spec.Components = make(map[string]interface{})
+	}
+	existing, ok := b.spec.Components[section].(map[string]interface{})
+	if !ok {
+		existing = make(map[string]interface{})
+		b.spec.Components[section] = existing
+	}
+	return existing
+}
+
+// hasMethod reports whether the documentation includes the given method (any case)
+func hasMethod(doc *llm.RouteDocumentation, method string) bool {
+	for m := range doc.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// buildPath adds one documented route and returns its path item as the JSON output has it
+func buildPath(t *testing.T, b *Builder, route models.APIRoute, doc *llm.RouteDocumentation) map[string]interface{} {
+	t.Helper()
+	b.AddRoute(route, doc)
+	spec := specJSON(t, b)
+	paths, _ := spec["paths"].(map[string]interface{})
+	item, ok := paths[route.Path].(map[string]interface{})
+	if !ok {
+		t.Fatalf("path %s missing from %v", route.Path, paths)
+	}
+	return item
+}
+
+// specJSON returns the built spec decoded from its JSON encoding
+func specJSON(t *testing.T, b *Builder) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(b.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// responseOf returns the response of an operation in a path item
+func responseOf(t *testing.T, item map[string]interface{}, method, status string) map[string]interface{} {
+	t.Helper()
+	operation, _ := item[method].(map[string]interface{})
+	responses, _ := operation["responses"].(map[string]interface{})
+	response, ok := responses[status].(map[string]interface{})
+	if !ok {
+		t.Fatalf("%s response %s missing from %v", method, status, operation)
+	}
+	return response
+}
+
+func TestHeadersApplyToTheirMethod(t *testing.T) {
+	route := models.APIRoute{
+		Path:     "/api/users",
+		Handlers: []models.Handler{{Method: "GET", StartLine: 1, EndLine: 4}, {Method: "POST", StartLine: 6, EndLine: 9}},
+		Headers:  map[string][]string{"POST": {"Location"}},
+	}
+	doc := &llm.RouteDocumentation{Methods: map[string]llm.Method{
+		"GET":  {Summary: "List users", Responses: map[string]llm.Response{"200": {Description: "Users"}}},
+		"POST": {Summary: "Create a user", Responses: map[string]llm.Response{"201": {Description: "Created"}}},
+	}}
+	item := buildPath(t, NewBuilder(), route, doc)
+
+	if headers, ok := responseOf(t, item, "get", "200")["headers"]; ok {
+		t.Errorf("GET 200 has headers %v, want none", headers)
+	}
+	headers, _ := responseOf(t, item, "post", "201")["headers"].(map[string]interface{})
+	if _, ok := headers["Location"]; !ok {
+		t.Errorf("POST 201 headers = %v, want Location", headers)
+	}
+}
+
+func TestOperationsWithoutParametersOmitTheKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  models.APIRoute
+		method string
+		params []llm.Parameter
+	}{
+		{"no parameters", models.APIRoute{Path: "/api/health"}, "GET", nil},
+		{"all moved to the body", models.APIRoute{Path: "/api/users"}, "POST", []llm.Parameter{
+			{Name: "email", Type: "string", In: "body", Required: true},
+			{Name: "name", Type: "string", In: "body"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &llm.RouteDocumentation{Methods: map[string]llm.Method{
+				tt.method: {Summary: tt.name, Parameters: tt.params, Responses: map[string]llm.Response{"200": {Description: "OK"}}},
+			}}
+			item := buildPath(t, NewBuilder(), tt.route, doc)
+			operation := item[strings.ToLower(tt.method)].(map[string]interface{})
+			if params, ok := operation["parameters"]; ok {
+				t.Errorf("parameters = %v, want the key omitted", params)
+			}
+		})
+	}
+}
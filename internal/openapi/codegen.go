@@ -0,0 +1,154 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Initialisms Go style writes in capitals (golint's list, abridged)
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "JWT": true,
+	"OS": true, "SKU": true, "SQL": true, "SSH": true, "TLS": true, "TTL": true, "UI": true,
+	"URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// SetCodegenHints sets which generator extensions the spec carries
+func (b *Builder) SetCodegenHints(hints models.CodegenHints) {
+	b.codegen = hints
+}
+
+// applyCodegenHints adds the extensions openapi-generator and oapi-codegen read:
+// x-enum-varnames for string enums, x-go-name where Go naming differs from the
+// generators' default, and x-nullable for generators that ignore `nullable`
+func applyCodegenHints(node interface{}, hints models.CodegenHints) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if hints.EnumVarNames {
+			if names := enumVarNames(v["enum"]); names != nil {
+				if _, set := v["x-enum-varnames"]; !set {
+					v["x-enum-varnames"] = names
+				}
+			}
+		}
+		if hints.Nullable && isNullable(v) {
+			v["x-nullable"] = true
+		}
+		if hints.GoNames {
+			if properties, ok := v["properties"].(map[string]interface{}); ok {
+				for name, prop := range properties {
+					schema, ok := prop.(map[string]interface{})
+					if !ok || schema["$ref"] != nil {
+						continue // Siblings of $ref are ignored in 3.0
+					}
+					if goName := goFieldName(name); goName != pascalCase(name) {
+						schema["x-go-name"] = goName
+					}
+				}
+			}
+		}
+		for _, child := range v {
+			applyCodegenHints(child, hints)
+		}
+	case []interface{}:
+		for _, child := range v {
+			applyCodegenHints(child, hints)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			applyCodegenHints(child, hints)
+		}
+	}
+}
+
+// enumVarNames names each value of a string enum as a constant, e.g.
+// "in-progress" -> "InProgress"; nil when the enum is not all strings
+func enumVarNames(enum interface{}) []string {
+	var values []string
+	switch v := enum.(type) {
+	case []string:
+		values = v
+	case []interface{}:
+		for _, value := range v {
+			s, ok := value.(string)
+			if !ok {
+				return nil
+			}
+			values = append(values, s)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(values))
+	seen := make(map[string]bool)
+	for i, value := range values {
+		name := pascalCase(value)
+		if name == "" || unicode.IsDigit(rune(name[0])) {
+			name = "Value" + name
+		}
+		for base, n := name, 2; seen[name]; n++ {
+			name = fmt.Sprintf("%s%d", base, n)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// goFieldName converts a JSON name to an idiomatic Go name: user_id -> UserID
+func goFieldName(name string) string {
+	var out strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); goInitialisms[upper] {
+			out.WriteString(upper)
+		} else {
+			out.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return out.String()
+}
+
+// splitWords splits snake_case, kebab-case and camelCase names into words
+func splitWords(name string) []string {
+	var words []string
+	for _, part := range nonAlphanumeric.Split(name, -1) {
+		start := 0
+		for i := 1; i < len(part); i++ {
+			if unicode.IsUpper(rune(part[i])) && unicode.IsLower(rune(part[i-1])) {
+				words = append(words, part[start:i])
+				start = i
+			}
+		}
+		if start < len(part) {
+			words = append(words, part[start:])
+		}
+	}
+	return words
+}
+
+// isNullable reports whether a schema allows null in either 3.0 or 3.1 form
+func isNullable(schema map[string]interface{}) bool {
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		return true
+	}
+	switch types := schema["type"].(type) {
+	case []interface{}:
+		for _, t := range types {
+			if t == "null" {
+				return true
+			}
+		}
+	case []string:
+		for _, t := range types {
+			if t == "null" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Naming strategies for hoisted component schemas
+const (
+	NamingPascal = "pascal" // Type name in PascalCase, falling back to path-based names
+	NamingPath   = "path"   // Method + path + role, e.g. GetUsersIdResponse
+)
+
+// Collision strategies when two different schemas want the same name
+const (
+	CollisionSuffix = "suffix" // User, User2, User3
+	CollisionPath   = "path"   // User, PostsUser, then numeric suffixes
+)
+
+// SchemaHint carries what is known about a schema when choosing its name
+type SchemaHint struct {
+	TypeName string // Declared type name, e.g. "User"
+	Method   string // HTTP method of the operation using it
+	Path     string // OpenAPI path of the operation using it
+	Role     string // "Request", "Response", "Error", ...
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// SetSchemaNaming configures how hoisted schemas are named
+func (b *Builder) SetSchemaNaming(naming models.SchemaNaming) {
+	b.naming = naming
+}
+
+// schemaRef stores a schema under components.schemas and returns a $ref to it.
+// Structurally identical schemas share a single component.
+func (b *Builder) schemaRef(hint SchemaHint, schema map[string]interface{}) map[string]interface{} {
+	schemas := b.component("schemas")
+	fingerprint := schemaFingerprint(schema)
+
+	for name, existing := range schemas {
+		if schemaFingerprint(existing) == fingerprint {
+			return refTo(name)
+		}
+	}
+
+	name := b.uniqueName(b.schemaName(hint), hint, schemas)
+	schemas[name] = schema
+	return refTo(name)
+}
+
+// hoistNamedSchemas moves the object schemas of an operation's request body and
+// responses that have a "title", e.g. "User", to components.schemas and refers
+// to them by $ref, so a model shared by several routes is declared once
+func (b *Builder) hoistNamedSchemas(path, method string, operation map[string]interface{}) {
+	if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+		b.hoistContent(SchemaHint{Method: method, Path: path, Role: "Request"}, body)
+	}
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, status := range slices.Sorted(maps.Keys(responses)) {
+		if response, ok := responses[status].(map[string]interface{}); ok {
+			b.hoistContent(SchemaHint{Method: method, Path: path, Role: "Response"}, response)
+		}
+	}
+}
+
+func (b *Builder) hoistContent(hint SchemaHint, holder map[string]interface{}) {
+	content, _ := holder["content"].(map[string]interface{})
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		media, ok := content[mediaType].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if schema, ok := media["schema"].(map[string]interface{}); ok {
+			media["schema"] = b.hoistSchema(hint, schema)
+		}
+	}
+}
+
+// hoistSchema hoists titled object schemas bottom-up, so a named model nested
+// in another is referenced from the outer component rather than copied into it
+func (b *Builder) hoistSchema(hint SchemaHint, schema map[string]interface{}) map[string]interface{} {
+	if _, ok := schema["$ref"]; ok {
+		return schema
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for _, name := range slices.Sorted(maps.Keys(properties)) {
+			if property, ok := properties[name].(map[string]interface{}); ok {
+				properties[name] = b.hoistSchema(hint, property)
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if child, ok := schema[key].(map[string]interface{}); ok {
+			schema[key] = b.hoistSchema(hint, child)
+		}
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		variants, _ := schema[key].([]interface{})
+		for i, variant := range variants {
+			if child, ok := variant.(map[string]interface{}); ok {
+				variants[i] = b.hoistSchema(hint, child)
+			}
+		}
+		if typed, ok := schema[key].([]map[string]interface{}); ok {
+			for i, child := range typed {
+				typed[i] = b.hoistSchema(hint, child)
+			}
+		}
+	}
+
+	title, _ := schema["title"].(string)
+	if title == "" || (schema["type"] != "object" && schema["properties"] == nil) {
+		return schema
+	}
+	hint.TypeName = title
+	if bareTitle(schema) {
+		// A placeholder for a model whose fields are unknown, e.g. a paginated
+		// resource without a type declaration: refer to the real component when
+		// there is one, and never hoist it as an empty User2
+		name := b.schemaName(hint)
+		if _, ok := b.component("schemas")[name]; ok {
+			return refTo(name)
+		}
+		delete(schema, "title")
+		return schema
+	}
+	return b.schemaRef(hint, schema)
+}
+
+// bareTitle reports whether a schema is an object known only by its title
+func bareTitle(schema map[string]interface{}) bool {
+	for key := range schema {
+		if key != "type" && key != "title" && key != "description" {
+			return false
+		}
+	}
+	return schema["type"] == "object"
+}
+
+// schemaName applies the configured naming strategy, prefix and suffix
+func (b *Builder) schemaName(hint SchemaHint) string {
+	var name string
+	if b.naming.Strategy != NamingPath && hint.TypeName != "" {
+		name = pascalCase(hint.TypeName)
+	} else {
+		name = pathName(hint)
+	}
+	return b.naming.Prefix + name + b.naming.Suffix
+}
+
+// uniqueName resolves collisions with an already registered, different schema
+func (b *Builder) uniqueName(name string, hint SchemaHint, schemas map[string]interface{}) string {
+	if _, taken := schemas[name]; !taken {
+		return name
+	}
+
+	if b.naming.Collision == CollisionPath {
+		qualified := b.naming.Prefix + pascalCase(resourceSegment(hint.Path)) + strings.TrimPrefix(name, b.naming.Prefix)
+		if _, taken := schemas[qualified]; !taken {
+			return qualified
+		}
+		name = qualified
+	}
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s%d", name, i)
+		if _, taken := schemas[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// pathName builds a name from the operation, e.g. GET /api/users/{id} -> GetUsersIdResponse
+func pathName(hint SchemaHint) string {
+	path := strings.TrimPrefix(hint.Path, "/api")
+	return pascalCase(strings.ToLower(hint.Method)+" "+path) + hint.Role
+}
+
+// resourceSegment returns the last static path segment, e.g. /api/posts/{id} -> posts
+func resourceSegment(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !strings.HasPrefix(segments[i], "{") {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// pascalCase converts "user-profile", "user_profile" or "userProfile" to "UserProfile"
+func pascalCase(s string) string {
+	var out strings.Builder
+	for _, word := range nonAlphanumeric.Split(s, -1) {
+		if word == "" {
+			continue
+		}
+		out.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return out.String()
+}
+
+func refTo(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// schemaFingerprint gives a stable identity for structural comparison
+func schemaFingerprint(schema interface{}) string {
+	data, _ := json.Marshal(schema) // map keys are marshaled in sorted order
+	return string(data)
+}
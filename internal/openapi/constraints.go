@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// applyConstraints adds validation keywords to a parameter schema. Rules found
+// in code win; the model's values only fill keywords the code left open
+func (b *Builder) applyConstraints(schema map[string]interface{}, param llm.Parameter, detected map[string]models.Constraint) {
+	c, ok := detected[param.Name]
+	if ok {
+		if c.Integer && schema["type"] == "number" {
+			schema["type"] = "integer"
+		}
+		b.setBound(schema, "minimum", "exclusiveMinimum", c.Minimum, c.ExclusiveMinimum)
+		b.setBound(schema, "maximum", "exclusiveMaximum", c.Maximum, c.ExclusiveMaximum)
+		if c.MinLength != nil {
+			schema["minLength"] = *c.MinLength
+		}
+		if c.MaxLength != nil {
+			schema["maxLength"] = *c.MaxLength
+		}
+		if c.Pattern != "" {
+			schema["pattern"] = c.Pattern
+		}
+		if c.Default != nil {
+			schema["default"] = c.Default
+		}
+		if len(c.Enum) > 0 {
+			schema["enum"] = c.Enum
+		}
+	}
+
+	fill := func(key string, value interface{}) {
+		if _, set := schema[key]; !set {
+			schema[key] = value
+		}
+	}
+	if param.Minimum != nil {
+		if _, set := schema["exclusiveMinimum"]; !set {
+			fill("minimum", *param.Minimum)
+		}
+	}
+	if param.Maximum != nil {
+		if _, set := schema["exclusiveMaximum"]; !set {
+			fill("maximum", *param.Maximum)
+		}
+	}
+	if param.Pattern != "" {
+		fill("pattern", param.Pattern)
+	}
+	if param.Default != nil {
+		fill("default", param.Default)
+	}
+	if len(param.Enum) > 0 {
+		fill("enum", param.Enum)
+	}
+}
+
+// setBound writes an inclusive or exclusive bound: 3.0 pairs the bound with a
+// boolean flag, 3.1 puts the value on exclusiveMinimum/exclusiveMaximum itself
+func (b *Builder) setBound(schema map[string]interface{}, inclusive, exclusive string, value *float64, isExclusive bool) {
+	switch {
+	case value == nil:
+	case !isExclusive:
+		schema[inclusive] = *value
+	case b.is31():
+		schema[exclusive] = *value
+	default:
+		schema[inclusive] = *value
+		schema[exclusive] = true
+	}
+}
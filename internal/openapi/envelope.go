@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// DefaultEnvelopeName is the component name of the response envelope
+const DefaultEnvelopeName = "Envelope"
+
+// SetResponseEnvelope wraps every success payload in the team's envelope
+func (b *Builder) SetResponseEnvelope(envelope models.ResponseEnvelope) {
+	b.envelope = envelope
+}
+
+// applyEnvelope wraps the JSON schema of each 2xx response in the envelope.
+// A payload the model already wrapped is unwrapped first, so every operation
+// ends up with the same envelope whatever the model guessed.
+func (b *Builder) applyEnvelope(operation map[string]interface{}) {
+	field := b.envelope.DataField
+	if field == "" {
+		return
+	}
+
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, status := range slices.Sorted(maps.Keys(responses)) {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		response, _ := responses[status].(map[string]interface{})
+		content, _ := response["content"].(map[string]interface{})
+		media, _ := content["application/json"].(map[string]interface{})
+		schema, ok := media["schema"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		media["schema"] = b.wrapInEnvelope(schema)
+	}
+}
+
+// wrapInEnvelope returns allOf [envelope, { <data field>: payload }]
+func (b *Builder) wrapInEnvelope(schema map[string]interface{}) map[string]interface{} {
+	field := b.envelope.DataField
+	envelope := b.envelopeRef()
+	if allOf, ok := schema["allOf"].([]interface{}); ok && len(allOf) > 0 {
+		if first, ok := allOf[0].(map[string]interface{}); ok && first["$ref"] == envelope["$ref"] {
+			return schema // Already wrapped
+		}
+	}
+
+	payload := schema
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		if data, ok := properties[field].(map[string]interface{}); ok {
+			payload = data
+		}
+	}
+
+	data := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{field: payload},
+	}
+	if slices.Contains(b.envelope.Required, field) {
+		data["required"] = []string{field}
+	}
+	return map[string]interface{}{"allOf": []interface{}{envelope, data}}
+}
+
+// envelopeRef registers the envelope's other fields as a shared component
+func (b *Builder) envelopeRef() map[string]interface{} {
+	name := b.envelope.Name
+	if name == "" {
+		name = DefaultEnvelopeName
+	}
+
+	schema := map[string]interface{}{"type": "object"}
+	properties := make(map[string]interface{})
+	for key, value := range b.envelope.Properties {
+		if key != b.envelope.DataField {
+			properties[key] = value
+		}
+	}
+	if len(properties) > 0 {
+		schema["properties"] = properties
+	}
+	var required []string
+	for _, key := range b.envelope.Required {
+		if key != b.envelope.DataField {
+			required = append(required, key)
+		}
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return b.schemaRef(SchemaHint{TypeName: name, Role: "Envelope"}, schema)
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/examples"
+	"nextjs-to-openapi/internal/models"
+)
+
+// SetExampleGeneration fills in examples the code and the model left out
+func (b *Builder) SetExampleGeneration(generate models.ExampleGeneration) {
+	b.examples = generate
+}
+
+// fillExamples gives every JSON request body, response and parameter without an
+// example one generated from its schema
+func (b *Builder) fillExamples() {
+	if !b.examples.Generate {
+		return
+	}
+	for _, path := range slices.Sorted(maps.Keys(b.spec.Paths)) {
+		pathItem, _ := b.spec.Paths[path].(map[string]interface{})
+		for _, method := range slices.Sorted(maps.Keys(pathItem)) {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok || method == "parameters" || method == "servers" {
+				continue
+			}
+			b.fillParameterExamples(operation["parameters"])
+			if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+				b.fillContentExamples(body["content"])
+			}
+			responses, _ := operation["responses"].(map[string]interface{})
+			for _, response := range responses {
+				if response, ok := response.(map[string]interface{}); ok {
+					b.fillContentExamples(response["content"])
+				}
+			}
+		}
+	}
+}
+
+// fillContentExamples sets "example" on JSON media types that have none
+func (b *Builder) fillContentExamples(content interface{}) {
+	media, _ := content.(map[string]interface{})
+	for mediaType, m := range media {
+		m, ok := m.(map[string]interface{})
+		if !ok || !jsonMediaType(mediaType) || hasExample(m) {
+			continue
+		}
+		// An object without properties says nothing an empty example would add
+		if value := examples.Fake(m["schema"], b.spec.Components); value != nil && !isEmptyObject(value) {
+			m["example"] = value
+		}
+	}
+}
+
+// fillParameterExamples sets "example" on parameters that have none
+func (b *Builder) fillParameterExamples(params interface{}) {
+	var list []map[string]interface{}
+	switch v := params.(type) {
+	case []map[string]interface{}:
+		list = v
+	case []interface{}:
+		for _, p := range v {
+			if p, ok := p.(map[string]interface{}); ok {
+				list = append(list, p)
+			}
+		}
+	}
+	for _, param := range list {
+		if _, ref := param["$ref"]; ref || hasExample(param) {
+			continue
+		}
+		name, _ := param["name"].(string)
+		if value := examples.FakeNamed(name, param["schema"], b.spec.Components); value != nil {
+			param["example"] = value
+		}
+	}
+}
+
+// hasExample reports whether a media type or parameter already carries an example
+func hasExample(node map[string]interface{}) bool {
+	if _, ok := node["example"]; ok {
+		return true
+	}
+	_, ok := node["examples"]
+	return ok
+}
+
+func jsonMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isEmptyObject(value interface{}) bool {
+	obj, ok := value.(map[string]interface{})
+	return ok && len(obj) == 0
+}
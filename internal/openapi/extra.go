@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// specFields are the top-level fields Spec has a field for
+var specFields = map[string]bool{
+	"openapi": true, "jsonSchemaDialect": true, "info": true, "servers": true, "tags": true,
+	"security": true, "paths": true, "webhooks": true, "x-webhooks": true, "components": true,
+}
+
+// spec has Spec's fields without its JSON methods
+type spec Spec
+
+// MarshalJSON writes the known fields in their usual order, followed by Extra
+func (s Spec) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(spec(s))
+	if err != nil || len(s.Extra) == 0 {
+		return data, err
+	}
+	var b bytes.Buffer
+	b.Write(data[:len(data)-1])
+	for _, key := range slices.Sorted(maps.Keys(s.Extra)) {
+		if specFields[key] {
+			continue
+		}
+		value, err := json.Marshal(s.Extra[key])
+		if err != nil {
+			return nil, err
+		}
+		name, _ := json.Marshal(key)
+		b.WriteByte(',')
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON reads the known fields and keeps the others in Extra
+func (s *Spec) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*spec)(s)); err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	s.Extra = nil
+	for key, value := range fields {
+		if specFields[key] {
+			continue
+		}
+		if s.Extra == nil {
+			s.Extra = make(map[string]interface{})
+		}
+		s.Extra[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Template return kinds
+const (
+	ReturnsItem = "item"
+	ReturnsList = "list"
+	ReturnsNone = "none"
+)
+
+// Built-in CRUD semantics for collection routes (/posts) and item routes (/posts/{id})
+var (
+	collectionTemplate = map[string]models.FactoryOperation{
+		"GET":  {Summary: "List {resources}", Description: "Returns all {resources}.", Status: "200", Returns: ReturnsList},
+		"POST": {Summary: "Create a {resource}", Description: "Creates a {resource} and returns it.", Status: "201", Returns: ReturnsItem},
+	}
+	itemTemplate = map[string]models.FactoryOperation{
+		"GET":    {Summary: "Get a {resource}", Description: "Returns the {resource} with the given ID.", Status: "200", Returns: ReturnsItem},
+		"PUT":    {Summary: "Replace a {resource}", Description: "Replaces the {resource} with the given ID and returns it.", Status: "200", Returns: ReturnsItem},
+		"PATCH":  {Summary: "Update a {resource}", Description: "Updates fields of the {resource} with the given ID and returns it.", Status: "200", Returns: ReturnsItem},
+		"DELETE": {Summary: "Delete a {resource}", Description: "Deletes the {resource} with the given ID.", Status: "204", Returns: ReturnsNone},
+	}
+)
+
+// SetCrudFactories sets the templates for routes whose handlers a factory creates
+func (b *Builder) SetCrudFactories(factories []models.CrudFactory) {
+	b.factories = factories
+}
+
+// FactoryDocumentation documents a route whose handlers a factory creates from
+// the factory's template, so the model is not asked about code it cannot see.
+// Configured factories use their operations; any other factory with "crud" in
+// its name gets the built-in CRUD template. It returns nil when neither applies.
+func (b *Builder) FactoryDocumentation(route models.APIRoute) *llm.RouteDocumentation {
+	call := route.Factory
+	if call == nil {
+		return nil
+	}
+
+	var factory *models.CrudFactory
+	for i := range b.factories {
+		if b.factories[i].Name == call.Name || strings.HasSuffix(call.Name, "."+b.factories[i].Name) {
+			factory = &b.factories[i]
+		}
+	}
+	if factory == nil && !strings.Contains(strings.ToLower(call.Name), "crud") {
+		return nil
+	}
+
+	item := strings.HasSuffix(route.Path, "}")
+	template := collectionTemplate
+	if item {
+		template = itemTemplate
+	}
+	resource := call.Argument
+	if factory != nil {
+		if factory.Resource != "" {
+			resource = factory.Resource
+		}
+		if len(factory.Operations) > 0 {
+			template = make(map[string]models.FactoryOperation, len(factory.Operations))
+			for method, op := range factory.Operations {
+				template[strings.ToUpper(method)] = op
+			}
+		}
+	}
+	singular, plural := resourceNames(resource)
+
+	doc := &llm.RouteDocumentation{
+		Path:        route.Path,
+		Description: fmt.Sprintf("%s endpoints created by %s", pascalCase(singular), call.Name),
+		Methods:     make(map[string]llm.Method),
+	}
+	fill := strings.NewReplacer("{resource}", singular, "{resources}", plural)
+	schema := resourceSchema(singular, route.Types)
+
+	for _, method := range call.Methods {
+		op, ok := template[method]
+		if !ok {
+			op = models.FactoryOperation{Summary: method + " {resources}", Returns: ReturnsNone}
+		}
+		status := op.Status
+		if status == "" {
+			status = "200"
+		}
+
+		response := llm.Response{Description: fill.Replace(op.Summary)}
+		switch op.Returns {
+		case ReturnsItem:
+			response.Schemas = []map[string]interface{}{schema}
+		case ReturnsList:
+			response.Schemas = []map[string]interface{}{{"type": "array", "items": schema}}
+		}
+		responses := map[string]llm.Response{status: response}
+		if item {
+			responses["404"] = llm.Response{Description: fill.Replace("No {resource} has this ID")}
+		}
+
+		doc.Methods[method] = llm.Method{
+			Summary:     fill.Replace(op.Summary),
+			Description: fill.Replace(op.Description),
+			Parameters:  factoryPathParams(route.Path, singular),
+			Responses:   responses,
+		}
+	}
+	return doc
+}
+
+// resourceNames turns a factory argument such as prisma.user, "posts" or
+// Category into display names, e.g. category and categories
+func resourceNames(argument string) (string, string) {
+	name := argument[strings.LastIndex(argument, ".")+1:]
+	name = strings.ToLower(strings.Join(splitWords(name), " "))
+	if name == "" {
+		name = "resource"
+	}
+
+	singular := name
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		singular = strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "ss"):
+	case strings.HasSuffix(name, "s"):
+		singular = strings.TrimSuffix(name, "s")
+	}
+
+	plural := singular + "s"
+	switch {
+	case strings.HasSuffix(singular, "y") && !strings.HasSuffix(singular, "ey"):
+		plural = strings.TrimSuffix(singular, "y") + "ies"
+	case strings.HasSuffix(singular, "s") || strings.HasSuffix(singular, "x") || strings.HasSuffix(singular, "ch"):
+		plural = singular + "es"
+	}
+	return singular, plural
+}
+
+// factoryPathParams declares the route's path parameters; the last one identifies the resource
+func factoryPathParams(path, resource string) []llm.Parameter {
+	var params []llm.Parameter
+	matches := pathParamPattern.FindAllStringSubmatch(path, -1)
+	for i, m := range matches {
+		description := "Path parameter " + m[1]
+		if i == len(matches)-1 {
+			description = "ID of the " + resource
+		}
+		params = append(params, llm.Parameter{Name: m[1], Type: "string", In: "path", Required: true, Description: description})
+	}
+	return params
+}
+
+// resourceSchema builds the resource's schema from a TypeScript type of the same
+// name declared in or imported by the route, or else an object titled after it,
+// which hoisting turns into a reference to that component if it exists
+func resourceSchema(resource string, types []models.TypeDecl) map[string]interface{} {
+	name := pascalCase(resource)
+	for _, decl := range types {
+		if decl.Name != name {
+			continue
+		}
+		properties := make(map[string]interface{}, len(decl.Fields))
+		var required []string
+		for _, field := range decl.Fields {
+			properties[field.Name] = tsSchema(field.Type)
+			if !field.Optional {
+				required = append(required, field.Name)
+			}
+		}
+		schema := map[string]interface{}{"type": "object", "title": name, "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{"type": "object", "title": name}
+}
+
+// tsSchema maps a TypeScript field type to a JSON schema; null in a union is
+// left to the nullable pass
+func tsSchema(tsType string) map[string]interface{} {
+	var members []string
+	for _, member := range strings.Split(tsType, "|") {
+		if member = strings.TrimSpace(member); member != "" && member != "null" && member != "undefined" {
+			members = append(members, member)
+		}
+	}
+
+	if len(members) > 1 {
+		var values []interface{}
+		for _, member := range members {
+			if len(member) < 2 || (member[0] != '\'' && member[0] != '"') {
+				return map[string]interface{}{}
+			}
+			values = append(values, strings.Trim(member, `'"`))
+		}
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	if len(members) == 0 {
+		return map[string]interface{}{}
+	}
+
+	member := members[0]
+	switch {
+	case strings.HasSuffix(member, "[]"):
+		return map[string]interface{}{"type": "array", "items": tsSchema(strings.TrimSuffix(member, "[]"))}
+	case strings.HasPrefix(member, "Array<") && strings.HasSuffix(member, ">"):
+		return map[string]interface{}{"type": "array", "items": tsSchema(member[len("Array<") : len(member)-1])}
+	}
+	switch member {
+	case "string":
+		return map[string]interface{}{"type": "string"}
+	case "number":
+		return map[string]interface{}{"type": "number"}
+	case "boolean":
+		return map[string]interface{}{"type": "boolean"}
+	case "Date":
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	return map[string]interface{}{"type": "object"}
+}
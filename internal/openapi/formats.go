@@ -0,0 +1,73 @@
+package openapi
+
+import "regexp"
+
+// Field-name conventions used when code gives no explicit format
+var nameFormats = []struct {
+	pattern *regexp.Regexp
+	format  string
+}{
+	{regexp.MustCompile(`(?i)^e?mail$|email(address)?$`), "email"},
+	{regexp.MustCompile(`(?i)(^|_)uuid$|[a-z]Uuid$|^guid$`), "uuid"},
+	{regexp.MustCompile(`(?i)(^|_)(url|uri|href|website)$|[a-z](Url|Uri|Href)$`), "uri"},
+	{regexp.MustCompile(`(?i)^(timestamp|datetime)$|[a-z](At|Date|Time)$|_(at|date|time)$`), "date-time"},
+}
+
+// applyFormats sets `format` on string properties, preferring formats detected in
+// code over field-name conventions, and never overriding one the schema already has
+func applyFormats(node interface{}, detected map[string]string) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if properties, ok := v["properties"].(map[string]interface{}); ok {
+			for name, prop := range properties {
+				if schema, ok := prop.(map[string]interface{}); ok {
+					applyFormat(name, schema, detected)
+				}
+			}
+		}
+		for _, child := range v {
+			applyFormats(child, detected)
+		}
+	case []interface{}:
+		for _, child := range v {
+			applyFormats(child, detected)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			applyFormats(child, detected)
+		}
+	}
+}
+
+func applyFormat(name string, schema map[string]interface{}, detected map[string]string) {
+	if _, ok := schema["format"]; ok {
+		return
+	}
+	if schema["type"] != "string" {
+		return
+	}
+
+	if format, ok := detected[name]; ok {
+		schema["format"] = format
+		return
+	}
+	for _, nf := range nameFormats {
+		if nf.pattern.MatchString(name) {
+			schema["format"] = nf.format
+			return
+		}
+	}
+}
+
+// paramFormat returns the format for a parameter name, if any
+func paramFormat(name string, detected map[string]string) string {
+	if format, ok := detected[name]; ok {
+		return format
+	}
+	for _, nf := range nameFormats {
+		if nf.pattern.MatchString(name) {
+			return nf.format
+		}
+	}
+	return ""
+}
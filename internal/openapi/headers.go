@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// knownHeaders provides descriptions and schemas for commonly set response headers
+var knownHeaders = map[string]models.HeaderRule{
+	"Location":              {Description: "URL of the created or redirected resource", Type: "string"},
+	"X-Request-Id":          {Description: "Unique identifier for tracing this request", Type: "string"},
+	"X-Ratelimit-Limit":     {Description: "Maximum number of requests allowed in the current window", Type: "integer"},
+	"X-Ratelimit-Remaining": {Description: "Requests remaining in the current window", Type: "integer"},
+	"X-Ratelimit-Reset":     {Description: "Time at which the current rate limit window resets", Type: "integer"},
+	"Retry-After":           {Description: "Seconds to wait before retrying", Type: "integer"},
+	"Link":                  {Description: "Pagination links (RFC 8288)", Type: "string"},
+	"X-Total-Count":         {Description: "Total number of items available", Type: "integer"},
+	"Etag":                  {Description: "Entity tag for the returned representation", Type: "string"},
+	"Cache-Control":         {Description: "Caching directives", Type: "string"},
+	"Content-Disposition":   {Description: "Suggested filename for the downloaded file", Type: "string"},
+}
+
+// AddHeaderRule registers a configured response header
+func (b *Builder) AddHeaderRule(rule models.HeaderRule) {
+	b.headers = append(b.headers, rule)
+}
+
+// applyHeaders documents the headers the method's handler sets and the
+// configured ones on an operation
+func (b *Builder) applyHeaders(path, method string, detected map[string][]string, operation map[string]interface{}) {
+	responses := operation["responses"].(map[string]interface{})
+
+	for _, name := range mergeScopes(slices.Clone(detected["*"]), detected[method]) {
+		rule := knownHeaders[name]
+		rule.Name = name
+		addResponseHeader(responses, rule)
+	}
+
+	for _, rule := range b.headers {
+		if rule.PathPrefix != "" && !underPrefix(path, rule.PathPrefix) {
+			continue
+		}
+		addResponseHeader(responses, rule)
+	}
+}
+
+// addResponseHeader adds the header to the rule's status, or every 2xx response
+func addResponseHeader(responses map[string]interface{}, rule models.HeaderRule) {
+	schemaType := rule.Type
+	if schemaType == "" {
+		schemaType = "string"
+	}
+	header := map[string]interface{}{
+		"schema": map[string]interface{}{"type": schemaType},
+	}
+	if rule.Description != "" {
+		header["description"] = rule.Description
+	}
+
+	for status, response := range responses {
+		if rule.Status != "" && status != rule.Status {
+			continue
+		}
+		if rule.Status == "" && !strings.HasPrefix(status, "2") {
+			continue
+		}
+
+		resp, ok := response.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		headers, ok := resp["headers"].(map[string]interface{})
+		if !ok {
+			headers = make(map[string]interface{})
+			resp["headers"] = headers
+		}
+		headers[rule.Name] = header
+	}
+}
@@ -0,0 +1,28 @@
+package openapi
+
+import "nextjs-to-openapi/internal/models"
+
+// SetRecentChanges makes operations list the recent commits of their route file
+func (b *Builder) SetRecentChanges(emit bool) {
+	b.recentChanges = emit
+}
+
+// applyRecentChanges adds x-recent-changes, the newest commits touching the
+// route file, so consumers can see what changed without reading the repository
+func (b *Builder) applyRecentChanges(route models.APIRoute, operation map[string]interface{}) {
+	if !b.recentChanges || len(route.RecentChanges) == 0 {
+		return
+	}
+	if _, ok := operation["x-recent-changes"]; ok {
+		return
+	}
+	changes := make([]map[string]interface{}, len(route.RecentChanges))
+	for i, change := range route.RecentChanges {
+		changes[i] = map[string]interface{}{
+			"commit":  change.Commit,
+			"date":    change.Date,
+			"summary": change.Subject,
+		}
+	}
+	operation["x-recent-changes"] = changes
+}
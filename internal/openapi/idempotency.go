@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// DefaultIdempotencyHeader is the header carrying the idempotency key
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// SetIdempotency sets the header name and the rules applying it to operations
+// whose handlers do not read it visibly
+func (b *Builder) SetIdempotency(idempotency models.Idempotency) {
+	b.idempotency = idempotency
+}
+
+// applyIdempotency documents the idempotency key header, with 409 for a key
+// whose first request is still in progress and 422 for a key reused with a
+// different payload, on operations that read it or that a rule selects
+func (b *Builder) applyIdempotency(path, method string, route models.APIRoute, operation map[string]interface{}) {
+	if !b.idempotent(path, method, route, operation) {
+		return
+	}
+
+	header := b.idempotency.Header
+	if header == "" {
+		header = DefaultIdempotencyHeader
+	}
+	params, _ := operation["parameters"].([]map[string]interface{})
+	for _, param := range params {
+		if param["in"] == "header" && strings.EqualFold(param["name"].(string), header) {
+			return
+		}
+	}
+	operation["parameters"] = append(params, map[string]interface{}{
+		"name":        header,
+		"in":          "header",
+		"required":    b.idempotency.Required,
+		"description": "Unique key for this request; retrying with the same key returns the original result instead of repeating the operation",
+		"schema":      map[string]interface{}{"type": "string", "maxLength": 255},
+	})
+
+	responses := operation["responses"].(map[string]interface{})
+	if _, ok := responses["409"]; !ok {
+		responses["409"] = b.errorResponse("A request with the same idempotency key is still being processed")
+	}
+	if _, ok := responses["422"]; !ok {
+		responses["422"] = b.errorResponse("The idempotency key was already used with a different request payload")
+	}
+}
+
+func (b *Builder) idempotent(path, method string, route models.APIRoute, operation map[string]interface{}) bool {
+	method = strings.ToUpper(method)
+	if slices.Contains(route.Idempotent, method) {
+		return true
+	}
+	if slices.Contains(route.Idempotent, "*") && method != "GET" && method != "HEAD" && method != "OPTIONS" {
+		return true
+	}
+
+	for _, rule := range b.idempotency.Rules {
+		methods := rule.Methods
+		if len(methods) == 0 {
+			methods = []string{"POST"}
+		}
+		if !slices.ContainsFunc(methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+			continue
+		}
+		if rule.Tag != "" && !hasTag(operation, rule.Tag) {
+			continue
+		}
+		if rule.PathPrefix != "" && !underPrefix(path, rule.PathPrefix) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// errorResponse describes an error status using the shared error schema
+func (b *Builder) errorResponse(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": b.errorSchemaRef(),
+			},
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// SetImplicitMethods sets whether the HEAD and OPTIONS handlers Next.js
+// provides on its own are documented
+func (b *Builder) SetImplicitMethods(policy models.ImplicitMethods) {
+	b.implicit = policy
+}
+
+// implicitMethod reports whether Next.js, rather than the route file, answers
+// method. Only App Router files (those with exported handlers) get implicit
+// HEAD and OPTIONS handlers.
+func implicitMethod(route models.APIRoute, method string) bool {
+	method = strings.ToUpper(method)
+	if len(route.Handlers) == 0 || route.Router == models.PagesRouter || (method != "HEAD" && method != "OPTIONS") {
+		return false
+	}
+	return !handles(route, method)
+}
+
+// handles reports whether the scanner found a handler for method in the route
+// file. Routes whose handlers could not be located handle every method.
+func handles(route models.APIRoute, method string) bool {
+	if len(route.Handlers) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(route.Handlers, func(h models.Handler) bool { return strings.EqualFold(h.Method, method) })
+}
+
+// addImplicitMethods synthesizes the implicit operations the policy documents,
+// so every route gets the same HEAD and OPTIONS whatever the model wrote
+func (b *Builder) addImplicitMethods(route models.APIRoute, doc *llm.RouteDocumentation, pathItem map[string]interface{}) {
+	if len(pathItem) == 0 {
+		return
+	}
+
+	if get, ok := pathItem["get"].(map[string]interface{}); ok && b.implicit.Head && implicitMethod(route, "HEAD") {
+		pathItem["head"] = headOperation(get)
+	}
+
+	if b.implicit.Options && implicitMethod(route, "OPTIONS") {
+		allow := strings.Join(allowedMethods(route, doc), ", ")
+		options := map[string]interface{}{
+			"summary":     "List supported methods",
+			"description": "Answered by Next.js: the Allow header lists the methods this path supports.",
+			"responses": map[string]interface{}{
+				"204": map[string]interface{}{
+					"description": "Supported methods",
+					"headers": map[string]interface{}{
+						"Allow": map[string]interface{}{
+							"description": "Methods this path supports",
+							"schema":      map[string]interface{}{"type": "string", "example": allow},
+						},
+					},
+				},
+			},
+		}
+		// Group it with the path's other operations
+		sibling, _ := pathItem[slices.Min(slices.Collect(maps.Keys(pathItem)))].(map[string]interface{})
+		for _, key := range []string{"tags", "x-internal", "x-owner", "x-owners"} {
+			if value, ok := sibling[key]; ok {
+				options[key] = value
+			}
+		}
+		pathItem["options"] = options
+	}
+}
+
+// headOperation mirrors a GET operation: same parameters and headers, no bodies
+func headOperation(get map[string]interface{}) map[string]interface{} {
+	head := maps.Clone(get)
+	if summary, _ := get["summary"].(string); summary != "" {
+		head["summary"] = summary + " (headers only)"
+	}
+	head["description"] = "Answered by Next.js with the headers of the GET handler and no body."
+
+	responses, _ := get["responses"].(map[string]interface{})
+	stripped := make(map[string]interface{}, len(responses))
+	for status, r := range responses {
+		response, ok := r.(map[string]interface{})
+		if !ok {
+			stripped[status] = r
+			continue
+		}
+		response = maps.Clone(response)
+		delete(response, "content")
+		delete(response, "links")
+		stripped[status] = response
+	}
+	head["responses"] = stripped
+	delete(head, "requestBody")
+	return head
+}
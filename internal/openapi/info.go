@@ -0,0 +1,38 @@
+package openapi
+
+import "nextjs-to-openapi/internal/models"
+
+// SetInfo fills the info object from the config, keeping the default title and
+// version when they are not set. Call it after SetVersion: the license
+// identifier only exists in OpenAPI 3.1.
+func (b *Builder) SetInfo(info models.SpecInfo) {
+	set := func(key, value string) {
+		if value != "" {
+			b.spec.Info[key] = value
+		}
+	}
+	set("title", info.Title)
+	set("version", info.Version)
+	set("description", info.Description)
+	set("termsOfService", info.TermsOfService)
+
+	if contact := info.Contact; contact != nil {
+		object := make(map[string]interface{})
+		for key, value := range map[string]string{"name": contact.Name, "url": contact.URL, "email": contact.Email} {
+			if value != "" {
+				object[key] = value
+			}
+		}
+		b.spec.Info["contact"] = object
+	}
+	if license := info.License; license != nil {
+		object := map[string]interface{}{"name": license.Name}
+		if license.URL != "" {
+			object["url"] = license.URL
+		}
+		if license.Identifier != "" && b.is31() {
+			object["identifier"] = license.Identifier
+		}
+		b.spec.Info["license"] = object
+	}
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/split"
+)
+
+// Internal route handling modes
+const (
+	InternalTag     = "tag"     // Tag the operations and mark them x-internal
+	InternalExclude = "exclude" // Leave them out of the spec
+)
+
+// DefaultInternalTag is the tag given to infrastructure endpoints
+const DefaultInternalTag = "internal"
+
+// Last path segments that name health, readiness and metrics endpoints
+var infrastructureSegment = regexp.MustCompile(`(?i)^_?(health|healthz|healthcheck|health-check|ready|readyz|readiness|live|livez|liveness|alive|ping|heartbeat|status|metrics|prometheus)$`)
+
+// internalRoutes decides which routes are infrastructure endpoints
+type internalRoutes struct {
+	config   models.InternalRoutes
+	patterns []*regexp.Regexp
+}
+
+// SetInternalRoutes sets how internal routes are treated: those matching the
+// configured globs, and, when a mode is set, health, readiness and metrics routes
+func (b *Builder) SetInternalRoutes(config models.InternalRoutes) error {
+	routes := &internalRoutes{config: config}
+	if routes.config.Tag == "" {
+		routes.config.Tag = DefaultInternalTag
+	}
+	for _, glob := range config.Paths {
+		pattern, err := split.Compile(glob)
+		if err != nil {
+			return fmt.Errorf("internalRoutes: %w", err)
+		}
+		routes.patterns = append(routes.patterns, pattern)
+	}
+
+	b.internal = routes
+	if config.Mode == InternalTag {
+		b.AddTag(routes.config.Tag, "Health, readiness and metrics endpoints for infrastructure, not API clients")
+	}
+	return nil
+}
+
+// Excludes reports whether a route is left out of the spec entirely
+func (b *Builder) Excludes(route models.APIRoute) bool {
+	return b.internal != nil && b.internal.config.Mode == InternalExclude && b.isInternal(route.Path, route)
+}
+
+// markInternal flags an internal operation x-internal, and tags it in tag mode
+func (b *Builder) markInternal(path string, route models.APIRoute, operation map[string]interface{}) {
+	if !b.isInternal(path, route) {
+		return
+	}
+
+	operation["x-internal"] = true
+	if b.internal != nil && b.internal.config.Mode == InternalTag {
+		tags, _ := operation["tags"].([]string)
+		operation["tags"] = append(tags, b.internal.config.Tag)
+	}
+}
+
+// isInternal reports whether a route is internal: marked in its source, matched
+// by a configured glob, or, once a mode is set, a conventional infrastructure path
+func (b *Builder) isInternal(path string, route models.APIRoute) bool {
+	if route.Internal {
+		return true
+	}
+	if b.internal == nil {
+		return false
+	}
+	for _, p := range []string{path, route.Path} {
+		if b.internal.matchesGlob(p) || (b.internal.config.Mode != "" && isInfrastructure(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *internalRoutes) matchesGlob(path string) bool {
+	for _, pattern := range r.patterns {
+		if pattern.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInfrastructure reports whether a path's last segment is a conventional
+// health or metrics name under no path parameter (so /api/orders/{id}/status is not)
+func isInfrastructure(path string) bool {
+	if path == "" {
+		return false
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if !infrastructureSegment.MatchString(segments[len(segments)-1]) {
+		return false
+	}
+	for _, segment := range segments {
+		if strings.Contains(segment, "{") {
+			return false
+		}
+	}
+	return true
+}
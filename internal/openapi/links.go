@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// linkedMethods are the operations a link may point to, in the order they are listed
+var linkedMethods = []string{"get", "put", "patch", "delete"}
+
+// addLinks connects related operations:
+//   - POST /users responding with an `id` links to /users/{id}
+//   - any operation on /users/{id} links to direct children such as /users/{id}/posts
+func (b *Builder) addLinks() {
+	paths := make([]string, 0, len(b.spec.Paths))
+	for path := range b.spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item, _ := b.spec.Paths[path].(map[string]interface{})
+		for _, child := range childPaths(path, paths) {
+			childItem, _ := b.spec.Paths[child].(map[string]interface{})
+			newParam := newPathParam(path, child)
+
+			for method, op := range item {
+				operation, ok := op.(map[string]interface{})
+				if !ok || method == "head" || method == "options" {
+					continue
+				}
+
+				parameters := requestPathParams(path)
+				status := "" // Every success response
+				if newParam != "" {
+					if method != "post" {
+						continue
+					}
+					var field string
+					status, field = b.matchingField(operation, newParam)
+					if field == "" {
+						continue
+					}
+					parameters[newParam] = "$response.body#/" + field
+				}
+
+				for _, target := range linkedMethods {
+					if _, ok := childItem[target]; !ok {
+						continue
+					}
+					addLink(operation, status, linkName(target, child), map[string]interface{}{
+						"operationRef": operationRef(child, target),
+						"parameters":   parameters,
+					})
+				}
+			}
+		}
+	}
+}
+
+// childPaths returns paths exactly one segment below path
+func childPaths(path string, paths []string) []string {
+	var children []string
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for _, candidate := range paths {
+		rest := strings.TrimPrefix(candidate, prefix)
+		if rest != candidate && rest != "" && !strings.Contains(rest, "/") {
+			children = append(children, candidate)
+		}
+	}
+	return children
+}
+
+// newPathParam returns the parameter name if child adds a {param} segment to path
+func newPathParam(path, child string) string {
+	last := child[strings.LastIndex(child, "/")+1:]
+	if m := pathParamPattern.FindStringSubmatch(last); m != nil && m[0] == last {
+		return m[1]
+	}
+	return ""
+}
+
+// requestPathParams forwards the current operation's path parameters
+func requestPathParams(path string) map[string]interface{} {
+	parameters := make(map[string]interface{})
+	for _, m := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		parameters[m[1]] = "$request.path." + m[1]
+	}
+	return parameters
+}
+
+// matchingField finds a success response property that carries the value for
+// param: the param name itself, or `id` for params like `id`/`userId`
+func (b *Builder) matchingField(operation map[string]interface{}, param string) (string, string) {
+	responses, _ := operation["responses"].(map[string]interface{})
+	for _, status := range []string{"201", "200"} {
+		properties := b.responseProperties(responses[status])
+		if properties == nil {
+			continue
+		}
+		if _, ok := properties[param]; ok {
+			return status, param
+		}
+		if _, ok := properties["id"]; ok && strings.HasSuffix(strings.ToLower(param), "id") {
+			return status, "id"
+		}
+	}
+	return "", ""
+}
+
+// responseProperties returns the JSON body properties of a response, following $refs
+func (b *Builder) responseProperties(response interface{}) map[string]interface{} {
+	resp, _ := response.(map[string]interface{})
+	content, _ := resp["content"].(map[string]interface{})
+	media, _ := content["application/json"].(map[string]interface{})
+	schema, _ := media["schema"].(map[string]interface{})
+
+	if ref, ok := schema["$ref"].(string); ok {
+		schemas, _ := b.spec.Components["schemas"].(map[string]interface{})
+		schema, _ = schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{})
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	return properties
+}
+
+// addLink adds a link to the given response, or to every 2xx response when status is empty
+func addLink(operation map[string]interface{}, status, name string, link map[string]interface{}) {
+	responses, _ := operation["responses"].(map[string]interface{})
+	for code, response := range responses {
+		resp, ok := response.(map[string]interface{})
+		if !ok || !strings.HasPrefix(code, "2") || (status != "" && code != status) {
+			continue
+		}
+		links, ok := resp["links"].(map[string]interface{})
+		if !ok {
+			links = make(map[string]interface{})
+			resp["links"] = links
+		}
+		links[name] = link
+	}
+}
+
+// operationRef builds a JSON pointer to an operation, escaping "/" as "~1"
+func operationRef(path, method string) string {
+	escaped := strings.ReplaceAll(strings.ReplaceAll(path, "~", "~0"), "/", "~1")
+	return "#/paths/" + escaped + "/" + method
+}
+
+// linkName names a link after its target, e.g. get /api/users/{id} -> GetUsersId
+func linkName(method, path string) string {
+	return pascalCase(method + " " + strings.TrimPrefix(path, "/api"))
+}
@@ -0,0 +1,211 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Nullability is tracked internally with the 3.0 `nullable: true` keyword and
+// converted to the target version when the spec is returned.
+
+// Dialect31 is the JSON Schema dialect of 3.1 documents: the OpenAPI base
+// vocabulary on top of JSON Schema 2020-12
+const Dialect31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// SetVersion sets the OpenAPI version of the emitted document, e.g. "3.1.0"
+func (b *Builder) SetVersion(version string) {
+	if version != "" {
+		b.spec.OpenAPI = version
+	}
+}
+
+// is31 reports whether the document targets OpenAPI 3.1
+func (b *Builder) is31() bool {
+	return strings.HasPrefix(b.spec.OpenAPI, "3.1")
+}
+
+// markNullable flags the fields of an operation that may be null. A field
+// typed `T | null` is only marked in schemas of its type: titled after it, or
+// with several properties, all declared by the type. A field read with optional
+// chaining is only marked in the operation of the handler reading it.
+func markNullable(method string, operation map[string]interface{}, fields []models.NullableField, types []models.TypeDecl) {
+	declared := make(map[string]map[string]bool)
+	for _, decl := range types {
+		if declared[decl.Name] != nil {
+			continue
+		}
+		declared[decl.Name] = make(map[string]bool, len(decl.Fields))
+		for _, field := range decl.Fields {
+			declared[decl.Name][field.Name] = true
+		}
+	}
+
+	var applicable []models.NullableField
+	for _, field := range fields {
+		if field.Type != "" || field.Method == "*" || strings.EqualFold(field.Method, method) {
+			applicable = append(applicable, field)
+		}
+	}
+	if len(applicable) > 0 {
+		markNullableIn(operation, applicable, declared)
+	}
+}
+
+func markNullableIn(node interface{}, fields []models.NullableField, declared map[string]map[string]bool) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if properties, ok := v["properties"].(map[string]interface{}); ok {
+			for _, field := range fields {
+				prop, ok := properties[field.Field].(map[string]interface{})
+				if ok && (field.Type == "" || isTypeSchema(v, properties, field.Type, declared[field.Type])) {
+					prop["nullable"] = true
+				}
+			}
+		}
+		for _, child := range v {
+			markNullableIn(child, fields, declared)
+		}
+	case []interface{}:
+		for _, child := range v {
+			markNullableIn(child, fields, declared)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			markNullableIn(child, fields, declared)
+		}
+	}
+}
+
+// isTypeSchema reports whether an object schema describes the named type
+func isTypeSchema(schema, properties map[string]interface{}, name string, fields map[string]bool) bool {
+	if title, ok := schema["title"].(string); ok {
+		return title == name
+	}
+	// A single shared property name says little about the type
+	if fields == nil || len(properties) < min(2, len(fields)) {
+		return false
+	}
+	for property := range properties {
+		if !fields[property] {
+			return false
+		}
+	}
+	return true
+}
+
+// schemaMaps are keywords whose value maps names to schemas. The names may be
+// anything, including "nullable", so only the schemas under them are rewritten.
+var schemaMaps = map[string]bool{
+	"properties": true, "patternProperties": true, "$defs": true, "definitions": true,
+	"dependentSchemas": true, "schemas": true,
+}
+
+// exampleKeys hold literal values rather than schemas
+var exampleKeys = map[string]bool{"example": true, "examples": true, "default": true, "enum": true, "const": true}
+
+// normalizeNullable rewrites every schema in node to the nullability style of the target version:
+// `nullable: true` for 3.0 and `type: [T, "null"]` for 3.1
+func normalizeNullable(node interface{}, is31 bool) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if is31 {
+			toTypeArray(v)
+		} else {
+			toNullableKeyword(v)
+		}
+		for key, child := range v {
+			if exampleKeys[key] {
+				continue
+			}
+			if names, ok := child.(map[string]interface{}); ok && schemaMaps[key] {
+				for _, schema := range names {
+					normalizeNullable(schema, is31)
+				}
+				continue
+			}
+			normalizeNullable(child, is31)
+		}
+	case []interface{}:
+		for _, child := range v {
+			normalizeNullable(child, is31)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			normalizeNullable(child, is31)
+		}
+	}
+}
+
+// toTypeArray converts `nullable: true` into a 3.1 type array
+func toTypeArray(schema map[string]interface{}) {
+	nullable, _ := schema["nullable"].(bool)
+	if _, ok := schema["nullable"]; !ok {
+		return
+	}
+	delete(schema, "nullable")
+	if !nullable {
+		return
+	}
+
+	switch t := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{t, "null"}
+	case []interface{}:
+		if !containsValue(t, "null") {
+			schema["type"] = append(t, "null")
+		}
+	case nil:
+		// $ref siblings are ignored, so wrap the reference instead
+		if ref, ok := schema["$ref"]; ok {
+			delete(schema, "$ref")
+			schema["anyOf"] = []interface{}{
+				map[string]interface{}{"$ref": ref},
+				map[string]interface{}{"type": "null"},
+			}
+		}
+	}
+}
+
+// toNullableKeyword converts a 3.1 type array containing "null" into `nullable: true`
+func toNullableKeyword(schema map[string]interface{}) {
+	types, ok := schema["type"].([]interface{})
+	if !ok {
+		return
+	}
+
+	var rest []interface{}
+	for _, t := range types {
+		if t != "null" {
+			rest = append(rest, t)
+		}
+	}
+	if len(rest) == len(types) {
+		return
+	}
+
+	schema["nullable"] = true
+	switch len(rest) {
+	case 0:
+		delete(schema, "type")
+	case 1:
+		schema["type"] = rest[0]
+	default:
+		// 3.0 has no multi-type schemas; fall back to anyOf
+		delete(schema, "type")
+		var anyOf []interface{}
+		for _, t := range rest {
+			anyOf = append(anyOf, map[string]interface{}{"type": t})
+		}
+		schema["anyOf"] = anyOf
+	}
+}
+
+func containsValue(values []interface{}, want interface{}) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+	return false
+}
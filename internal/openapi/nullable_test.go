@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeNullableKeepsPropertyNamedNullable(t *testing.T) {
+	schema := func() map[string]interface{} {
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"nullable": map[string]interface{}{"type": "boolean"},
+				"name":     map[string]interface{}{"type": "string", "nullable": true},
+			},
+			"example": map[string]interface{}{"nullable": true, "name": nil},
+		}
+	}
+
+	tests := []struct {
+		name string
+		is31 bool
+		want map[string]interface{}
+	}{
+		{
+			name: "3.1",
+			is31: true,
+			want: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"nullable": map[string]interface{}{"type": "boolean"},
+					"name":     map[string]interface{}{"type": []interface{}{"string", "null"}},
+				},
+				"example": map[string]interface{}{"nullable": true, "name": nil},
+			},
+		},
+		{
+			name: "3.0",
+			is31: false,
+			want: schema(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			components := map[string]interface{}{
+				"schemas": map[string]interface{}{
+					"Flags":    schema(),
+					"nullable": map[string]interface{}{"type": "string"},
+				},
+			}
+			normalizeNullable(components, tt.is31)
+			schemas := components["schemas"].(map[string]interface{})
+			if !reflect.DeepEqual(schemas["Flags"], tt.want) {
+				t.Errorf("Flags = %v, want %v", schemas["Flags"], tt.want)
+			}
+			if _, ok := schemas["nullable"]; !ok {
+				t.Error("component schema named nullable was dropped")
+			}
+		})
+	}
+}
+
+func TestNormalizeNullableToKeyword(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"type": map[string]interface{}{"type": []interface{}{"string", "null"}},
+		},
+	}
+	normalizeNullable(schema, false)
+	want := map[string]interface{}{"type": "string", "nullable": true}
+	if got := schema["properties"].(map[string]interface{})["type"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("property = %v, want %v", got, want)
+	}
+	if schema["type"] != "object" {
+		t.Errorf("type = %v, want object", schema["type"])
+	}
+}
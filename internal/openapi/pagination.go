@@ -0,0 +1,103 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// applyPagination documents the success response of a handler returning a page
+// of a Prisma findMany as the envelope it builds: the page as an array of the
+// model, with the count and page fields next to it, instead of a bare array or
+// generic object
+func (b *Builder) applyPagination(method string, route models.APIRoute, operation map[string]interface{}) {
+	var page *models.Pagination
+	for i, p := range route.Pagination {
+		if strings.EqualFold(p.Method, method) || (p.Method == "" && strings.EqualFold(method, "GET")) {
+			page = &route.Pagination[i]
+			break
+		}
+	}
+	if page == nil {
+		return
+	}
+
+	responses := operation["responses"].(map[string]interface{})
+	status := "200"
+	for _, code := range slices.Sorted(maps.Keys(responses)) {
+		if strings.HasPrefix(code, "2") {
+			status = code
+			break
+		}
+	}
+	response, _ := responses[status].(map[string]interface{})
+	if response == nil {
+		response = map[string]interface{}{"description": "A page of " + page.Model + " records"}
+	}
+	documented := jsonSchema(response)
+
+	items := documentedAt(documented, page.Items)
+	itemSchema, _ := items["items"].(map[string]interface{})
+	if items["type"] != "array" || itemSchema == nil {
+		itemSchema = resourceSchema(page.Model, route.Types)
+	}
+
+	schema := map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	setField(schema, page.Items, map[string]interface{}{"type": "array", "items": itemSchema})
+	for _, path := range slices.Sorted(maps.Keys(page.Fields)) {
+		field := map[string]interface{}{"type": page.Fields[path]}
+		if page.Fields[path] == "" {
+			field = documentedAt(documented, path)
+		}
+		setField(schema, path, field)
+	}
+
+	response["content"] = map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+	responses[status] = response
+}
+
+// jsonSchema returns the JSON schema of a response, if any
+func jsonSchema(response map[string]interface{}) map[string]interface{} {
+	content, _ := response["content"].(map[string]interface{})
+	media, _ := content["application/json"].(map[string]interface{})
+	schema, _ := media["schema"].(map[string]interface{})
+	return schema
+}
+
+// documentedAt returns the property at a dotted path of a schema, or an empty schema
+func documentedAt(schema map[string]interface{}, path string) map[string]interface{} {
+	for _, key := range strings.Split(path, ".") {
+		properties, _ := schema["properties"].(map[string]interface{})
+		schema, _ = properties[key].(map[string]interface{})
+	}
+	if schema == nil {
+		return map[string]interface{}{}
+	}
+	return schema
+}
+
+// setField adds a required property at a dotted path, creating nested objects
+func setField(schema map[string]interface{}, path string, field map[string]interface{}) {
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		properties := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]string)
+		if !slices.Contains(required, key) {
+			schema["required"] = append(required, key)
+		}
+		if i == len(keys)-1 {
+			properties[key] = field
+			return
+		}
+		next, ok := properties[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+			properties[key] = next
+		}
+		schema = next
+	}
+}
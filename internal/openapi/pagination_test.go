@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// pageItems returns the items schema of the page GET /api/posts returns
+func pageItems(t *testing.T, spec map[string]interface{}) interface{} {
+	t.Helper()
+	paths := spec["paths"].(map[string]interface{})
+	response := responseOf(t, paths["/api/posts"].(map[string]interface{}), "get", "200")
+	schema := response["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	data := schema["properties"].(map[string]interface{})["data"].(map[string]interface{})
+	return data["items"]
+}
+
+func paginatedRoute() (models.APIRoute, *llm.RouteDocumentation) {
+	route := models.APIRoute{
+		Path:       "/api/posts",
+		Pagination: []models.Pagination{{Method: "GET", Model: "user", Items: "data", Fields: map[string]string{"total": "integer"}}},
+	}
+	doc := &llm.RouteDocumentation{Methods: map[string]llm.Method{
+		"GET": {Summary: "List users", Responses: map[string]llm.Response{"200": {Description: "A page of users"}}},
+	}}
+	return route, doc
+}
+
+func TestPaginationReusesExistingComponent(t *testing.T) {
+	b := NewBuilder()
+	user := map[string]interface{}{
+		"type": "object", "title": "User",
+		"properties": map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+	}
+	b.AddRoute(models.APIRoute{Path: "/api/users/{id}"}, &llm.RouteDocumentation{Methods: map[string]llm.Method{
+		"GET": {Summary: "Get a user", Responses: map[string]llm.Response{"200": {Description: "The user", Schemas: []map[string]interface{}{user}}}},
+	}})
+	b.AddRoute(paginatedRoute())
+	spec := specJSON(t, b)
+
+	if got, want := pageItems(t, spec), map[string]interface{}{"$ref": "#/components/schemas/User"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("page items = %v, want %v", got, want)
+	}
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if _, ok := schemas["User2"]; ok {
+		t.Errorf("components.schemas has a duplicate User2: %v", schemas["User2"])
+	}
+}
+
+func TestPaginationWithoutComponentStaysInline(t *testing.T) {
+	b := NewBuilder()
+	b.AddRoute(paginatedRoute())
+	spec := specJSON(t, b)
+
+	if got, want := pageItems(t, spec), map[string]interface{}{"type": "object"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("page items = %v, want %v", got, want)
+	}
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"User", "User2"} {
+		if schema, ok := schemas[name]; ok {
+			t.Errorf("components.schemas.%s = %v, want no placeholder component", name, schema)
+		}
+	}
+}
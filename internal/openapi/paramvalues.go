@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// SetPathParamValues sets the configured values of path parameters
+func (b *Builder) SetPathParamValues(rules []models.PathParamValues) {
+	b.paramValues = rules
+}
+
+// applyParamValues gives a path parameter the enum configured for it. Values
+// found in the code (generateStaticParams, validators) win over the config, and
+// the config over the model.
+func (b *Builder) applyParamValues(path, name string, schema map[string]interface{}, detected map[string]models.Constraint) {
+	if len(detected[name].Enum) > 0 || !strings.Contains(path, "{"+name+"}") {
+		return
+	}
+	for _, rule := range b.paramValues {
+		if rule.Name == name && strings.HasPrefix(path, rule.PathPrefix) {
+			schema["enum"] = rule.Values
+			return
+		}
+	}
+}
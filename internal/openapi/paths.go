@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// SetPathStyle sets how emitted paths are normalized
+func (b *Builder) SetPathStyle(style models.PathStyle) {
+	b.pathStyle = style
+}
+
+// SetTrailingSlash records an app's next.config trailingSlash setting, used
+// unless the path style sets one ("" is the single-app project)
+func (b *Builder) SetTrailingSlash(app string, on bool) {
+	b.trailingSlash[app] = on
+}
+
+// normalizePath makes a path match how the deployment resolves URLs
+func (b *Builder) normalizePath(path, app string) string {
+	trailing := b.trailingSlash[app]
+	if b.pathStyle.TrailingSlash != nil {
+		trailing = *b.pathStyle.TrailingSlash
+	}
+	return NormalizePath(path, trailing, b.pathStyle.Lowercase)
+}
+
+// NormalizePath adds or strips the trailing slash and, for case-insensitive
+// hosts, lowercases static segments; parameter names keep their case
+func NormalizePath(path string, trailingSlash, lowercase bool) string {
+	if lowercase {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			if !strings.HasPrefix(segment, "{") {
+				segments[i] = strings.ToLower(segment)
+			}
+		}
+		path = strings.Join(segments, "/")
+	}
+
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		return "/"
+	}
+	if trailingSlash {
+		path += "/"
+	}
+	return path
+}
+
+// PathParamRenames maps the path parameters the model named in its path to the
+// ones in the path the file serves, by position, so its parameter list still
+// matches when the names differ ({userId} for [id])
+func PathParamRenames(modelPath, path string) map[string]string {
+	named := pathParamPattern.FindAllStringSubmatch(modelPath, -1)
+	served := pathParamPattern.FindAllStringSubmatch(path, -1)
+	if len(named) != len(served) {
+		return nil
+	}
+	renames := make(map[string]string)
+	for i := range named {
+		if named[i][1] != served[i][1] {
+			renames[named[i][1]] = served[i][1]
+		}
+	}
+	return renames
+}
+
+// underPrefix reports whether path is prefix or below it, segment-wise, so
+// /api/user does not match /api/users
+func underPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
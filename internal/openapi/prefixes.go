@@ -0,0 +1,65 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// AddPathPrefix publishes paths under a prefix that is not in the file tree,
+// such as a tenant segment added by a middleware rewrite
+func (b *Builder) AddPathPrefix(prefix models.PathPrefix) {
+	b.prefixes = append(b.prefixes, prefix)
+}
+
+// RewritePrefix applies the first prefix rule matching path, returning the
+// published path and the rule that applied
+func RewritePrefix(path string, prefixes []models.PathPrefix) (string, *models.PathPrefix) {
+	for i, p := range prefixes {
+		from := strings.TrimSuffix(p.Prefix, "/")
+		if path == from || strings.HasPrefix(path, from+"/") {
+			return strings.TrimSuffix(p.PublishedAs, "/") + strings.TrimPrefix(path, from), &prefixes[i]
+		}
+	}
+	return path, nil
+}
+
+// prefixParams builds the parameters a prefix rule injects, skipping those
+// the operation already declares
+func prefixParams(rule *models.PathPrefix, existing []map[string]interface{}) []map[string]interface{} {
+	if rule == nil {
+		return nil
+	}
+	declared := make(map[string]bool)
+	for _, param := range existing {
+		if param["in"] == "path" {
+			declared[param["name"].(string)] = true
+		}
+	}
+
+	var params []map[string]interface{}
+	for _, m := range pathParamPattern.FindAllStringSubmatch(rule.PublishedAs, -1) {
+		name := m[1]
+		if declared[name] {
+			continue
+		}
+		schema := map[string]interface{}{"type": "string"}
+		param := map[string]interface{}{"name": name, "in": "path", "required": true, "schema": schema}
+		for _, p := range rule.Parameters {
+			if p.Name != name {
+				continue
+			}
+			if p.Type != "" {
+				schema["type"] = p.Type
+			}
+			if p.Format != "" {
+				schema["format"] = p.Format
+			}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
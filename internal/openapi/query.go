@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// applyQueryParams types query parameters and fills their defaults from the
+// code, and adds those the handler reads but the model left out. Without
+// located handlers, parameters are only attributed when the route has one method.
+func (b *Builder) applyQueryParams(method string, route models.APIRoute, single bool, params []map[string]interface{}) []map[string]interface{} {
+	for _, qp := range route.QueryParams {
+		if !readBy(method, route.Handlers, qp.Line, single) {
+			continue
+		}
+
+		var param, schema map[string]interface{}
+		for _, p := range params {
+			if p["name"] == qp.Name && p["in"] == "query" {
+				param = p
+				schema, _ = p["schema"].(map[string]interface{})
+				break
+			}
+		}
+		if param == nil {
+			schema = map[string]interface{}{"type": "string"}
+			if format := paramFormat(qp.Name, route.Formats); format != "" && qp.Type == "" {
+				schema["format"] = format
+			}
+			b.applyConstraints(schema, llm.Parameter{Name: qp.Name}, route.Constraints)
+			param = map[string]interface{}{
+				"name":     qp.Name,
+				"in":       "query",
+				"required": false,
+				"schema":   schema,
+			}
+			params = append(params, param)
+		}
+		if schema == nil {
+			schema = map[string]interface{}{"type": "string"}
+			param["schema"] = schema
+		}
+
+		// The conversion in code is what the handler actually accepts
+		if qp.Type != "" && !(qp.Type == "number" && schema["type"] == "integer") {
+			schema["type"] = qp.Type
+			delete(schema, "format")
+		}
+		if qp.Type == "array" && schema["items"] == nil {
+			schema["items"] = map[string]interface{}{"type": "string"} // ?tag=a&tag=b
+		}
+		if _, set := schema["default"]; !set && qp.Default != nil {
+			schema["default"] = qp.Default
+		}
+		if qp.Required {
+			param["required"] = true
+		}
+		if _, set := param["description"]; !set && qp.Description != "" {
+			param["description"] = qp.Description
+		}
+	}
+	return params
+}
+
+// readBy reports whether a line belongs to the handler for method
+func readBy(method string, handlers []models.Handler, line int, single bool) bool {
+	if len(handlers) == 0 {
+		return single
+	}
+	for _, h := range handlers {
+		if strings.EqualFold(h.Method, method) && line >= h.StartLine && line <= h.EndLine {
+			return true
+		}
+	}
+	return false
+}
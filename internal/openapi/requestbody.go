@@ -0,0 +1,103 @@
+package openapi
+
+// wholeBodyNames are body parameter names the model uses for the entire payload
+// rather than one field of it
+var wholeBodyNames = map[string]bool{"body": true, "payload": true}
+
+// extractRequestBody moves the parameters the model placed "in": "body" or
+// "formData" (which OpenAPI 3 does not allow) into a requestBody. Each becomes a
+// property of the body schema, required when the parameter was. Form fields
+// make a multipart/form-data body when one is a file and an
+// application/x-www-form-urlencoded body otherwise; body parameters make a JSON
+// body. It returns the remaining parameters and the request body, or nil when
+// there were no body parameters.
+func extractRequestBody(params []map[string]interface{}) ([]map[string]interface{}, map[string]interface{}) {
+	var kept, fields []map[string]interface{}
+	form, files := false, false
+	for _, param := range params {
+		switch param["in"] {
+		case "formData":
+			form = true
+			files = files || isFileField(param)
+			fields = append(fields, param)
+		case "body":
+			fields = append(fields, param)
+		default:
+			kept = append(kept, param)
+		}
+	}
+	if len(fields) == 0 {
+		return params, nil
+	}
+
+	var schema map[string]interface{}
+	required := false
+	if name, _ := fields[0]["name"].(string); len(fields) == 1 && !form && wholeBodyNames[name] {
+		schema = bodyProperty(fields[0])
+		required, _ = fields[0]["required"].(bool)
+	} else {
+		properties := make(map[string]interface{})
+		var requiredFields []string
+		for _, field := range fields {
+			name, _ := field["name"].(string)
+			properties[name] = bodyProperty(field)
+			if req, _ := field["required"].(bool); req {
+				requiredFields = append(requiredFields, name)
+			}
+		}
+		schema = map[string]interface{}{"type": "object", "properties": properties}
+		if len(requiredFields) > 0 {
+			schema["required"] = requiredFields
+			required = true
+		}
+	}
+
+	mediaType := "application/json"
+	switch {
+	case files:
+		mediaType = "multipart/form-data"
+	case form:
+		mediaType = "application/x-www-form-urlencoded"
+	}
+	body := map[string]interface{}{
+		"content": map[string]interface{}{
+			mediaType: map[string]interface{}{"schema": schema},
+		},
+	}
+	if required {
+		body["required"] = true
+	}
+	return kept, body
+}
+
+// bodyProperty is the schema of a body parameter, carrying its description
+func bodyProperty(param map[string]interface{}) map[string]interface{} {
+	property := make(map[string]interface{})
+	if schema, ok := param["schema"].(map[string]interface{}); ok {
+		for key, value := range schema {
+			property[key] = value
+		}
+	}
+	if property["type"] == nil {
+		if paramType, ok := param["type"].(string); ok {
+			property["type"] = paramType // Swagger 2 puts form field types on the parameter
+		}
+	}
+	if property["type"] == "" {
+		delete(property, "type")
+	}
+	if property["type"] == "file" {
+		property["type"] = "string"
+		property["format"] = "binary"
+	}
+	if description, ok := param["description"].(string); ok && description != "" {
+		property["description"] = description
+	}
+	return property
+}
+
+// isFileField reports whether a form field uploads a file
+func isFileField(param map[string]interface{}) bool {
+	schema, _ := param["schema"].(map[string]interface{})
+	return param["type"] == "file" || schema["type"] == "file" || schema["format"] == "binary"
+}
@@ -0,0 +1,183 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+)
+
+// applyResponses overlays responses documented by the model onto the defaults
+func (b *Builder) applyResponses(path, method string, documented map[string]llm.Response, operation map[string]interface{}) {
+	responses := operation["responses"].(map[string]interface{})
+
+	for _, status := range slices.Sorted(maps.Keys(documented)) {
+		doc := documented[status]
+		response := map[string]interface{}{
+			"description": doc.Description,
+		}
+		if doc.Description == "" {
+			response["description"] = "Response " + status
+		}
+
+		hint := SchemaHint{Method: method, Path: path, Role: "Response"}
+		if schema := b.responseSchema(hint, doc.Schemas); schema != nil {
+			response["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": schema,
+				},
+			}
+		}
+
+		responses[status] = response
+	}
+}
+
+// responseSchema collapses one or more response shapes into a single schema.
+// Several shapes become a oneOf, with a discriminator when a tag field exists.
+func (b *Builder) responseSchema(hint SchemaHint, schemas []map[string]interface{}) map[string]interface{} {
+	// A single schema may itself already be a oneOf
+	if len(schemas) == 1 {
+		if variants := schemaList(schemas[0]["oneOf"]); len(variants) > 1 {
+			schemas = variants
+		} else {
+			return schemas[0]
+		}
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	property, values := discriminatorProperty(schemas)
+	if property == "" {
+		return map[string]interface{}{"oneOf": schemas}
+	}
+
+	// Discriminator mappings must point at named schemas
+	var variants []map[string]interface{}
+	mapping := make(map[string]interface{})
+	for i, schema := range schemas {
+		variantHint := hint
+		variantHint.TypeName, _ = schema["title"].(string)
+		variantHint.Role = pascalCase(values[i]) + hint.Role
+		if variantHint.TypeName == "" {
+			variantHint.TypeName = pascalCase(resourceSegment(hint.Path)) + variantHint.Role
+		}
+
+		ref := b.schemaRef(variantHint, schema)
+		variants = append(variants, ref)
+		mapping[values[i]] = ref["$ref"]
+	}
+
+	return map[string]interface{}{
+		"oneOf": variants,
+		"discriminator": map[string]interface{}{
+			"propertyName": property,
+			"mapping":      mapping,
+		},
+	}
+}
+
+// discriminatorProperty finds a property present in every variant with a single,
+// distinct constant value, returning its name and the value for each variant
+func discriminatorProperty(schemas []map[string]interface{}) (string, []string) {
+	first, _ := schemas[0]["properties"].(map[string]interface{})
+
+	// Check candidates in a stable order, preferring conventional tag names
+	var candidates []string
+	for name := range first {
+		candidates = append(candidates, name)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		pi, pj := tagPriority(candidates[i]), tagPriority(candidates[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return candidates[i] < candidates[j]
+	})
+
+	for _, name := range candidates {
+		values := make([]string, 0, len(schemas))
+		seen := make(map[string]bool)
+		for _, schema := range schemas {
+			properties, _ := schema["properties"].(map[string]interface{})
+			prop, _ := properties[name].(map[string]interface{})
+			value, ok := constantValue(prop)
+			if !ok || seen[value] {
+				break
+			}
+			seen[value] = true
+			values = append(values, value)
+		}
+		if len(values) == len(schemas) {
+			return name, values
+		}
+	}
+	return "", nil
+}
+
+func tagPriority(name string) int {
+	switch strings.ToLower(name) {
+	case "type", "kind":
+		return 0
+	case "status", "success", "ok":
+		return 1
+	}
+	return 2
+}
+
+// constantValue returns the single allowed string value of a property schema
+func constantValue(prop map[string]interface{}) (string, bool) {
+	if prop == nil {
+		return "", false
+	}
+	if value, ok := prop["const"].(string); ok {
+		return value, true
+	}
+	if enum, ok := prop["enum"].([]interface{}); ok && len(enum) == 1 {
+		value, ok := enum[0].(string)
+		return value, ok
+	}
+	return "", false
+}
+
+func schemaList(value interface{}) []map[string]interface{} {
+	items, _ := value.([]interface{})
+	var schemas []map[string]interface{}
+	for _, item := range items {
+		if schema, ok := item.(map[string]interface{}); ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas
+}
+
+// applyBinaryResponse documents the success response as a file download
+func applyBinaryResponse(mediaType string, operation map[string]interface{}) {
+	responses := operation["responses"].(map[string]interface{})
+	content := map[string]interface{}{
+		mediaType: map[string]interface{}{
+			"schema": map[string]interface{}{
+				"type":   "string",
+				"format": "binary",
+			},
+		},
+	}
+
+	for status, response := range responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		if resp, ok := response.(map[string]interface{}); ok {
+			resp["content"] = content
+		}
+	}
+	if _, ok := responses["200"]; !ok {
+		responses["200"] = map[string]interface{}{
+			"description": "File download",
+			"content":     content,
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package openapi
+
+import "nextjs-to-openapi/internal/models"
+
+// mergeRouters combines a path item with the one already documented for the
+// same path by the other router, which --router-conflicts merge keeps during
+// a migration. The app router's operation wins for a method both define and
+// its route stays the path's origin.
+func (b *Builder) mergeRouters(path string, route models.APIRoute, pathItem map[string]interface{}) (map[string]interface{}, models.APIRoute) {
+	existing, ok := b.spec.Paths[path].(map[string]interface{})
+	origin := b.origins[path]
+	if !ok || (origin.Router == models.PagesRouter) == (route.Router == models.PagesRouter) {
+		return pathItem, route
+	}
+
+	pages, app := pathItem, existing
+	if route.Router != models.PagesRouter {
+		pages, app, origin = existing, pathItem, route
+	}
+	merged := make(map[string]interface{}, len(pages)+len(app))
+	for key, value := range pages {
+		merged[key] = value
+	}
+	for key, value := range app {
+		merged[key] = value
+	}
+	return merged, origin
+}
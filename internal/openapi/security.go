@@ -0,0 +1,335 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// SecurityRule attaches a security requirement to every matching operation.
+// An operation matches when it satisfies every selector that is set.
+type SecurityRule struct {
+	Pattern    string   // Next.js matcher syntax, e.g. /api/admin/:path*
+	PathPrefix string   // Plain path prefix, e.g. /api/admin
+	Tag        string   // Operation tag
+	App        string   // Workspace app the operation belongs to, e.g. the one whose middleware declared the rule
+	Scheme     string   // Name of a scheme under components.securitySchemes
+	Scopes     []string // Required scopes (OAuth2) or roles
+	matcher    *pathMatcher
+}
+
+// DefaultBearerScheme is used when middleware protects routes without further detail
+const DefaultBearerScheme = "bearerAuth"
+
+// AddSecurityScheme registers a scheme under components.securitySchemes
+func (b *Builder) AddSecurityScheme(name string, scheme map[string]interface{}) {
+	b.component("securitySchemes")[name] = scheme
+}
+
+// AddGlobalSecurity adds a top-level security requirement, which applies to
+// every operation that declares none of its own. Several are alternatives.
+func (b *Builder) AddGlobalSecurity(scheme string, scopes []string) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	for _, requirement := range b.spec.Security {
+		if _, ok := requirement[scheme]; ok {
+			return
+		}
+	}
+	b.spec.Security = append(b.spec.Security, map[string]interface{}{scheme: scopes})
+}
+
+// AddSecurityRule registers a rule that is applied to every matching operation
+func (b *Builder) AddSecurityRule(rule SecurityRule) error {
+	if rule.Pattern != "" {
+		matcher, err := compileMatcher(rule.Pattern)
+		if err != nil {
+			return err
+		}
+		rule.matcher = matcher
+	}
+	b.security = append(b.security, rule)
+	return nil
+}
+
+// ApplySecurityConfig registers the schemes and rules declared in the config file
+func (b *Builder) ApplySecurityConfig(cfg models.SecurityConfig) error {
+	for name, scheme := range cfg.Schemes {
+		b.AddSecurityScheme(name, SchemeObject(scheme))
+	}
+
+	for _, requirement := range cfg.Global {
+		b.AddGlobalSecurity(requirement.Scheme, requirement.Scopes)
+	}
+
+	for _, rule := range cfg.Rules {
+		err := b.AddSecurityRule(SecurityRule{
+			PathPrefix: rule.PathPrefix,
+			Tag:        rule.Tag,
+			Scheme:     rule.Scheme,
+			Scopes:     rule.Scopes,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SchemeObject converts a configured scheme into an OpenAPI security scheme object
+func SchemeObject(scheme models.SecurityScheme) map[string]interface{} {
+	obj := map[string]interface{}{"type": scheme.Type}
+	if scheme.Description != "" {
+		obj["description"] = scheme.Description
+	}
+
+	switch scheme.Type {
+	case "http":
+		obj["scheme"] = scheme.Scheme
+		if scheme.BearerFormat != "" {
+			obj["bearerFormat"] = scheme.BearerFormat
+		}
+	case "apiKey":
+		obj["in"] = scheme.In
+		obj["name"] = scheme.Name
+	case "oauth2":
+		flows := make(map[string]interface{})
+		for flowType, flow := range scheme.Flows {
+			scopes := flow.Scopes
+			if scopes == nil {
+				scopes = map[string]string{} // scopes is required, even when empty
+			}
+			f := map[string]interface{}{"scopes": scopes}
+			if flow.AuthorizationURL != "" {
+				f["authorizationUrl"] = flow.AuthorizationURL
+			}
+			if flow.TokenURL != "" {
+				f["tokenUrl"] = flow.TokenURL
+			}
+			if flow.RefreshURL != "" {
+				f["refreshUrl"] = flow.RefreshURL
+			}
+			flows[flowType] = f
+		}
+		obj["flows"] = flows
+	}
+
+	return obj
+}
+
+// applySecurity adds the requirements of all matching rules to an operation
+func (b *Builder) applySecurity(path, app string, operation map[string]interface{}) {
+	for _, rule := range b.security {
+		if !rule.matches(path, app, operation) {
+			continue
+		}
+		addSecurityRequirement(operation, rule.Scheme, rule.Scopes)
+	}
+}
+
+// applyRoles documents the roles checked in a method's handler and the
+// resulting 403 response. Roles become scopes of OAuth2 and OpenID Connect
+// requirements; other schemes have no scopes in 3.0, so they are listed in
+// x-roles instead.
+func (b *Builder) applyRoles(method string, checked map[string][]string, operation map[string]interface{}) {
+	roles := mergeScopes(slices.Clone(checked["*"]), checked[method])
+	if len(roles) == 0 {
+		return
+	}
+
+	requirements, _ := operation["security"].([]map[string][]string)
+	if len(requirements) == 0 {
+		b.EnsureDefaultScheme()
+		addSecurityRequirement(operation, DefaultBearerScheme, nil)
+		requirements, _ = operation["security"].([]map[string][]string)
+	}
+	listed := false
+	for _, requirement := range requirements {
+		for scheme, scopes := range requirement {
+			if b.hasScopes(scheme) {
+				requirement[scheme] = mergeScopes(scopes, roles)
+			} else {
+				listed = true
+			}
+		}
+	}
+	if listed {
+		operation["x-roles"] = roles
+	}
+
+	responses := operation["responses"].(map[string]interface{})
+	responses["403"] = map[string]interface{}{
+		"description": "Forbidden - requires one of: " + strings.Join(roles, ", "),
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": b.errorSchemaRef(),
+			},
+		},
+	}
+}
+
+// applyAuthentication requires the default bearer scheme on methods the scanner
+// found behind an auth helper, unless a rule or the spec's top-level security
+// already covers them, and documents a 401 on operations with their own
+// requirements or an auth helper
+func (b *Builder) applyAuthentication(method string, route models.APIRoute, operation map[string]interface{}) {
+	requirements, _ := operation["security"].([]map[string][]string)
+	detected := slices.Contains(route.Authenticated, method) || slices.Contains(route.Authenticated, "*")
+	if detected && len(requirements) == 0 && len(b.spec.Security) == 0 {
+		b.EnsureDefaultScheme()
+		addSecurityRequirement(operation, DefaultBearerScheme, nil)
+		requirements, _ = operation["security"].([]map[string][]string)
+	}
+	if !detected && !slices.ContainsFunc(requirements, func(r map[string][]string) bool { return len(r) > 0 }) {
+		// Only operations that require a signed-in user can answer 401
+		return
+	}
+
+	responses := operation["responses"].(map[string]interface{})
+	if _, documented := responses["401"]; !documented {
+		responses["401"] = map[string]interface{}{
+			"description": "Unauthorized - authentication required",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": b.errorSchemaRef(),
+				},
+			},
+		}
+	}
+}
+
+// EnsureDefaultScheme registers the bearer scheme unless it already exists
+func (b *Builder) EnsureDefaultScheme() {
+	schemes := b.component("securitySchemes")
+	if _, ok := schemes[DefaultBearerScheme]; !ok {
+		schemes[DefaultBearerScheme] = map[string]interface{}{
+			"type":   "http",
+			"scheme": "bearer",
+		}
+	}
+}
+
+func (r SecurityRule) matches(path, app string, operation map[string]interface{}) bool {
+	if r.App != "" && r.App != app {
+		return false
+	}
+	if r.matcher != nil && !r.matcher.match(path) {
+		return false
+	}
+	if r.PathPrefix != "" && !underPrefix(path, r.PathPrefix) {
+		return false
+	}
+	if r.Tag != "" && !hasTag(operation, r.Tag) {
+		return false
+	}
+	return true
+}
+
+func hasTag(operation map[string]interface{}, tag string) bool {
+	tags, _ := operation["tags"].([]string)
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// addSecurityRequirement merges a scheme requirement into an operation's security list
+func addSecurityRequirement(operation map[string]interface{}, scheme string, scopes []string) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	requirements, _ := operation["security"].([]map[string][]string)
+	for _, requirement := range requirements {
+		if existing, ok := requirement[scheme]; ok {
+			requirement[scheme] = mergeScopes(existing, scopes)
+			return
+		}
+	}
+	operation["security"] = append(requirements, map[string][]string{scheme: scopes})
+}
+
+// hasScopes reports whether a registered scheme's requirements list scopes
+func (b *Builder) hasScopes(scheme string) bool {
+	object, _ := b.component("securitySchemes")[scheme].(map[string]interface{})
+	return object["type"] == "oauth2" || object["type"] == "openIdConnect"
+}
+
+func mergeScopes(existing, extra []string) []string {
+	for _, scope := range extra {
+		found := false
+		for _, e := range existing {
+			if e == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, scope)
+		}
+	}
+	return existing
+}
+
+// pathMatcher evaluates a Next.js middleware matcher against an OpenAPI path
+type pathMatcher struct {
+	regex   *regexp.Regexp
+	exclude []string // Prefixes rejected by a negative lookahead
+}
+
+var (
+	namedParamPattern = regexp.MustCompile(`/:([A-Za-z0-9_]+)([*+?]?)`)
+	lookaheadPattern  = regexp.MustCompile(`\(\?!([^)]*)\)`)
+)
+
+// compileMatcher converts path-to-regexp syntax into a Go regular expression.
+// Go's regexp has no lookahead, so the common `/((?!api|_next).*)` form is
+// handled by recording the excluded prefixes separately.
+func compileMatcher(pattern string) (*pathMatcher, error) {
+	m := &pathMatcher{}
+
+	if lookahead := lookaheadPattern.FindStringSubmatch(pattern); lookahead != nil {
+		for _, alt := range strings.Split(lookahead[1], "|") {
+			if alt = strings.TrimSpace(alt); alt != "" {
+				m.exclude = append(m.exclude, strings.ReplaceAll(alt, `\.`, "."))
+			}
+		}
+		pattern = strings.Replace(pattern, lookahead[0], "", 1)
+	}
+
+	expr := namedParamPattern.ReplaceAllStringFunc(pattern, func(segment string) string {
+		switch segment[len(segment)-1] {
+		case '*':
+			return `(?:/.*)?`
+		case '+':
+			return `/.+`
+		case '?':
+			return `(?:/[^/]+)?`
+		default:
+			return `/[^/]+`
+		}
+	})
+
+	regex, err := regexp.Compile("^" + expr + "/?$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher %q: %w", pattern, err)
+	}
+	m.regex = regex
+	return m, nil
+}
+
+func (m *pathMatcher) match(path string) bool {
+	rest := strings.TrimPrefix(path, "/")
+	for _, prefix := range m.exclude {
+		if strings.HasPrefix(rest, prefix) {
+			return false
+		}
+	}
+	return m.regex.MatchString(path)
+}
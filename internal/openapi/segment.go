@@ -0,0 +1,46 @@
+package openapi
+
+import (
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// applySegmentConfig surfaces a route's segment config as extensions:
+// x-max-duration (seconds) and x-region on every operation, and x-dynamic and
+// x-revalidate on GET, the only handlers Next.js caches. Extensions an
+// @openapi annotation already set are kept.
+func applySegmentConfig(method string, config *models.SegmentConfig, operation map[string]interface{}) {
+	if config == nil {
+		return
+	}
+	set := func(key string, value interface{}) {
+		if _, ok := operation[key]; !ok {
+			operation[key] = value
+		}
+	}
+
+	if config.MaxDuration > 0 {
+		set("x-max-duration", config.MaxDuration)
+	}
+	switch len(config.PreferredRegion) {
+	case 0:
+	case 1:
+		set("x-region", config.PreferredRegion[0])
+	default:
+		set("x-region", config.PreferredRegion)
+	}
+
+	if !strings.EqualFold(method, "GET") {
+		return
+	}
+	if config.Dynamic != "" {
+		set("x-dynamic", config.Dynamic)
+	}
+	if seconds, err := strconv.Atoi(config.Revalidate); err == nil {
+		set("x-revalidate", seconds)
+	} else if config.Revalidate == "false" {
+		set("x-revalidate", false)
+	}
+}
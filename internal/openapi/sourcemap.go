@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// SourceMap links spec operations and schemas back to the code they came from,
+// for editor integrations
+type SourceMap struct {
+	Operations []OperationSource `json:"operations"`
+	Schemas    []SchemaSource    `json:"schemas"`
+}
+
+// OperationSource locates the handler behind one operation
+type OperationSource struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	File      string `json:"file"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// SchemaSource locates a component schema. Lines are only known when the schema
+// matches a TypeScript type declared in one of the route files using it.
+type SchemaSource struct {
+	Name      string   `json:"name"`
+	File      string   `json:"file,omitempty"`
+	StartLine int      `json:"start_line,omitempty"`
+	EndLine   int      `json:"end_line,omitempty"`
+	UsedBy    []string `json:"used_by"` // "GET /api/users"
+}
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// SourceMap builds the source map for every route added so far
+func (b *Builder) SourceMap() SourceMap {
+	sm := SourceMap{Operations: []OperationSource{}, Schemas: []SchemaSource{}}
+	schemas, _ := b.spec.Components["schemas"].(map[string]interface{})
+	users := make(map[string][]string)
+	userRoutes := make(map[string][]models.APIRoute)
+
+	for _, path := range slices.Sorted(maps.Keys(b.origins)) {
+		route := b.origins[path]
+		item, _ := b.spec.Paths[path].(map[string]interface{})
+
+		for _, method := range slices.Sorted(maps.Keys(item)) {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok || method == "servers" || method == "parameters" {
+				continue
+			}
+
+			source := OperationSource{Method: strings.ToUpper(method), Path: path, File: route.FilePath}
+			for _, h := range route.Handlers {
+				if strings.EqualFold(h.Method, method) {
+					source.StartLine, source.EndLine = h.StartLine, h.EndLine
+				}
+			}
+			sm.Operations = append(sm.Operations, source)
+
+			operationID := source.Method + " " + path
+			for name := range referencedSchemas(operation, schemas) {
+				users[name] = append(users[name], operationID)
+				userRoutes[name] = append(userRoutes[name], route)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(users)) {
+		source := SchemaSource{Name: name, UsedBy: users[name]}
+		source.File = userRoutes[name][0].FilePath
+
+		typeName := strings.TrimSuffix(strings.TrimPrefix(name, b.naming.Prefix), b.naming.Suffix)
+	search:
+		for _, route := range userRoutes[name] {
+			for _, decl := range route.Types {
+				if pascalCase(decl.Name) == typeName {
+					source.File, source.StartLine, source.EndLine = route.FilePath, decl.StartLine, decl.EndLine
+					if decl.File != "" {
+						source.File = decl.File
+					}
+					break search
+				}
+			}
+		}
+		sm.Schemas = append(sm.Schemas, source)
+	}
+
+	return sm
+}
+
+// referencedSchemas collects the component schemas a value refers to, following
+// references between schemas
+func referencedSchemas(value interface{}, schemas map[string]interface{}) map[string]bool {
+	found := make(map[string]bool)
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, schemaRefPrefix) {
+				name := strings.TrimPrefix(ref, schemaRefPrefix)
+				if !found[name] {
+					found[name] = true
+					walk(schemas[name])
+				}
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case []map[string]interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(value)
+	return found
+}
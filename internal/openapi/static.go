@@ -0,0 +1,67 @@
+package openapi
+
+import (
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// singletonTemplate words operations on a route named by a singular noun, such
+// as /api/health or /api/login, which is one thing rather than a collection
+var singletonTemplate = map[string]models.FactoryOperation{
+	"GET":    {Summary: "Get {resource}", Description: "Returns the {resource}.", Status: "200"},
+	"POST":   {Summary: "Submit {resource}", Description: "Submits a {resource} request.", Status: "200"},
+	"PUT":    {Summary: "Replace {resource}", Description: "Replaces the {resource}.", Status: "200"},
+	"PATCH":  {Summary: "Update {resource}", Description: "Updates fields of the {resource}.", Status: "200"},
+	"DELETE": {Summary: "Delete {resource}", Description: "Deletes the {resource}.", Status: "204"},
+}
+
+// StaticDocumentation documents a route from what the scanner found alone, for
+// routes too simple to be worth a model call: each exported handler gets a
+// summary, description and success response worded after its method and path,
+// plus its path parameters. Query parameters are added from the scan as for any
+// route. It returns nil when the route exports no handlers.
+func (b *Builder) StaticDocumentation(route models.APIRoute) *llm.RouteDocumentation {
+	if len(route.Handlers) == 0 {
+		return nil
+	}
+
+	resource := ""
+	segments := strings.Split(strings.Trim(route.Path, "/"), "/")
+	for i := len(segments) - 1; i >= 0 && resource == ""; i-- {
+		if !strings.HasPrefix(segments[i], "{") {
+			resource = segments[i]
+		}
+	}
+	singular, plural := resourceNames(resource)
+
+	item := strings.HasSuffix(route.Path, "}")
+	template := collectionTemplate
+	switch {
+	case item:
+		template = itemTemplate
+	case singular == strings.ToLower(strings.Join(splitWords(resource), " ")):
+		template = singletonTemplate
+	}
+	fill := strings.NewReplacer("{resource}", singular, "{resources}", plural)
+
+	doc := &llm.RouteDocumentation{Path: route.Path, Methods: make(map[string]llm.Method)}
+	for _, h := range route.Handlers {
+		op, ok := template[h.Method]
+		if !ok {
+			op = models.FactoryOperation{Summary: h.Method + " {resource}", Status: "200"}
+		}
+		responses := map[string]llm.Response{op.Status: {Description: fill.Replace(op.Summary)}}
+		if item {
+			responses["404"] = llm.Response{Description: fill.Replace("No {resource} has this ID")}
+		}
+		doc.Methods[h.Method] = llm.Method{
+			Summary:     fill.Replace(op.Summary),
+			Description: fill.Replace(op.Description),
+			Parameters:  factoryPathParams(route.Path, singular),
+			Responses:   responses,
+		}
+	}
+	return doc
+}
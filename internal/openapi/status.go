@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Methods a Next.js route handler can export; others get a 405
+var handlerMethods = []string{"GET", "HEAD", "OPTIONS", "POST", "PUT", "PATCH", "DELETE"}
+
+// SetDefaultResponses sets which framework responses every matching operation gets
+func (b *Builder) SetDefaultResponses(defaults models.DefaultResponses) {
+	b.defaults = defaults
+}
+
+// applyFrameworkResponses documents what Next.js itself returns: 404 for
+// dynamic routes whose parameters match nothing, and 405 with an Allow header
+// for methods the route file does not export. The model's responses override them.
+func (b *Builder) applyFrameworkResponses(path string, route models.APIRoute, doc *llm.RouteDocumentation, operation map[string]interface{}) {
+	responses := operation["responses"].(map[string]interface{})
+
+	if enabled(b.defaults.NotFound) && strings.Contains(path, "{") {
+		responses["404"] = map[string]interface{}{
+			"description": "Not found: nothing matches the path parameters",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": b.errorSchemaRef(),
+				},
+			},
+		}
+	}
+
+	allowed := allowedMethods(route, doc)
+	if enabled(b.defaults.MethodNotAllowed) && len(allowed) < len(handlerMethods) {
+		allow := strings.Join(allowed, ", ")
+		responses["405"] = map[string]interface{}{
+			"description": "Method not allowed: this path supports " + allow,
+			"headers": map[string]interface{}{
+				"Allow": map[string]interface{}{
+					"description": "Methods this path supports",
+					"schema":      map[string]interface{}{"type": "string", "example": allow},
+				},
+			},
+		}
+	}
+}
+
+// allowedMethods lists the methods a route answers, in handlerMethods order.
+// Exported handlers are preferred over the model's list; Next.js adds HEAD for
+// GET and answers OPTIONS itself.
+func allowedMethods(route models.APIRoute, doc *llm.RouteDocumentation) []string {
+	exported := map[string]bool{"OPTIONS": true}
+	for _, h := range route.Handlers {
+		exported[strings.ToUpper(h.Method)] = true
+	}
+	if len(route.Handlers) == 0 {
+		for method := range doc.Methods {
+			exported[strings.ToUpper(method)] = true
+		}
+	}
+	if exported["GET"] {
+		exported["HEAD"] = true
+	}
+
+	return slices.DeleteFunc(slices.Clone(handlerMethods), func(m string) bool { return !exported[m] })
+}
+
+// enabled treats an unset option as on
+func enabled(option *bool) bool {
+	return option == nil || *option
+}
@@ -0,0 +1,56 @@
+package openapi
+
+import (
+	"regexp"
+	"strings"
+)
+
+var versionSegment = regexp.MustCompile(`^v\d+$`)
+
+// SetPathTags tags operations that get no tag from their app or folder
+// overview with the first segment of their path
+func (b *Builder) SetPathTags(enabled bool) {
+	b.pathTags = enabled
+}
+
+// PathTag returns the resource a path belongs to: its first static segment
+// after /api and a version, e.g. /api/v1/users/{id} -> users
+func PathTag(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			segments = append(segments, segment)
+		}
+	}
+	for len(segments) > 1 && (segments[0] == "api" || versionSegment.MatchString(segments[0])) {
+		segments = segments[1:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return segments[0]
+}
+
+// applyPathTag tags an untagged operation with its path's resource, after
+// the tag prefix of its workspace app
+func (b *Builder) applyPathTag(path, prefix string, operation map[string]interface{}) {
+	if !b.pathTags {
+		return
+	}
+	if tags, _ := operation["tags"].([]string); len(tags) > 0 {
+		return
+	}
+	if tag := PathTag(path); tag != "" {
+		tag = prefixedTag(prefix, tag)
+		operation["tags"] = []string{tag}
+		b.AddTag(tag, "")
+	}
+}
+
+// prefixedTag puts a workspace app's tag prefix in front of a tag
+func prefixedTag(prefix, tag string) string {
+	if prefix == "" {
+		return tag
+	}
+	return prefix + "/" + tag
+}
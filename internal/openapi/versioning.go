@@ -0,0 +1,167 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+// Versioning modes: document the version header on each operation, also write
+// one spec per version, or leave version headers undocumented
+const (
+	VersioningHeader = "header"
+	VersioningSplit  = "split"
+	VersioningOff    = "off"
+)
+
+// VersionsExtension records, on an operation, the version header and the
+// versions its handler tells apart, with the model's note on each
+const VersionsExtension = "x-api-versions"
+
+// versionNotesIntro starts the per-version section appended to descriptions
+const versionNotesIntro = "\n\nBehavior by `"
+
+// SetVersioning sets how API version headers found in handlers are documented
+func (b *Builder) SetVersioning(versioning models.APIVersioning) {
+	b.versioning = versioning
+}
+
+// applyVersioning documents the version header a handler branches on: a header
+// parameter listing the versions, and a note on how each behaves
+func (b *Builder) applyVersioning(method string, route models.APIRoute, details llm.Method, operation map[string]interface{}) {
+	header := route.Versioning
+	if header == nil || b.versioning.Mode == VersioningOff {
+		return
+	}
+	if len(header.Methods) > 0 && !slices.ContainsFunc(header.Methods, func(m string) bool { return strings.EqualFold(m, method) }) {
+		return
+	}
+
+	schema := map[string]interface{}{"type": "string", "enum": header.Versions}
+	if header.Default != "" {
+		schema["default"] = header.Default
+	}
+	param := map[string]interface{}{
+		"name":        header.Name,
+		"in":          "header",
+		"required":    header.Default == "",
+		"description": "API version to serve: " + strings.Join(header.Versions, ", "),
+		"schema":      schema,
+	}
+	params, _ := operation["parameters"].([]map[string]interface{})
+	replaced := false
+	for i, existing := range params {
+		if existing["in"] == "header" && strings.EqualFold(fmt.Sprint(existing["name"]), header.Name) {
+			params[i], replaced = param, true
+		}
+	}
+	if !replaced {
+		params = append(params, param)
+	}
+	operation["parameters"] = params
+
+	notes := make(map[string]interface{}, len(header.Versions))
+	var lines []string
+	for _, version := range header.Versions {
+		note := details.Versions[version]
+		notes[version] = note
+		if note != "" {
+			lines = append(lines, fmt.Sprintf("- `%s`: %s", version, note))
+		}
+	}
+	operation[VersionsExtension] = map[string]interface{}{"header": header.Name, "versions": notes}
+	if len(lines) > 0 {
+		description, _ := operation["description"].(string)
+		operation["description"] = description + versionNotesIntro + header.Name + "`:\n" + strings.Join(lines, "\n")
+	}
+}
+
+// VersionedSpec returns spec as clients of one API version see it: operations
+// branching on a version header have it pinned to that version and keep only
+// that version's note
+func VersionedSpec(spec map[string]interface{}, version string) (map[string]interface{}, error) {
+	// Work on a copy so every version starts from the same spec
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var versioned map[string]interface{}
+	if err := json.Unmarshal(data, &versioned); err != nil {
+		return nil, err
+	}
+
+	paths, _ := versioned["paths"].(map[string]interface{})
+	for _, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, op := range pathItem {
+			operation, ok := op.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ext, ok := operation[VersionsExtension].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			delete(operation, VersionsExtension)
+			notes, _ := ext["versions"].(map[string]interface{})
+			if _, listed := notes[version]; !listed {
+				continue
+			}
+
+			name, _ := ext["header"].(string)
+			params, _ := operation["parameters"].([]interface{})
+			for _, p := range params {
+				param, _ := p.(map[string]interface{})
+				if param["in"] == "header" && strings.EqualFold(fmt.Sprint(param["name"]), name) {
+					schema := map[string]interface{}{"type": "string", "enum": []interface{}{version}}
+					// The default version is still served without the header
+					if old, _ := param["schema"].(map[string]interface{}); old["default"] == version {
+						schema["default"] = version
+					} else {
+						param["required"] = true
+					}
+					param["schema"] = schema
+					param["description"] = "API version; must be " + version
+				}
+			}
+
+			description, _ := operation["description"].(string)
+			if i := strings.Index(description, versionNotesIntro); i >= 0 {
+				description = description[:i]
+			}
+			if note, _ := notes[version].(string); note != "" {
+				description += "\n\n" + note
+			}
+			operation["description"] = description
+		}
+	}
+	return versioned, nil
+}
+
+// SpecVersions lists the API versions operations in spec branch on
+func SpecVersions(spec map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var versions []string
+	paths, _ := spec["paths"].(map[string]interface{})
+	for _, item := range paths {
+		pathItem, _ := item.(map[string]interface{})
+		for _, op := range pathItem {
+			operation, _ := op.(map[string]interface{})
+			ext, _ := operation[VersionsExtension].(map[string]interface{})
+			notes, _ := ext["versions"].(map[string]interface{})
+			for version := range notes {
+				if !seen[version] {
+					seen[version] = true
+					versions = append(versions, version)
+				}
+			}
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// SetWebhooks sets the outgoing requests the API sends to subscribers
+func (b *Builder) SetWebhooks(hooks []models.Webhook) {
+	b.webhooks = hooks
+}
+
+// MissingWebhookSchemas returns the webhook schemas the last Spec could not
+// find in components, whose payloads were documented as plain objects
+func (b *Builder) MissingWebhookSchemas() []string {
+	return b.missingHooks
+}
+
+// addWebhooks documents the configured webhooks under `webhooks`, which only
+// exists in 3.1; 3.0 documents get the x-webhooks extension Redoc reads instead
+func (b *Builder) addWebhooks() {
+	b.spec.Webhooks, b.spec.XWebhooks, b.missingHooks = nil, nil, nil
+	if len(b.webhooks) == 0 {
+		return
+	}
+
+	hooks := make(map[string]interface{}, len(b.webhooks))
+	for _, hook := range b.webhooks {
+		method := strings.ToLower(hook.Method)
+		if method == "" {
+			method = "post"
+		}
+
+		schema := map[string]interface{}{"type": "object"}
+		if hook.Schema != "" {
+			// A reference to a schema no route produced would leave the
+			// document invalid, so the payload stays a plain object
+			schemas, _ := b.spec.Components["schemas"].(map[string]interface{})
+			if _, ok := schemas[hook.Schema]; ok {
+				schema = map[string]interface{}{"$ref": "#/components/schemas/" + hook.Schema}
+			} else if !slices.Contains(b.missingHooks, hook.Schema) {
+				b.missingHooks = append(b.missingHooks, hook.Schema)
+			}
+		}
+		operation := map[string]interface{}{
+			"requestBody": map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schema},
+				},
+			},
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "Return a 2xx status to acknowledge the event",
+				},
+			},
+		}
+		if hook.Summary != "" {
+			operation["summary"] = hook.Summary
+		}
+		if hook.Description != "" {
+			operation["description"] = hook.Description
+		}
+		if len(hook.Tags) > 0 {
+			operation["tags"] = hook.Tags
+		}
+		hooks[hook.Name] = map[string]interface{}{method: operation}
+	}
+
+	if b.is31() {
+		b.spec.Webhooks = hooks
+	} else {
+		b.spec.XWebhooks = hooks
+	}
+}
@@ -0,0 +1,62 @@
+package openapi
+
+import "nextjs-to-openapi/internal/models"
+
+// AddWorkspaceApp registers a monorepo app: the tags of its routes are
+// prefixed with the app's tag, its paths with its path prefix, and its server
+// is listed at the top level and on each of its paths
+func (b *Builder) AddWorkspaceApp(app models.WorkspaceApp) {
+	b.apps[app.Name] = app
+
+	if app.Server != "" {
+		b.AddServer(app.Server, app.Name)
+	}
+}
+
+// AddServer appends an entry to the top-level servers list, skipping duplicates
+func (b *Builder) AddServer(url, description string) {
+	b.AddTemplatedServer(url, description, nil)
+}
+
+// AddTemplatedServer appends a server whose URL contains {variables}, each
+// with the given default
+func (b *Builder) AddTemplatedServer(url, description string, variables map[string]string) {
+	for _, server := range b.spec.Servers {
+		if server["url"] == url {
+			return
+		}
+	}
+	server := map[string]interface{}{"url": url}
+	if description != "" {
+		server["description"] = description
+	}
+	if len(variables) > 0 {
+		vars := make(map[string]interface{})
+		for name, value := range variables {
+			vars[name] = map[string]interface{}{"default": value}
+		}
+		server["variables"] = vars
+	}
+	b.spec.Servers = append(b.spec.Servers, server)
+}
+
+// AddTag declares a top-level tag, keeping the first description given
+func (b *Builder) AddTag(name, description string) {
+	for _, tag := range b.spec.Tags {
+		if tag["name"] == name {
+			return
+		}
+	}
+	tag := map[string]interface{}{"name": name}
+	if description != "" {
+		tag["description"] = description
+	}
+	b.spec.Tags = append(b.spec.Tags, tag)
+}
+
+func appTag(app models.WorkspaceApp) string {
+	if app.TagPrefix != "" {
+		return app.TagPrefix
+	}
+	return app.Name
+}
@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// applyZodSchemas documents the request body and responses of an operation
+// with the Zod schemas its handler parses the body with or builds responses
+// from, which are exact where the model's schemas are guesses. Titled object
+// schemas are hoisted to components afterwards like any other.
+func (b *Builder) applyZodSchemas(method string, route models.APIRoute, operation map[string]interface{}) {
+	for _, z := range route.Zod {
+		if z.Method != "*" && !strings.EqualFold(z.Method, method) {
+			continue
+		}
+		schema := b.zodSchema(z.Schema)
+
+		switch z.Target {
+		case models.ZodRequestBody:
+			if z.Method == "*" && !hasRequestBody(method) {
+				continue
+			}
+			body := map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": schema},
+				},
+			}
+			if existing, ok := operation["requestBody"].(map[string]interface{}); ok && existing["description"] != nil {
+				body["description"] = existing["description"]
+			}
+			operation["requestBody"] = body
+		case models.ZodResponse:
+			responses := operation["responses"].(map[string]interface{})
+			status := strconv.Itoa(z.Status)
+			if z.Status == 0 {
+				status = "200"
+				for _, code := range slices.Sorted(maps.Keys(responses)) {
+					if strings.HasPrefix(code, "2") {
+						status = code
+						break
+					}
+				}
+			}
+			response, _ := responses[status].(map[string]interface{})
+			if response == nil && strings.HasPrefix(status, "2") {
+				// The handler answers with another success status than the model
+				// documented, e.g. 201 for 200; move that response over
+				for _, code := range slices.Sorted(maps.Keys(responses)) {
+					if strings.HasPrefix(code, "2") {
+						response, _ = responses[code].(map[string]interface{})
+						delete(responses, code)
+						break
+					}
+				}
+			}
+			if response == nil {
+				response = map[string]interface{}{"description": "Successful response"}
+			}
+			response["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": schema},
+			}
+			responses[status] = response
+		}
+	}
+}
+
+// hasRequestBody reports whether a method usually carries a body
+func hasRequestBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "DELETE", "OPTIONS":
+		return false
+	}
+	return true
+}
+
+// zodSchema copies a translated schema for one operation, which later steps
+// modify, moving exclusive bounds to the 3.1 form when needed
+func (b *Builder) zodSchema(node interface{}) map[string]interface{} {
+	schema, _ := b.copyZod(node).(map[string]interface{})
+	return schema
+}
+
+func (b *Builder) copyZod(node interface{}) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			out[key] = b.copyZod(value)
+		}
+		if b.is31() {
+			for _, bound := range []struct{ inclusive, exclusive string }{{"minimum", "exclusiveMinimum"}, {"maximum", "exclusiveMaximum"}} {
+				if out[bound.exclusive] == true {
+					out[bound.exclusive] = out[bound.inclusive]
+					delete(out, bound.inclusive)
+				}
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = b.copyZod(value)
+		}
+		return out
+	case []string:
+		return slices.Clone(v)
+	}
+	return node
+}
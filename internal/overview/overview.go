@@ -0,0 +1,153 @@
+package overview
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MaxOperations caps how many operations a digest lists one by one
+const MaxOperations = 150
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Digest condenses a spec into the facts an overview is written from: title,
+// tags, security schemes, operations, and the parameters, headers and
+// response codes many operations share
+func Digest(spec map[string]interface{}) string {
+	var b strings.Builder
+
+	info := mapOf(spec["info"])
+	fmt.Fprintf(&b, "Title: %v (version %v)\n", info["title"], info["version"])
+
+	if tags := listOf(spec["tags"]); len(tags) > 0 {
+		b.WriteString("\nTags:\n")
+		for _, t := range tags {
+			tag := mapOf(t)
+			fmt.Fprintf(&b, "- %v", tag["name"])
+			if description, _ := tag["description"].(string); description != "" {
+				fmt.Fprintf(&b, ": %s", firstLine(description))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	schemes := mapOf(mapOf(spec["components"])["securitySchemes"])
+	if len(schemes) > 0 {
+		b.WriteString("\nSecurity schemes:\n")
+		for _, name := range slices.Sorted(maps.Keys(schemes)) {
+			scheme := mapOf(schemes[name])
+			fmt.Fprintf(&b, "- %s: %v", name, scheme["type"])
+			for _, key := range []string{"scheme", "in", "name"} {
+				if value, ok := scheme[key]; ok {
+					fmt.Fprintf(&b, " %s=%v", key, value)
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	paths := mapOf(spec["paths"])
+	params := make(map[string]int)
+	statuses := make(map[string]int)
+	var operations []string
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item := mapOf(paths[path])
+		for _, method := range methods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			line := fmt.Sprintf("- %s %s", strings.ToUpper(method), path)
+			if summary, _ := op["summary"].(string); summary != "" {
+				line += ": " + summary
+			}
+			if tags := listOf(op["tags"]); len(tags) > 0 {
+				line += fmt.Sprintf(" %v", tags)
+			}
+			if security, ok := op["security"]; ok {
+				line += " (" + requirements(listOf(security)) + ")"
+			}
+			operations = append(operations, line)
+
+			for _, p := range listOf(op["parameters"]) {
+				param := mapOf(p)
+				if param["in"] != "path" {
+					params[fmt.Sprintf("%v (%v)", param["name"], param["in"])]++
+				}
+			}
+			for status := range mapOf(op["responses"]) {
+				statuses[status]++
+			}
+		}
+	}
+	if security, ok := spec["security"]; ok {
+		fmt.Fprintf(&b, "\nDefault security: %s\n", requirements(listOf(security)))
+	}
+
+	fmt.Fprintf(&b, "\nOperations (%d):\n", len(operations))
+	for i, line := range operations {
+		if i == MaxOperations {
+			fmt.Fprintf(&b, "- ... and %d more\n", len(operations)-MaxOperations)
+			break
+		}
+		b.WriteString(line + "\n")
+	}
+
+	if shared := counted(params, 2); shared != "" {
+		b.WriteString("\nParameters used by several operations: " + shared + "\n")
+	}
+	if len(statuses) > 0 {
+		b.WriteString("\nResponse codes: " + counted(statuses, 1) + "\n")
+	}
+	return b.String()
+}
+
+// requirements lists the schemes of security requirements, "public" for none
+func requirements(list []interface{}) string {
+	var names []string
+	for _, r := range list {
+		names = append(names, slices.Sorted(maps.Keys(mapOf(r)))...)
+	}
+	if len(names) == 0 {
+		return "public"
+	}
+	return "requires " + strings.Join(names, " or ")
+}
+
+// counted lists the names used at least the given number of times, most used first
+func counted(counts map[string]int, least int) string {
+	var names []string
+	for name, n := range counts {
+		if n >= least {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	for i, name := range names {
+		names[i] = fmt.Sprintf("%s ×%d", name, counts[name])
+	}
+	return strings.Join(names, ", ")
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+func mapOf(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func listOf(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
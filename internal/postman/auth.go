@@ -0,0 +1,90 @@
+package postman
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// auth converts security requirements into a Postman auth object, recording the
+// collection variables it uses in vars. Postman takes a single method, so the
+// first scheme of the first requirement is used. It returns nil when there are
+// no requirements, leaving the request to inherit the collection's auth.
+func auth(security interface{}, schemes map[string]interface{}, vars map[string]bool) map[string]interface{} {
+	requirements, ok := security.([]interface{})
+	if !ok {
+		return nil
+	}
+	if len(requirements) == 0 {
+		return map[string]interface{}{"type": "noauth"} // Explicitly public
+	}
+	requirement, _ := requirements[0].(map[string]interface{})
+	if len(requirement) == 0 {
+		return map[string]interface{}{"type": "noauth"}
+	}
+	name := slices.Sorted(maps.Keys(requirement))[0]
+	scheme, _ := schemes[name].(map[string]interface{})
+
+	switch scheme["type"] {
+	case "http":
+		if strings.EqualFold(str(scheme["scheme"]), "basic") {
+			vars["username"], vars["password"] = true, true
+			return method("basic", "username", "{{username}}", "password", "{{password}}")
+		}
+		vars["bearerToken"] = true
+		return method("bearer", "token", "{{bearerToken}}")
+	case "apiKey":
+		in := str(scheme["in"])
+		if in != "query" {
+			in = "header" // Postman cannot send a key as a cookie
+		}
+		vars["apiKey"] = true
+		return method("apikey", "key", str(scheme["name"]), "value", "{{apiKey}}", "in", in)
+	case "oauth2":
+		vars["clientId"], vars["clientSecret"] = true, true
+		pairs := []string{"clientId", "{{clientId}}", "clientSecret", "{{clientSecret}}", "addTokenTo", "header"}
+		flows, _ := scheme["flows"].(map[string]interface{})
+		for _, flow := range []struct{ name, grant string }{
+			{"clientCredentials", "client_credentials"},
+			{"authorizationCode", "authorization_code"},
+			{"password", "password_credentials"},
+			{"implicit", "implicit"},
+		} {
+			f, ok := flows[flow.name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pairs = append(pairs, "grant_type", flow.grant)
+			if url := str(f["tokenUrl"]); url != "" {
+				pairs = append(pairs, "accessTokenUrl", url)
+			}
+			if url := str(f["authorizationUrl"]); url != "" {
+				pairs = append(pairs, "authUrl", url)
+			}
+			if scopes := requirement[name]; len(asList(scopes)) > 0 {
+				var names []string
+				for _, scope := range asList(scopes) {
+					names = append(names, str(scope))
+				}
+				pairs = append(pairs, "scope", strings.Join(names, " "))
+			}
+			break
+		}
+		return method("oauth2", pairs...)
+	}
+	return nil
+}
+
+// method builds an auth object of type kind from key/value pairs
+func method(kind string, pairs ...string) map[string]interface{} {
+	var entries []interface{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		entries = append(entries, map[string]interface{}{"key": pairs[i], "value": pairs[i+1], "type": "string"})
+	}
+	return map[string]interface{}{"type": kind, kind: entries}
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
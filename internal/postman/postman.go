@@ -0,0 +1,187 @@
+package postman
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/examples"
+)
+
+// SchemaURL identifies the Postman collection format written
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// DefaultBaseURL is used when the spec declares no server
+const DefaultBaseURL = "http://localhost:3000"
+
+var (
+	methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+	pathParam   = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// Collection converts a spec into a Postman v2.1 collection with one folder per
+// tag. URLs start with {{baseUrl}}, path parameters become :name variables, and
+// bodies and parameter values are filled from the spec's examples.
+// Security requirements become auth settings on the collection and on requests,
+// with the credentials left as collection variables to fill in.
+func Collection(spec map[string]interface{}) map[string]interface{} {
+	info, _ := spec["info"].(map[string]interface{})
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	vars := make(map[string]bool)
+
+	folders := make(map[string][]interface{})
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+		for _, method := range methodOrder {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			tag := "default"
+			if tags, _ := operation["tags"].([]interface{}); len(tags) > 0 {
+				tag = fmt.Sprint(tags[0])
+			}
+			params := append(asList(item["parameters"]), asList(operation["parameters"])...)
+			item := request(method, path, operation, params, components)
+			if a := auth(operation["security"], schemes, vars); a != nil {
+				item["request"].(map[string]interface{})["auth"] = a
+			}
+			folders[tag] = append(folders[tag], item)
+		}
+	}
+
+	var items []interface{}
+	for _, tag := range slices.Sorted(maps.Keys(folders)) {
+		if len(folders) == 1 && tag == "default" {
+			items = folders[tag]
+			break
+		}
+		items = append(items, map[string]interface{}{"name": tag, "item": folders[tag]})
+	}
+
+	name := "API"
+	if title, ok := info["title"].(string); ok && title != "" {
+		name = title
+	}
+	collectionInfo := map[string]interface{}{"name": name, "schema": SchemaURL}
+	if description, ok := info["description"].(string); ok && description != "" {
+		collectionInfo["description"] = description
+	}
+	collection := map[string]interface{}{
+		"info": collectionInfo,
+		"item": items,
+	}
+	if a := auth(spec["security"], schemes, vars); a != nil {
+		collection["auth"] = a
+	}
+	variables := []interface{}{map[string]interface{}{"key": "baseUrl", "value": baseURL(spec)}}
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		variables = append(variables, map[string]interface{}{"key": name, "value": ""})
+	}
+	collection["variable"] = variables
+	return collection
+}
+
+// request builds one collection item
+func request(method, path string, operation map[string]interface{}, params []interface{}, components map[string]interface{}) map[string]interface{} {
+	segments := strings.Split(strings.TrimPrefix(pathParam.ReplaceAllString(path, ":$1"), "/"), "/")
+	url := map[string]interface{}{
+		"host": []string{"{{baseUrl}}"},
+		"path": segments,
+	}
+
+	var query, variables, headers []interface{}
+	for _, p := range params {
+		param := examples.Parameter(p, components)
+		name := fmt.Sprint(param["name"])
+		value := ""
+		if v := examples.FakeNamed(name, param["schema"], components); v != nil {
+			value = fmt.Sprint(v)
+		}
+		if ex, ok := param["example"]; ok {
+			value = fmt.Sprint(ex)
+		}
+		entry := map[string]interface{}{"key": name, "value": value}
+		if description, ok := param["description"].(string); ok && description != "" {
+			entry["description"] = description
+		}
+		switch param["in"] {
+		case "path":
+			variables = append(variables, entry)
+		case "query":
+			if param["required"] != true {
+				entry["disabled"] = true
+			}
+			query = append(query, entry)
+		case "header":
+			headers = append(headers, entry)
+		}
+	}
+
+	raw := "{{baseUrl}}/" + strings.Join(segments, "/")
+	if len(query) > 0 {
+		url["query"] = query
+		var pairs []string
+		for _, q := range query {
+			if entry := q.(map[string]interface{}); entry["disabled"] != true {
+				pairs = append(pairs, fmt.Sprintf("%s=%s", entry["key"], entry["value"]))
+			}
+		}
+		if len(pairs) > 0 {
+			raw += "?" + strings.Join(pairs, "&")
+		}
+	}
+	url["raw"] = raw
+	if len(variables) > 0 {
+		url["variable"] = variables
+	}
+
+	req := map[string]interface{}{
+		"method": strings.ToUpper(method),
+		"url":    url,
+	}
+	if description, ok := operation["description"].(string); ok && description != "" {
+		req["description"] = description
+	}
+	if body := examples.RequestBody(operation, components); body != nil {
+		data, _ := json.MarshalIndent(body, "", "  ")
+		req["body"] = map[string]interface{}{
+			"mode":    "raw",
+			"raw":     string(data),
+			"options": map[string]interface{}{"raw": map[string]interface{}{"language": "json"}},
+		}
+		headers = append(headers, map[string]interface{}{"key": "Content-Type", "value": "application/json"})
+	}
+	if len(headers) > 0 {
+		req["header"] = headers
+	}
+
+	name, _ := operation["summary"].(string)
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+	return map[string]interface{}{"name": name, "request": req}
+}
+
+// baseURL is the first server's URL, or DefaultBaseURL
+func baseURL(spec map[string]interface{}) string {
+	servers, _ := spec["servers"].([]interface{})
+	if len(servers) > 0 {
+		if server, ok := servers[0].(map[string]interface{}); ok {
+			if url, ok := server["url"].(string); ok && url != "" {
+				return strings.TrimSuffix(url, "/")
+			}
+		}
+	}
+	return DefaultBaseURL
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
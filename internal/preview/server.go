@@ -0,0 +1,217 @@
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Statuses of a route's preview
+const (
+	StatusPending = "pending"
+	StatusOK      = "ok"
+	StatusError   = "error"
+)
+
+// DocumentFunc documents one route file and returns its OpenAPI fragment
+type DocumentFunc func(file string) (interface{}, error)
+
+// Result is the latest preview of one route file
+type Result struct {
+	File      string      `json:"file"`
+	Status    string      `json:"status"`
+	Error     string      `json:"error,omitempty"`
+	Spec      interface{} `json:"spec,omitempty"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Server keeps a per-file OpenAPI preview up to date and serves it over HTTP
+type Server struct {
+	document DocumentFunc
+	root     string // Directory files may be documented from
+
+	mu          sync.Mutex
+	results     map[string]Result
+	subscribers map[chan Result]struct{}
+	running     map[string]bool
+}
+
+// NewServer creates a preview server that documents files below root with document
+func NewServer(root string, document DocumentFunc) *Server {
+	return &Server{
+		document:    document,
+		root:        root,
+		results:     make(map[string]Result),
+		subscribers: make(map[chan Result]struct{}),
+		running:     make(map[string]bool),
+	}
+}
+
+// Update re-documents a file and notifies subscribers. Saves arriving while a
+// file is being documented are coalesced into one follow-up run.
+func (s *Server) Update(file string) Result {
+	file = filepath.Clean(file)
+
+	s.mu.Lock()
+	if s.running[file] {
+		s.results[file] = Result{File: file, Status: StatusPending, UpdatedAt: time.Now()}
+		s.mu.Unlock()
+		return s.results[file]
+	}
+	s.running[file] = true
+	s.mu.Unlock()
+	s.publish(Result{File: file, Status: StatusPending, UpdatedAt: time.Now()})
+
+	for {
+		result := Result{File: file, Status: StatusOK, UpdatedAt: time.Now()}
+		spec, err := s.document(file)
+		if err != nil {
+			result.Status, result.Error = StatusError, err.Error()
+		} else {
+			result.Spec = spec
+		}
+
+		s.mu.Lock()
+		again := s.results[file].Status == StatusPending && s.results[file].UpdatedAt.After(result.UpdatedAt)
+		if !again {
+			delete(s.running, file)
+		}
+		s.mu.Unlock()
+
+		if !again {
+			s.publish(result)
+			return result
+		}
+	}
+}
+
+// Forget drops the preview of a deleted file
+func (s *Server) Forget(file string) {
+	file = filepath.Clean(file)
+	s.mu.Lock()
+	delete(s.results, file)
+	s.mu.Unlock()
+}
+
+func (s *Server) publish(result Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results[result.File] = result
+	for ch := range s.subscribers {
+		select {
+		case ch <- result:
+		default: // Slow subscribers miss intermediate states, never the final one on reconnect
+		}
+	}
+}
+
+// Handler exposes the previews:
+//
+//	GET  /routes          every known preview (without specs)
+//	GET  /route?file=...  the preview of one file, documenting it if needed
+//	POST /route?file=...  re-document one file now
+//	GET  /events          server-sent events for every status change
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/routes", s.handleRoutes)
+	mux.HandleFunc("/route", s.handleRoute)
+	mux.HandleFunc("/events", s.handleEvents)
+	return mux
+}
+
+func (s *Server) handleRoutes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	list := make([]Result, 0, len(s.results))
+	for _, result := range s.results {
+		result.Spec = nil
+		list = append(list, result)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].File < list[j].File })
+	writeJSON(w, list)
+}
+
+func (s *Server) handleRoute(w http.ResponseWriter, r *http.Request) {
+	file := r.URL.Query().Get("file")
+	if file == "" {
+		http.Error(w, "missing file parameter", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	result, ok := s.results[filepath.Clean(file)]
+	s.mu.Unlock()
+	if !ok && !s.underRoot(file) {
+		// Only route files are documented; anything else would be sent to the model
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method == http.MethodPost || !ok {
+		result = s.Update(file)
+	}
+	writeJSON(w, result)
+}
+
+// underRoot reports whether file is inside the scanned directory
+func (s *Server) underRoot(file string) bool {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return false
+	}
+	root, err := filepath.Abs(s.root)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(root, abs)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan Result, 16)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case result := <-ch:
+			data, err := json.Marshal(result)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", result.Status, data)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
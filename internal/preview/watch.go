@@ -0,0 +1,100 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounce absorbs the burst of events editors emit for a single save
+const debounce = 200 * time.Millisecond
+
+// Watch calls onSave for files under dir accepted by match after they are
+// written, and onDelete when they are removed. It blocks until ctx is done.
+func Watch(ctx context.Context, dir string, match func(path string) bool, onSave, onDelete func(file string)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// fsnotify is not recursive, so watch every directory
+	if err := addTree(watcher, dir); err != nil {
+		return err
+	}
+
+	var mu sync.Mutex
+	timers := make(map[string]*time.Timer)
+	schedule := func(file string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if t, ok := timers[file]; ok {
+			t.Stop()
+		}
+		timers[file] = time.AfterFunc(debounce, func() {
+			mu.Lock()
+			delete(timers, file)
+			mu.Unlock()
+			onSave(file)
+		})
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watcher failed: %w", err)
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					addTree(watcher, event.Name)
+					continue
+				}
+			}
+			if !match(event.Name) {
+				continue
+			}
+
+			switch {
+			case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+				// Editors that save by rename recreate the file right after
+				if _, err := os.Stat(event.Name); err != nil {
+					onDelete(event.Name)
+				} else {
+					schedule(event.Name)
+				}
+			case event.Has(fsnotify.Write) || event.Has(fsnotify.Create):
+				schedule(event.Name)
+			}
+		}
+	}
+}
+
+func addTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if name := d.Name(); path != dir && (name == "node_modules" || name[0] == '.') {
+			return filepath.SkipDir
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
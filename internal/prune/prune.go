@@ -0,0 +1,307 @@
+package prune
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Options control how a spec is shrunk
+type Options struct {
+	MaxBytes       int  // Target size; zero disables the budget
+	OmitExamples   bool // Always drop examples
+	MaxDescription int  // Always cut descriptions to this many characters; zero keeps them
+}
+
+// Report describes what Fit did
+type Report struct {
+	Before int
+	After  int
+	Steps  []string // Reductions applied, in order
+}
+
+// Over reports whether the spec is still larger than the budget
+func (r Report) Over(opts Options) bool {
+	return opts.MaxBytes > 0 && r.After > opts.MaxBytes
+}
+
+// budgetDescription is how long descriptions may stay when the budget forces a cut
+const budgetDescription = 120
+
+// minShared is the smallest inline schema, in bytes, worth moving into components
+const minShared = 64
+
+// valueKeys hold instance values rather than schemas or spec objects, which
+// are left as written
+var valueKeys = map[string]bool{"example": true, "examples": true, "enum": true, "default": true, "const": true}
+
+// Fit applies the requested reductions, then, while the spec measures larger
+// than the budget, applies more in order of what is lost: sharing repeated
+// schemas through $ref (nothing), dropping examples, shortening descriptions
+func Fit(spec map[string]interface{}, opts Options, measure func(interface{}) (int, error)) (Report, error) {
+	var report Report
+	if opts.MaxDescription < 0 || opts.MaxDescription == 1 {
+		return report, fmt.Errorf("invalid description length %d (expected at least 2, or 0 to keep them)", opts.MaxDescription)
+	}
+	var err error
+	if report.Before, err = measure(spec); err != nil {
+		return report, err
+	}
+	report.After = report.Before
+
+	over := func() (bool, error) {
+		if opts.MaxBytes <= 0 {
+			return false, nil
+		}
+		size, err := measure(spec)
+		report.After = size
+		return err == nil && size > opts.MaxBytes, err
+	}
+
+	if opts.OmitExamples {
+		omitExamples(spec)
+		report.Steps = append(report.Steps, "examples omitted")
+	}
+	if opts.MaxDescription > 0 {
+		shortenDescriptions(spec, opts.MaxDescription)
+		report.Steps = append(report.Steps, fmt.Sprintf("descriptions cut to %d characters", opts.MaxDescription))
+	}
+
+	steps := []struct {
+		name  string
+		apply func()
+	}{
+		{"repeated schemas moved to components", func() { shareSchemas(spec) }},
+		{"examples omitted", func() { omitExamples(spec) }},
+		{"descriptions cut to their first sentence", func() { shortenDescriptions(spec, budgetDescription) }},
+	}
+	for _, step := range steps {
+		isOver, err := over()
+		if err != nil {
+			return report, err
+		}
+		if !isOver {
+			break
+		}
+		if !slices.Contains(report.Steps, step.name) {
+			step.apply()
+			report.Steps = append(report.Steps, step.name)
+		}
+	}
+
+	// Measure the final result
+	if report.After, err = measure(spec); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// ParseSize reads sizes such as "2MB", "500KB" or "1048576"
+func ParseSize(s string) (int, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := 1
+	for _, unit := range []struct {
+		suffix string
+		factor int
+	}{{"MB", 1 << 20}, {"KB", 1 << 10}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(value, unit.suffix) {
+			value, multiplier = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix)), unit.factor
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 2MB, 500KB)", s)
+	}
+	return int(n * float64(multiplier)), nil
+}
+
+// omitExamples removes example values and the components that hold them
+func omitExamples(spec map[string]interface{}) {
+	if components, ok := spec["components"].(map[string]interface{}); ok {
+		delete(components, "examples")
+	}
+	walk(spec, "", func(node map[string]interface{}, parent string) {
+		if parent == "properties" {
+			return // Keys here are field names, not keywords
+		}
+		delete(node, "example")
+		delete(node, "examples")
+	})
+}
+
+// shortenDescriptions keeps the first sentence of each description, cut to max characters
+func shortenDescriptions(spec map[string]interface{}, max int) {
+	walk(spec, "", func(node map[string]interface{}, parent string) {
+		if parent == "properties" {
+			return
+		}
+		description, ok := node["description"].(string)
+		if !ok {
+			return
+		}
+		if end := strings.Index(description, ". "); end >= 0 {
+			description = description[:end+1]
+		}
+		if runes := []rune(description); len(runes) > max {
+			description = strings.TrimSpace(string(runes[:max-1])) + "…"
+		}
+		node["description"] = description
+	})
+}
+
+// shareSchemas replaces inline object schemas that appear more than once, or
+// that equal an existing component, with a $ref to a component
+func shareSchemas(spec map[string]interface{}) {
+	components, _ := spec["components"].(map[string]interface{})
+	if components == nil {
+		components = make(map[string]interface{})
+	}
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if schemas == nil {
+		schemas = make(map[string]interface{})
+	}
+
+	named := make(map[string]string) // Canonical JSON -> component name
+	for _, name := range slices.Sorted(maps.Keys(schemas)) {
+		if key := canonical(schemas[name]); key != "" {
+			if _, ok := named[key]; !ok {
+				named[key] = name
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	walk(spec, "", func(node map[string]interface{}, parent string) {
+		if isObjectSchema(node) {
+			counts[canonical(node)]++
+		}
+	})
+
+	var replace func(value interface{}, parent string) interface{}
+	replace = func(value interface{}, parent string) interface{} {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if isObjectSchema(v) {
+				key := canonical(v)
+				name, ok := named[key]
+				if !ok && counts[key] > 1 && len(key) >= minShared {
+					name = uniqueName(schemas, v)
+					named[key] = name
+					schemas[name] = v
+					replaceChildren(v, parent, replace)
+				}
+				if name != "" {
+					return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+				}
+			}
+			replaceChildren(v, parent, replace)
+		case []interface{}:
+			for i := range v {
+				v[i] = replace(v[i], parent)
+			}
+		}
+		return value
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(spec)) {
+		if key != "components" {
+			spec[key] = replace(spec[key], key)
+		}
+	}
+	for _, section := range slices.Sorted(maps.Keys(components)) {
+		entries, ok := components[section].(map[string]interface{})
+		if !ok || isValue("components", section) {
+			continue
+		}
+		for _, name := range slices.Sorted(maps.Keys(entries)) {
+			if section == "schemas" {
+				// A component is never replaced by a reference to itself
+				if entry, ok := entries[name].(map[string]interface{}); ok {
+					replaceChildren(entry, name, replace)
+				}
+				continue
+			}
+			entries[name] = replace(entries[name], name)
+		}
+	}
+
+	if len(schemas) > 0 {
+		components["schemas"] = schemas
+		spec["components"] = components
+	}
+}
+
+func replaceChildren(node map[string]interface{}, parent string, replace func(interface{}, string) interface{}) {
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		if !isValue(parent, key) {
+			node[key] = replace(node[key], key)
+		}
+	}
+}
+
+// isObjectSchema reports whether a node is an inline object schema
+func isObjectSchema(node map[string]interface{}) bool {
+	_, hasProperties := node["properties"].(map[string]interface{})
+	return hasProperties && node["$ref"] == nil
+}
+
+// uniqueName names a shared schema after its title, or Shared1, Shared2, ...
+func uniqueName(schemas map[string]interface{}, schema map[string]interface{}) string {
+	base, _ := schema["title"].(string)
+	base = strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return -1
+	}, base)
+	if base != "" {
+		if _, taken := schemas[base]; !taken {
+			return base
+		}
+	} else {
+		base = "Shared"
+	}
+	for i := 1; ; i++ {
+		name := base + strconv.Itoa(i)
+		if _, taken := schemas[name]; !taken {
+			return name
+		}
+	}
+}
+
+func canonical(value interface{}) string {
+	data, err := json.Marshal(value) // Map keys are sorted, so equal schemas encode equally
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// walk visits every map in a spec with the key it is stored under, without
+// entering example, enum or default values
+func walk(value interface{}, parent string, visit func(node map[string]interface{}, parent string)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		visit(v, parent)
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if !isValue(parent, key) {
+				walk(v[key], key, visit)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			walk(child, parent, visit)
+		}
+	}
+}
+
+// isValue reports whether key, stored in a map under parent, holds an instance
+// value; keys of properties are field names
+func isValue(parent, key string) bool {
+	return parent != "properties" && valueKeys[key]
+}
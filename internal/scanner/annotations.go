@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// // @openapi tag:billing externalId:BILL-123 team:"Payments core"
+	openapiAnnotation = regexp.MustCompile(`(?m)(?://|/\*|^\s*\*)[^\n]*?@openapi\b([^\n]*)`)
+	annotationPair    = regexp.MustCompile(`([A-Za-z][\w.-]*):("[^"]*"|[^\s"]+)`)
+)
+
+// DetectAnnotations reads `@openapi key:value` comments. A comment inside a
+// handler, or in the comment block right above it, applies to that method;
+// anywhere else it applies to every method of the route.
+func DetectAnnotations(content string, handlers []models.Handler) []models.Annotation {
+	lines := strings.Split(content, "\n")
+
+	var annotations []models.Annotation
+	for _, loc := range openapiAnnotation.FindAllStringSubmatchIndex(content, -1) {
+		method := annotatedHandler(lines, lineOf(content, loc[0]), handlers)
+		text := strings.TrimSuffix(strings.TrimSpace(content[loc[2]:loc[3]]), "*/")
+		for _, m := range annotationPair.FindAllStringSubmatch(text, -1) {
+			annotations = append(annotations, models.Annotation{
+				Method: method,
+				Key:    m[1],
+				Value:  strings.Trim(m[2], `"`),
+			})
+		}
+	}
+	return annotations
+}
+
+// annotatedHandler returns the method whose handler contains line or starts
+// right after the comment block holding it
+func annotatedHandler(lines []string, line int, handlers []models.Handler) string {
+	for _, h := range handlers {
+		if line >= h.StartLine && line <= h.EndLine {
+			return h.Method
+		}
+	}
+	for _, h := range handlers {
+		if h.StartLine <= line {
+			continue
+		}
+		between := true
+		for l := line + 1; l < h.StartLine; l++ {
+			if !isCommentLine(lines[l-1]) {
+				between = false
+				break
+			}
+		}
+		if between {
+			return h.Method
+		}
+	}
+	return ""
+}
+
+func isCommentLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*")
+}
@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// ArtifactVersion is bumped whenever analyzed routes change shape, so a
+// generate job never documents routes scanned by an incompatible build
+const ArtifactVersion = 2
+
+// DefaultArtifact is the file scan writes when --out is not given
+const DefaultArtifact = "routes.json"
+
+// Artifact is the output of the scan phase: every route with its source and
+// everything the detectors found, ready to be documented on another machine
+type Artifact struct {
+	Version int               `json:"version"`
+	Routes  []models.APIRoute `json:"routes"`
+}
+
+// SaveArtifact writes analyzed routes for a later generate --from
+func SaveArtifact(path string, routes []models.APIRoute) error {
+	data, err := json.MarshalIndent(Artifact{Version: ArtifactVersion, Routes: routes}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode routes: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write routes file: %w", err)
+	}
+	return nil
+}
+
+// LoadArtifact reads routes written by SaveArtifact
+func LoadArtifact(path string) ([]models.APIRoute, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file: %w", err)
+	}
+	var artifact Artifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("failed to parse routes file %s: %w", path, err)
+	}
+	if artifact.Version != ArtifactVersion {
+		return nil, fmt.Errorf("routes file %s has version %d, this build reads version %d; scan again with the same build", path, artifact.Version, ArtifactVersion)
+	}
+	return artifact.Routes, nil
+}
@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Authorization checks that name a role or scope as a string literal, e.g.
+// `session.user.roles.includes('admin')`, `user.role === "editor"`, `hasScope('billing:write')`
+var rolePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:roles|scopes|permissions|groups)\??\.includes\(\s*['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]\s*\)`),
+	regexp.MustCompile(`\b(?:role|scope)\s*[!=]==?\s*['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`),
+	regexp.MustCompile(`['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]\s*[!=]==?\s*(?:[\w?.]+\.)?(?:role|scope)\b`),
+	regexp.MustCompile(`\b(?:hasRole|hasScope|hasPermission|requireRole|requireScope|checkRole)\(\s*(?:[\w.?]+\s*,\s*)?['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`),
+}
+
+// authPattern matches calls that require a signed-in user: wrappers such as
+// withAuth(...) and withApiAuthRequired(...), NextAuth's getServerSession(),
+// getToken() and auth(), Clerk's auth() and currentUser(), and Supabase's auth.getUser()
+var authPattern = regexp.MustCompile(`\b(?:with(?:Api)?Auth\w*|require(?:Auth|User|Session)|getServerSession|getToken|currentUser)\s*\(|(?:^|[^.\w])auth\s*\(\s*\)|\.auth\.getUser\s*\(`)
+
+var (
+	// function requireUser(, async function* getToken(
+	functionKeyword = regexp.MustCompile(`\bfunction\s*\*?\s*$`)
+	// The parameter list of a method definition is followed by its body:
+	// requireUser(req) {, async getToken(req): Promise<string> {
+	methodBody = regexp.MustCompile(`^\s*(?::[^;{}()=]+)?\{`)
+)
+
+// DetectAuthentication returns the methods whose handlers require a signed-in
+// user, or "*" when the check is outside any handler, e.g. in a shared helper
+// or around a pages router default export
+func DetectAuthentication(content string, handlers []models.Handler) []string {
+	var methods []string
+	for _, loc := range authPattern.FindAllStringIndex(content, -1) {
+		if isDeclaration(content, loc) {
+			// Defining a helper does not protect anything; calling it does
+			continue
+		}
+		line := lineOf(content, loc[0])
+		found := false
+		for _, h := range handlers {
+			if line >= h.StartLine && line <= h.EndLine {
+				found = true
+				if !contains(methods, h.Method) {
+					methods = append(methods, h.Method)
+				}
+			}
+		}
+		if !found && !contains(methods, "*") {
+			methods = append(methods, "*")
+		}
+	}
+	return methods
+}
+
+// isDeclaration reports whether an authPattern match declares the function
+// rather than calls it
+func isDeclaration(content string, loc []int) bool {
+	start := loc[0]
+	if c := content[start]; c != '_' && c != '$' && !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+		start++ // The bare auth() match starts with the character before it
+	}
+	if functionKeyword.MatchString(content[:start]) {
+		return true
+	}
+	open := strings.LastIndexByte(content[:loc[1]], '(')
+	if open < 0 {
+		return false
+	}
+	end := open + closingBracket(content[open:])
+	return end > open && end+1 < len(content) && methodBody.MatchString(content[end+1:])
+}
+
+// DetectRoles returns the roles/scopes each handler checks for, sorted and
+// deduplicated, under "*" for checks outside any handler
+func DetectRoles(content string, handlers []models.Handler) map[string][]string {
+	roles := make(map[string][]string)
+	for _, pattern := range rolePatterns {
+		for _, m := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			role := content[m[2]:m[3]]
+			line := lineOf(content, m[0])
+			found := false
+			for _, h := range handlers {
+				if line >= h.StartLine && line <= h.EndLine {
+					found = true
+					if !contains(roles[h.Method], role) {
+						roles[h.Method] = append(roles[h.Method], role)
+					}
+				}
+			}
+			if !found && !contains(roles["*"], role) {
+				roles["*"] = append(roles["*"], role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	for _, list := range roles {
+		sort.Strings(list)
+	}
+	return roles
+}
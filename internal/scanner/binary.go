@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	contentTypePattern = regexp.MustCompile(`(?i)['"]Content-Type['"]\s*[:,]\s*['"]([^'"]+)['"]`)
+	binaryMediaType    = regexp.MustCompile(`(?i)^(?:(?:image|audio|video|font)/[\w.+-]+|application/(?:pdf|zip|gzip|octet-stream|vnd\.[\w.+-]+|x-[\w.+-]+)|text/csv)$`)
+	// Media types the pattern above would take for files: form posts, JSON lines
+	// and JSON vendor types
+	textMediaType = regexp.MustCompile(`(?i)^application/(?:x-www-form-urlencoded|x-ndjson|[\w.+-]+\+json)$`)
+	// A stream or buffer returned as the body: return new Response(fileStream),
+	// return new NextResponse(await blob.arrayBuffer()), createReadStream(path).pipe(res)
+	binaryBodyPattern = regexp.MustCompile(`return\s+new\s+(?:Next)?Response\(\s*(?:await\s+)?(?:createReadStream\(|Buffer\.from\(|[\w.]*(?:[Bb]uffer|[Ss]tream|[Bb]lob|[Ff]ile)\b|\w+\.arrayBuffer\(\))|createReadStream\([^)]*\)\s*\.pipe\(\s*res\s*\)`)
+)
+
+// DetectBinaryResponse reports whether a handler returns file or binary content,
+// returning the declared media type or application/octet-stream when unknown
+func DetectBinaryResponse(content string) string {
+	textual := false
+	for _, m := range contentTypePattern.FindAllStringSubmatch(content, -1) {
+		mediaType := strings.ToLower(strings.TrimSpace(strings.SplitN(m[1], ";", 2)[0]))
+		if binaryMediaType.MatchString(mediaType) && !textMediaType.MatchString(mediaType) {
+			return mediaType
+		}
+		textual = true // JSON, server-sent events, text
+	}
+	if strings.Contains(content, "Content-Disposition") {
+		return "application/octet-stream"
+	}
+	// Returned streams only count when no other content type is declared and the
+	// handler never responds with JSON
+	if !textual && binaryBodyPattern.MatchString(content) && !strings.Contains(content, "Response.json(") {
+		return "application/octet-stream"
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+package scanner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// limit: z.coerce.number().int().min(1).max(100).default(20)
+	zodFieldPattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)['"]?\s*:\s*z\.(?:coerce\.)?(number|string|bigint|enum)\(((?:[^()]|\([^()]*\))*)\)((?:\s*\.\w+\((?:[^()]|\([^()]*\))*\))*)`)
+	zodCallPattern  = regexp.MustCompile(`\.(\w+)\(((?:[^()]|\([^()]*\))*)\)`)
+	// const pageSize = Number(searchParams.get('limit') ?? 20)
+	searchParamAlias = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*[^;\n]*?searchParams\.get\(\s*['"]([\w-]+)['"]\s*\)`)
+	ifPattern        = regexp.MustCompile(`\bif\s*\(([^{;]*)\)`)
+	comparePattern   = regexp.MustCompile(`([A-Za-z_$][\w$]*)(\.length)?\s*(>=|<=|>|<)\s*(-?\d+(?:\.\d+)?)`)
+	reversePattern   = regexp.MustCompile(`(-?\d+(?:\.\d+)?)\s*(>=|<=|>|<)\s*([A-Za-z_$][\w$]*)(\.length)?`)
+	clampMinPattern  = regexp.MustCompile(`Math\.min\(\s*([A-Za-z_$][\w$]*)\s*,\s*(-?\d+(?:\.\d+)?)\s*\)|Math\.min\(\s*(-?\d+(?:\.\d+)?)\s*,\s*([A-Za-z_$][\w$]*)\s*\)`)
+	clampMaxPattern  = regexp.MustCompile(`Math\.max\(\s*([A-Za-z_$][\w$]*)\s*,\s*(-?\d+(?:\.\d+)?)\s*\)|Math\.max\(\s*(-?\d+(?:\.\d+)?)\s*,\s*([A-Za-z_$][\w$]*)\s*\)`)
+	enumValuePattern = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// DetectConstraints finds min/max, length, pattern, default and enum rules for
+// parameters and fields from Zod validators, guard clauses such as
+// `if (limit > 100)` and clamps
+func DetectConstraints(content string) map[string]models.Constraint {
+	constraints := make(map[string]models.Constraint)
+	update := func(name string, apply func(c *models.Constraint)) {
+		c := constraints[name]
+		apply(&c)
+		constraints[name] = c
+	}
+
+	for _, m := range zodFieldPattern.FindAllStringSubmatch(content, -1) {
+		name, kind, args, chain := m[1], m[2], m[3], m[4]
+		update(name, func(c *models.Constraint) { applyZod(c, kind, args, chain) })
+	}
+
+	// Checks on a variable read from the query string apply to the parameter
+	aliases := make(map[string]string)
+	for _, m := range searchParamAlias.FindAllStringSubmatch(content, -1) {
+		aliases[m[1]] = m[2]
+	}
+	resolve := func(name string) string {
+		if param, ok := aliases[name]; ok {
+			return param
+		}
+		return name
+	}
+
+	// Guard clauses reject values outside the allowed range
+	for _, cond := range ifPattern.FindAllStringSubmatch(content, -1) {
+		for _, m := range comparePattern.FindAllStringSubmatch(cond[1], -1) {
+			value, _ := strconv.ParseFloat(m[4], 64)
+			update(resolve(m[1]), func(c *models.Constraint) { applyGuard(c, m[3], value, m[2] != "") })
+		}
+		for _, m := range reversePattern.FindAllStringSubmatch(cond[1], -1) {
+			value, _ := strconv.ParseFloat(m[1], 64)
+			update(resolve(m[3]), func(c *models.Constraint) { applyGuard(c, flip(m[2]), value, m[4] != "") })
+		}
+	}
+
+	// Math.min(limit, 100) caps a value; Math.max(page, 1) floors it
+	for _, m := range clampMinPattern.FindAllStringSubmatch(content, -1) {
+		name, value := firstNonEmpty(m[1], m[4]), firstNonEmpty(m[2], m[3])
+		v, _ := strconv.ParseFloat(value, 64)
+		update(resolve(name), func(c *models.Constraint) { c.Maximum = &v })
+	}
+	for _, m := range clampMaxPattern.FindAllStringSubmatch(content, -1) {
+		name, value := firstNonEmpty(m[1], m[4]), firstNonEmpty(m[2], m[3])
+		v, _ := strconv.ParseFloat(value, 64)
+		update(resolve(name), func(c *models.Constraint) { c.Minimum = &v })
+	}
+
+	for name, c := range constraints {
+		if isEmpty(c) {
+			delete(constraints, name)
+		}
+	}
+	return constraints
+}
+
+// applyZod reads a Zod chain; min/max bound numbers but limit string length
+func applyZod(c *models.Constraint, kind, args, chain string) {
+	if kind == "enum" {
+		for _, v := range enumValuePattern.FindAllStringSubmatch(args, -1) {
+			c.Enum = append(c.Enum, v[1])
+		}
+	}
+
+	for _, call := range zodCallPattern.FindAllStringSubmatch(chain, -1) {
+		method, arg := call[1], strings.TrimSpace(call[2])
+		if i := strings.Index(arg, ","); i >= 0 && method != "default" {
+			arg = strings.TrimSpace(arg[:i]) // Drop custom error messages
+		}
+		n, numErr := strconv.ParseFloat(arg, 64)
+		isString := kind == "string"
+
+		switch {
+		case method == "int":
+			c.Integer = true
+		case method == "positive":
+			zero := 0.0
+			c.Minimum, c.ExclusiveMinimum = &zero, true
+		case method == "nonnegative":
+			zero := 0.0
+			c.Minimum = &zero
+		case method == "regex":
+			c.Pattern = regexLiteral(arg)
+		case method == "default":
+			if strings.HasPrefix(arg, "'") || strings.HasPrefix(arg, `"`) {
+				c.Default = strings.Trim(arg, `'"`)
+			} else {
+				c.Default = literal("", "", arg)
+			}
+		case numErr != nil:
+		case isString && (method == "min" || method == "nonempty"):
+			length := int(n)
+			c.MinLength = &length
+		case isString && method == "max":
+			length := int(n)
+			c.MaxLength = &length
+		case isString && method == "length":
+			length := int(n)
+			c.MinLength, c.MaxLength = &length, &length
+		case method == "min" || method == "gte":
+			c.Minimum = &n
+		case method == "max" || method == "lte":
+			c.Maximum = &n
+		case method == "gt":
+			c.Minimum, c.ExclusiveMinimum = &n, true
+		case method == "lt":
+			c.Maximum, c.ExclusiveMaximum = &n, true
+		}
+	}
+}
+
+// applyGuard turns a rejected condition into the allowed range: rejecting
+// `x > 100` means maximum 100, rejecting `x >= 100` means exclusive maximum 100
+func applyGuard(c *models.Constraint, op string, value float64, length bool) {
+	if length {
+		n := int(value)
+		switch op {
+		case ">":
+			c.MaxLength = &n
+		case ">=":
+			n--
+			c.MaxLength = &n
+		case "<":
+			c.MinLength = &n
+		case "<=":
+			n++
+			c.MinLength = &n
+		}
+		return
+	}
+
+	switch op {
+	case ">":
+		c.Maximum = &value
+	case ">=":
+		c.Maximum, c.ExclusiveMaximum = &value, true
+	case "<":
+		c.Minimum = &value
+	case "<=":
+		c.Minimum, c.ExclusiveMinimum = &value, true
+	}
+}
+
+func flip(op string) string {
+	return map[string]string{">": "<", "<": ">", ">=": "<=", "<=": ">="}[op]
+}
+
+// literal converts a captured JS literal: quoted strings, numbers or booleans
+func literal(single, double, bare string) interface{} {
+	switch {
+	case single != "":
+		return single
+	case double != "":
+		return double
+	case bare == "true" || bare == "false":
+		return bare == "true"
+	}
+	if n, err := strconv.ParseFloat(bare, 64); err == nil {
+		return n
+	}
+	return nil
+}
+
+// regexLiteral strips the slashes and flags of /.../i
+func regexLiteral(s string) string {
+	if strings.HasPrefix(s, "/") {
+		if end := strings.LastIndex(s, "/"); end > 0 {
+			return s[1:end]
+		}
+	}
+	return strings.Trim(s, `'"`)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func isEmpty(c models.Constraint) bool {
+	return c.Minimum == nil && c.Maximum == nil && c.MinLength == nil && c.MaxLength == nil &&
+		c.Pattern == "" && c.Default == nil && len(c.Enum) == 0 && !c.Integer
+}
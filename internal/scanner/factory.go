@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// export const { GET, POST } = createCrudHandlers(prisma.user)
+	factoryExportPattern = regexp.MustCompile(`export\s+const\s*\{([^}]*)\}\s*=\s*([A-Za-z_$][\w$.]*)\s*(?:<[^>]*>)?\s*\(([^)]*)\)`)
+	// const handlers = createCrudHandlers(prisma.user)
+	factoryAliasPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*([A-Za-z_$][\w$.]*)\s*(?:<[^>]*>)?\s*\(([^)]*)\)`)
+	// export const { GET, POST } = handlers, export const GET = handlers.GET
+	aliasExportPattern  = regexp.MustCompile(`export\s+const\s*\{([^}]*)\}\s*=\s*([A-Za-z_$][\w$]*)\s*;?\s*(?:\n|$)`)
+	memberExportPattern = regexp.MustCompile(`export\s+const\s+(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s*=\s*([A-Za-z_$][\w$]*)\.(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\b`)
+)
+
+// DetectFactory finds handlers created by a factory call, such as
+// `export const { GET, POST } = createCrudHandlers(prisma.user)`, directly or
+// through a variable holding the factory's result
+func DetectFactory(content string) *models.FactoryCall {
+	for _, m := range factoryExportPattern.FindAllStringSubmatchIndex(content, -1) {
+		if methods := exportedMethods(content[m[2]:m[3]]); len(methods) > 0 {
+			return newFactoryCall(content, content[m[4]:m[5]], content[m[6]:m[7]], methods, m[0])
+		}
+	}
+
+	calls := make(map[string][]int)
+	for _, m := range factoryAliasPattern.FindAllStringSubmatchIndex(content, -1) {
+		calls[content[m[2]:m[3]]] = m
+	}
+	var call *models.FactoryCall
+	add := func(alias string, methods []string, offset int) {
+		m, ok := calls[alias]
+		if !ok || len(methods) == 0 {
+			return
+		}
+		if call == nil {
+			call = newFactoryCall(content, content[m[4]:m[5]], content[m[6]:m[7]], nil, offset)
+		}
+		for _, method := range methods {
+			if !contains(call.Methods, method) {
+				call.Methods = append(call.Methods, method)
+			}
+		}
+	}
+	for _, m := range aliasExportPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(content[m[4]:m[5]], exportedMethods(content[m[2]:m[3]]), m[0])
+	}
+	for _, m := range memberExportPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(content[m[4]:m[5]], []string{content[m[2]:m[3]]}, m[0])
+	}
+	return call
+}
+
+// exportedMethods picks the HTTP methods out of a destructuring pattern
+func exportedMethods(pattern string) []string {
+	var methods []string
+	for _, binding := range strings.Split(pattern, ",") {
+		// { list: GET, create: POST } exports the names after the colon
+		name := binding
+		if _, renamed, ok := strings.Cut(binding, ":"); ok {
+			name = renamed
+		}
+		switch name = strings.TrimSpace(name); name {
+		case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+			methods = append(methods, name)
+		}
+	}
+	return methods
+}
+
+func newFactoryCall(content, name, args string, methods []string, offset int) *models.FactoryCall {
+	call := &models.FactoryCall{Name: name, Methods: methods, Line: lineOf(content, offset)}
+	if first := strings.TrimSpace(strings.Split(args, ",")[0]); first != "" {
+		call.Argument = strings.Trim(first, `'"`+"`")
+	}
+	return call
+}
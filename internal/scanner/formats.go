@@ -0,0 +1,86 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// email: z.string().email(), id: z.string().min(1).uuid()
+	zodFormatPattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)['"]?\s*:\s*z\.(?:coerce\.)?string\(\)(?:\.\w+\([^()]*\))*?\.(uuid|email|url|datetime|date|time|ip|cuid|cuid2|ulid)\(`)
+	zodDatePattern   = regexp.MustCompile(`([A-Za-z_$][\w$]*)['"]?\s*:\s*z\.(?:coerce\.)?date\(\)`)
+	// type UserId = string & { __brand: 'UUID' } or Brand<string, 'Email'>
+	brandIntersectionPattern = regexp.MustCompile(`\btype\s+([A-Za-z_$][\w$]*)\s*=\s*string\s*&\s*\{[^}]*['"]([\w-]+)['"]`)
+	brandGenericPattern      = regexp.MustCompile(`\btype\s+([A-Za-z_$][\w$]*)\s*=\s*(?:Brand|Branded|Tagged)<\s*string\s*,\s*['"]([\w-]+)['"]\s*>`)
+)
+
+var zodFormats = map[string]string{
+	"uuid":     "uuid",
+	"email":    "email",
+	"url":      "uri",
+	"datetime": "date-time",
+	"date":     "date",
+	"time":     "time",
+	"ip":       "ipv4",
+	"cuid":     "cuid",
+	"cuid2":    "cuid2",
+	"ulid":     "ulid",
+}
+
+// DetectFormats maps field names to string formats declared by Zod validators,
+// branded TypeScript types, and Date-typed fields
+func DetectFormats(content string, types []models.TypeDecl) map[string]string {
+	formats := make(map[string]string)
+
+	for _, m := range zodFormatPattern.FindAllStringSubmatch(content, -1) {
+		formats[m[1]] = zodFormats[m[2]]
+	}
+	for _, m := range zodDatePattern.FindAllStringSubmatch(content, -1) {
+		formats[m[1]] = "date-time"
+	}
+
+	brands := make(map[string]string)
+	for _, pattern := range []*regexp.Regexp{brandIntersectionPattern, brandGenericPattern} {
+		for _, m := range pattern.FindAllStringSubmatch(content, -1) {
+			if format := brandFormat(m[2]); format != "" {
+				brands[m[1]] = format
+			}
+		}
+	}
+
+	for _, decl := range types {
+		for _, field := range decl.Fields {
+			fieldType := strings.TrimSpace(strings.Split(field.Type, "|")[0])
+			if format, ok := brands[fieldType]; ok {
+				formats[field.Name] = format
+			} else if fieldType == "Date" {
+				formats[field.Name] = "date-time"
+			}
+		}
+	}
+
+	if len(formats) == 0 {
+		return nil
+	}
+	return formats
+}
+
+// brandFormat maps a brand label like "UUID" or "EmailAddress" to a format
+func brandFormat(brand string) string {
+	brand = strings.ToLower(brand)
+	switch {
+	case strings.Contains(brand, "uuid"):
+		return "uuid"
+	case strings.Contains(brand, "email"):
+		return "email"
+	case strings.Contains(brand, "url") || strings.Contains(brand, "uri"):
+		return "uri"
+	case strings.Contains(brand, "datetime") || strings.Contains(brand, "timestamp") || strings.Contains(brand, "iso"):
+		return "date-time"
+	case strings.Contains(brand, "date"):
+		return "date"
+	}
+	return ""
+}
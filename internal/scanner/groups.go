@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// groupFiles are read, in order, for a folder's overview
+var groupFiles = []string{"README.md", "readme.md", "Readme.md"}
+
+// indexFiles may open with a /** doc comment */ describing their folder
+var indexFiles = []string{"index.ts", "index.js", "index.tsx", "index.jsx"}
+
+var (
+	leadingComment = regexp.MustCompile(`^\s*/\*\*[\s\S]*?\*/`)
+	topHeading     = regexp.MustCompile(`^#\s+[^\n]*\n*`)
+)
+
+// group finds the folder closest to dir, below the scanned directory, that
+// documents itself with a README.md or an index file comment
+func (s *Scanner) group(dir string) *models.RouteGroup {
+	root := filepath.Clean(s.rootDir)
+	var visited []string
+	var found *models.RouteGroup
+	for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+		if g, ok := s.groups[dir]; ok {
+			found = g
+			break
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			break
+		}
+		visited = append(visited, dir)
+		if found = readGroup(dir); found != nil {
+			break
+		}
+	}
+	for _, d := range visited {
+		s.groups[d] = found
+	}
+	return found
+}
+
+// readGroup reads a folder's own overview, if it has one
+func readGroup(dir string) *models.RouteGroup {
+	name := strings.Trim(filepath.Base(dir), "()")
+	for _, file := range groupFiles {
+		path := filepath.Join(dir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		text := strings.TrimSpace(topHeading.ReplaceAllString(strings.TrimSpace(string(data)), ""))
+		if text != "" {
+			return &models.RouteGroup{Name: name, Description: text, Source: path}
+		}
+	}
+	for _, file := range indexFiles {
+		path := filepath.Join(dir, file)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if text := commentText(leadingComment.FindString(string(data))); text != "" {
+			return &models.RouteGroup{Name: name, Description: text, Source: path}
+		}
+	}
+	return nil
+}
+
+// commentText strips doc comment markers, keeping the lines and blank lines
+// between them
+func commentText(comment string) string {
+	comment = strings.TrimSpace(comment)
+	comment = strings.TrimPrefix(comment, "/**")
+	comment = strings.TrimSuffix(comment, "*/")
+
+	var lines []string
+	for _, line := range strings.Split(comment, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "*")
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "@") {
+			continue // JSDoc tags such as @module
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,82 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	handlerFunctionPattern = regexp.MustCompile(`export\s+(?:async\s+)?function\s+(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s*\(`)
+	handlerConstPattern    = regexp.MustCompile(`export\s+const\s+(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s*(?::[^=]+)?=`)
+	arrowStartPattern      = regexp.MustCompile(`^(?:async\s*)?(?:\([^)]*\)|[A-Za-z_$][\w$]*)\s*(?::[^=]+)?=>`)
+)
+
+// DetectHandlers locates each exported HTTP method handler and the lines it spans
+func DetectHandlers(content string) []models.Handler {
+	var handlers []models.Handler
+
+	for _, loc := range handlerFunctionPattern.FindAllStringSubmatchIndex(content, -1) {
+		// Skip the parameter list, which may itself contain braces
+		params := loc[1] - 1
+		afterParams := params + closingBracket(content[params:]) + 1
+		if afterParams >= len(content) {
+			continue
+		}
+		open := strings.IndexByte(content[afterParams:], '{')
+		if open < 0 {
+			continue
+		}
+		open += afterParams
+		end := open + closingBracket(content[open:])
+		handlers = append(handlers, newHandler(content, content[loc[2]:loc[3]], loc[0], end))
+	}
+
+	for _, loc := range handlerConstPattern.FindAllStringSubmatchIndex(content, -1) {
+		rest := content[loc[1]:]
+		trimmed := strings.TrimLeft(rest, " \t\r\n")
+		start := loc[1] + len(rest) - len(trimmed)
+
+		var end int
+		if m := arrowStartPattern.FindStringIndex(trimmed); m != nil {
+			// Arrow function: a block body or a single expression
+			body := start + m[1]
+			body += len(content[body:]) - len(strings.TrimLeft(content[body:], " \t\r\n"))
+			if body < len(content) && content[body] == '{' {
+				end = body + closingBracket(content[body:])
+			} else {
+				end = lineEnd(content, body)
+			}
+		} else if open := strings.IndexAny(trimmed, "({"); open >= 0 && !strings.ContainsRune(trimmed[:open], '\n') {
+			// Wrapped handler, e.g. withAuth(async (req) => { ... })
+			end = start + open + closingBracket(trimmed[open:])
+		} else {
+			end = lineEnd(content, start)
+		}
+		handlers = append(handlers, newHandler(content, content[loc[2]:loc[3]], loc[0], end))
+	}
+
+	sort.Slice(handlers, func(i, j int) bool { return handlers[i].StartLine < handlers[j].StartLine })
+	return handlers
+}
+
+func newHandler(content, method string, start, end int) models.Handler {
+	if end >= len(content) {
+		end = len(content) - 1
+	}
+	return models.Handler{Method: method, StartLine: lineOf(content, start), EndLine: lineOf(content, end)}
+}
+
+// lineOf returns the 1-based line number of a byte offset
+func lineOf(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func lineEnd(content string, offset int) int {
+	if i := strings.IndexByte(content[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(content)
+}
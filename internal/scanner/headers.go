@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	headerSetPattern    = regexp.MustCompile(`headers\??\.(?:set|append)\(\s*['"` + "`" + `]([A-Za-z0-9-]+)['"` + "`" + `]`)
+	// new NextResponse(body, init), NextResponse.json(data, init), Response.redirect(url, init)
+	responseInitPattern = regexp.MustCompile(`\bnew\s+(?:Next)?Response\s*\(|\b(?:NextResponse|Response)\s*\.\s*(?:json|redirect)\s*\(`)
+	headersEntry        = regexp.MustCompile(`^headers\s*(?::\s*([\s\S]+))?$`)
+	headersValue        = regexp.MustCompile(`^(?:new\s+Headers\(\s*)?\{`)
+	headerKeyPattern    = regexp.MustCompile(`(?:^|[{,\s])['"]?([A-Za-z][A-Za-z0-9-]*)['"]?\s*:`)
+	redirectPattern     = regexp.MustCompile(`(?:NextResponse|Response)\.redirect\(`)
+)
+
+// DetectResponseHeaders returns the response headers each handler sets, in
+// canonical case, by method ("*" for headers set outside any handler)
+func DetectResponseHeaders(content string, handlers []models.Handler) map[string][]string {
+	headers := make(map[string][]string)
+	add := func(name string, offset int) {
+		name = canonicalHeader(name)
+		for _, method := range handlersAt(content, offset, handlers) {
+			if !contains(headers[method], name) {
+				headers[method] = append(headers[method], name)
+			}
+		}
+	}
+
+	for _, m := range headerSetPattern.FindAllStringSubmatchIndex(content, -1) {
+		add(content[m[2]:m[3]], m[0])
+	}
+
+	// NextResponse.json(data, { headers: { 'X-Request-Id': id, Location: url } }).
+	// Only response init objects count: the headers of outbound fetch() calls
+	// are request headers of another API.
+	for _, object := range responseHeaderObjects(content) {
+		for _, m := range headerKeyPattern.FindAllStringSubmatch(object.body, -1) {
+			// Header names are either quoted or capitalized identifiers like Location
+			if strings.Contains(m[1], "-") || m[1][0] >= 'A' && m[1][0] <= 'Z' || strings.ContainsAny(m[0], `'"`) {
+				add(m[1], object.offset)
+			}
+		}
+	}
+
+	for _, loc := range redirectPattern.FindAllStringIndex(content, -1) {
+		add("Location", loc[0])
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+	for _, list := range headers {
+		sort.Strings(list)
+	}
+	return headers
+}
+
+// headerObject is the body of a headers object and where the response using it is built
+type headerObject struct {
+	body   string
+	offset int
+}
+
+// responseHeaderObjects returns the bodies of the headers objects passed in
+// response init objects, following a headers variable to its declaration
+func responseHeaderObjects(content string) []headerObject {
+	var objects []headerObject
+	for _, loc := range responseInitPattern.FindAllStringIndex(content, -1) {
+		open := loc[1] - 1
+		args := splitArgs(content[open+1 : open+closingBracket(content[open:])])
+		if len(args) < 2 {
+			continue
+		}
+		init := strings.TrimSpace(args[1])
+		if !strings.HasPrefix(init, "{") {
+			continue
+		}
+		for _, entry := range splitArgs(init[1:closingBracket(init)]) {
+			m := headersEntry.FindStringSubmatch(strings.TrimSpace(entry))
+			if m == nil {
+				continue
+			}
+			value := strings.TrimSpace(m[1])
+			if value == "" {
+				value = "headers" // { headers } shorthand
+			}
+			if name := zodNamePattern.FindString(value); name == value {
+				value = declaredObject(content, name)
+			}
+			if headersValue.MatchString(value) {
+				brace := strings.IndexByte(value, '{')
+				objects = append(objects, headerObject{body: value[brace+1 : brace+closingBracket(value[brace:])], offset: loc[0]})
+			}
+		}
+	}
+	return objects
+}
+
+// declaredObject returns the object literal a variable is declared as, or ""
+func declaredObject(content, name string) string {
+	pattern := regexp.MustCompile(`(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\s*(?::[^=;\n]+)?=\s*((?:new\s+Headers\(\s*)?\{)`)
+	loc := pattern.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return ""
+	}
+	brace := loc[3] - 1
+	return content[loc[2] : brace+closingBracket(content[brace:])+1]
+}
+
+// canonicalHeader converts x-request-id to X-Request-Id
+func canonicalHeader(name string) string {
+	parts := strings.Split(strings.ToLower(name), "-")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectResponseHeadersPerHandler(t *testing.T) {
+	content := `import { NextResponse } from 'next/server'
+
+export async function GET() {
+  const users = await db.user.findMany()
+  return NextResponse.json(users)
+}
+
+export async function POST(req: Request) {
+  const user = await db.user.create({ data: await req.json() })
+  return NextResponse.json(user, { status: 201, headers: { Location: '/api/users/' + user.id } })
+}
+`
+	got := DetectResponseHeaders(content, DetectHandlers(content))
+	want := map[string][]string{"POST": {"Location"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectResponseHeaders() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectResponseHeadersOutsideHandlers(t *testing.T) {
+	content := `const headers = { 'X-Request-Id': crypto.randomUUID() }
+const respond = (data) => NextResponse.json(data, { headers })
+`
+	got := DetectResponseHeaders(content, nil)
+	want := map[string][]string{"*": {"X-Request-Id"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectResponseHeaders() = %v, want %v", got, want)
+	}
+}
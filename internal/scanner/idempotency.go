@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// headers.get('Idempotency-Key'), req.headers['idempotency-key'], ...
+var idempotencyKeyPattern = regexp.MustCompile(`(?i)headers(?:\??\.get\(\s*|\[\s*)['"` + "`" + `]idempotency-key['"` + "`" + `]`)
+
+// DetectIdempotencyKey returns the methods whose handlers read an
+// Idempotency-Key header. When no handler can be located, "*" stands for all.
+func DetectIdempotencyKey(content string, handlers []models.Handler) []string {
+	var methods []string
+	for _, loc := range idempotencyKeyPattern.FindAllStringIndex(content, -1) {
+		line := lineOf(content, loc[0])
+		found := false
+		for _, h := range handlers {
+			if line >= h.StartLine && line <= h.EndLine {
+				found = true
+				if !contains(methods, h.Method) {
+					methods = append(methods, h.Method)
+				}
+			}
+		}
+		// Read in a shared helper: every handler may use it
+		if !found && !contains(methods, "*") {
+			methods = append(methods, "*")
+		}
+	}
+	return methods
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// An `@internal` tag in a line or JSDoc comment
+var internalAnnotation = regexp.MustCompile(`(?m)(?://|/\*|^\s*\*)[^\n]*?@internal\b`)
+
+// IsInternal reports whether a route is marked internal, either with an
+// `@internal` comment or by living under an `(internal)` route group
+func IsInternal(path, content string) bool {
+	for _, segment := range strings.Split(filepath.ToSlash(filepath.Dir(path)), "/") {
+		if segment == "(internal)" {
+			return true
+		}
+	}
+	return internalAnnotation.MatchString(content)
+}
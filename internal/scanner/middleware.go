@@ -0,0 +1,134 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Middleware describes a Next.js middleware file and the paths it runs on
+type Middleware struct {
+	FilePath string
+	Matchers []string
+	IsAuth   bool // Whether the middleware appears to enforce authentication
+}
+
+var (
+	matcherKeyPattern = regexp.MustCompile(`matcher\s*:`)
+	stringLiteral     = regexp.MustCompile(`'([^']*)'|"([^"]*)"|` + "`([^`]*)`")
+	sourceKeyPattern  = regexp.MustCompile(`source\s*:\s*(` + stringLiteral.String() + `)`)
+	authHintPattern   = regexp.MustCompile(`(?i)auth|session|token|jwt|signin|login|clerk`)
+)
+
+// FindMiddleware looks for middleware.(ts|js) in the API directory and its ancestors,
+// which covers both the project root and the src/ layout.
+func FindMiddleware(apiDir string) (*Middleware, error) {
+	dir, err := filepath.Abs(apiDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 4; i++ {
+		for _, name := range []string{"middleware.ts", "middleware.js"} {
+			path := filepath.Join(dir, name)
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			return ParseMiddleware(path, string(content)), nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return nil, nil
+}
+
+// ParseMiddleware extracts the `config.matcher` patterns from middleware source
+func ParseMiddleware(path, content string) *Middleware {
+	return &Middleware{
+		FilePath: path,
+		Matchers: parseMatchers(content),
+		IsAuth:   authHintPattern.MatchString(content),
+	}
+}
+
+// parseMatchers handles the string, string array, and `{ source }` object forms
+func parseMatchers(content string) []string {
+	loc := matcherKeyPattern.FindStringIndex(content)
+	if loc == nil {
+		return nil
+	}
+
+	rest := strings.TrimSpace(content[loc[1]:])
+	if rest == "" {
+		return nil
+	}
+
+	// Single string matcher
+	if rest[0] != '[' {
+		if m := stringLiteral.FindStringSubmatchIndex(rest); m != nil && m[0] == 0 {
+			return []string{literalValue(stringLiteral.FindStringSubmatch(rest))}
+		}
+		return nil
+	}
+
+	body := rest[1:closingBracket(rest)]
+
+	// Object form: only the `source` keys are paths
+	if sources := sourceKeyPattern.FindAllStringSubmatch(body, -1); len(sources) > 0 {
+		var matchers []string
+		for _, m := range sources {
+			matchers = append(matchers, literalValue(m[1:]))
+		}
+		return matchers
+	}
+
+	var matchers []string
+	for _, m := range stringLiteral.FindAllStringSubmatch(body, -1) {
+		matchers = append(matchers, literalValue(m))
+	}
+	return matchers
+}
+
+// literalValue returns the contents of whichever quote style matched
+func literalValue(match []string) string {
+	for _, group := range match[1:] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
+
+// closingBracket returns the index of the bracket closing the one at s[0]
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[' || c == '{' || c == '(':
+			depth++
+		case c == ']' || c == '}' || c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxFileSize is the largest route file read; anything bigger is a bundle or data, not a handler
+const MaxFileSize = 1 << 20
+
+const (
+	minifiedMinSize    = 1024 // Shorter files are never treated as minified
+	minifiedLongLine   = 2000 // A line this long is not written by hand
+	minifiedLineLength = 300  // Nor is an average line this long
+)
+
+var bundlerPattern = regexp.MustCompile(`(?m)__webpack_require__|__turbopack_|/\*! For license information|^//# sourceMappingURL=`)
+
+// checkText rejects files that are too large, binary or not UTF-8, which can
+// only match the route file pattern by accident
+func checkText(path string, content []byte) error {
+	if len(content) > MaxFileSize {
+		return fmt.Errorf("%s is %d KB, larger than the %d KB a route file may be", path, len(content)>>10, MaxFileSize>>10)
+	}
+	if bytes.IndexByte(content, 0) >= 0 || !utf8.Valid(content) {
+		return fmt.Errorf("%s is not a UTF-8 text file", path)
+	}
+	return nil
+}
+
+// IsMinified reports whether content looks minified or bundled rather than
+// written by hand, e.g. a compiled route.js from a build directory
+func IsMinified(content string) bool {
+	if len(content) < minifiedMinSize {
+		return false
+	}
+	lines := strings.Split(content, "\n")
+	for _, line := range lines {
+		if len(line) > minifiedLongLine {
+			return true
+		}
+	}
+	if len(content)/len(lines) > minifiedLineLength {
+		return true
+	}
+	return bundlerPattern.MatchString(content)
+}
+
+// Beautify breaks minified JavaScript into indented lines after statements and
+// braces, leaving strings and comments alone. It is not a formatter: it only
+// makes the code readable enough for the detectors and the model.
+func Beautify(content string) string {
+	var out strings.Builder
+	out.Grow(len(content) + len(content)/8)
+
+	depth, parens := 0, 0
+	newline := func() {
+		out.WriteByte('\n')
+		out.WriteString(strings.Repeat("  ", depth))
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			end := stringEnd(content, i)
+			out.WriteString(content[i:end])
+			i = end - 1
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '/':
+			end := strings.IndexByte(content[i:], '\n')
+			if end < 0 {
+				end = len(content) - i
+			}
+			out.WriteString(content[i : i+end])
+			i += end - 1
+			continue
+		case c == '/' && i+1 < len(content) && content[i+1] == '*':
+			end := strings.Index(content[i+2:], "*/")
+			if end < 0 {
+				end = len(content) - i - 4
+			}
+			out.WriteString(content[i : i+end+4])
+			i += end + 3
+			continue
+		case c == '\n' || c == '\r':
+			continue
+		}
+
+		switch c {
+		case '(':
+			parens++
+		case ')':
+			parens = max(0, parens-1)
+		case '{':
+			depth++
+			out.WriteByte(c)
+			newline()
+			i = skipSpace(content, i)
+			continue
+		case '}':
+			depth = max(0, depth-1)
+			newline()
+			out.WriteByte(c)
+			if i+1 < len(content) && !strings.ContainsRune("),;.", rune(content[i+1])) {
+				newline()
+				i = skipSpace(content, i)
+			}
+			continue
+		case ';':
+			out.WriteByte(c)
+			// Keep for (;;) headers on one line
+			if parens == 0 {
+				newline()
+				i = skipSpace(content, i)
+			}
+			continue
+		}
+		out.WriteByte(c)
+	}
+	return strings.TrimSpace(out.String()) + "\n"
+}
+
+// stringEnd returns the index just past the string literal starting at i
+func stringEnd(content string, i int) int {
+	quote := content[i]
+	for j := i + 1; j < len(content); j++ {
+		switch content[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(content)
+}
+
+// skipSpace returns the index of the last whitespace byte following i
+func skipSpace(content string, i int) int {
+	for i+1 < len(content) && strings.ContainsRune(" \t\r\n", rune(content[i+1])) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,239 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// import { userSchema } from '@/lib/validators', export * from './user'
+var importPattern = regexp.MustCompile(`(?m)^\s*(?:import|export)\s[^;]*?\bfrom\s+['"]([^'"]+)['"]`)
+
+var moduleExtensions = []string{".ts", ".tsx", ".js", ".jsx"}
+
+// module is what the detectors found in one imported file
+type module struct {
+	modTime time.Time
+	size    int64
+
+	imports     []string // Resolved files this module imports
+	types       []models.TypeDecl
+	nullable    []models.NullableField
+	formats     map[string]string
+	constraints map[string]models.Constraint
+	objects     map[string][]models.QueryParam // Zod object schemas by name
+	schemas     map[string]string              // Zod schema expressions by name
+}
+
+// moduleCache parses each imported file once and reuses the result for every
+// route importing it, re-parsing only when the file changes on disk
+type moduleCache struct {
+	mu      sync.Mutex
+	modules map[string]*module
+	roots   map[string]string // Directory -> project root for "@/" imports
+
+	parsed, reused int
+}
+
+// sharedModules is used by every scanner, so workspace apps share validator packages
+var sharedModules = &moduleCache{modules: make(map[string]*module), roots: make(map[string]string)}
+
+// ModuleStats reports how many imported files were parsed and how often a parse was reused
+func ModuleStats() (parsed, reused int) {
+	sharedModules.mu.Lock()
+	defer sharedModules.mu.Unlock()
+	return sharedModules.parsed, sharedModules.reused
+}
+
+// importedModules returns the modules a route file imports, directly or through
+// re-exports, each once
+func (c *moduleCache) importedModules(path, content string) []*module {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var found []*module
+	seen := map[string]bool{path: true}
+	var visit func(files []string)
+	visit = func(files []string) {
+		for _, file := range files {
+			if seen[file] {
+				continue
+			}
+			seen[file] = true
+			if mod := c.load(file); mod != nil {
+				found = append(found, mod)
+				visit(mod.imports)
+			}
+		}
+	}
+	visit(c.resolveImports(path, content))
+	return found
+}
+
+// load returns the parsed module for file, parsing it if it is new or changed
+func (c *moduleCache) load(file string) *module {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil
+	}
+	if mod, ok := c.modules[file]; ok && mod.modTime.Equal(info.ModTime()) && mod.size == info.Size() {
+		c.reused++
+		return mod
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil
+	}
+	content := string(data)
+	types := ParseTypes(content)
+	for i := range types {
+		types[i].File = file
+	}
+	mod := &module{
+		modTime:     info.ModTime(),
+		size:        info.Size(),
+		imports:     c.resolveImports(file, content),
+		types:       types,
+		nullable:    nullableTypeFields(types),
+		formats:     DetectFormats(content, types),
+		constraints: DetectConstraints(content),
+		objects:     zodObjects(content),
+		schemas:     zodDeclarations(content),
+	}
+	c.modules[file] = mod
+	c.parsed++
+	return mod
+}
+
+// resolveImports maps relative and "@/" import specifiers to files on disk;
+// package imports are skipped
+func (c *moduleCache) resolveImports(from, content string) []string {
+	var files []string
+	for _, m := range importPattern.FindAllStringSubmatch(content, -1) {
+		var base []string
+		switch spec := m[1]; {
+		case strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../"):
+			base = []string{filepath.Join(filepath.Dir(from), spec)}
+		case strings.HasPrefix(spec, "@/"):
+			root := c.projectRoot(filepath.Dir(from))
+			rest := strings.TrimPrefix(spec, "@/")
+			base = []string{filepath.Join(root, "src", rest), filepath.Join(root, rest)}
+		}
+		for _, b := range base {
+			if file := resolveModuleFile(b); file != "" {
+				files = append(files, file)
+				break
+			}
+		}
+	}
+	return files
+}
+
+// projectRoot is the nearest directory above dir with a tsconfig.json or package.json
+func (c *moduleCache) projectRoot(dir string) string {
+	if root, ok := c.roots[dir]; ok {
+		return root
+	}
+	root := dir
+	for current := dir; ; {
+		if fileExists(filepath.Join(current, "tsconfig.json")) || fileExists(filepath.Join(current, "package.json")) {
+			root = current
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+	c.roots[dir] = root
+	return root
+}
+
+// resolveModuleFile applies TypeScript's lookup: the path itself, with an
+// extension, or as a directory with an index file
+func resolveModuleFile(base string) string {
+	candidates := []string{base}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, base+ext)
+	}
+	for _, ext := range moduleExtensions {
+		candidates = append(candidates, filepath.Join(base, "index"+ext))
+	}
+	for _, candidate := range candidates {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// mergeModules adds what imported modules declare to a route's own findings;
+// declarations in the route file win
+func mergeModules(route *models.APIRoute, modules []*module) {
+	declared := make(map[string]bool, len(route.Types))
+	for _, decl := range route.Types {
+		declared[decl.Name] = true
+	}
+	nullable := make(map[models.NullableField]bool, len(route.Nullable))
+	for _, field := range route.Nullable {
+		nullable[field] = true
+	}
+
+	for _, mod := range modules {
+		for _, decl := range mod.types {
+			if !declared[decl.Name] {
+				declared[decl.Name] = true
+				route.Types = append(route.Types, decl)
+			}
+		}
+		for _, field := range mod.nullable {
+			if !nullable[field] {
+				nullable[field] = true
+				route.Nullable = append(route.Nullable, field)
+			}
+		}
+		for name, format := range mod.formats {
+			if _, ok := route.Formats[name]; !ok {
+				if route.Formats == nil {
+					route.Formats = make(map[string]string)
+				}
+				route.Formats[name] = format
+			}
+		}
+		for name, constraint := range mod.constraints {
+			if _, ok := route.Constraints[name]; !ok {
+				if route.Constraints == nil {
+					route.Constraints = make(map[string]models.Constraint)
+				}
+				route.Constraints[name] = constraint
+			}
+		}
+	}
+	sortNullable(route.Nullable)
+}
+
+// moduleObjects combines the Zod object schemas of a file with those of the
+// modules it imports; the file's own win
+func moduleObjects(content string, modules []*module) map[string][]models.QueryParam {
+	objects := zodObjects(content)
+	for _, mod := range modules {
+		for name, fields := range mod.objects {
+			if _, ok := objects[name]; !ok {
+				objects[name] = fields
+			}
+		}
+	}
+	return objects
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	pagesExtPattern      = regexp.MustCompile(`^[^_.][^.]*\.(js|ts|jsx|tsx)$`)
+	pagesMethodPattern   = regexp.MustCompile(`\.method\s*[!=]==?\s*['"](GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)['"]|case\s+['"](GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)['"]\s*:`)
+	pagesAllowedPattern  = regexp.MustCompile(`\[([^\]]*)\]\.includes\(\s*\w+\.method`)
+	pagesMethodName      = regexp.MustCompile(`['"](GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)['"]`)
+	pagesDefaultExport   = regexp.MustCompile(`export\s+default\s+(?:async\s+)?(?:function\b|(?:\w+\(\s*)*(\w+))`)
+	pagesTestFilePattern = regexp.MustCompile(`\.(test|spec)\.\w+$`)
+)
+
+// isPagesAPIFile reports whether a file is a pages router API route: a script
+// below pages/api that is not a _-prefixed special file, a test or a type declaration
+func isPagesAPIFile(path string) bool {
+	name := filepath.Base(path)
+	if !pagesExtPattern.MatchString(name) || pagesTestFilePattern.MatchString(name) {
+		return false
+	}
+	return strings.Contains(filepath.ToSlash(filepath.Dir(path))+"/", "pages/api/")
+}
+
+// pagesPath derives the URL a pages router file serves: the file name is the
+// last segment, except for index files, so pages/api/users/[id].ts is /api/users/{id}
+func (s *Scanner) pagesPath(file string) string {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+	if filepath.Base(base) == "index" {
+		return s.urlPath(file)
+	}
+	return s.urlPath(filepath.Join(base, "index"))
+}
+
+// DetectPagesHandlers lists the methods a pages router handler branches on,
+// e.g. req.method === 'POST' or case 'GET':. Every method spans the default
+// export, since one function serves them all. A handler that never checks the
+// method has none.
+func DetectPagesHandlers(content string) []models.Handler {
+	methods := make(map[string]bool)
+	for _, m := range pagesMethodPattern.FindAllStringSubmatch(content, -1) {
+		methods[m[1]+m[2]] = true
+	}
+	for _, m := range pagesAllowedPattern.FindAllStringSubmatch(content, -1) {
+		for _, name := range pagesMethodName.FindAllStringSubmatch(m[1], -1) {
+			methods[name[1]] = true
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+
+	start, end := pagesHandlerSpan(content)
+	var handlers []models.Handler
+	for _, method := range []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"} {
+		if methods[method] {
+			handlers = append(handlers, models.Handler{Method: method, StartLine: start, EndLine: end})
+		}
+	}
+	return handlers
+}
+
+// pagesHandlerSpan returns the lines of the default-exported handler. An
+// export of a name, possibly wrapped (export default withAuth(handler)), spans
+// the function declared under that name. The whole file is used when the
+// handler cannot be found.
+func pagesHandlerSpan(content string) (int, int) {
+	m := pagesDefaultExport.FindStringSubmatchIndex(content)
+	if m == nil {
+		return 1, lineOf(content, len(content))
+	}
+	start := m[0]
+	if m[2] >= 0 {
+		name := regexp.QuoteMeta(content[m[2]:m[3]])
+		decl := regexp.MustCompile(`(?:function\s+` + name + `\s*\(|(?:const|let|var)\s+` + name + `\s*(?::[^=]+)?=)`)
+		loc := decl.FindStringIndex(content)
+		if loc == nil {
+			return 1, lineOf(content, len(content))
+		}
+		start = loc[0]
+	}
+
+	// Skip the parameter list, which may destructure, to reach the body
+	params := strings.IndexByte(content[start:], '(')
+	if params < 0 {
+		return lineOf(content, start), lineOf(content, len(content))
+	}
+	params += start
+	body := strings.IndexByte(content[params+closingBracket(content[params:]):], '{')
+	if body < 0 {
+		return lineOf(content, start), lineOf(content, len(content))
+	}
+	body += params + closingBracket(content[params:])
+	return lineOf(content, start), lineOf(content, min(body+closingBracket(content[body:]), len(content)))
+}
@@ -0,0 +1,146 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// const users = await prisma.user.findMany({ skip, take })
+	findManyPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:await\s+)?[\w$.]*?\.([A-Za-z_$][\w$]*)\.findMany\(`)
+	// const total = await prisma.user.count()
+	countPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:await\s+)?[\w$.]*?\.([A-Za-z_$][\w$]*)\.count\(`)
+	// const [users, total] = await prisma.$transaction([...]) or Promise.all([...])
+	pairPattern     = regexp.MustCompile(`(?:const|let|var)\s*\[\s*([A-Za-z_$][\w$]*)\s*,\s*([A-Za-z_$][\w$]*)\s*\]\s*=\s*(?:await\s+)?(?:[\w$.]*\$transaction|Promise\.all)\(\s*\[`)
+	pairCallPattern = regexp.MustCompile(`\.([A-Za-z_$][\w$]*)\.(findMany|count)\(`)
+	// NextResponse.json({ ... }), Response.json({ ... }), res.status(200).json({ ... })
+	jsonObjectPattern = regexp.MustCompile(`(?:NextResponse|Response|\bres(?:\.status\(\s*\d+\s*\))?)\.json\(\s*\{`)
+	objectKeyPattern  = regexp.MustCompile(`^['"]?([A-Za-z_$][\w$]*)['"]?\s*(?::\s*([\s\S]*))?$`)
+)
+
+// Integer fields of a page envelope, by lowercase name
+var pageFields = map[string]string{
+	"page": "integer", "pagesize": "integer", "perpage": "integer", "limit": "integer",
+	"offset": "integer", "skip": "integer", "take": "integer", "totalpages": "integer",
+	"pages": "integer", "pagecount": "integer", "count": "integer", "total": "integer",
+	"totalcount": "integer", "hasmore": "boolean", "hasnext": "boolean", "hasnextpage": "boolean",
+	"hasprevious": "boolean", "hasprev": "boolean", "hasprevpage": "boolean",
+	"nextcursor": "string", "cursor": "string", "prevcursor": "string",
+}
+
+// DetectPagination finds handlers that return a page of a Prisma findMany along
+// with a count of the same model, and the shape of the object they return
+func DetectPagination(content string, handlers []models.Handler) []models.Pagination {
+	spans := handlers
+	if len(spans) == 0 {
+		spans = []models.Handler{{StartLine: 1, EndLine: strings.Count(content, "\n") + 1}}
+	}
+
+	var pages []models.Pagination
+	for _, h := range spans {
+		source := handlerLines(content, h)
+		items, counts := pagedQueries(source)
+		if len(items) == 0 || len(counts) == 0 {
+			continue
+		}
+		loc := jsonObjectPattern.FindStringIndex(source)
+		if loc == nil {
+			continue
+		}
+		body := source[loc[1]-1:]
+		end := closingBracket(body)
+		if end < 0 {
+			continue
+		}
+
+		page := models.Pagination{Method: h.Method, Fields: make(map[string]string)}
+		envelopeFields(body[1:end], "", items, counts, &page)
+		if page.Items != "" {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
+
+// pagedQueries maps variables holding findMany results and counts to their models
+func pagedQueries(source string) (items, counts map[string]string) {
+	items, counts = make(map[string]string), make(map[string]string)
+	for _, m := range findManyPattern.FindAllStringSubmatch(source, -1) {
+		items[m[1]] = m[2]
+	}
+	for _, m := range countPattern.FindAllStringSubmatch(source, -1) {
+		counts[m[1]] = m[2]
+	}
+	for _, loc := range pairPattern.FindAllStringSubmatchIndex(source, -1) {
+		names := []string{source[loc[2]:loc[3]], source[loc[4]:loc[5]]}
+		list := source[loc[1]-1:]
+		if end := closingBracket(list); end > 0 {
+			list = list[:end]
+		}
+		calls := pairCallPattern.FindAllStringSubmatch(list, 2)
+		for i, call := range calls {
+			if call[2] == "findMany" {
+				items[names[i]] = call[1]
+			} else {
+				counts[names[i]] = call[1]
+			}
+		}
+	}
+
+	// Only a count of the listed model makes a page
+	for name, model := range items {
+		found := false
+		for _, counted := range counts {
+			found = found || counted == model
+		}
+		if !found {
+			delete(items, name)
+		}
+	}
+	return items, counts
+}
+
+// envelopeFields records the fields of a returned object literal, descending
+// into nested objects such as `meta: { total, page }`
+func envelopeFields(object, prefix string, items, counts map[string]string, page *models.Pagination) {
+	for _, member := range splitTopLevel(object) {
+		member = strings.TrimSpace(member)
+		if member == "" || strings.HasPrefix(member, "...") {
+			continue
+		}
+		m := objectKeyPattern.FindStringSubmatch(member)
+		if m == nil {
+			continue
+		}
+		key, value := m[1], strings.TrimSpace(m[2])
+		if value == "" {
+			value = key
+		}
+		path := prefix + key
+
+		switch {
+		case items[value] != "" && page.Items == "":
+			page.Items, page.Model = path, items[value]
+		case counts[value] != "":
+			page.Fields[path] = "integer"
+		case strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+			envelopeFields(value[1:len(value)-1], path+".", items, counts, page)
+		case strings.HasPrefix(value, "Math."):
+			page.Fields[path] = "integer"
+		default:
+			page.Fields[path] = pageFields[strings.ToLower(key)]
+		}
+	}
+}
+
+// handlerLines returns the source of a handler
+func handlerLines(content string, h models.Handler) string {
+	lines := strings.Split(content, "\n")
+	start, end := max(h.StartLine-1, 0), min(h.EndLine, len(lines))
+	if start >= end {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
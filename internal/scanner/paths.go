@@ -0,0 +1,81 @@
+package scanner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var dynamicSegment = regexp.MustCompile(`^\[{1,2}(?:\.\.\.)?([^\]]+)\]{1,2}$`)
+
+// urlPath derives the URL a route file serves from its location. Everything after
+// the Next.js app/ (or pages/) directory is the URL; otherwise the path is taken
+// relative to the parent of the scanned directory, so ./api/users/route.ts is /api/users.
+func (s *Scanner) urlPath(file string) string {
+	dir := filepath.ToSlash(filepath.Dir(file))
+	segments := strings.Split(dir, "/")
+
+	start := s.routerStart(segments)
+	if start < 0 {
+		rel, err := filepath.Rel(filepath.Dir(filepath.Clean(s.rootDir)), filepath.Dir(file))
+		if err != nil {
+			return "/" + dir
+		}
+		segments, start = strings.Split(filepath.ToSlash(rel), "/"), 0
+	}
+
+	var parts []string
+	for _, segment := range segments[start:] {
+		switch {
+		case segment == "" || segment == ".":
+			continue
+		case strings.HasPrefix(segment, "(") && strings.HasSuffix(segment, ")"):
+			continue // Route groups do not appear in the URL
+		case strings.HasPrefix(segment, "@"):
+			continue // Parallel route slots do not either
+		}
+		if m := dynamicSegment.FindStringSubmatch(segment); m != nil {
+			segment = "{" + m[1] + "}"
+		}
+		parts = append(parts, segment)
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// routerStart returns the index of the first URL segment in the segments of a
+// route's directory: the one after the router root. When the scanned
+// directory is inside the router (src/app/api) that is its last app or pages
+// segment; otherwise it is the first one below the scanned directory, so
+// folders named app or pages inside the routes (app/api/pages/[id]) stay in
+// the URL. It returns -1 when there is no router root.
+func (s *Scanner) routerStart(segments []string) int {
+	root := strings.Split(filepath.ToSlash(filepath.Clean(s.rootDir)), "/")
+	under := len(root) <= len(segments)
+	for i := range root {
+		if under && root[i] != segments[i] {
+			under = false
+		}
+	}
+	if root[0] == "." {
+		root, under = nil, true // Scanning the working directory
+	}
+	if !under {
+		root = nil // Not below the scanned directory, e.g. a single file
+	}
+
+	for i := len(root) - 1; i >= 0; i-- {
+		if isRouterRoot(root[i]) {
+			return i + 1
+		}
+	}
+	for i := len(root); i < len(segments); i++ {
+		if isRouterRoot(segments[i]) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func isRouterRoot(segment string) bool {
+	return segment == "app" || segment == "pages"
+}
@@ -0,0 +1,126 @@
+package scanner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// searchParams.get('page'), on url.searchParams, req.nextUrl.searchParams, ...
+	queryGetPattern = regexp.MustCompile(`searchParams\.get\(\s*['"]([\w.-]+)['"]\s*\)`)
+	fallbackPattern = regexp.MustCompile(`(?:\?\?|\|\|)\s*(?:'([^']*)'|"([^"]*)"|(-?\d+(?:\.\d+)?|true|false))`)
+	// const { page = '1', q } = req.query  /  = Object.fromEntries(searchParams)
+	queryDestructure = regexp.MustCompile(`\{([^{}]*)\}\s*=\s*(?:req\.query|Object\.fromEntries\([^;\n]*searchParams[^;\n]*\))`)
+	destructuredName = regexp.MustCompile(`^([A-Za-z_$][\w$]*)(?:\s*:\s*[A-Za-z_$][\w$]*)?(?:\s*=\s*(?:'([^']*)'|"([^"]*)"|(-?\d+(?:\.\d+)?|true|false)))?$`)
+	booleanCompare   = regexp.MustCompile(`[!=]==?\s*['"](?:true|false)['"]`)
+)
+
+// DetectQueryParams finds query parameters read in the code, with the type
+// they are converted to (`Number(...)`, `parseInt(...)`, `=== 'true'`) and the
+// default used when they are absent (`?? 1`, `|| 'asc'`, `{ page = 1 }`)
+func DetectQueryParams(content string) []models.QueryParam {
+	var params []models.QueryParam
+	seen := make(map[string]bool)
+	add := func(p models.QueryParam) {
+		key := p.Name + "@" + strconv.Itoa(p.Line)
+		if !seen[key] {
+			seen[key] = true
+			params = append(params, p)
+		}
+	}
+
+	for _, loc := range queryGetPattern.FindAllStringSubmatchIndex(content, -1) {
+		start := strings.LastIndexByte(content[:loc[0]], '\n') + 1
+		end := lineEnd(content, loc[1])
+		if semi := strings.IndexByte(content[loc[1]:end], ';'); semi >= 0 {
+			end = loc[1] + semi
+		}
+		statement := content[start:end]
+		p := models.QueryParam{Name: content[loc[2]:loc[3]], Line: lineOf(content, loc[0])}
+
+		// Only attribute a conversion when the statement reads one parameter
+		if len(queryGetPattern.FindAllString(statement, -1)) == 1 {
+			if f := fallbackPattern.FindStringSubmatch(content[loc[1]:end]); f != nil {
+				p.Default = literal(f[1], f[2], f[3])
+			}
+			p.Type = conversionType(statement, p.Default)
+			p.Default = coerce(p.Default, p.Type)
+		}
+		add(p)
+	}
+
+	for _, loc := range queryDestructure.FindAllStringSubmatchIndex(content, -1) {
+		for _, entry := range strings.Split(content[loc[2]:loc[3]], ",") {
+			m := destructuredName.FindStringSubmatch(strings.TrimSpace(entry))
+			if m == nil {
+				continue
+			}
+			p := models.QueryParam{Name: m[1], Line: lineOf(content, loc[0])}
+			if m[2] != "" || m[3] != "" || m[4] != "" {
+				p.Default = literal(m[2], m[3], m[4])
+			}
+			// A non-string default tells what the handler expects
+			switch v := p.Default.(type) {
+			case float64:
+				p.Type = conversionType("Number(", v)
+				p.Default = coerce(v, p.Type)
+			case bool:
+				p.Type = "boolean"
+			}
+			add(p)
+		}
+	}
+	return params
+}
+
+// conversionType reads the type a query string value is converted to
+func conversionType(statement string, fallback interface{}) string {
+	switch {
+	case strings.Contains(statement, "parseInt("):
+		return "integer"
+	case strings.Contains(statement, "parseFloat("):
+		return "number"
+	case strings.Contains(statement, "Number("):
+		if n, ok := fallback.(float64); ok && n == float64(int64(n)) {
+			return "integer"
+		}
+		if s, ok := fallback.(string); ok && !strings.Contains(s, ".") {
+			if _, err := strconv.Atoi(s); err == nil {
+				return "integer"
+			}
+		}
+		return "number"
+	case booleanCompare.MatchString(statement):
+		return "boolean"
+	}
+	return ""
+}
+
+// coerce converts a default such as "20" to the parameter's type
+func coerce(value interface{}, typ string) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		if n, ok := value.(float64); ok && typ == "integer" {
+			return int64(n)
+		}
+		return value
+	}
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+	return value
+}
@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// const querySchema = z.object({
+	zodObjectPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)(?:\s*:\s*[^=]+)?\s*=\s*z\s*\.\s*object\(\s*\{`)
+	zodEntryPattern  = regexp.MustCompile(`^['"]?([A-Za-z_$][\w$-]*)['"]?\s*:\s*(z\s*\.[\s\S]*)$`)
+	zodKindPattern   = regexp.MustCompile(`^z\s*\.\s*(?:coerce\s*\.\s*)?(\w+)\(`)
+	zodDefault       = regexp.MustCompile(`\.default\(\s*(?:'([^']*)'|"([^"]*)"|(-?\d+(?:\.\d+)?|true|false))\s*\)`)
+	zodDescribe      = regexp.MustCompile(`\.describe\(\s*(?:'([^']*)'|"([^"]*)")\s*\)`)
+	// querySchema.parse(...), .safeParse(...), .parseAsync(...)
+	schemaParsePattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*\.\s*(?:safeParse|parse)(?:Async)?\(`)
+	// parseQuery(request, querySchema), getSearchParams(querySchema, req)
+	queryHelperPattern = regexp.MustCompile(`\b([A-Za-z_$][\w$]*(?:[Qq]uery|SearchParams)[\w$]*)\s*\(`)
+	// const query = Object.fromEntries(searchParams), = qs.parse(url.search), = req.query
+	queryObjectAlias  = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*((?:Object\.fromEntries|qs\.parse|parse)\([^;\n]*\)|req\.query)`)
+	identifierPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+)
+
+// zodObjects maps the Zod object schemas declared in content to their fields,
+// typed and defaulted like query parameters
+func zodObjects(content string) map[string][]models.QueryParam {
+	objects := make(map[string][]models.QueryParam)
+	for _, loc := range zodObjectPattern.FindAllStringSubmatchIndex(content, -1) {
+		open := loc[1] - 1
+		body := content[open+1 : open+closingBracket(content[open:])]
+
+		var fields []models.QueryParam
+		for _, entry := range splitTopLevel(body) {
+			m := zodEntryPattern.FindStringSubmatch(strings.TrimSpace(entry))
+			if m == nil {
+				continue
+			}
+			fields = append(fields, zodField(m[1], m[2]))
+		}
+		objects[content[loc[2]:loc[3]]] = fields
+	}
+	return objects
+}
+
+// zodField reads the type, default, description and optionality of one field
+func zodField(name, expr string) models.QueryParam {
+	p := models.QueryParam{Name: name, Required: true}
+
+	if m := zodKindPattern.FindStringSubmatch(expr); m != nil {
+		switch m[1] {
+		case "number":
+			p.Type = "number"
+			if strings.Contains(expr, ".int()") {
+				p.Type = "integer"
+			}
+		case "bigint":
+			p.Type = "integer"
+		case "boolean":
+			p.Type = "boolean"
+		case "array":
+			p.Type = "array"
+		case "optional":
+			p.Required = false
+		}
+	}
+	for _, optional := range []string{".optional()", ".nullish()", ".default(", ".catch("} {
+		if strings.Contains(expr, optional) {
+			p.Required = false
+		}
+	}
+	if m := zodDefault.FindStringSubmatch(expr); m != nil {
+		p.Default = coerce(literal(m[1], m[2], m[3]), p.Type)
+	}
+	if m := zodDescribe.FindStringSubmatch(expr); m != nil {
+		p.Description = firstNonEmpty(m[1], m[2])
+	}
+	return p
+}
+
+// DetectQueryObjects expands Zod schemas that validate the whole query string
+// (`querySchema.parse(Object.fromEntries(searchParams))`, `qs.parse(...)`,
+// helpers such as `parseQuery(req, querySchema)`) into one parameter per field.
+// objects holds the schemas declared in the file and the modules it imports.
+func DetectQueryObjects(content string, objects map[string][]models.QueryParam) []models.QueryParam {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	aliases := make(map[string]bool)
+	for _, m := range queryObjectAlias.FindAllStringSubmatch(content, -1) {
+		if isQuerySource(m[2], nil) {
+			aliases[m[1]] = true
+		}
+	}
+
+	var params []models.QueryParam
+	expand := func(schema string, offset int) {
+		line := lineOf(content, offset)
+		for _, field := range objects[schema] {
+			field.Line = line
+			params = append(params, field)
+		}
+	}
+
+	for _, loc := range schemaParsePattern.FindAllStringSubmatchIndex(content, -1) {
+		schema := content[loc[2]:loc[3]]
+		if _, ok := objects[schema]; !ok {
+			continue
+		}
+		open := loc[1] - 1
+		if isQuerySource(content[open+1:open+closingBracket(content[open:])], aliases) {
+			expand(schema, loc[0])
+		}
+	}
+	for _, loc := range queryHelperPattern.FindAllStringSubmatchIndex(content, -1) {
+		open := loc[1] - 1
+		for _, arg := range identifierPattern.FindAllString(content[open+1:open+closingBracket(content[open:])], -1) {
+			if _, ok := objects[arg]; ok {
+				expand(arg, loc[0])
+				break
+			}
+		}
+	}
+	return params
+}
+
+// isQuerySource reports whether an expression is the parsed query string
+func isQuerySource(expr string, aliases map[string]bool) bool {
+	expr = strings.TrimSpace(expr)
+	if aliases[expr] {
+		return true
+	}
+	switch {
+	case strings.Contains(expr, "fromEntries(") && strings.Contains(expr, "searchParams"):
+		return true
+	case strings.Contains(expr, "qs.parse("):
+		return true
+	case strings.HasPrefix(expr, "parse(") && strings.Contains(expr, "search"):
+		return true // import { parse } from "qs"
+	case expr == "req.query" || expr == "request.query":
+		return true
+	}
+	return false
+}
+
+// withQueryObjects adds the fields of validated query objects to the
+// parameters read one by one
+func withQueryObjects(params, fields []models.QueryParam) []models.QueryParam {
+	seen := make(map[string]bool, len(params))
+	for _, p := range params {
+		seen[p.Name+"@"+strconv.Itoa(p.Line)] = true
+	}
+	for _, f := range fields {
+		if key := f.Name + "@" + strconv.Itoa(f.Line); !seen[key] {
+			seen[key] = true
+			params = append(params, f)
+		}
+	}
+	return params
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"nextjs-to-openapi/internal/models"
@@ -12,34 +13,44 @@ import (
 
 type Scanner struct {
 	rootDir string
+	groups  map[string]*models.RouteGroup // Folder -> nearest documented group
 }
 
 func NewScanner(rootDir string) *Scanner {
-	return &Scanner{rootDir: rootDir}
+	return &Scanner{rootDir: rootDir, groups: make(map[string]*models.RouteGroup)}
 }
 
 // Simplified scanner - just find files and read content
 func (s *Scanner) ScanRoutes() ([]models.APIRoute, error) {
+	routes, err := s.Discover()
+
+	var loaded []models.APIRoute
+	for _, route := range routes {
+		if route, err := Load(route); err == nil {
+			loaded = append(loaded, route)
+		}
+	}
+	return loaded, err
+}
+
+// Discover lists route files and the URL paths they serve without reading them
+func (s *Scanner) Discover() ([]models.APIRoute, error) {
 	var routes []models.APIRoute
 
 	err := filepath.WalkDir(s.rootDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
+		if err != nil {
 			return nil
 		}
-
-		if isRouteFile(d.Name()) {
-			content, err := os.ReadFile(path)
-			if err != nil {
-				return nil
-			}
-
-			route := models.APIRoute{
-				Path:     path,
-				FileType: strings.TrimPrefix(filepath.Ext(path), "."),
-				Content:  string(content),
+		if d.IsDir() {
+			// Build output and dependencies hold compiled route.js files
+			if path != s.rootDir && (d.Name() == "node_modules" || d.Name() == ".next") {
+				return filepath.SkipDir
 			}
+			return nil
+		}
 
-			routes = append(routes, route)
+		if isRouteFile(d.Name()) || isPagesAPIFile(path) {
+			routes = append(routes, s.route(path))
 		}
 		return nil
 	})
@@ -47,6 +58,95 @@ func (s *Scanner) ScanRoutes() ([]models.APIRoute, error) {
 	return routes, err
 }
 
+// ScanFile reads and analyzes a single route file
+func (s *Scanner) ScanFile(path string) (models.APIRoute, error) {
+	return Load(s.route(path))
+}
+
+// Analyze runs every detector over route source that may not exist on disk
+func (s *Scanner) Analyze(path, content string) models.APIRoute {
+	return analyze(s.route(path), content)
+}
+
+// Load reads a discovered route's file and runs every detector over it,
+// keeping what was already set on the route. Binary and oversized files are
+// rejected; minified ones are beautified first.
+func Load(route models.APIRoute) (models.APIRoute, error) {
+	content, err := os.ReadFile(route.FilePath)
+	if err != nil {
+		return route, err
+	}
+	if err := checkText(route.FilePath, content); err != nil {
+		return route, err
+	}
+	text := string(content)
+	if IsMinified(text) {
+		text, route.Minified = Beautify(text), true
+	}
+	return analyze(route, text), nil
+}
+
+// route describes a route file before it is read
+func (s *Scanner) route(path string) models.APIRoute {
+	route := models.APIRoute{
+		Path:     s.urlPath(path),
+		FilePath: path,
+		FileType: strings.TrimPrefix(filepath.Ext(path), "."),
+		Group:    s.group(filepath.Dir(path)),
+	}
+	if !isRouteFile(filepath.Base(path)) && isPagesAPIFile(path) {
+		route.Path, route.Router = s.pagesPath(path), models.PagesRouter
+	}
+	return route
+}
+
+// analyze fills in everything the detectors find in content
+func analyze(route models.APIRoute, content string) models.APIRoute {
+	path := route.FilePath
+	types := ParseTypes(content)
+	handlers := DetectHandlers(content)
+	if route.Router == models.PagesRouter {
+		handlers = DetectPagesHandlers(content)
+	}
+
+	route.Content = content
+	route.Roles = DetectRoles(content, handlers)
+	route.Headers = DetectResponseHeaders(content, handlers)
+	route.Types = types
+	route.Nullable = DetectNullableFields(content, types, handlers)
+	route.Formats = DetectFormats(content, types)
+	route.BinaryType = DetectBinaryResponse(content)
+	route.Handlers = handlers
+	route.Idempotent = DetectIdempotencyKey(content, handlers)
+	route.Authenticated = DetectAuthentication(content, handlers)
+	route.Constraints = withStaticParams(DetectConstraints(content), DetectStaticParams(content))
+	modules := sharedModules.importedModules(path, content)
+	route.QueryParams = withQueryObjects(DetectQueryParams(content), DetectQueryObjects(content, moduleObjects(content, modules)))
+	route.Zod = DetectZodSchemas(content, handlers, moduleSchemas(content, modules))
+	route.Internal = IsInternal(path, content)
+	route.Annotations = DetectAnnotations(content, handlers)
+	route.Versioning = DetectVersionHeader(content, handlers)
+	route.Factory = DetectFactory(content)
+	route.Pagination = DetectPagination(content, handlers)
+	route.Segment = DetectSegmentConfig(content)
+	if route.Factory != nil {
+		// Factory-made handlers count as exported
+		for _, method := range route.Factory.Methods {
+			if !slices.ContainsFunc(handlers, func(h models.Handler) bool { return h.Method == method }) {
+				route.Handlers = append(route.Handlers, models.Handler{Method: method, StartLine: route.Factory.Line, EndLine: route.Factory.Line})
+			}
+		}
+	}
+	mergeModules(&route, modules)
+	return route
+}
+
+// IsRouteFile reports whether a file is a Next.js route handler, either an app
+// router route file or a script below pages/api
+func IsRouteFile(path string) bool {
+	return isRouteFile(filepath.Base(path)) || isPagesAPIFile(path)
+}
+
 func isRouteFile(filename string) bool {
 	// Match: route.js, route.ts, route.jsx, route.tsx
 	matched, _ := regexp.MatchString(`^route\.(js|ts|jsx|tsx)$`, filename)
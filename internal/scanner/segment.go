@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	segmentConfigPattern = regexp.MustCompile(`(?m)^\s*export\s+const\s+(dynamic|revalidate|maxDuration|preferredRegion)\s*(?::[^=\n]+)?=\s*([^;\n]+)`)
+	quotedPattern        = regexp.MustCompile(`['"` + "`" + `]([^'"` + "`" + `]+)['"` + "`" + `]`)
+)
+
+// DetectSegmentConfig reads the route segment config a route file exports:
+// dynamic, revalidate, maxDuration and preferredRegion. Values that are not
+// literals, such as imported constants, are skipped.
+func DetectSegmentConfig(content string) *models.SegmentConfig {
+	var config models.SegmentConfig
+	found := false
+	for _, m := range segmentConfigPattern.FindAllStringSubmatch(content, -1) {
+		value := strings.TrimSpace(m[2])
+		switch m[1] {
+		case "dynamic":
+			if q := quotedPattern.FindStringSubmatch(value); q != nil {
+				config.Dynamic, found = q[1], true
+			}
+		case "revalidate":
+			if value == "false" {
+				config.Revalidate, found = "false", true
+			} else if _, err := strconv.Atoi(value); err == nil {
+				config.Revalidate, found = value, true
+			}
+		case "maxDuration":
+			if seconds, err := strconv.Atoi(value); err == nil {
+				config.MaxDuration, found = seconds, true
+			}
+		case "preferredRegion":
+			for _, q := range quotedPattern.FindAllStringSubmatch(value, -1) {
+				config.PreferredRegion, found = append(config.PreferredRegion, q[1]), true
+			}
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &config
+}
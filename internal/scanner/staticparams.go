@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// export async function generateStaticParams() {
+	staticParamsFunc = regexp.MustCompile(`export\s+(?:async\s+)?function\s+generateStaticParams\s*\([^)]*\)[^{]*\{`)
+	// export const generateStaticParams = async () =>
+	staticParamsArrow = regexp.MustCompile(`export\s+const\s+generateStaticParams\s*(?::[^=]*)?=\s*(?:async\s*)?\([^)]*\)\s*(?::[^=]*)?=>\s*`)
+	// { slug: 'intro', lang: "en" }
+	paramObjectPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+	paramMemberPattern = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*:\s*['"]([^'"]+)['"]`)
+	// ['en', 'de'].map((locale) => ({ locale })), LOCALES.map(l => ({ locale: l }))
+	paramMapPattern   = regexp.MustCompile(`(?:\[([^\]]*)\]|\b([A-Za-z_$][\w$]*))\s*\.map\(\s*(?:async\s*)?\(?\s*([A-Za-z_$][\w$]*)\s*\)?\s*=>\s*\(?\s*\{([^{}]*)\}`)
+	paramValuePattern = regexp.MustCompile(`['"]([^'"]+)['"]`)
+)
+
+// DetectStaticParams reads the values generateStaticParams returns for each
+// route parameter, from literal objects or a literal list mapped to objects
+func DetectStaticParams(content string) map[string][]string {
+	body := staticParamsBody(content)
+	if body == "" {
+		return nil
+	}
+
+	values := make(map[string][]string)
+	add := func(name, value string) {
+		if !contains(values[name], value) {
+			values[name] = append(values[name], value)
+		}
+	}
+
+	for _, m := range paramMapPattern.FindAllStringSubmatch(body, -1) {
+		list, variable, members := m[1], m[3], m[4]
+		if list == "" {
+			list = arrayLiteral(content, m[2])
+		}
+		for _, member := range strings.Split(members, ",") {
+			name, value, found := strings.Cut(strings.TrimSpace(member), ":")
+			if name = strings.TrimSpace(name); !found {
+				value = name
+			}
+			if strings.TrimSpace(value) != variable {
+				continue
+			}
+			for _, s := range paramValuePattern.FindAllStringSubmatch(list, -1) {
+				add(name, s[1])
+			}
+		}
+	}
+	for _, object := range paramObjectPattern.FindAllStringSubmatch(body, -1) {
+		for _, m := range paramMemberPattern.FindAllStringSubmatch(object[1], -1) {
+			add(m[1], m[2])
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// withStaticParams turns the values generateStaticParams lists into enums on
+// the parameter constraints. A validator's enum is the stricter statement and wins.
+func withStaticParams(constraints map[string]models.Constraint, values map[string][]string) map[string]models.Constraint {
+	for name, list := range values {
+		if constraints == nil {
+			constraints = make(map[string]models.Constraint)
+		}
+		if c := constraints[name]; len(c.Enum) == 0 {
+			c.Enum = list
+			constraints[name] = c
+		}
+	}
+	return constraints
+}
+
+// staticParamsBody returns the source of generateStaticParams, if the file exports it
+func staticParamsBody(content string) string {
+	if loc := staticParamsFunc.FindStringIndex(content); loc != nil {
+		rest := content[loc[1]-1:]
+		if end := closingBracket(rest); end > 0 {
+			return rest[:end+1]
+		}
+	}
+	if loc := staticParamsArrow.FindStringIndex(content); loc != nil {
+		rest := content[loc[1]:]
+		if end := closingBracket(rest); end > 0 && strings.ContainsRune("{[(", rune(rest[0])) {
+			return rest[:end+1]
+		}
+		// An expression such as LOCALES.map(...) ends with its line
+		if end := strings.IndexByte(rest, '\n'); end >= 0 {
+			return rest[:end]
+		}
+		return rest
+	}
+	return ""
+}
+
+// arrayLiteral returns the elements of `const name = [...]` declared in content
+func arrayLiteral(content, name string) string {
+	pattern := regexp.MustCompile(`(?:const|let|var)\s+` + regexp.QuoteMeta(name) + `\s*(?::[^=]*)?=\s*\[([^\]]*)\]`)
+	if m := pattern.FindStringSubmatch(content); m != nil {
+		return m[1]
+	}
+	return ""
+}
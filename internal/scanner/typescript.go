@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	interfacePattern     = regexp.MustCompile(`\binterface\s+([A-Za-z_$][\w$]*)(?:<[^>{]*>)?(?:\s+extends\s+[^{]+)?\s*\{`)
+	typeAliasPattern     = regexp.MustCompile(`\btype\s+([A-Za-z_$][\w$]*)(?:<[^>=]*>)?\s*=\s*\{`)
+	fieldPattern         = regexp.MustCompile(`^(?:readonly\s+)?['"]?([A-Za-z_$][\w$]*)['"]?\s*(\?)?\s*:\s*([\s\S]+)$`)
+	optionalChainPattern = regexp.MustCompile(`\.([A-Za-z_$][\w$]*)\?\.`)
+	nullUnionPattern     = regexp.MustCompile(`(?:^|\|)\s*null\s*(?:\||$)`)
+)
+
+// ParseTypes extracts interfaces and object type aliases declared in a TypeScript file
+func ParseTypes(content string) []models.TypeDecl {
+	var decls []models.TypeDecl
+
+	for _, pattern := range []*regexp.Regexp{interfacePattern, typeAliasPattern} {
+		for _, loc := range pattern.FindAllStringSubmatchIndex(content, -1) {
+			name := content[loc[2]:loc[3]]
+			open := loc[1] - 1
+			end := open + closingBracket(content[open:])
+			body := content[open+1 : min(end, len(content))]
+			decls = append(decls, models.TypeDecl{
+				Name:      name,
+				Fields:    parseFields(body),
+				StartLine: lineOf(content, loc[0]),
+				EndLine:   lineOf(content, min(end, len(content))),
+			})
+		}
+	}
+
+	return decls
+}
+
+// parseFields splits an object type body into its top-level members
+func parseFields(body string) []models.TypeField {
+	var fields []models.TypeField
+	for _, member := range splitTopLevel(body) {
+		m := fieldPattern.FindStringSubmatch(strings.TrimSpace(member))
+		if m == nil {
+			continue
+		}
+		fieldType := strings.TrimSpace(m[3])
+		fields = append(fields, models.TypeField{
+			Name:     m[1],
+			Type:     fieldType,
+			Optional: m[2] == "?",
+			Nullable: nullUnionPattern.MatchString(fieldType),
+		})
+	}
+	return fields
+}
+
+// splitTopLevel splits on ';', ',' and newlines that are not nested in brackets
+func splitTopLevel(body string) []string {
+	var members []string
+	depth, start := 0, 0
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '{', '[', '(', '<':
+			depth++
+		case '}', ']', ')', '>':
+			if depth > 0 {
+				depth--
+			}
+		case ';', ',', '\n':
+			if depth == 0 {
+				members = append(members, body[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(members, body[start:])
+}
+
+// DetectNullableFields returns the fields that may be null: fields typed
+// `T | null`, scoped to their type, and properties accessed with optional
+// chaining (`user.profile?.avatar` -> profile), scoped to the handler
+func DetectNullableFields(content string, types []models.TypeDecl, handlers []models.Handler) []models.NullableField {
+	fields := nullableTypeFields(types)
+	seen := make(map[models.NullableField]bool)
+	for _, loc := range optionalChainPattern.FindAllStringSubmatchIndex(content, -1) {
+		field := models.NullableField{Field: content[loc[2]:loc[3]], Method: "*"}
+		line := lineOf(content, loc[0])
+		for _, h := range handlers {
+			if line >= h.StartLine && line <= h.EndLine {
+				field.Method = h.Method
+				break
+			}
+		}
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	sortNullable(fields)
+	return fields
+}
+
+// nullableTypeFields returns the fields typed `T | null` in types
+func nullableTypeFields(types []models.TypeDecl) []models.NullableField {
+	var fields []models.NullableField
+	for _, decl := range types {
+		for _, field := range decl.Fields {
+			if field.Nullable {
+				fields = append(fields, models.NullableField{Field: field.Name, Type: decl.Name})
+			}
+		}
+	}
+	return fields
+}
+
+func sortNullable(fields []models.NullableField) {
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Method < b.Method
+	})
+}
@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// headers.get('X-API-Version'), req.headers['accept-version'], headers().get("api-version")
+	versionHeaderPattern = regexp.MustCompile(`(?i)headers(?:\(\))?(?:\??\.get\(\s*|\[\s*)['"` + "`" + `]((?:[\w-]*-)?version)['"` + "`" + `]\s*[\])]`)
+	versionAliasPattern  = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*=\s*(?:await\s+)?[\w$.()?]*$`)
+	versionFallback      = regexp.MustCompile(`^\s*(?:\?\?|\|\|)\s*['"]([^'"]+)['"]`)
+	versionLiteral       = regexp.MustCompile(`^\s*[!=]==?\s*['"]([^'"]+)['"]`)
+	versionCasePattern   = regexp.MustCompile(`\bcase\s+['"]([^'"]+)['"]\s*:`)
+)
+
+// DetectVersionHeader finds handlers that branch on an API version header
+// (Accept-Version, X-API-Version, ...) and the versions they compare against.
+// It returns nil when the header is read but never compared.
+func DetectVersionHeader(content string, handlers []models.Handler) *models.VersionHeader {
+	var header *models.VersionHeader
+	versions := make(map[string]bool)
+
+	for _, loc := range versionHeaderPattern.FindAllStringSubmatchIndex(content, -1) {
+		if header == nil {
+			header = &models.VersionHeader{Name: content[loc[2]:loc[3]]}
+		}
+		for _, h := range handlers {
+			if line := lineOf(content, loc[0]); line >= h.StartLine && line <= h.EndLine && !contains(header.Methods, h.Method) {
+				header.Methods = append(header.Methods, h.Method)
+			}
+		}
+
+		rest := content[loc[1]:]
+		if m := versionFallback.FindStringSubmatch(rest); m != nil {
+			header.Default = m[1]
+			versions[m[1]] = true
+		}
+		if m := versionLiteral.FindStringSubmatch(rest); m != nil {
+			versions[m[1]] = true
+		}
+
+		// const version = req.headers.get('x-api-version') ?? '1'
+		lineStart := strings.LastIndexByte(content[:loc[0]], '\n') + 1
+		if m := versionAliasPattern.FindStringSubmatch(content[lineStart:loc[0]]); m != nil {
+			collectVersionChecks(content, m[1], versions)
+		}
+	}
+
+	if header == nil || len(versions) == 0 {
+		return nil
+	}
+	for version := range versions {
+		header.Versions = append(header.Versions, version)
+	}
+	sort.Strings(header.Versions)
+	return header
+}
+
+// collectVersionChecks adds the literals a variable is compared with, directly
+// or as the cases of a switch on it
+func collectVersionChecks(content, alias string, versions map[string]bool) {
+	quoted := regexp.QuoteMeta(alias)
+	compare := regexp.MustCompile(`\b` + quoted + `\s*[!=]==?\s*['"]([^'"]+)['"]|['"]([^'"]+)['"]\s*[!=]==?\s*` + quoted + `\b`)
+	for _, m := range compare.FindAllStringSubmatch(content, -1) {
+		versions[firstNonEmpty(m[1], m[2])] = true
+	}
+
+	switchOn := regexp.MustCompile(`\bswitch\s*\(\s*` + quoted + `\s*\)\s*\{`)
+	for _, loc := range switchOn.FindAllStringIndex(content, -1) {
+		open := loc[1] - 1
+		body := content[open : open+closingBracket(content[open:])]
+		for _, m := range versionCasePattern.FindAllStringSubmatch(body, -1) {
+			versions[m[1]] = true
+		}
+	}
+}
@@ -0,0 +1,861 @@
+package scanner
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+var (
+	// const createUserSchema = z.object({, export const userSchema: z.ZodType<User> = base.extend({
+	zodDeclPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)(?:\s*:[^=;\n]+)?\s*=\s*(z\s*\.|[A-Za-z_$][\w$]*\s*\.\s*(?:extend|merge|pick|omit|partial|required|strict|passthrough|catchall|array|optional|nullable|nullish|or|and|describe|default|refine|superRefine|transform)\s*\()`)
+	zodHeadPattern = regexp.MustCompile(`^z\s*\.\s*(?:coerce\s*\.\s*)?(\w+)\s*\(`)
+	zodNamePattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*`)
+	zodChainCall   = regexp.MustCompile(`^\.\s*(\w+)\s*\(`)
+	zodShapeField  = regexp.MustCompile(`^\.\s*shape\s*(?:\.\s*([A-Za-z_$][\w$]*))?`)
+	zodObjectEntry = regexp.MustCompile(`^['"]?([A-Za-z_$][\w$-]*)['"]?\s*:\s*([\s\S]+)$`)
+	zodLiteral     = regexp.MustCompile(`^(?:'([^']*)'|"([^"]*)"|(-?\d+(?:\.\d+)?|true|false))$`)
+	// await req.json(), await request.json()
+	requestJSONPattern = regexp.MustCompile(`\b(?:req|request)\s*\.\s*json\s*\(`)
+	// const body = await req.json(), const body = req.body
+	bodyVarPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*(?::[^=;\n]+)?=\s*(?:await\s+(?:req|request)\s*\.\s*json\(\s*\)|(?:req|request)\s*\.\s*body\b)`)
+	// parseBody(req, createUserSchema), validateBody(schema, request); only calls
+	// that also take the request or its body count
+	bodyHelperPattern = regexp.MustCompile(`\b([A-Za-z_$][\w$]*(?:[Bb]ody|[Pp]ayload)[\w$]*)\s*\(`)
+	// NextResponse.json(...), Response.json(...), res.status(201).json(...)
+	jsonCallPattern = regexp.MustCompile(`\b(?:NextResponse|Response)\s*\.\s*json\s*\(|\bres\s*(?:\.\s*status\s*\(\s*(\d{3})\s*\))?\s*\.\s*json\s*\(`)
+	jsonStatus      = regexp.MustCompile(`\bstatus\s*:\s*(\d{3})\b`)
+	// z.infer<typeof userSchema>, z.output<typeof userSchema>
+	zodInferPattern = regexp.MustCompile(`^z\s*\.\s*(?:infer|output|input)\s*<\s*typeof\s+([A-Za-z_$][\w$]*)\s*>(\s*\[\s*\])?$`)
+	inferAlias      = regexp.MustCompile(`\btype\s+([A-Za-z_$][\w$]*)\s*=\s*(z\s*\.\s*(?:infer|output|input)\s*<\s*typeof\s+[A-Za-z_$][\w$]*\s*>)`)
+	typedVarPattern = regexp.MustCompile(`(?:const|let|var)\s+([A-Za-z_$][\w$]*)\s*:\s*([^=;\n]+?)\s*=`)
+	satisfiesType   = regexp.MustCompile(`\bsatisfies\s+([^,;)]+?)\s*$`)
+)
+
+// Names too generic to become a component name
+var genericSchemaNames = []string{"", "body", "data", "input", "payload", "query", "request", "response"}
+
+// zodDeclarations maps the Zod schemas declared in content to their expressions
+func zodDeclarations(content string) map[string]string {
+	content = blankComments(content)
+	declared := make(map[string]string)
+	for _, loc := range zodDeclPattern.FindAllStringSubmatchIndex(content, -1) {
+		start := loc[4]
+		declared[content[loc[2]:loc[3]]] = content[start : start+expressionEnd(content[start:])]
+	}
+	return declared
+}
+
+// moduleSchemas combines the Zod schemas declared in a file with those of the
+// modules it imports; the file's own win
+func moduleSchemas(content string, modules []*module) map[string]string {
+	declared := zodDeclarations(content)
+	for _, mod := range modules {
+		for name, expr := range mod.schemas {
+			if _, ok := declared[name]; !ok {
+				declared[name] = expr
+			}
+		}
+	}
+	return declared
+}
+
+// DetectZodSchemas finds the Zod schemas each handler parses its request body
+// with (`createUserSchema.parse(await req.json())`, body helpers taking the
+// schema) and those its JSON responses are built from (`userSchema.parse(user)`
+// or a value typed `z.infer<typeof userSchema>` passed to NextResponse.json),
+// translated to JSON Schema. declared holds the schemas of the file and the
+// modules it imports.
+func DetectZodSchemas(content string, handlers []models.Handler, declared map[string]string) []models.ZodSchema {
+	if len(declared) == 0 {
+		return nil
+	}
+	content = blankComments(content)
+
+	var found []models.ZodSchema
+	add := func(name, target string, status, offset int) {
+		t := &zodTranslator{declared: declared, resolving: make(map[string]bool), output: target == models.ZodResponse}
+		for _, method := range handlersAt(content, offset, handlers) {
+			if slices.ContainsFunc(found, func(z models.ZodSchema) bool {
+				return z.Method == method && z.Target == target && z.Status == status
+			}) {
+				continue // The first parse in a handler wins
+			}
+			if typ, ok := t.named(name); ok {
+				found = append(found, models.ZodSchema{
+					Method: method, Name: name, Target: target, Status: status,
+					Schema: typ.schema, Line: lineOf(content, offset),
+				})
+			}
+		}
+	}
+
+	bodyVars := make(map[string]bool)
+	for _, m := range bodyVarPattern.FindAllStringSubmatch(content, -1) {
+		bodyVars[m[1]] = true
+	}
+	for _, loc := range schemaParsePattern.FindAllStringSubmatchIndex(content, -1) {
+		name := content[loc[2]:loc[3]]
+		if _, ok := declared[name]; !ok {
+			continue
+		}
+		open := loc[1] - 1
+		if isBodySource(content[open+1:open+closingBracket(content[open:])], bodyVars) {
+			add(name, models.ZodRequestBody, 0, loc[0])
+		}
+	}
+	for _, loc := range bodyHelperPattern.FindAllStringSubmatchIndex(content, -1) {
+		open := loc[1] - 1
+		args := content[open+1 : open+closingBracket(content[open:])]
+		if !slices.ContainsFunc(splitArgs(args), func(arg string) bool {
+			arg = strings.TrimSpace(arg)
+			return arg == "req" || arg == "request" || isBodySource(arg, bodyVars)
+		}) {
+			continue // e.g. formatPayload(user, schema) on a response
+		}
+		for _, arg := range identifierPattern.FindAllString(args, -1) {
+			if _, ok := declared[arg]; ok {
+				add(arg, models.ZodRequestBody, 0, loc[0])
+				break
+			}
+		}
+	}
+
+	aliases := make(map[string]string)
+	for _, m := range inferAlias.FindAllStringSubmatch(content, -1) {
+		aliases[m[1]] = m[2]
+	}
+	typed := make(map[string]string)
+	for _, m := range typedVarPattern.FindAllStringSubmatch(content, -1) {
+		typed[m[1]] = m[2]
+	}
+	for _, loc := range jsonCallPattern.FindAllStringSubmatchIndex(content, -1) {
+		open := loc[1] - 1
+		args := splitArgs(content[open+1 : open+closingBracket(content[open:])])
+		if len(args) == 0 {
+			continue
+		}
+		status := 0
+		if loc[2] >= 0 {
+			status, _ = strconv.Atoi(content[loc[2]:loc[3]])
+		} else if len(args) > 1 {
+			if m := jsonStatus.FindStringSubmatch(args[1]); m != nil {
+				status, _ = strconv.Atoi(m[1])
+			}
+		}
+		if name, list := responseSchema(strings.TrimSpace(args[0]), declared, aliases, typed); name != "" {
+			if list {
+				name += "[]"
+			}
+			add(name, models.ZodResponse, status, loc[0])
+		}
+	}
+	return found
+}
+
+// handlersAt returns the methods whose handlers contain offset, or "*"
+func handlersAt(content string, offset int, handlers []models.Handler) []string {
+	line := lineOf(content, offset)
+	var methods []string
+	for _, h := range handlers {
+		if line >= h.StartLine && line <= h.EndLine {
+			methods = append(methods, h.Method)
+		}
+	}
+	if len(methods) == 0 {
+		return []string{"*"}
+	}
+	return methods
+}
+
+// isBodySource reports whether an expression is the parsed request body
+func isBodySource(expr string, bodyVars map[string]bool) bool {
+	expr = strings.TrimSpace(expr)
+	return bodyVars[expr] || requestJSONPattern.MatchString(expr) ||
+		expr == "req.body" || expr == "request.body"
+}
+
+// responseSchema names the schema a response body is built from, and whether
+// the body is a list of it
+func responseSchema(arg string, declared, aliases, typed map[string]string) (string, bool) {
+	for _, loc := range schemaParsePattern.FindAllStringSubmatchIndex(arg, -1) {
+		if name := arg[loc[2]:loc[3]]; declared[name] != "" {
+			return name, false
+		}
+	}
+	typeExpr := ""
+	if m := satisfiesType.FindStringSubmatch(arg); m != nil {
+		typeExpr = m[1]
+	} else if zodNamePattern.FindString(arg) == arg {
+		typeExpr = typed[arg]
+	}
+	typeExpr = strings.TrimSpace(typeExpr)
+	list := strings.HasSuffix(typeExpr, "[]")
+	if alias, ok := aliases[strings.TrimSpace(strings.TrimSuffix(typeExpr, "[]"))]; ok {
+		typeExpr = alias
+	} else {
+		list = false
+	}
+	if m := zodInferPattern.FindStringSubmatch(typeExpr); m != nil && declared[m[1]] != "" {
+		return m[1], list || m[2] != ""
+	}
+	return "", false
+}
+
+// zodTranslator turns Zod expressions into JSON Schemas, resolving the schemas
+// they refer to by name
+type zodTranslator struct {
+	declared  map[string]string
+	resolving map[string]bool // Names being translated, to stop at recursive schemas
+	output    bool            // Translating parsed output, where defaulted fields are always set
+}
+
+// zodType is a translated schema and whether an object field of it may be absent
+type zodType struct {
+	schema   map[string]interface{}
+	optional bool
+}
+
+// named translates a declared schema, titled after its name so the builder
+// can hoist it to components. A trailing [] asks for a list of it.
+func (t *zodTranslator) named(name string) (zodType, bool) {
+	if base, ok := strings.CutSuffix(name, "[]"); ok {
+		typ, ok := t.named(base)
+		return zodType{schema: map[string]interface{}{"type": "array", "items": typ.schema}}, ok
+	}
+	expr, ok := t.declared[name]
+	if !ok {
+		return zodType{}, false
+	}
+	if t.resolving[name] {
+		return zodType{schema: map[string]interface{}{}}, true
+	}
+	t.resolving[name] = true
+	defer delete(t.resolving, name)
+
+	typ, ok := t.translate(expr)
+	if ok && typ.schema["type"] == "object" {
+		if title := schemaTitle(name); title != "" {
+			typ.schema["title"] = title
+		}
+	}
+	return typ, ok
+}
+
+// translate reads a base schema, z.string() or a declared name, and applies
+// the method chain that follows it
+func (t *zodTranslator) translate(expr string) (zodType, bool) {
+	expr = strings.TrimSpace(expr)
+	var typ zodType
+	var rest string
+	if m := zodHeadPattern.FindStringSubmatch(expr); m != nil {
+		open := len(m[0]) - 1
+		end := open + closingBracket(expr[open:])
+		if end <= open || end >= len(expr) {
+			return zodType{}, false
+		}
+		var ok bool
+		if typ, ok = t.base(m[1], expr[open+1:end]); !ok {
+			return zodType{}, false
+		}
+		rest = expr[end+1:]
+	} else if name := zodNamePattern.FindString(expr); name != "" {
+		var ok bool
+		if typ, ok = t.named(name); !ok {
+			return zodType{}, false
+		}
+		rest = expr[len(name):]
+	} else {
+		return zodType{}, false
+	}
+
+	for {
+		rest = strings.TrimSpace(rest)
+		if m := zodShapeField.FindStringSubmatch(rest); m != nil && !zodChainCall.MatchString(rest) {
+			// userSchema.shape.email
+			if m[1] != "" {
+				properties, _ := typ.schema["properties"].(map[string]interface{})
+				field, ok := properties[m[1]].(map[string]interface{})
+				if !ok {
+					return zodType{}, false
+				}
+				typ = zodType{schema: field, optional: !slices.Contains(requiredOf(typ.schema), m[1])}
+			}
+			rest = rest[len(m[0]):]
+			continue
+		}
+		m := zodChainCall.FindStringSubmatch(rest)
+		if m == nil {
+			return typ, true
+		}
+		open := len(m[0]) - 1
+		end := open + closingBracket(rest[open:])
+		if end <= open || end >= len(rest) {
+			return typ, true
+		}
+		typ = t.chain(typ, m[1], strings.TrimSpace(rest[open+1:end]))
+		rest = rest[end+1:]
+	}
+}
+
+// base translates z.<kind>(args)
+func (t *zodTranslator) base(kind, args string) (zodType, bool) {
+	schema := func(pairs ...interface{}) zodType {
+		s := make(map[string]interface{})
+		for i := 0; i+1 < len(pairs); i += 2 {
+			s[pairs[i].(string)] = pairs[i+1]
+		}
+		return zodType{schema: s}
+	}
+	list := splitArgs(args)
+	arg := func(i int) string {
+		if i >= 0 && i < len(list) {
+			return strings.TrimSpace(list[i])
+		}
+		return ""
+	}
+
+	switch kind {
+	case "string":
+		return schema("type", "string"), true
+	case "number":
+		return schema("type", "number"), true
+	case "int", "bigint":
+		return schema("type", "integer"), true
+	case "boolean":
+		return schema("type", "boolean"), true
+	case "date":
+		return schema("type", "string", "format", "date-time"), true
+	case "null":
+		return schema("nullable", true), true
+	case "undefined", "void", "never":
+		typ := schema()
+		typ.optional = true
+		return typ, true
+	case "any", "unknown", "nativeEnum", "custom", "function":
+		return schema(), true
+	case "instanceof":
+		if arg(0) == "File" || arg(0) == "Blob" {
+			return schema("type", "string", "format", "binary"), true
+		}
+		return schema(), true
+	case "literal":
+		value, typ := zodValue(arg(0))
+		if typ == "" {
+			return schema(), true
+		}
+		return schema("type", typ, "enum", []interface{}{value}), true
+	case "enum":
+		var values []interface{}
+		for _, item := range listItems(arg(0)) {
+			if value, typ := zodValue(item); typ == "string" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return schema("type", "string"), true
+		}
+		return schema("type", "string", "enum", values), true
+	case "object", "strictObject", "looseObject":
+		typ := schema("type", "object", "properties", map[string]interface{}{})
+		t.addFields(typ.schema, arg(0))
+		if kind == "strictObject" {
+			typ.schema["additionalProperties"] = false
+		}
+		return typ, true
+	case "array", "set":
+		items, ok := t.translate(arg(0))
+		if !ok {
+			return zodType{}, false
+		}
+		typ := schema("type", "array", "items", items.schema)
+		if kind == "set" {
+			typ.schema["uniqueItems"] = true
+		}
+		return typ, true
+	case "record", "map":
+		values, ok := t.translate(arg(len(list) - 1))
+		if !ok {
+			return zodType{}, false
+		}
+		return schema("type", "object", "additionalProperties", values.schema), true
+	case "tuple":
+		var items []zodType
+		for _, item := range listItems(arg(0)) {
+			if typ, ok := t.translate(item); ok {
+				items = append(items, typ)
+			}
+		}
+		element := unionOf(items).schema
+		return schema("type", "array", "items", element, "minItems", len(items), "maxItems", len(items)), true
+	case "union", "discriminatedUnion", "xor":
+		var members []zodType
+		for _, item := range listItems(arg(len(list) - 1)) {
+			typ, ok := t.translate(item)
+			if !ok {
+				return schema(), true // Unknown members leave the union open
+			}
+			members = append(members, typ)
+		}
+		return unionOf(members), true
+	case "intersection":
+		left, okLeft := t.translate(arg(0))
+		right, okRight := t.translate(arg(1))
+		if !okLeft || !okRight {
+			return schema(), true
+		}
+		return schema("allOf", []interface{}{left.schema, right.schema}), true
+	case "optional", "nullable", "nullish":
+		inner, ok := t.translate(arg(0))
+		if !ok {
+			return zodType{}, false
+		}
+		return t.chain(inner, kind, ""), true
+	case "lazy":
+		// z.lazy(() => categorySchema)
+		if _, body, ok := strings.Cut(arg(0), "=>"); ok {
+			if typ, ok := t.translate(body); ok {
+				return typ, true
+			}
+		}
+		return schema(), true
+	case "preprocess", "pipeline":
+		return t.translate(arg(len(list) - 1))
+	}
+	if format, ok := zodFormats[kind]; ok {
+		// Zod 4 top-level formats, e.g. z.email()
+		return schema("type", "string", "format", format), true
+	}
+	return zodType{}, false
+}
+
+// addFields adds the entries of an object literal to an object schema
+func (t *zodTranslator) addFields(schema map[string]interface{}, body string) {
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, "{") {
+		return
+	}
+	properties := schema["properties"].(map[string]interface{})
+	required := requiredOf(schema)
+	for _, entry := range splitArgs(body[1 : len(body)-1]) {
+		entry = strings.TrimSpace(entry)
+		var name, expr string
+		switch m := zodObjectEntry.FindStringSubmatch(entry); {
+		case strings.HasPrefix(entry, "..."):
+			// ...baseSchema.shape
+			if spread, ok := t.translate(strings.TrimPrefix(entry, "...")); ok {
+				mergeObject(schema, spread.schema)
+				required = requiredOf(schema)
+			}
+			continue
+		case m != nil:
+			name, expr = m[1], m[2]
+		case zodNamePattern.FindString(entry) == entry && entry != "":
+			name, expr = entry, entry // Shorthand for a schema of the same name
+		default:
+			continue
+		}
+		field, ok := t.translate(expr)
+		if !ok {
+			field = zodType{schema: map[string]interface{}{}}
+		}
+		properties[name] = field.schema
+		required = slices.DeleteFunc(required, func(r string) bool { return r == name })
+		if !field.optional {
+			required = append(required, name)
+		}
+	}
+	setRequired(schema, required)
+}
+
+// chain applies one method of a chain such as .min(1) or .optional()
+func (t *zodTranslator) chain(typ zodType, method, args string) zodType {
+	schema := typ.schema
+	number := func() (float64, bool) {
+		n, err := strconv.ParseFloat(strings.TrimSpace(strings.SplitN(args, ",", 2)[0]), 64)
+		return n, err == nil
+	}
+	kind, _ := schema["type"].(string)
+
+	switch method {
+	case "optional":
+		typ.optional = true
+	case "nullable":
+		schema["nullable"] = true
+	case "nullish":
+		schema["nullable"] = true
+		typ.optional = true
+	case "catch", "prefault":
+		typ.optional = !t.output
+	case "default":
+		typ.optional = !t.output
+		if value, valueType := zodValue(args); valueType != "" {
+			schema["default"] = value
+		}
+	case "describe":
+		if value, valueType := zodValue(args); valueType == "string" {
+			schema["description"] = value
+		}
+	case "int":
+		schema["type"] = "integer"
+	case "regex":
+		schema["pattern"] = regexLiteral(strings.TrimSpace(strings.SplitN(args, ",", 2)[0]))
+	case "min", "gte", "max", "lte", "length", "nonempty", "gt", "lt", "positive", "negative", "nonnegative", "nonpositive":
+		n, ok := number()
+		switch method {
+		case "positive", "negative", "nonnegative", "nonpositive", "nonempty":
+			n, ok = 0, true
+		}
+		if !ok {
+			break
+		}
+		switch kind {
+		case "string", "array":
+			minKey, maxKey := "minLength", "maxLength"
+			if kind == "array" {
+				minKey, maxKey = "minItems", "maxItems"
+			}
+			switch method {
+			case "min", "gte":
+				schema[minKey] = int(n)
+			case "max", "lte":
+				schema[maxKey] = int(n)
+			case "length":
+				schema[minKey], schema[maxKey] = int(n), int(n)
+			case "nonempty":
+				schema[minKey] = 1
+			}
+		case "number", "integer":
+			switch method {
+			case "min", "gte", "nonnegative":
+				schema["minimum"] = n
+			case "max", "lte", "nonpositive":
+				schema["maximum"] = n
+			case "gt", "positive":
+				schema["minimum"], schema["exclusiveMinimum"] = n, true
+			case "lt", "negative":
+				schema["maximum"], schema["exclusiveMaximum"] = n, true
+			}
+		}
+	case "multipleOf", "step":
+		if n, ok := number(); ok {
+			schema["multipleOf"] = n
+		}
+	case "array":
+		return zodType{schema: map[string]interface{}{"type": "array", "items": schema}}
+	case "or":
+		if other, ok := t.translate(args); ok {
+			return unionOf([]zodType{typ, other})
+		}
+		return zodType{schema: map[string]interface{}{}, optional: typ.optional}
+	case "and":
+		if other, ok := t.translate(args); ok {
+			return zodType{schema: map[string]interface{}{"allOf": []interface{}{schema, other.schema}}, optional: typ.optional}
+		}
+	case "extend", "safeExtend":
+		if kind == "object" {
+			delete(schema, "title")
+			t.addFields(schema, args)
+		}
+	case "merge":
+		if other, ok := t.translate(args); ok && kind == "object" {
+			delete(schema, "title")
+			mergeObject(schema, other.schema)
+		}
+	case "pick", "omit":
+		if kind == "object" {
+			delete(schema, "title")
+			keys := objectKeys(args)
+			properties, _ := schema["properties"].(map[string]interface{})
+			keep := func(name string) bool { return slices.Contains(keys, name) == (method == "pick") }
+			for name := range properties {
+				if !keep(name) {
+					delete(properties, name)
+				}
+			}
+			setRequired(schema, slices.DeleteFunc(requiredOf(schema), func(name string) bool { return !keep(name) }))
+		}
+	case "partial", "required":
+		if kind == "object" {
+			delete(schema, "title")
+			properties, _ := schema["properties"].(map[string]interface{})
+			keys := objectKeys(args)
+			if len(keys) == 0 {
+				for name := range properties {
+					keys = append(keys, name)
+				}
+			}
+			required := slices.DeleteFunc(requiredOf(schema), func(name string) bool { return slices.Contains(keys, name) })
+			if method == "required" {
+				required = append(required, keys...)
+			}
+			setRequired(schema, required)
+		}
+	case "strict":
+		if kind == "object" {
+			schema["additionalProperties"] = false
+		}
+	case "catchall":
+		if other, ok := t.translate(args); ok && kind == "object" {
+			schema["additionalProperties"] = other.schema
+		}
+	case "keyof":
+		properties, _ := schema["properties"].(map[string]interface{})
+		var names []interface{}
+		for _, name := range slices.Sorted(maps.Keys(properties)) {
+			names = append(names, name)
+		}
+		return zodType{schema: map[string]interface{}{"type": "string", "enum": names}}
+	default:
+		if format, ok := zodFormats[method]; ok && kind == "string" {
+			schema["format"] = format
+		}
+		// refine, transform, trim, brand and the like keep the shape
+	}
+	return typ
+}
+
+// unionOf combines the members of a union: null members make the rest
+// nullable and unions of literals of one type become an enum
+func unionOf(members []zodType) zodType {
+	var union zodType
+	var options []map[string]interface{}
+	nullable := false
+	for _, m := range members {
+		union.optional = union.optional || m.optional
+		if len(m.schema) == 1 && m.schema["nullable"] == true {
+			nullable = true
+			continue
+		}
+		options = append(options, m.schema)
+	}
+
+	switch {
+	case len(options) == 0:
+		union.schema = map[string]interface{}{}
+	case len(options) == 1:
+		union.schema = options[0]
+	default:
+		var values []interface{}
+		for _, option := range options {
+			enum, _ := option["enum"].([]interface{})
+			if enum == nil || option["type"] != options[0]["type"] {
+				values = nil
+				break
+			}
+			values = append(values, enum...)
+		}
+		if values != nil {
+			union.schema = map[string]interface{}{"type": options[0]["type"], "enum": values}
+		} else {
+			oneOf := make([]interface{}, len(options))
+			for i, option := range options {
+				oneOf[i] = option
+			}
+			union.schema = map[string]interface{}{"oneOf": oneOf}
+		}
+	}
+	if nullable {
+		union.schema["nullable"] = true
+	}
+	return union
+}
+
+// mergeObject adds the properties and required fields of other to schema
+func mergeObject(schema, other map[string]interface{}) {
+	properties, _ := schema["properties"].(map[string]interface{})
+	otherProperties, _ := other["properties"].(map[string]interface{})
+	if properties == nil {
+		return
+	}
+	required := requiredOf(schema)
+	for name, property := range otherProperties {
+		properties[name] = property
+		required = slices.DeleteFunc(required, func(r string) bool { return r == name })
+		if slices.Contains(requiredOf(other), name) {
+			required = append(required, name)
+		}
+	}
+	setRequired(schema, required)
+}
+
+func requiredOf(schema map[string]interface{}) []string {
+	required, _ := schema["required"].([]string)
+	return slices.Clone(required)
+}
+
+func setRequired(schema map[string]interface{}, required []string) {
+	if len(required) == 0 {
+		delete(schema, "required")
+		return
+	}
+	slices.Sort(required)
+	schema["required"] = slices.Compact(required)
+}
+
+// objectKeys lists the keys of a mask such as { id: true, createdAt: true }
+func objectKeys(mask string) []string {
+	mask = strings.TrimSpace(mask)
+	if !strings.HasPrefix(mask, "{") {
+		return nil
+	}
+	var keys []string
+	for _, entry := range splitArgs(mask[1 : len(mask)-1]) {
+		if m := zodObjectEntry.FindStringSubmatch(strings.TrimSpace(entry)); m != nil {
+			keys = append(keys, m[1])
+		}
+	}
+	return keys
+}
+
+// zodValue reads a string, number or boolean literal and its JSON type
+func zodValue(s string) (interface{}, string) {
+	m := zodLiteral.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, ""
+	}
+	switch value := literal(m[1], m[2], m[3]).(type) {
+	case bool:
+		return value, "boolean"
+	case float64:
+		if value == float64(int64(value)) {
+			return int64(value), "integer"
+		}
+		return value, "number"
+	default:
+		if m[1] == "" && m[2] == "" {
+			return "", "string" // An empty string literal
+		}
+		return value, "string"
+	}
+}
+
+// schemaTitle names the component of a schema variable: createUserSchema -> CreateUser
+func schemaTitle(name string) string {
+	for _, suffix := range []string{"Schema", "Validator", "schema"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+	if slices.Contains(genericSchemaNames, strings.ToLower(name)) {
+		return ""
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// listItems splits an array literal such as ['a', 'b'] into its items
+func listItems(s string) []string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "[") {
+		return nil
+	}
+	end := closingBracket(s)
+	if end <= 0 {
+		return nil
+	}
+	items := splitArgs(s[1:end])
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// splitArgs splits on commas that are not nested in brackets or strings,
+// dropping empty pieces such as the one after a trailing comma
+func splitArgs(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return slices.DeleteFunc(parts, func(p string) bool { return strings.TrimSpace(p) == "" })
+}
+
+// expressionEnd returns where the expression starting s ends: a semicolon, or
+// a line break not followed by a chained call
+func expressionEnd(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(' || c == '[' || c == '{':
+			depth++
+		case c == ')' || c == ']' || c == '}':
+			depth--
+			if depth < 0 {
+				return i
+			}
+		case depth == 0 && c == ';':
+			return i
+		case depth == 0 && c == '\n':
+			if next := strings.TrimLeft(s[i:], " \t\r\n"); !strings.HasPrefix(next, ".") {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// blankComments replaces comments with spaces, keeping offsets and line numbers
+func blankComments(content string) string {
+	b := []byte(content)
+	var quote byte
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote || c == '\n' && quote != '`' {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(b) && b[i+1] == '/' && (i == 0 || b[i-1] != '\\'):
+			for ; i < len(b) && b[i] != '\n'; i++ {
+				b[i] = ' '
+			}
+		case c == '/' && i+1 < len(b) && b[i+1] == '*':
+			for ; i < len(b) && !(b[i] == '*' && i+1 < len(b) && b[i+1] == '/'); i++ {
+				if b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			if i+1 < len(b) {
+				b[i], b[i+1] = ' ', ' '
+				i++
+			}
+		}
+	}
+	return string(b)
+}
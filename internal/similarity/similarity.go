@@ -0,0 +1,139 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Dimensions is the length of every embedding
+const Dimensions = 512
+
+// MinScore is the cosine similarity below which routes are not considered alike
+const MinScore = 0.5
+
+// Vector is a unit-length embedding of a source file
+type Vector []float64
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_$][A-Za-z0-9_$]*`)
+
+// Words that appear in every route file and say nothing about what it does
+var stopWords = map[string]bool{
+	"const": true, "let": true, "var": true, "function": true, "async": true, "await": true,
+	"return": true, "export": true, "import": true, "from": true, "new": true, "if": true,
+	"else": true, "try": true, "catch": true, "throw": true, "type": true, "interface": true,
+	"string": true, "number": true, "boolean": true, "null": true, "undefined": true, "true": true,
+	"false": true, "next": true, "request": true, "response": true, "req": true, "res": true,
+	"json": true, "status": true, "error": true, "the": true, "of": true, "and": true, "to": true,
+}
+
+// Embed turns source code into a vector of hashed identifier words, weighted by
+// log frequency, so files using the same names, calls and fields end up close.
+// It runs locally and gives the same vector for the same content.
+func Embed(content string) Vector {
+	counts := make(map[string]int)
+	for _, identifier := range identifierPattern.FindAllString(content, -1) {
+		for _, word := range splitWords(identifier) {
+			if len(word) > 1 && !stopWords[word] {
+				counts[word]++
+			}
+		}
+	}
+
+	vector := make(Vector, Dimensions)
+	for word, count := range counts {
+		h := fnv.New32a()
+		h.Write([]byte(word))
+		sum := h.Sum32()
+		weight := 1 + math.Log(float64(count))
+		// The top bit picks a sign so colliding words tend to cancel out
+		if sum&(1<<31) != 0 {
+			weight = -weight
+		}
+		vector[sum%Dimensions] += weight
+	}
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm > 0 {
+		norm = math.Sqrt(norm)
+		for i := range vector {
+			vector[i] /= norm
+		}
+	}
+	return vector
+}
+
+// Cosine returns the similarity of two embeddings, from -1 to 1
+func Cosine(a, b Vector) float64 {
+	var dot float64
+	for i := range min(len(a), len(b)) {
+		dot += a[i] * b[i]
+	}
+	return dot
+}
+
+// splitWords splits camelCase and snake_case identifiers into lowercase words
+func splitWords(identifier string) []string {
+	var words []string
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, strings.ToLower(current.String()))
+			current.Reset()
+		}
+	}
+	runes := []rune(identifier)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '$':
+			flush()
+			continue
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+		}
+		current.WriteRune(r)
+	}
+	flush()
+	return words
+}
+
+// Index finds the most similar of a set of embedded documents
+type Index struct {
+	keys    []string
+	vectors []Vector
+}
+
+// Add embeds a document under key
+func (idx *Index) Add(key, content string) {
+	idx.keys = append(idx.keys, key)
+	idx.vectors = append(idx.vectors, Embed(content))
+}
+
+// Len returns the number of documents in the index
+func (idx *Index) Len() int {
+	return len(idx.keys)
+}
+
+// Nearest returns the key of the document most similar to content, other than
+// exclude, if any scores at least MinScore. Ties go to the document added first.
+func (idx *Index) Nearest(content, exclude string) (string, float64, bool) {
+	vector := Embed(content)
+	best, bestScore := -1, MinScore
+	for i, candidate := range idx.vectors {
+		if idx.keys[i] == exclude {
+			continue
+		}
+		if score := Cosine(vector, candidate); score >= bestScore && (best < 0 || score > bestScore) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return "", 0, false
+	}
+	return idx.keys[best], bestScore, true
+}
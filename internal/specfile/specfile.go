@@ -0,0 +1,134 @@
+package specfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrSpecInvalid is matched by errors for files that are not a usable spec
+var ErrSpecInvalid = errors.New("invalid spec")
+
+// InvalidError is a spec file that could not be parsed. It matches ErrSpecInvalid.
+type InvalidError struct {
+	Path string
+	Err  error
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("failed to parse spec %s: %v", e.Path, e.Err)
+}
+
+func (e *InvalidError) Unwrap() []error {
+	return []error{ErrSpecInvalid, e.Err}
+}
+
+// IsYAML reports whether a file name calls for YAML rather than JSON
+func IsYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Read loads a JSON or YAML spec (chosen by extension) into a generic map
+func Read(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+
+	var spec map[string]interface{}
+	if IsYAML(path) {
+		var raw interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, &InvalidError{Path: path, Err: err}
+		}
+		spec, _ = normalize(raw).(map[string]interface{})
+		if spec == nil {
+			return nil, &InvalidError{Path: path, Err: errors.New("not a YAML mapping")}
+		}
+		return spec, nil
+	}
+
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, &InvalidError{Path: path, Err: err}
+	}
+	return spec, nil
+}
+
+// Write saves a spec as indented JSON, or YAML when the extension says so
+func Write(path string, spec interface{}) error {
+	data, err := Encode(path, spec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Encode returns the bytes Write would save for path
+func Encode(path string, spec interface{}) ([]byte, error) {
+	if !IsYAML(path) {
+		return json.MarshalIndent(spec, "", "  ")
+	}
+
+	// Round-trip through JSON so struct tags and omitempty apply. Decoding
+	// into a node rather than a map keeps the keys in the JSON's order.
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	blockStyle(&doc)
+	return MarshalYAML(&doc)
+}
+
+// blockStyle drops the flow and quoting styles a node picked up from JSON
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, child := range n.Content {
+		blockStyle(child)
+	}
+}
+
+// MarshalYAML encodes a generic value as YAML with two-space indentation
+func MarshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	err := encoder.Close()
+	return buf.Bytes(), err
+}
+
+// normalize converts YAML's non-string map keys (e.g. unquoted 200) to strings
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = normalize(child)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			m[fmt.Sprint(k)] = normalize(child)
+		}
+		return m
+	case []interface{}:
+		for i, child := range v {
+			v[i] = normalize(child)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,76 @@
+package specfile
+
+import (
+	"strings"
+	"testing"
+)
+
+type orderedSpec struct {
+	OpenAPI    string                 `json:"openapi"`
+	Info       map[string]interface{} `json:"info"`
+	Paths      map[string]interface{} `json:"paths"`
+	Components map[string]interface{} `json:"components,omitempty"`
+}
+
+func TestEncodeYAMLKeepsKeyOrder(t *testing.T) {
+	spec := orderedSpec{
+		OpenAPI: "3.0.3",
+		Info:    map[string]interface{}{"title": "API", "version": "1.0.0"},
+		Paths: map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get": map[string]interface{}{
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "OK"},
+					},
+				},
+			},
+		},
+		Components: map[string]interface{}{"schemas": map[string]interface{}{}},
+	}
+
+	data, err := Encode("openapi.yaml", spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+
+	var top []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" && line[0] != ' ' {
+			top = append(top, strings.SplitN(line, ":", 2)[0])
+		}
+	}
+	want := []string{"openapi", "info", "paths", "components"}
+	if strings.Join(top, ",") != strings.Join(want, ",") {
+		t.Errorf("top-level keys = %v, want %v\n%s", top, want, out)
+	}
+	if !strings.Contains(out, `"200":`) {
+		t.Errorf("status code key should stay a quoted string:\n%s", out)
+	}
+	if strings.Contains(out, `"title"`) || strings.Contains(out, "{description") {
+		t.Errorf("expected block style YAML without JSON quoting:\n%s", out)
+	}
+}
+
+func TestEncodeYAMLRoundTrips(t *testing.T) {
+	spec := map[string]interface{}{
+		"openapi": "3.1.0",
+		"info":    map[string]interface{}{"title": "API", "version": "1.0.0"},
+		"paths":   map[string]interface{}{},
+	}
+	data, err := Encode("spec.yml", spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := t.TempDir() + "/spec.yml"
+	if err := Write(path, spec); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v\n%s", err, data)
+	}
+	if got["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v, want 3.1.0", got["openapi"])
+	}
+}
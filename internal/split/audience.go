@@ -0,0 +1,69 @@
+package split
+
+import (
+	"fmt"
+	"maps"
+)
+
+// Audiences a spec can be filtered for
+const (
+	AudienceAll      = "all"      // Every operation
+	AudiencePublic   = "public"   // Operations without x-internal
+	AudienceInternal = "internal" // Only operations marked x-internal
+)
+
+// Audience returns a copy of spec holding only the operations meant for the
+// audience, with the components and tags they use
+func Audience(spec map[string]interface{}, audience string) (map[string]interface{}, error) {
+	switch audience {
+	case AudienceAll:
+		return spec, nil
+	case AudiencePublic, AudienceInternal:
+	default:
+		return nil, fmt.Errorf("unknown audience %q (expected public, internal or all)", audience)
+	}
+
+	filtered := make(map[string]interface{})
+	for key, value := range spec {
+		if key != "paths" && key != "components" && key != "tags" {
+			filtered[key] = value
+		}
+	}
+
+	kept := make(map[string]interface{})
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, value := range paths {
+		item, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result := maps.Clone(item)
+		operations := 0
+		for method, op := range item {
+			operation, ok := op.(map[string]interface{})
+			if !ok || !isOperation(method) {
+				continue
+			}
+			if (operation["x-internal"] == true) != (audience == AudienceInternal) {
+				delete(result, method)
+				continue
+			}
+			operations++
+		}
+		if operations > 0 {
+			kept[path] = result
+		}
+	}
+	filtered["paths"] = kept
+
+	keepUsed(filtered, spec)
+	return filtered, nil
+}
+
+func isOperation(key string) bool {
+	switch key {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+	return false
+}
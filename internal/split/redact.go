@@ -0,0 +1,205 @@
+package split
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Redaction actions
+const (
+	RedactStrip = "strip" // Remove the property
+	RedactMask  = "mask"  // Keep the property but only its type
+)
+
+// maskedDescription replaces the description of a masked property
+const maskedDescription = "Not documented publicly."
+
+// Redact returns a copy of spec with the fields matched by the rules stripped or
+// masked, and how many properties were changed. A rule's field is a property
+// name matched in every schema, or Schema.property for one component. Example
+// values are redacted with the schemas that describe them, so generated and
+// documented examples do not show what the schema hides.
+func Redact(spec map[string]interface{}, rules []models.RedactionRule) (map[string]interface{}, int, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, 0, err
+	}
+	var redacted map[string]interface{}
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, 0, err
+	}
+
+	count := 0
+	components, _ := redacted["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, rule := range rules {
+		if schemaName, field, ok := strings.Cut(rule.Field, "."); ok {
+			count += redactIn(schemas[schemaName], field, rule.Action, false)
+			redactExamplesOf(redacted, "#/components/schemas/"+schemaName, field, rule.Action)
+			continue
+		}
+		count += redactIn(redacted["paths"], rule.Field, rule.Action, true)
+		count += redactIn(schemas, rule.Field, rule.Action, true)
+	}
+	return redacted, count, nil
+}
+
+// redactIn redacts field in the properties of node, and of every schema nested
+// in it when deep is set
+func redactIn(node interface{}, field, action string, deep bool) int {
+	count := 0
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if properties, ok := v["properties"].(map[string]interface{}); ok {
+			if prop, ok := properties[field].(map[string]interface{}); ok {
+				count++
+				if action == RedactMask {
+					properties[field] = masked(prop)
+				} else {
+					delete(properties, field)
+					if required, ok := v["required"].([]interface{}); ok {
+						required = slices.DeleteFunc(required, func(r interface{}) bool { return r == field })
+						if len(required) == 0 {
+							delete(v, "required") // An empty list is invalid in 3.0
+						} else {
+							v["required"] = required
+						}
+					}
+				}
+			}
+		}
+		redactExamples(v, field, action)
+		if deep {
+			for key, child := range v {
+				if key != "example" && key != "examples" {
+					count += redactIn(child, field, action, deep)
+				}
+			}
+		}
+	case []interface{}:
+		if deep {
+			for _, child := range v {
+				count += redactIn(child, field, action, deep)
+			}
+		}
+	}
+	return count
+}
+
+// redactExamples redacts field in the example data of a schema, parameter or
+// media type: "example", and the values of "examples"
+func redactExamples(node map[string]interface{}, field, action string) {
+	if example, ok := node["example"]; ok {
+		node["example"] = redactValue(example, field, action)
+	}
+	switch examples := node["examples"].(type) {
+	case map[string]interface{}:
+		// Media type examples by name, each with a value
+		for _, e := range examples {
+			if e, ok := e.(map[string]interface{}); ok {
+				if value, ok := e["value"]; ok {
+					e["value"] = redactValue(value, field, action)
+				}
+			}
+		}
+	case []interface{}:
+		// Schema examples in 3.1
+		for i, value := range examples {
+			examples[i] = redactValue(value, field, action)
+		}
+	}
+}
+
+// redactExamplesOf redacts field in the examples of every media type and
+// parameter whose schema refers to ref, anywhere in spec
+func redactExamplesOf(node interface{}, ref, field, action string) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if schema, ok := v["schema"]; ok && refersTo(schema, ref) {
+			redactExamples(v, field, action)
+		}
+		for key, child := range v {
+			if key != "example" && key != "examples" {
+				redactExamplesOf(child, ref, field, action)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			redactExamplesOf(child, ref, field, action)
+		}
+	}
+}
+
+func refersTo(schema interface{}, ref string) bool {
+	switch v := schema.(type) {
+	case map[string]interface{}:
+		if v["$ref"] == ref {
+			return true
+		}
+		for _, child := range v {
+			if refersTo(child, ref) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if refersTo(child, ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue strips or masks field in every object of example data. A masked
+// value becomes the empty value of its type.
+func redactValue(value interface{}, field, action string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if fieldValue, ok := v[field]; ok {
+			if action == RedactMask {
+				v[field] = emptyValue(fieldValue)
+			} else {
+				delete(v, field)
+			}
+		}
+		for key, child := range v {
+			v[key] = redactValue(child, field, action)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = redactValue(child, field, action)
+		}
+	}
+	return value
+}
+
+func emptyValue(value interface{}) interface{} {
+	switch value.(type) {
+	case string:
+		return ""
+	case float64:
+		return 0
+	case bool:
+		return false
+	case map[string]interface{}:
+		return map[string]interface{}{}
+	case []interface{}:
+		return []interface{}{}
+	}
+	return nil
+}
+
+// masked keeps only the shape of a property: its type and nullability
+func masked(prop map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"description": maskedDescription}
+	for _, key := range []string{"type", "nullable"} {
+		if value, ok := prop[key]; ok {
+			result[key] = value
+		}
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+package split
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Part is the spec written for one output rule
+type Part struct {
+	Output models.SpecOutput
+	Spec   map[string]interface{}
+	Paths  int
+}
+
+// Split distributes the paths of a spec over the output rules; the first rule
+// whose pattern matches a path gets it. Each part keeps only the components and
+// tags its operations use. Paths no rule matches are returned sorted.
+func Split(spec map[string]interface{}, outputs []models.SpecOutput) ([]Part, []string, error) {
+	patterns := make([]*regexp.Regexp, len(outputs))
+	for i, output := range outputs {
+		pattern, err := Compile(output.Paths)
+		if err != nil {
+			return nil, nil, err
+		}
+		patterns[i] = pattern
+	}
+
+	parts := make([]Part, len(outputs))
+	for i, output := range outputs {
+		parts[i] = Part{Output: output, Spec: skeleton(spec, output)}
+	}
+
+	var unmatched []string
+	paths, _ := spec["paths"].(map[string]interface{})
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		i := slices.IndexFunc(patterns, func(p *regexp.Regexp) bool { return p.MatchString(path) })
+		if i < 0 {
+			unmatched = append(unmatched, path)
+			continue
+		}
+		parts[i].Spec["paths"].(map[string]interface{})[path] = paths[path]
+		parts[i].Paths++
+	}
+
+	for i := range parts {
+		keepUsed(parts[i].Spec, spec)
+	}
+	return parts, unmatched, nil
+}
+
+// Compile turns a path glob into a regular expression: `*` matches within one
+// segment and `**` matches any number of segments, so "/api/v1/**" covers
+// "/api/v1" and everything below it
+func Compile(glob string) (*regexp.Regexp, error) {
+	if !strings.HasPrefix(glob, "/") {
+		return nil, fmt.Errorf("path pattern %q must start with /", glob)
+	}
+
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "/**"):
+			i += 2
+			expr.WriteString("(?:/.*)?")
+		case strings.HasPrefix(glob[i:], "**"):
+			i++
+			expr.WriteString(".*")
+		case c == '*':
+			expr.WriteString("[^/]*")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return regexp.Compile("^" + expr.String() + "$")
+}
+
+// skeleton copies the document-level fields of a spec, with the rule's title
+// and version applied
+func skeleton(spec map[string]interface{}, output models.SpecOutput) map[string]interface{} {
+	part := map[string]interface{}{"paths": map[string]interface{}{}}
+	for _, key := range []string{"openapi", "servers", "security", "externalDocs"} {
+		if value, ok := spec[key]; ok {
+			part[key] = value
+		}
+	}
+
+	info := make(map[string]interface{})
+	if original, ok := spec["info"].(map[string]interface{}); ok {
+		maps.Copy(info, original)
+	}
+	if output.Title != "" {
+		info["title"] = output.Title
+	}
+	if output.Version != "" {
+		info["version"] = output.Version
+	}
+	part["info"] = info
+	return part
+}
+
+var refPattern = regexp.MustCompile(`"#/components/([^/"]+)/([^"]+)"`)
+
+// keepUsed copies the components referenced from the part's paths, following
+// references between components, and the tags its operations carry
+func keepUsed(part, spec map[string]interface{}) {
+	components, _ := spec["components"].(map[string]interface{})
+	kept := make(map[string]interface{})
+
+	// Security schemes are referenced by name, not $ref; keep them all
+	if schemes, ok := components["securitySchemes"]; ok {
+		kept["securitySchemes"] = schemes
+	}
+
+	queue := []interface{}{part["paths"]}
+	for len(queue) > 0 {
+		data, _ := json.Marshal(queue[0])
+		queue = queue[1:]
+		for _, m := range refPattern.FindAllStringSubmatch(string(data), -1) {
+			section, name := m[1], m[2]
+			source, _ := components[section].(map[string]interface{})
+			value, ok := source[name]
+			if !ok {
+				continue
+			}
+			target, _ := kept[section].(map[string]interface{})
+			if target == nil {
+				target = make(map[string]interface{})
+				kept[section] = target
+			}
+			if _, seen := target[name]; !seen {
+				target[name] = value
+				queue = append(queue, value)
+			}
+		}
+	}
+	if len(kept) > 0 {
+		part["components"] = kept
+	}
+
+	used := make(map[string]bool)
+	for _, item := range part["paths"].(map[string]interface{}) {
+		operations, _ := item.(map[string]interface{})
+		for _, op := range operations {
+			operation, _ := op.(map[string]interface{})
+			tags, _ := operation["tags"].([]interface{})
+			for _, tag := range tags {
+				if name, ok := tag.(string); ok {
+					used[name] = true
+				}
+			}
+		}
+	}
+	var tags []interface{}
+	all, _ := spec["tags"].([]interface{})
+	for _, tag := range all {
+		if t, ok := tag.(map[string]interface{}); ok && used[fmt.Sprint(t["name"])] {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		part["tags"] = tags
+	}
+}
@@ -0,0 +1,564 @@
+package swagger
+
+import (
+	"fmt"
+	"strings"
+
+	"nextjs-to-openapi/internal/specfile"
+)
+
+// Options control the conversion
+type Options struct {
+	// FoldBasePath prepends basePath to every path instead of putting it in the
+	// server URL, so paths match routes documented as /api/...
+	FoldBasePath bool
+}
+
+// IsV2 reports whether a parsed document is Swagger 2.0
+func IsV2(doc map[string]interface{}) bool {
+	version, _ := doc["swagger"].(string)
+	return strings.HasPrefix(version, "2.")
+}
+
+var refRenames = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+var oauthFlows = map[string]string{
+	"implicit":    "implicit",
+	"password":    "password",
+	"application": "clientCredentials",
+	"accessCode":  "authorizationCode",
+}
+
+// schemaKeys are the parameter/header fields that move into "schema" in 3.x
+var schemaKeys = []string{"type", "format", "items", "collectionFormat", "default", "maximum", "exclusiveMaximum",
+	"minimum", "exclusiveMinimum", "maxLength", "minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf"}
+
+// Convert upgrades a Swagger 2.0 document to OpenAPI 3.0.0
+func Convert(doc map[string]interface{}, opts Options) (map[string]interface{}, error) {
+	if !IsV2(doc) {
+		return nil, fmt.Errorf("%w: not a Swagger 2.0 document", specfile.ErrSpecInvalid)
+	}
+	doc = rewriteRefs(doc).(map[string]interface{})
+
+	out := map[string]interface{}{"openapi": "3.0.0"}
+	for key, v := range doc {
+		if key == "info" || key == "tags" || key == "security" || key == "externalDocs" || strings.HasPrefix(key, "x-") {
+			out[key] = v
+		}
+	}
+
+	basePath := strings.TrimSuffix(stringAt(doc, "basePath"), "/")
+	serverPath := basePath
+	if opts.FoldBasePath {
+		serverPath = ""
+	}
+	if servers := convertServers(doc, serverPath); len(servers) > 0 {
+		out["servers"] = servers
+	}
+
+	consumes := stringsAt(doc, "consumes")
+	produces := stringsAt(doc, "produces")
+	components := make(map[string]interface{})
+
+	if definitions := mapAt(doc, "definitions"); len(definitions) > 0 {
+		schemas := make(map[string]interface{}, len(definitions))
+		for name, schema := range definitions {
+			schemas[name] = convertSchema(schema)
+		}
+		components["schemas"] = schemas
+	}
+
+	// Shared body parameters have no 3.x equivalent outside requestBodies, and
+	// shared form fields none at all: operations referencing them get them as
+	// properties of their request body
+	params, bodies := make(map[string]interface{}), make(map[string]interface{})
+	for name, p := range mapAt(doc, "parameters") {
+		param, _ := p.(map[string]interface{})
+		switch param["in"] {
+		case "body":
+			bodies[name] = requestBody([]interface{}{param}, consumes)
+		case "formData":
+			bodies[name] = formField{param}
+		default:
+			params[name] = convertParameter(param)
+		}
+	}
+	if len(params) > 0 {
+		components["parameters"] = params
+	}
+	requestBodies := make(map[string]interface{})
+	for name, body := range bodies {
+		if _, isForm := body.(formField); !isForm {
+			requestBodies[name] = body
+		}
+	}
+	if len(requestBodies) > 0 {
+		components["requestBodies"] = requestBodies
+	}
+
+	if responses := mapAt(doc, "responses"); len(responses) > 0 {
+		converted := make(map[string]interface{}, len(responses))
+		for name, r := range responses {
+			converted[name] = convertResponse(r, produces)
+		}
+		components["responses"] = converted
+	}
+
+	if definitions := mapAt(doc, "securityDefinitions"); len(definitions) > 0 {
+		schemes := make(map[string]interface{}, len(definitions))
+		for name, d := range definitions {
+			schemes[name] = convertSecurityScheme(d)
+		}
+		components["securitySchemes"] = schemes
+	}
+	if len(components) > 0 {
+		out["components"] = components
+	}
+
+	paths := make(map[string]interface{})
+	for path, item := range mapAt(doc, "paths") {
+		paths[basePathFor(opts, basePath)+path] = convertPathItem(item, consumes, produces, bodies)
+	}
+	out["paths"] = paths
+
+	return out, nil
+}
+
+func basePathFor(opts Options, basePath string) string {
+	if opts.FoldBasePath {
+		return basePath
+	}
+	return ""
+}
+
+func convertServers(doc map[string]interface{}, basePath string) []interface{} {
+	host := stringAt(doc, "host")
+	schemes := stringsAt(doc, "schemes")
+	if host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []interface{}{map[string]interface{}{"url": basePath}}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"https"}
+	}
+
+	var servers []interface{}
+	for _, scheme := range schemes {
+		servers = append(servers, map[string]interface{}{"url": scheme + "://" + host + basePath})
+	}
+	return servers
+}
+
+func convertPathItem(v interface{}, consumes, produces []string, bodies map[string]interface{}) map[string]interface{} {
+	item, _ := v.(map[string]interface{})
+	out := make(map[string]interface{}, len(item))
+
+	for key, value := range item {
+		switch key {
+		case "parameters":
+			list, _ := value.([]interface{})
+			if converted := convertParameters(list, bodies); len(converted) > 0 {
+				out[key] = converted
+			}
+		case "get", "put", "post", "delete", "options", "head", "patch":
+			out[key] = convertOperation(value, item, consumes, produces, bodies)
+		default:
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func convertOperation(v interface{}, item map[string]interface{}, consumes, produces []string, bodies map[string]interface{}) map[string]interface{} {
+	op, _ := v.(map[string]interface{})
+	out := make(map[string]interface{}, len(op))
+
+	if c := stringsAt(op, "consumes"); len(c) > 0 {
+		consumes = c
+	}
+	if p := stringsAt(op, "produces"); len(p) > 0 {
+		produces = p
+	}
+
+	for key, value := range op {
+		switch key {
+		case "consumes", "produces", "schemes":
+			// Folded into content types and servers
+		case "parameters":
+			list, _ := value.([]interface{})
+			if converted := convertParameters(list, bodies); len(converted) > 0 {
+				out[key] = converted
+			}
+		case "responses":
+			responses, _ := value.(map[string]interface{})
+			converted := make(map[string]interface{}, len(responses))
+			for code, r := range responses {
+				converted[code] = convertResponse(r, produces)
+			}
+			out[key] = converted
+		default:
+			out[key] = value
+		}
+	}
+
+	// Body and form parameters from the operation and its path item become the request body
+	params, _ := op["parameters"].([]interface{})
+	shared, _ := item["parameters"].([]interface{})
+	var payload []interface{}
+	for _, p := range append(append([]interface{}{}, shared...), params...) {
+		param, _ := p.(map[string]interface{})
+		if ref, ok := param["$ref"].(string); ok && strings.HasPrefix(ref, "#/components/parameters/") {
+			name := strings.TrimPrefix(ref, "#/components/parameters/")
+			if field, isForm := bodies[name].(formField); isForm {
+				payload = append(payload, field.param)
+			} else if _, isBody := bodies[name]; isBody {
+				out["requestBody"] = map[string]interface{}{"$ref": "#/components/requestBodies/" + name}
+			}
+			continue
+		}
+		if param["in"] == "body" || param["in"] == "formData" {
+			payload = append(payload, param)
+		}
+	}
+	if len(payload) > 0 {
+		out["requestBody"] = requestBody(payload, consumes)
+	}
+
+	return out
+}
+
+// convertParameters converts non-body parameters, dropping body/form ones
+// (including references to shared body parameters)
+func convertParameters(list []interface{}, bodies map[string]interface{}) []interface{} {
+	var out []interface{}
+	for _, p := range list {
+		param, _ := p.(map[string]interface{})
+		if param["in"] == "body" || param["in"] == "formData" {
+			continue
+		}
+		if ref, ok := param["$ref"].(string); ok {
+			if _, isBody := bodies[strings.TrimPrefix(ref, "#/components/parameters/")]; !isBody {
+				out = append(out, param)
+			}
+			continue
+		}
+		out = append(out, convertParameter(param))
+	}
+	return out
+}
+
+// formField is a shared formData parameter, which becomes a property of the
+// request body of each operation referencing it
+type formField struct {
+	param map[string]interface{}
+}
+
+func convertParameter(param map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	schema := make(map[string]interface{})
+	for key, value := range param {
+		if contains(schemaKeys, key) {
+			if key != "collectionFormat" {
+				schema[key] = convertSchema(value)
+			}
+			continue
+		}
+		out[key] = value
+	}
+	if style, explode, ok := collectionStyle(param); ok {
+		out["style"], out["explode"] = style, explode
+		if format := stringAt(param, "collectionFormat"); format == "tsv" || style == "simple" && format != "" && format != "csv" {
+			// No 3.x style separates items this way
+			out["x-collectionFormat"] = format
+		}
+	}
+	if len(schema) > 0 {
+		out["schema"] = schema
+	}
+	return out
+}
+
+// requestBody builds a 3.x request body from body or formData parameters
+func requestBody(params []interface{}, consumes []string) map[string]interface{} {
+	body := make(map[string]interface{})
+	properties := make(map[string]interface{})
+	encoding := make(map[string]interface{}) // How array fields separate their items
+	var required []interface{}
+	var schema interface{}
+	hasFile := false
+
+	for _, p := range params {
+		param, _ := p.(map[string]interface{})
+		if param["in"] == "body" {
+			schema = convertSchema(param["schema"])
+			if d, ok := param["description"]; ok {
+				body["description"] = d
+			}
+			if param["required"] == true {
+				body["required"] = true
+			}
+			continue
+		}
+
+		// formData
+		name := stringAt(param, "name")
+		prop := convertParameter(param)["schema"]
+		if param["type"] == "file" {
+			hasFile = true
+			prop = map[string]interface{}{"type": "string", "format": "binary"}
+		}
+		if d, ok := param["description"]; ok {
+			if m, ok := prop.(map[string]interface{}); ok {
+				m["description"] = d
+			}
+		}
+		properties[name] = prop
+		if style, explode, ok := collectionStyle(param); ok {
+			encoding[name] = map[string]interface{}{"style": style, "explode": explode}
+		}
+		if param["required"] == true {
+			required = append(required, name)
+		}
+	}
+
+	mediaTypes := consumes
+	if schema == nil {
+		form := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			form["required"] = required
+			body["required"] = true
+		}
+		schema = form
+		mediaTypes = formMediaTypes(consumes, hasFile)
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{"application/json"}
+	}
+
+	content := make(map[string]interface{}, len(mediaTypes))
+	for _, mt := range mediaTypes {
+		media := map[string]interface{}{"schema": schema}
+		if len(encoding) > 0 {
+			media["encoding"] = encoding
+		}
+		content[mt] = media
+	}
+	body["content"] = content
+	return body
+}
+
+// collectionStyle maps how a 2.0 array parameter separates its items to a 3.x
+// style and explode. Arrays without a collectionFormat are comma-separated.
+func collectionStyle(param map[string]interface{}) (string, bool, bool) {
+	if param["type"] != "array" {
+		return "", false, false
+	}
+	format := stringAt(param, "collectionFormat")
+	if param["in"] == "path" || param["in"] == "header" {
+		// Only simple, comma-separated values are possible here
+		return "simple", false, true
+	}
+	switch format {
+	case "multi":
+		return "form", true, true
+	case "ssv":
+		return "spaceDelimited", false, true
+	case "pipes":
+		return "pipeDelimited", false, true
+	}
+	return "form", false, true // csv, and tsv, which has no 3.x style
+}
+
+func formMediaTypes(consumes []string, hasFile bool) []string {
+	var forms []string
+	for _, mt := range consumes {
+		if mt == "multipart/form-data" || mt == "application/x-www-form-urlencoded" {
+			forms = append(forms, mt)
+		}
+	}
+	if len(forms) > 0 {
+		return forms
+	}
+	if hasFile {
+		return []string{"multipart/form-data"}
+	}
+	return []string{"application/x-www-form-urlencoded"}
+}
+
+func convertResponse(v interface{}, produces []string) map[string]interface{} {
+	response, _ := v.(map[string]interface{})
+	out := make(map[string]interface{}, len(response))
+
+	for key, value := range response {
+		switch key {
+		case "schema", "examples":
+			// Moved under content
+		case "headers":
+			headers, _ := value.(map[string]interface{})
+			converted := make(map[string]interface{}, len(headers))
+			for name, h := range headers {
+				header, _ := h.(map[string]interface{})
+				converted[name] = convertParameter(header)
+			}
+			out[key] = converted
+		default:
+			out[key] = value
+		}
+	}
+	if _, ok := out["description"]; !ok && response["$ref"] == nil {
+		out["description"] = ""
+	}
+
+	if schema, ok := response["schema"]; ok {
+		mediaTypes := produces
+		if len(mediaTypes) == 0 {
+			mediaTypes = []string{"application/json"}
+		}
+		examples, _ := response["examples"].(map[string]interface{})
+		content := make(map[string]interface{}, len(mediaTypes))
+		for _, mt := range mediaTypes {
+			media := map[string]interface{}{"schema": convertSchema(schema)}
+			if example, ok := examples[mt]; ok {
+				media["example"] = example
+			}
+			content[mt] = media
+		}
+		out["content"] = content
+	}
+	return out
+}
+
+func convertSecurityScheme(v interface{}) map[string]interface{} {
+	scheme, _ := v.(map[string]interface{})
+	out := make(map[string]interface{})
+	if d, ok := scheme["description"]; ok {
+		out["description"] = d
+	}
+
+	switch scheme["type"] {
+	case "basic":
+		out["type"] = "http"
+		out["scheme"] = "basic"
+	case "apiKey":
+		out["type"] = "apiKey"
+		out["in"] = scheme["in"]
+		out["name"] = scheme["name"]
+	case "oauth2":
+		out["type"] = "oauth2"
+		flow := map[string]interface{}{"scopes": scheme["scopes"]}
+		if flow["scopes"] == nil {
+			flow["scopes"] = map[string]interface{}{}
+		}
+		if url, ok := scheme["authorizationUrl"]; ok {
+			flow["authorizationUrl"] = url
+		}
+		if url, ok := scheme["tokenUrl"]; ok {
+			flow["tokenUrl"] = url
+		}
+		out["flows"] = map[string]interface{}{oauthFlows[stringAt(scheme, "flow")]: flow}
+	default:
+		for k, v := range scheme {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// convertSchema fixes the few schema keywords that changed: file types and
+// the x-nullable extension
+func convertSchema(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			switch key {
+			case "x-nullable":
+				out["nullable"] = value
+			case "discriminator":
+				if name, ok := value.(string); ok {
+					out[key] = map[string]interface{}{"propertyName": name}
+				} else {
+					out[key] = value
+				}
+			default:
+				out[key] = convertSchema(value)
+			}
+		}
+		if out["type"] == "file" {
+			out["type"] = "string"
+			out["format"] = "binary"
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = convertSchema(child)
+		}
+		return out
+	}
+	return v
+}
+
+// rewriteRefs points every 2.0 $ref at its 3.x location
+func rewriteRefs(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				for from, to := range refRenames {
+					if strings.HasPrefix(ref, from) {
+						ref = to + strings.TrimPrefix(ref, from)
+					}
+				}
+				out[key] = ref
+				continue
+			}
+			out[key] = rewriteRefs(value)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = rewriteRefs(child)
+		}
+		return out
+	}
+	return v
+}
+
+func mapAt(m map[string]interface{}, key string) map[string]interface{} {
+	child, _ := m[key].(map[string]interface{})
+	return child
+}
+
+func stringAt(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func stringsAt(m map[string]interface{}, key string) []string {
+	list, _ := m[key].([]interface{})
+	var out []string
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package testgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"nextjs-to-openapi/internal/examples"
+)
+
+// File is one generated test file
+type File struct {
+	Name    string // Relative path, e.g. api/users/{id}.test.ts
+	Content string
+}
+
+// Options control the generated tests
+type Options struct {
+	Extension string // "ts" (default) or "js"
+}
+
+var (
+	methodOrder = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+	pathParam   = regexp.MustCompile(`\{([^}]+)\}`)
+	nonWord     = regexp.MustCompile(`\W+`)
+)
+
+// Generate writes one Jest/supertest file per path with a happy-path test and a
+// skeleton for every documented error status of each operation
+func Generate(spec map[string]interface{}, opts Options) []File {
+	if opts.Extension == "" {
+		opts.Extension = "ts"
+	}
+	paths, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	globalSecurity, _ := spec["security"].([]interface{})
+
+	var files []File
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		item, _ := paths[path].(map[string]interface{})
+
+		var b strings.Builder
+		b.WriteString("import request from 'supertest';\n\n")
+		b.WriteString("// Generated from the OpenAPI spec. Fill in the TODOs, then keep these as contract tests.\n")
+		b.WriteString("const app = process.env.API_BASE_URL ?? 'http://localhost:3000';\n")
+
+		written := 0
+		for _, method := range methodOrder {
+			operation, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			security := globalSecurity
+			if s, ok := operation["security"].([]interface{}); ok {
+				security = s
+			}
+			params := append(asList(item["parameters"]), asList(operation["parameters"])...)
+			writeDescribe(&b, method, path, operation, params, security, components)
+			written++
+		}
+		if written == 0 {
+			continue
+		}
+
+		files = append(files, File{Name: fileName(path, opts.Extension), Content: b.String()})
+	}
+	return files
+}
+
+func writeDescribe(b *strings.Builder, method, path string, operation map[string]interface{}, params, security []interface{}, components map[string]interface{}) {
+	responses, _ := operation["responses"].(map[string]interface{})
+	codes := slices.Sorted(maps.Keys(responses))
+
+	fmt.Fprintf(b, "\ndescribe(%s, () => {\n", jsValue(strings.ToUpper(method)+" "+path))
+
+	happy := ""
+	for _, code := range codes {
+		if strings.HasPrefix(code, "2") {
+			happy = code
+			break
+		}
+	}
+	if happy != "" {
+		fmt.Fprintf(b, "  it(%s, async () => {\n", jsValue("responds "+happy+describeResponse(responses[happy])))
+		writeRequest(b, method, path, operation, params, security, components, "    ")
+		fmt.Fprintf(b, "\n    expect(res.status).toBe(%s);\n", happy)
+		if mediaType := jsonContent(responses[happy]); mediaType != "" {
+			b.WriteString("    expect(res.headers['content-type']).toMatch(/json/);\n")
+			b.WriteString("    // TODO: assert on the response body\n")
+		}
+		b.WriteString("  });\n")
+	}
+
+	for _, code := range codes {
+		if code == happy || strings.HasPrefix(code, "2") || code == "default" {
+			continue
+		}
+		fmt.Fprintf(b, "\n  it(%s, async () => {\n", jsValue("responds "+code+describeResponse(responses[code])))
+		fmt.Fprintf(b, "    // TODO: arrange a request that triggers %s\n", code)
+		writeRequest(b, method, path, operation, params, securityFor(code, security), components, "    ")
+		fmt.Fprintf(b, "\n    expect(res.status).toBe(%s);\n", code)
+		b.WriteString("  });\n")
+	}
+
+	b.WriteString("});\n")
+}
+
+// securityFor leaves out credentials in the 401 test
+func securityFor(code string, security []interface{}) []interface{} {
+	if code == "401" {
+		return nil
+	}
+	return security
+}
+
+func writeRequest(b *strings.Builder, method, path string, operation map[string]interface{}, params, security []interface{}, components map[string]interface{}, indent string) {
+	url := pathParam.ReplaceAllStringFunc(path, func(m string) string {
+		value := paramExample(m[1:len(m)-1], params, components)
+		if str, ok := value.(string); ok {
+			return strings.NewReplacer("`", "", "$", "", "/", "-").Replace(str)
+		}
+		return "${" + jsValue(value) + "}"
+	})
+	fmt.Fprintf(b, "%sconst res = await request(app)\n%s  .%s(`%s`)", indent, indent, method, url)
+
+	query := make(map[string]interface{})
+	for _, p := range params {
+		param := examples.Parameter(p, components)
+		if param["in"] == "query" && param["required"] == true {
+			query[fmt.Sprint(param["name"])] = examples.Example(param["schema"], components)
+		}
+	}
+	if len(query) > 0 {
+		fmt.Fprintf(b, "\n%s  .query(%s)", indent, jsValue(query))
+	}
+
+	for _, header := range authHeaders(security, components) {
+		fmt.Fprintf(b, "\n%s  .set(%s)", indent, header)
+	}
+
+	if body := examples.RequestBody(operation, components); body != nil {
+		fmt.Fprintf(b, "\n%s  .send(%s)", indent, jsValue(body))
+	}
+	b.WriteString(";\n")
+}
+
+// authHeaders turns the first security requirement into .set() arguments
+func authHeaders(security []interface{}, components map[string]interface{}) []string {
+	if len(security) == 0 {
+		return nil
+	}
+	requirement, _ := security[0].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+
+	var headers []string
+	for _, name := range slices.Sorted(maps.Keys(requirement)) {
+		scheme, _ := schemes[name].(map[string]interface{})
+		env := "API_" + strings.ToUpper(nonWord.ReplaceAllString(name, "_"))
+		switch {
+		case scheme["type"] == "apiKey" && scheme["in"] == "header":
+			headers = append(headers, fmt.Sprintf("'%s', process.env.%s ?? ''", scheme["name"], env))
+		case scheme["type"] == "http" && scheme["scheme"] == "basic":
+			headers = append(headers, fmt.Sprintf("'Authorization', `Basic ${process.env.%s}`", env))
+		default:
+			headers = append(headers, fmt.Sprintf("'Authorization', `Bearer ${process.env.%s}`", env))
+		}
+	}
+	return headers
+}
+
+func paramExample(name string, params []interface{}, components map[string]interface{}) interface{} {
+	for _, p := range params {
+		param := examples.Parameter(p, components)
+		if param["name"] == name && param["in"] == "path" {
+			if ex := examples.Example(param["schema"], components); ex != nil {
+				return ex
+			}
+		}
+	}
+	return "example-" + name
+}
+
+func describeResponse(v interface{}) string {
+	response, _ := v.(map[string]interface{})
+	description, _ := response["description"].(string)
+	if description == "" {
+		return ""
+	}
+	return " — " + strings.Join(strings.Fields(description), " ")
+}
+
+func jsonContent(v interface{}) string {
+	response, _ := v.(map[string]interface{})
+	content, _ := response["content"].(map[string]interface{})
+	for mediaType := range content {
+		if strings.Contains(mediaType, "json") {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+// jsValue renders a value as a JavaScript literal
+func jsValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "undefined"
+	}
+	return string(data)
+}
+
+func asList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+// fileName maps a path to a test file, e.g. /api/users/{id} -> api/users/[id].test.ts
+func fileName(path, ext string) string {
+	name := strings.Trim(pathParam.ReplaceAllString(path, "[$1]"), "/")
+	if name == "" {
+		name = "index"
+	}
+	return name + ".test." + ext
+}
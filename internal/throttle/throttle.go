@@ -0,0 +1,70 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Throttle spaces out requests to a shared provider and caps how many are
+// outstanding at once, so one long run leaves capacity for other users
+type Throttle struct {
+	interval time.Duration // Minimum gap between request starts; 0 is unlimited
+	slots    chan struct{} // Outstanding requests; nil is unlimited
+
+	mu   sync.Mutex
+	next time.Time // Earliest start of the next request
+}
+
+// New creates a throttle allowing maxRPS request starts per second and
+// maxInflight outstanding requests. Zero disables either limit.
+func New(maxRPS float64, maxInflight int) *Throttle {
+	t := &Throttle{}
+	if maxRPS > 0 {
+		t.interval = time.Duration(float64(time.Second) / maxRPS)
+	}
+	if maxInflight > 0 {
+		t.slots = make(chan struct{}, maxInflight)
+	}
+	return t
+}
+
+// Acquire blocks until a request may start, or until ctx is done. The returned
+// function must be called once the request has finished.
+func (t *Throttle) Acquire(ctx context.Context) (func(), error) {
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if t.slots != nil {
+			<-t.slots
+		}
+	}
+
+	if t.interval > 0 {
+		t.mu.Lock()
+		now := time.Now()
+		if t.next.Before(now) {
+			t.next = now
+		}
+		wait := t.next.Sub(now)
+		t.next = t.next.Add(t.interval)
+		t.mu.Unlock()
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+	return release, nil
+}
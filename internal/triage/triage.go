@@ -0,0 +1,133 @@
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// Classes, from cheapest to document to most expensive
+const (
+	Trivial  = "trivial"
+	Standard = "standard"
+	Complex  = "complex"
+)
+
+// Strategies
+const (
+	Static = "static" // Documented from the scan alone, without the model
+	LLM    = "llm"    // Documented by the model
+)
+
+// Classes lists the classes in order
+var Classes = []string{Trivial, Standard, Complex}
+
+// Default limits: trivial routes stay within every trivial limit, complex routes
+// reach any complex limit, and everything else is standard
+var (
+	defaultTrivial = models.TriageClass{Lines: 30, Branches: 2, Strategy: Static}
+	defaultComplex = models.TriageClass{Lines: 150, Branches: 12, Validators: 3, Strategy: LLM}
+)
+
+var (
+	branchPattern    = regexp.MustCompile(`\b(?:if|case|catch|for|while)\b|\?\s*[^\s?.:]`)
+	validatorPattern = regexp.MustCompile(`\b(?:z|yup|Joi|joi|v)\.object\(|\.(?:safeParse|parse|parseAsync|validate|validateSync)\(`)
+	bodyReadPattern  = regexp.MustCompile(`\b(?:req|request)\.(?:json|formData|text|arrayBuffer|blob)\(\)|\breq\.body\b`)
+)
+
+// Metrics are what a route is classified by
+type Metrics struct {
+	Lines      int  `json:"lines"`
+	Branches   int  `json:"branches"`
+	Validators int  `json:"validators"`
+	ReadsBody  bool `json:"reads_body"` // A body always needs the model to describe it
+}
+
+// Measure counts a loaded route's metrics
+func Measure(route models.APIRoute) Metrics {
+	m := Metrics{
+		Branches:   len(branchPattern.FindAllString(route.Content, -1)),
+		Validators: len(validatorPattern.FindAllString(route.Content, -1)) + len(route.Constraints),
+		ReadsBody:  bodyReadPattern.MatchString(route.Content),
+	}
+	for _, line := range strings.Split(route.Content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			m.Lines++
+		}
+	}
+	return m
+}
+
+// Rules classify routes with a project's limits
+type Rules struct {
+	classes map[string]models.TriageClass
+}
+
+// New fills in the default limits and strategies
+func New(cfg models.RouteTriage) *Rules {
+	trivial, standard, complex := cfg.Trivial, cfg.Standard, cfg.Complex
+	withDefaults(&trivial, defaultTrivial)
+	withDefaults(&complex, defaultComplex)
+	if standard.Strategy == "" {
+		standard.Strategy = LLM
+	}
+	return &Rules{classes: map[string]models.TriageClass{Trivial: trivial, Standard: standard, Complex: complex}}
+}
+
+func withDefaults(class *models.TriageClass, defaults models.TriageClass) {
+	if class.Lines == 0 {
+		class.Lines = defaults.Lines
+	}
+	if class.Branches == 0 {
+		class.Branches = defaults.Branches
+	}
+	if class.Validators == 0 {
+		class.Validators = defaults.Validators
+	}
+	if class.Strategy == "" {
+		class.Strategy = defaults.Strategy
+	}
+}
+
+// Classify returns a route's class and the metrics it was based on
+func (r *Rules) Classify(route models.APIRoute) (string, Metrics) {
+	m := Measure(route)
+	trivial, complex := r.classes[Trivial], r.classes[Complex]
+	switch {
+	case m.Lines >= complex.Lines || m.Branches >= complex.Branches || m.Validators >= complex.Validators:
+		return Complex, m
+	case m.Lines <= trivial.Lines && m.Branches <= trivial.Branches && m.Validators <= trivial.Validators && !m.ReadsBody:
+		return Trivial, m
+	}
+	return Standard, m
+}
+
+// Class returns how a class is documented
+func (r *Rules) Class(name string) models.TriageClass {
+	return r.classes[name]
+}
+
+// Validate checks the strategies and that the limits do not overlap
+func Validate(cfg models.RouteTriage) error {
+	for name, class := range map[string]models.TriageClass{Trivial: cfg.Trivial, Standard: cfg.Standard, Complex: cfg.Complex} {
+		switch class.Strategy {
+		case "", Static, LLM:
+		default:
+			return fmt.Errorf("%s: unknown strategy %q (expected static or llm)", name, class.Strategy)
+		}
+		if class.Model != "" && class.Strategy == Static {
+			return fmt.Errorf("%s: a model only applies to the llm strategy", name)
+		}
+		if class.Lines < 0 || class.Branches < 0 || class.Validators < 0 {
+			return fmt.Errorf("%s: limits cannot be negative", name)
+		}
+	}
+	r := New(cfg)
+	trivial, complex := r.classes[Trivial], r.classes[Complex]
+	if trivial.Lines >= complex.Lines || trivial.Branches >= complex.Branches {
+		return fmt.Errorf("trivial limits must be below complex ones")
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+package tui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"nextjs-to-openapi/internal/models"
+)
+
+// ErrAborted is returned when the user quits before every route was processed
+var ErrAborted = errors.New("run aborted")
+
+// ProcessFunc documents one route
+type ProcessFunc func(route models.APIRoute) error
+
+// Route statuses
+const (
+	statusPending = "pending"
+	statusRunning = "running"
+	statusDone    = "done"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+const logLines = 8
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	cursorStyle   = lipgloss.NewStyle().Bold(true).Reverse(true)
+	dimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	paneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1)
+	statusSymbols = map[string]string{
+		statusPending: "·",
+		statusRunning: "⏳",
+		statusDone:    "✅",
+		statusFailed:  "❌",
+		statusSkipped: "⏭️",
+	}
+)
+
+type item struct {
+	route    models.APIRoute
+	status   string
+	retries  int
+	err      error
+	started  time.Time
+	duration time.Duration
+}
+
+// Messages from the worker and log reader
+type (
+	startedMsg  struct{ job job }
+	finishedMsg struct {
+		job job
+		err error
+	}
+	logMsg  string
+	idleMsg struct{}
+)
+
+// job is one attempt at a route; messages about an earlier attempt than the
+// item's current one are stale and ignored
+type job struct {
+	index   int
+	attempt int
+}
+
+// queue hands jobs to the worker; retries are appended at the end
+type queue struct {
+	mu      sync.Mutex
+	pending []job
+	wake    chan struct{}
+}
+
+func (q *queue) push(j job) {
+	q.mu.Lock()
+	q.pending = append(q.pending, j)
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *queue) pop() (job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return job{}, false
+	}
+	j := q.pending[0]
+	q.pending = q.pending[1:]
+	return j, true
+}
+
+type model struct {
+	items     []item
+	queue     *queue
+	skipped   *sync.Map // Jobs skipped before the worker reached them
+	cursor    int
+	inspect   bool
+	logs      []string
+	height    int
+	running   bool
+	quitting  bool
+	completed bool
+}
+
+// Run processes up to workers routes at once behind a live dashboard. Routes can
+// be skipped while pending and retried once failed or skipped. Anything written
+// to stdout while the dashboard is up is shown in its log pane. It returns the
+// number of routes that ended up failed or skipped.
+func Run(routes []models.APIRoute, workers int, process ProcessFunc) (int, error) {
+	// Capture stdout so progress output does not tear the screen
+	terminal := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture output: %w", err)
+	}
+	os.Stdout = writer
+	defer func() { os.Stdout = terminal }()
+
+	m := &model{
+		items:   make([]item, len(routes)),
+		queue:   &queue{wake: make(chan struct{}, 1)},
+		skipped: &sync.Map{},
+		height:  24,
+		running: true,
+	}
+	for i, route := range routes {
+		m.items[i] = item{route: route, status: statusPending}
+		m.queue.pending = append(m.queue.pending, job{index: i})
+	}
+
+	program := tea.NewProgram(m, tea.WithOutput(terminal), tea.WithAltScreen())
+	go readLogs(program, reader)
+	for range max(1, workers) {
+		go work(program, m.queue, m.skipped, routes, process)
+	}
+
+	final, err := program.Run()
+	writer.Close()
+	if err != nil {
+		return 0, fmt.Errorf("dashboard failed: %w", err)
+	}
+
+	result := final.(*model)
+	if !result.completed {
+		return 0, ErrAborted
+	}
+	failed := 0
+	for _, it := range result.items {
+		if it.status == statusFailed || it.status == statusSkipped {
+			failed++
+		}
+	}
+	return failed, nil
+}
+
+func readLogs(program *tea.Program, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		program.Send(logMsg(scanner.Text()))
+	}
+}
+
+// work runs routes from the queue, waiting for retries once it runs dry
+func work(program *tea.Program, q *queue, skipped *sync.Map, routes []models.APIRoute, process ProcessFunc) {
+	for {
+		j, ok := q.pop()
+		if !ok {
+			program.Send(idleMsg{})
+			<-q.wake
+			continue
+		}
+		if _, skip := skipped.LoadAndDelete(j); skip {
+			continue
+		}
+		program.Send(startedMsg{job: j})
+		err := process(routes[j.index])
+		program.Send(finishedMsg{job: j, err: err})
+	}
+}
+
+func (m *model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+
+	case startedMsg:
+		it := &m.items[msg.job.index]
+		if msg.job.attempt != it.retries {
+			break
+		}
+		// A skip pressed after the worker took the job came too late
+		m.skipped.Delete(msg.job)
+		m.running = true
+		it.status = statusRunning
+		it.started = time.Now()
+
+	case finishedMsg:
+		it := &m.items[msg.job.index]
+		if msg.job.attempt != it.retries {
+			break
+		}
+		it.duration = time.Since(it.started)
+		it.err = msg.err
+		it.status = statusDone
+		if msg.err != nil {
+			it.status = statusFailed
+			m.appendLog(fmt.Sprintf("❌ %s: %v", it.route.FilePath, msg.err))
+		}
+
+	case idleMsg:
+		// Other workers may still be busy when one runs out of routes
+		m.running = slices.ContainsFunc(m.items, func(it item) bool { return it.status == statusRunning })
+
+	case logMsg:
+		m.appendLog(string(msg))
+
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+	return m, nil
+}
+
+func (m *model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		m.quitting = true
+		m.completed = !m.running && msg.String() == "q"
+		return tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case "enter", "i":
+		m.inspect = !m.inspect
+	case "s":
+		if it := &m.items[m.cursor]; it.status == statusPending {
+			it.status = statusSkipped
+			m.skipped.Store(job{index: m.cursor, attempt: it.retries}, true)
+		}
+	case "r":
+		if it := &m.items[m.cursor]; it.status == statusFailed || it.status == statusSkipped {
+			it.status = statusPending
+			it.err = nil
+			m.running = true
+			// A route skipped while queued is still in the queue; unskipping it
+			// is enough, and pushing it again would document it twice
+			if _, queued := m.skipped.LoadAndDelete(job{index: m.cursor, attempt: it.retries}); queued {
+				break
+			}
+			it.retries++
+			m.queue.push(job{index: m.cursor, attempt: it.retries})
+		}
+	}
+	return nil
+}
+
+func (m *model) appendLog(line string) {
+	m.logs = append(m.logs, line)
+	if len(m.logs) > 200 {
+		m.logs = m.logs[len(m.logs)-200:]
+	}
+}
+
+func (m *model) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	counts := make(map[string]int)
+	for _, it := range m.items {
+		counts[it.status]++
+	}
+	state := "running"
+	if !m.running {
+		state = "finished — press q to write the spec"
+	}
+	fmt.Fprintf(&b, "%s  %d/%d done · %d failed · %d skipped · %s\n\n",
+		titleStyle.Render("nextjs-to-openapi"), counts[statusDone], len(m.items), counts[statusFailed], counts[statusSkipped], state)
+
+	// Keep the cursor visible in the space left by the bottom pane
+	rows := max(m.height-logLines-7, 3)
+	start := max(0, min(m.cursor-rows/2, len(m.items)-rows))
+	for i := start; i < min(start+rows, len(m.items)); i++ {
+		b.WriteString(m.renderItem(i))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(paneStyle.Render(m.bottomPane()))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("↑/↓ move · s skip · r retry · enter inspect · q quit"))
+	return b.String()
+}
+
+func (m *model) renderItem(i int) string {
+	it := m.items[i]
+	line := fmt.Sprintf("%s %s", statusSymbols[it.status], it.route.FilePath)
+	if it.status == statusDone || it.status == statusFailed {
+		line += dimStyle.Render(fmt.Sprintf("  %s", it.duration.Round(100*time.Millisecond)))
+	}
+	if it.retries > 0 {
+		line += dimStyle.Render(fmt.Sprintf("  retries %d", it.retries))
+	}
+	if i == m.cursor {
+		return cursorStyle.Render(line)
+	}
+	return line
+}
+
+func (m *model) bottomPane() string {
+	if !m.inspect {
+		logs := m.logs
+		if len(logs) > logLines {
+			logs = logs[len(logs)-logLines:]
+		}
+		return strings.Join(logs, "\n")
+	}
+
+	it := m.items[m.cursor]
+	lines := []string{
+		"File:    " + it.route.FilePath,
+		"Path:    " + it.route.Path,
+		"Status:  " + it.status,
+		fmt.Sprintf("Retries: %d", it.retries),
+	}
+	for _, h := range it.route.Handlers {
+		lines = append(lines, fmt.Sprintf("Handler: %s (lines %d-%d)", h.Method, h.StartLine, h.EndLine))
+	}
+	if len(it.route.Owners) > 0 {
+		lines = append(lines, "Owners:  "+strings.Join(it.route.Owners, ", "))
+	}
+	if it.err != nil {
+		lines = append(lines, errorStyle.Render("Error:   "+it.err.Error()))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,402 @@
+// Package validate checks an assembled OpenAPI 3.0 or 3.1 document against the
+// structural rules of the specification, so documents built from model
+// answers are caught before they are written
+package validate
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Problem is one rule the document breaks
+type Problem struct {
+	Path     string `json:"path,omitempty"` // URL template the problem is under, empty for the rest of the document
+	Location string `json:"location"`       // Where in the document, e.g. paths./api/users.get.responses
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Location + ": " + p.Message
+}
+
+var (
+	operationKeys  = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	parameterIns   = []string{"query", "header", "path", "cookie"}
+	schemaTypes    = []string{"string", "number", "integer", "boolean", "array", "object"}
+	statusPattern  = regexp.MustCompile(`^(?:[1-5]\d\d|[1-5]XX|default)$`)
+	templateParams = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+type validator struct {
+	spec     map[string]interface{}
+	is31     bool
+	problems []Problem
+	path     string // Path being checked
+}
+
+// Spec validates a document in its generic form, returning the problems grouped
+// by path, with those elsewhere in the document first
+func Spec(spec map[string]interface{}) []Problem {
+	v := &validator{spec: spec}
+	version, _ := spec["openapi"].(string)
+	switch {
+	case strings.HasPrefix(version, "3.1."):
+		v.is31 = true
+	case strings.HasPrefix(version, "3.0."):
+	default:
+		v.add("openapi", "unsupported version %q (expected 3.0.x or 3.1.x)", version)
+	}
+
+	info, _ := spec["info"].(map[string]interface{})
+	if info == nil {
+		v.add("info", "is required")
+	} else {
+		for _, field := range []string{"title", "version"} {
+			if s, _ := info[field].(string); s == "" {
+				v.add("info."+field, "is required")
+			}
+		}
+	}
+
+	paths, ok := spec["paths"].(map[string]interface{})
+	if !ok && !(v.is31 && spec["paths"] == nil) {
+		v.add("paths", "is required")
+	}
+	operationIDs := make(map[string]string)
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		v.path = path
+		v.checkPathItem(path, paths[path], operationIDs)
+	}
+	v.path = ""
+
+	components, _ := spec["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(schemas)) {
+		v.checkSchema("components.schemas."+name, schemas[name])
+	}
+	v.checkSecurity("security", spec["security"])
+	v.checkRefs("", spec)
+
+	slices.SortStableFunc(v.problems, func(a, b Problem) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return v.problems
+}
+
+func (v *validator) add(location, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{Path: v.path, Location: location, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) checkPathItem(path string, value interface{}, operationIDs map[string]string) {
+	location := "paths." + path
+	if !strings.HasPrefix(path, "/") {
+		v.add(location, "path must start with /")
+	}
+	item, ok := value.(map[string]interface{})
+	if !ok {
+		v.add(location, "path item must be an object")
+		return
+	}
+
+	shared := v.checkParameters(location+".parameters", item)
+	for _, method := range operationKeys {
+		operation, ok := item[method].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		opLocation := location + "." + method
+		params := v.checkParameters(opLocation+".parameters", operation)
+
+		// Every template variable needs a path parameter, and the reverse
+		declared := make(map[string]bool)
+		for _, key := range append(shared, params...) {
+			if name, ok := strings.CutPrefix(key, "path:"); ok {
+				declared[name] = true
+			}
+		}
+		for _, match := range templateParams.FindAllStringSubmatch(path, -1) {
+			if !declared[match[1]] {
+				v.add(opLocation, "path parameter %q is not declared", match[1])
+			}
+			delete(declared, match[1])
+		}
+		for _, name := range slices.Sorted(maps.Keys(declared)) {
+			v.add(opLocation, "path parameter %q is not in the path", name)
+		}
+
+		if id, _ := operation["operationId"].(string); id != "" {
+			if other, taken := operationIDs[id]; taken {
+				v.add(opLocation+".operationId", "%q is also used by %s", id, other)
+			} else {
+				operationIDs[id] = opLocation
+			}
+		}
+
+		if body, ok := operation["requestBody"].(map[string]interface{}); ok {
+			if _, ref := body["$ref"]; !ref {
+				v.checkContent(opLocation+".requestBody", body["content"], true)
+			}
+		}
+		v.checkResponses(opLocation+".responses", operation["responses"])
+		v.checkSecurity(opLocation+".security", operation["security"])
+	}
+}
+
+// checkParameters validates the parameter list of a path item or operation,
+// returning "in:name" for each
+func (v *validator) checkParameters(location string, holder map[string]interface{}) []string {
+	value, present := holder["parameters"]
+	if !present {
+		return nil
+	}
+	params, ok := value.([]interface{})
+	if !ok {
+		v.add(location, "parameters must be an array")
+		return nil
+	}
+	var keys []string
+	for i, p := range params {
+		at := fmt.Sprintf("%s[%d]", location, i)
+		param, ok := p.(map[string]interface{})
+		if !ok {
+			v.add(at, "parameter must be an object")
+			continue
+		}
+		if _, ref := param["$ref"]; ref {
+			continue
+		}
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		if name == "" {
+			v.add(at, "parameter needs a name")
+		}
+		if !slices.Contains(parameterIns, in) {
+			v.add(at, "parameter %q has invalid location %q (expected query, header, path or cookie)", name, in)
+		}
+		if required, _ := param["required"].(bool); in == "path" && !required {
+			v.add(at, "path parameter %q must be required", name)
+		}
+		_, hasSchema := param["schema"]
+		_, hasContent := param["content"]
+		if hasSchema == hasContent {
+			v.add(at, "parameter %q needs exactly one of schema or content", name)
+		}
+		if hasSchema {
+			v.checkSchema(at+".schema", param["schema"])
+		}
+		key := in + ":" + name
+		if slices.Contains(keys, key) {
+			v.add(at, "parameter %q in %s is declared twice", name, in)
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (v *validator) checkResponses(location string, value interface{}) {
+	responses, ok := value.(map[string]interface{})
+	if !ok || len(responses) == 0 {
+		v.add(location, "at least one response is required")
+		return
+	}
+	for _, status := range slices.Sorted(maps.Keys(responses)) {
+		at := location + "." + status
+		if !statusPattern.MatchString(status) {
+			v.add(at, "%q is not a status code", status)
+		}
+		response, ok := responses[status].(map[string]interface{})
+		if !ok {
+			v.add(at, "response must be an object")
+			continue
+		}
+		if _, ref := response["$ref"]; ref {
+			continue
+		}
+		if _, ok := response["description"].(string); !ok {
+			v.add(at, "response needs a description")
+		}
+		if content, ok := response["content"]; ok {
+			v.checkContent(at, content, false)
+		}
+	}
+}
+
+func (v *validator) checkContent(location string, value interface{}, required bool) {
+	content, ok := value.(map[string]interface{})
+	if !ok || len(content) == 0 {
+		if required || value != nil {
+			v.add(location+".content", "needs at least one media type")
+		}
+		return
+	}
+	for _, mediaType := range slices.Sorted(maps.Keys(content)) {
+		media, ok := content[mediaType].(map[string]interface{})
+		if !ok {
+			v.add(location+".content."+mediaType, "media type must be an object")
+			continue
+		}
+		if schema, ok := media["schema"]; ok {
+			v.checkSchema(location+".content."+mediaType+".schema", schema)
+		}
+	}
+}
+
+func (v *validator) checkSchema(location string, value interface{}) {
+	schema, ok := value.(map[string]interface{})
+	if !ok {
+		if _, isBool := value.(bool); !(v.is31 && isBool) {
+			v.add(location, "schema must be an object")
+		}
+		return
+	}
+	if _, ref := schema["$ref"]; ref && !v.is31 {
+		return
+	}
+
+	switch t := schema["type"].(type) {
+	case nil:
+	case string:
+		if !slices.Contains(schemaTypes, t) && !(v.is31 && t == "null") {
+			v.add(location, "unknown type %q", t)
+		}
+	case []interface{}:
+		if !v.is31 {
+			v.add(location, "a list of types needs OpenAPI 3.1")
+		}
+	default:
+		v.add(location, "type must be a string")
+	}
+	if schema["type"] == "array" && schema["items"] == nil && !v.is31 {
+		v.add(location, "array schema needs items")
+	}
+	if _, ok := schema["nullable"]; ok && v.is31 {
+		v.add(location, "nullable is not allowed in OpenAPI 3.1; add \"null\" to type instead")
+	}
+	if required, ok := schema["required"]; ok {
+		names, isList := required.([]interface{})
+		for _, name := range names {
+			if _, isString := name.(string); !isString {
+				isList = false
+			}
+		}
+		if !isList {
+			v.add(location+".required", "must be a list of property names")
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		v.checkSchema(location+".properties."+name, properties[name])
+	}
+	if items, ok := schema["items"]; ok {
+		v.checkSchema(location+".items", items)
+	}
+	if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+		v.checkSchema(location+".additionalProperties", extra)
+	}
+	for _, key := range []string{"oneOf", "anyOf", "allOf"} {
+		for i, variant := range list(schema[key]) {
+			v.checkSchema(fmt.Sprintf("%s.%s[%d]", location, key, i), variant)
+		}
+	}
+}
+
+// checkSecurity checks that requirements name declared schemes and list scopes
+// only where the scheme has them: scopes of one of its flows for OAuth2, and
+// nothing but roles in 3.1 for schemes other than OpenID Connect
+func (v *validator) checkSecurity(location string, value interface{}) {
+	components, _ := v.spec["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	for i, r := range list(value) {
+		requirement, _ := r.(map[string]interface{})
+		at := fmt.Sprintf("%s[%d]", location, i)
+		for _, name := range slices.Sorted(maps.Keys(requirement)) {
+			scheme, ok := schemes[name].(map[string]interface{})
+			if !ok {
+				v.add(at, "security scheme %q is not declared", name)
+				continue
+			}
+			scopes := list(requirement[name])
+			if requirement[name] != nil && scopes == nil {
+				v.add(at, "scopes of %q must be a list", name)
+				continue
+			}
+			switch scheme["type"] {
+			case "oauth2":
+				declared := oauthScopes(scheme)
+				for _, scope := range scopes {
+					if s, _ := scope.(string); !declared[s] {
+						v.add(at, "scope %q is not declared by any flow of %q", scope, name)
+					}
+				}
+			case "openIdConnect":
+			default:
+				if len(scopes) > 0 && !v.is31 {
+					v.add(at, "%q is not an OAuth2 or OpenID Connect scheme, so its scopes must be empty", name)
+				}
+			}
+		}
+	}
+}
+
+// oauthScopes returns the scopes declared by the flows of an OAuth2 scheme
+func oauthScopes(scheme map[string]interface{}) map[string]bool {
+	declared := make(map[string]bool)
+	flows, _ := scheme["flows"].(map[string]interface{})
+	for _, flow := range flows {
+		f, _ := flow.(map[string]interface{})
+		scopes, _ := f["scopes"].(map[string]interface{})
+		for scope := range scopes {
+			declared[scope] = true
+		}
+	}
+	return declared
+}
+
+// checkRefs reports local $refs that point nowhere
+func (v *validator) checkRefs(location string, value interface{}) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if location == "paths" {
+			for _, path := range slices.Sorted(maps.Keys(value)) {
+				v.path = path
+				v.checkRefs("paths."+path, value[path])
+			}
+			v.path = ""
+			return
+		}
+		if ref, ok := value["$ref"].(string); ok && strings.HasPrefix(ref, "#/") && !v.resolves(ref) {
+			v.add(location, "$ref %s does not resolve", ref)
+		}
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			v.checkRefs(strings.TrimPrefix(location+"."+key, "."), value[key])
+		}
+	case []interface{}:
+		for i, item := range value {
+			v.checkRefs(fmt.Sprintf("%s[%d]", location, i), item)
+		}
+	}
+}
+
+func (v *validator) resolves(ref string) bool {
+	var node interface{} = v.spec
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if node, ok = m[token]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func list(v interface{}) []interface{} {
+	items, _ := v.([]interface{})
+	return items
+}
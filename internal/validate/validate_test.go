@@ -0,0 +1,46 @@
+package validate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters string // JSON value of the operation's parameters, empty to leave it out
+		want       string // Expected problem message, empty for none
+	}{
+		{"missing", "", ""},
+		{"empty", `[]`, ""},
+		{"null", `null`, "parameters must be an array"},
+		{"object", `{"name": "id"}`, "parameters must be an array"},
+		{"query parameter", `[{"name": "q", "in": "query", "schema": {"type": "string"}}]`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operation := `"responses": {"200": {"description": "OK"}}`
+			if tt.parameters != "" {
+				operation += `, "parameters": ` + tt.parameters
+			}
+			var spec map[string]interface{}
+			document := `{"openapi": "3.0.0", "info": {"title": "T", "version": "1"}, "paths": {"/api/health": {"get": {` + operation + `}}}}`
+			if err := json.Unmarshal([]byte(document), &spec); err != nil {
+				t.Fatal(err)
+			}
+
+			problems := Spec(spec)
+			var messages []string
+			for _, p := range problems {
+				messages = append(messages, p.String())
+			}
+			switch {
+			case tt.want == "" && len(problems) > 0:
+				t.Errorf("Spec() = %v, want no problems", messages)
+			case tt.want != "" && !strings.Contains(strings.Join(messages, "\n"), "paths./api/health.get.parameters: "+tt.want):
+				t.Errorf("Spec() = %v, want %q", messages, tt.want)
+			}
+		})
+	}
+}
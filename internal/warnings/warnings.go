@@ -0,0 +1,273 @@
+package warnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+	"nextjs-to-openapi/internal/openapi"
+)
+
+// Code identifies a class of issue so it can be suppressed or enforced
+type Code string
+
+const (
+	PathMismatch       Code = "W001" // The model's path differs from the one the file serves
+	UnexportedMethod   Code = "W002" // A documented method has no exported handler
+	UndocumentedMethod Code = "W003" // An exported handler was not documented
+	MissingSummary     Code = "W004" // An operation has no summary
+	MinifiedSource     Code = "W005" // The route file was minified or bundled
+	NoSuccessResponse  Code = "W010" // No 2xx response was documented
+	MissingPathParam   Code = "W011" // A {param} in the path is not documented as a path parameter
+	MissingRequestBody Code = "W012" // The handler reads a request body that is not documented
+)
+
+// Codes describes every warning code
+var Codes = map[Code]string{
+	PathMismatch:       "path mismatch: the model documented a different path than the file serves",
+	UnexportedMethod:   "documented method without an exported handler",
+	UndocumentedMethod: "exported handler missing from the documentation",
+	MissingSummary:     "operation without a summary",
+	MinifiedSource:     "minified source: the route file was minified or bundled and was beautified before prompting",
+	NoSuccessResponse:  "no 2xx response documented",
+	MissingPathParam:   "path parameter not documented",
+	MissingRequestBody: "missing request body: the handler reads a body that is not documented",
+}
+
+// All stands for every code in suppress and fail-on lists
+const All = "all"
+
+// Warning is one issue found in a route's documentation
+type Warning struct {
+	Code    Code   `json:"code"`
+	File    string `json:"file"`
+	Path    string `json:"path,omitempty"`
+	Method  string `json:"method,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report is the JSON written by --warnings-report
+type Report struct {
+	Warnings   []Warning    `json:"warnings"`
+	Counts     map[Code]int `json:"counts"`
+	Suppressed int          `json:"suppressed"`
+	Enforced   []Code       `json:"enforced,omitempty"` // Codes that failed the run
+}
+
+// Collector gathers warnings, printing each one unless its code is suppressed
+type Collector struct {
+	mu         sync.Mutex
+	suppress   map[Code]bool
+	failOn     map[Code]bool
+	warnings   []Warning
+	suppressed int
+}
+
+// NewCollector validates the codes and returns a collector applying them
+func NewCollector(suppress, failOn []string) (*Collector, error) {
+	c := &Collector{}
+	var err error
+	if c.suppress, err = ParseCodes(suppress); err != nil {
+		return nil, err
+	}
+	if c.failOn, err = ParseCodes(failOn); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ParseCodes parses a list of codes, expanding "all"
+func ParseCodes(values []string) (map[Code]bool, error) {
+	set := make(map[Code]bool)
+	for _, value := range values {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if strings.EqualFold(value, All) {
+			for code := range Codes {
+				set[code] = true
+			}
+			continue
+		}
+		if _, ok := Codes[Code(value)]; !ok {
+			return nil, fmt.Errorf("unknown warning code %q", value)
+		}
+		set[Code(value)] = true
+	}
+	return set, nil
+}
+
+// Add records a warning. A nil collector ignores it.
+func (c *Collector) Add(w Warning) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.suppress[w.Code] {
+		c.suppressed++
+		return
+	}
+	c.warnings = append(c.warnings, w)
+	fmt.Printf("⚠️ %s %s: %s\n", w.Code, w.File, w.Message)
+}
+
+// Report summarizes what was collected
+func (c *Collector) Report() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := Report{Warnings: c.warnings, Counts: make(map[Code]int), Suppressed: c.suppressed}
+	if report.Warnings == nil {
+		report.Warnings = []Warning{}
+	}
+	for _, w := range c.warnings {
+		report.Counts[w.Code]++
+		if c.failOn[w.Code] && !slices.Contains(report.Enforced, w.Code) {
+			report.Enforced = append(report.Enforced, w.Code)
+		}
+	}
+	sort.Slice(report.Enforced, func(i, j int) bool { return report.Enforced[i] < report.Enforced[j] })
+	return report
+}
+
+// Summary is a one-line count by code, e.g. "3 warnings (W001 ×1, W012 ×2)"
+func (r Report) Summary() string {
+	codes := make([]string, 0, len(r.Counts))
+	for code := range r.Counts {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%s ×%d", code, r.Counts[Code(code)])
+	}
+
+	summary := fmt.Sprintf("%d warnings", len(r.Warnings))
+	if len(parts) > 0 {
+		summary += " (" + strings.Join(parts, ", ") + ")"
+	}
+	if r.Suppressed > 0 {
+		summary += fmt.Sprintf(", %d suppressed", r.Suppressed)
+	}
+	return summary
+}
+
+// Write saves the report as JSON
+func (r Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode warnings report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write warnings report: %w", err)
+	}
+	return nil
+}
+
+var (
+	pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+	bodyReadPattern  = regexp.MustCompile(`\b(?:req|request)\.(?:json|formData|text|arrayBuffer|blob)\(\)|\breq\.body\b`)
+)
+
+// Check compares a route's documentation with what the scanner found in the code
+func Check(route models.APIRoute, doc *llm.RouteDocumentation) []Warning {
+	var found []Warning
+	add := func(code Code, method, format string, args ...interface{}) {
+		found = append(found, Warning{Code: code, File: route.FilePath, Path: route.Path, Method: method, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if route.Minified {
+		add(MinifiedSource, "", "the file looks minified or bundled; document the source it was built from instead")
+	}
+	if doc.Path != "" && !samePath(doc.Path, route.Path) {
+		add(PathMismatch, "", "model documented %s, but the file serves %s", doc.Path, route.Path)
+	}
+
+	// The spec uses the file's path, renaming the model's path parameters to match
+	renames := openapi.PathParamRenames(doc.Path, route.Path)
+
+	exported := make(map[string]models.Handler)
+	for _, h := range route.Handlers {
+		exported[h.Method] = h
+	}
+	methods := make([]string, 0, len(doc.Methods))
+	for method := range doc.Methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	for _, method := range methods {
+		details := doc.Methods[method]
+		upper := strings.ToUpper(method)
+		if _, ok := exported[upper]; len(exported) > 0 && !ok && upper != "HEAD" && upper != "OPTIONS" {
+			add(UnexportedMethod, upper, "%s is documented but the file exports no %s handler; it was left out of the spec", upper, upper)
+			continue
+		}
+		if strings.TrimSpace(details.Summary) == "" {
+			add(MissingSummary, upper, "%s has no summary", upper)
+		}
+
+		success := false
+		for status := range details.Responses {
+			success = success || strings.HasPrefix(status, "2")
+		}
+		if !success && len(details.Responses) > 0 {
+			add(NoSuccessResponse, upper, "%s documents no 2xx response", upper)
+		}
+
+		params := make(map[string]bool)
+		hasBody := false
+		for _, param := range details.Parameters {
+			if param.In == "path" {
+				params[param.Name] = true
+				if name, ok := renames[param.Name]; ok {
+					params[name] = true
+				}
+			}
+			hasBody = hasBody || param.In == "body"
+		}
+		for _, z := range route.Zod {
+			// The builder documents the body from the Zod schema it is parsed with
+			hasBody = hasBody || z.Target == models.ZodRequestBody && (z.Method == "*" || strings.EqualFold(z.Method, upper))
+		}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(route.Path, -1) {
+			if !params[m[1]] {
+				add(MissingPathParam, upper, "%s does not document path parameter %s", upper, m[1])
+			}
+		}
+
+		if h, ok := exported[upper]; ok && !hasBody && bodyReadPattern.MatchString(handlerSource(route.Content, h)) {
+			add(MissingRequestBody, upper, "%s reads a request body, but none is documented", upper)
+		}
+	}
+
+	for _, h := range route.Handlers {
+		if !slices.ContainsFunc(methods, func(m string) bool { return strings.EqualFold(m, h.Method) }) {
+			add(UndocumentedMethod, h.Method, "the file exports %s, but it was not documented", h.Method)
+		}
+	}
+	return found
+}
+
+// handlerSource returns the lines a handler spans
+func handlerSource(content string, h models.Handler) string {
+	lines := strings.Split(content, "\n")
+	start, end := max(h.StartLine-1, 0), min(h.EndLine, len(lines))
+	if start >= end {
+		return ""
+	}
+	return strings.Join(lines[start:end], "\n")
+}
+
+// samePath compares paths ignoring parameter names, case and trailing slashes
+func samePath(a, b string) bool {
+	a = strings.TrimSuffix(pathParamPattern.ReplaceAllString(a, "{}"), "/")
+	b = strings.TrimSuffix(pathParamPattern.ReplaceAllString(b, "{}"), "/")
+	return strings.EqualFold(a, b)
+}
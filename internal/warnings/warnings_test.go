@@ -0,0 +1,37 @@
+package warnings
+
+import (
+	"testing"
+
+	"nextjs-to-openapi/internal/llm"
+	"nextjs-to-openapi/internal/models"
+)
+
+func TestCheckDroppedMethodOnlyWarnsOnce(t *testing.T) {
+	route := models.APIRoute{
+		FilePath: "app/api/users/[id]/route.ts",
+		Path:     "/api/users/{id}",
+		Content:  "export async function GET(req, { params }) {\n  return Response.json({})\n}\n",
+		Handlers: []models.Handler{{Method: "GET", StartLine: 1, EndLine: 3}},
+	}
+	doc := &llm.RouteDocumentation{
+		Path: "/api/users/{id}",
+		Methods: map[string]llm.Method{
+			"get": {
+				Summary:    "Get a user",
+				Parameters: []llm.Parameter{{Name: "id", In: "path", Required: true}},
+				Responses:  map[string]llm.Response{"200": {Description: "OK"}},
+			},
+			// Not exported, so it never reaches the spec: only W002 applies
+			"delete": {},
+		},
+	}
+
+	found := Check(route, doc)
+	if len(found) != 1 {
+		t.Fatalf("got %d warnings, want 1: %+v", len(found), found)
+	}
+	if found[0].Code != UnexportedMethod || found[0].Method != "DELETE" {
+		t.Errorf("got %s for %s, want %s for DELETE", found[0].Code, found[0].Method, UnexportedMethod)
+	}
+}